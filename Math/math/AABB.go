@@ -0,0 +1,108 @@
+package math
+
+// AABB represents an axis aligned bounding box
+type AABB struct {
+	Min Vector3f // The corner with the smallest coordinates
+	Max Vector3f // The corner with the largest coordinates
+}
+
+// AABBFromPoints returns the smallest AABB that contains all the points. No points returns an empty box at the origin
+func AABBFromPoints(points ...Vector3f) AABB {
+	if len(points) == 0 {
+		return AABB{}
+	}
+	box := AABB{points[0], points[0]}
+	for _, point := range points[1:] {
+		box = box.Encapsulate(point)
+	}
+	return box
+}
+
+// AABBFromCenterExtents returns an AABB centered on center that extends extents in each direction
+func AABBFromCenterExtents(center Vector3f, extents Vector3f) AABB {
+	return AABB{center.Sub(extents), center.Add(extents)}
+}
+
+// Center returns the center point of the box
+func (box AABB) Center() Vector3f {
+	return box.Min.Add(box.Max).Scale(0.5)
+}
+
+// Size returns the width, height and depth of the box
+func (box AABB) Size() Vector3f {
+	return box.Max.Sub(box.Min)
+}
+
+// Extents returns half the size of the box
+func (box AABB) Extents() Vector3f {
+	return box.Size().Scale(0.5)
+}
+
+// Corners returns the 8 corners of the box
+func (box AABB) Corners() [8]Vector3f {
+	return [8]Vector3f{
+		{box.Min.X, box.Min.Y, box.Min.Z},
+		{box.Max.X, box.Min.Y, box.Min.Z},
+		{box.Min.X, box.Max.Y, box.Min.Z},
+		{box.Max.X, box.Max.Y, box.Min.Z},
+		{box.Min.X, box.Min.Y, box.Max.Z},
+		{box.Max.X, box.Min.Y, box.Max.Z},
+		{box.Min.X, box.Max.Y, box.Max.Z},
+		{box.Max.X, box.Max.Y, box.Max.Z},
+	}
+}
+
+// Encapsulate returns the box grown to contain point
+func (box AABB) Encapsulate(point Vector3f) AABB {
+	return AABB{box.Min.Min(point), box.Max.Max(point)}
+}
+
+// Union returns the smallest box containing both boxes
+func (box AABB) Union(other AABB) AABB {
+	return AABB{box.Min.Min(other.Min), box.Max.Max(other.Max)}
+}
+
+// ContainsPoint returns true if point is inside or on the surface of the box
+func (box AABB) ContainsPoint(point Vector3f) bool {
+	return point.X >= box.Min.X && point.X <= box.Max.X &&
+		point.Y >= box.Min.Y && point.Y <= box.Max.Y &&
+		point.Z >= box.Min.Z && point.Z <= box.Max.Z
+}
+
+// ClosestPoint returns the point on or in the box closest to point
+func (box AABB) ClosestPoint(point Vector3f) Vector3f {
+	return point.Max(box.Min).Min(box.Max)
+}
+
+// Intersects returns true if the two boxes overlap. Touching counts as overlapping
+func (box AABB) Intersects(other AABB) bool {
+	return box.Min.X <= other.Max.X && box.Max.X >= other.Min.X &&
+		box.Min.Y <= other.Max.Y && box.Max.Y >= other.Min.Y &&
+		box.Min.Z <= other.Max.Z && box.Max.Z >= other.Min.Z
+}
+
+// IntersectsSphere returns true if the box and sphere overlap
+func (box AABB) IntersectsSphere(sphere BoundingSphere) bool {
+	return box.ClosestPoint(sphere.Center).DistanceSquared(sphere.Center) <= sphere.Radius*sphere.Radius
+}
+
+// Transform returns the AABB that bounds this box after being transformed by mat.
+// The result is usually larger than the transformed box since it has to stay axis aligned
+func (box AABB) Transform(mat *Matrix4x4) AABB {
+	// Arvo's method: each output axis is the translation plus the min/max contribution of every input axis
+	translation := mat.Translation()
+	newMin, newMax := translation, translation
+	boxMin := [3]float32{box.Min.X, box.Min.Y, box.Min.Z}
+	boxMax := [3]float32{box.Max.X, box.Max.Y, box.Max.Z}
+	outMin := [3]*float32{&newMin.X, &newMin.Y, &newMin.Z}
+	outMax := [3]*float32{&newMax.X, &newMax.Y, &newMax.Z}
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			a := mat[col*4+row] * boxMin[col]
+			b := mat[col*4+row] * boxMax[col]
+			*outMin[row] += Min(a, b)
+			*outMax[row] += Max(a, b)
+		}
+	}
+	return AABB{newMin, newMax}
+}
@@ -0,0 +1,59 @@
+package math
+
+import "testing"
+
+func TestAABBFromPoints(t *testing.T) {
+	box := AABBFromPoints(Vector3f{1, -2, 3}, Vector3f{-1, 4, 0}, Vector3f{0, 0, 5})
+	expected := AABB{Vector3f{-1, -2, 0}, Vector3f{1, 4, 5}}
+	if box != expected {
+		t.Errorf("Got %v, expected %v", box, expected)
+	}
+	if empty := AABBFromPoints(); empty != (AABB{}) {
+		t.Errorf("No points gave %v, expected an empty box", empty)
+	}
+	if box.Center() != (Vector3f{0, 1, 2.5}) || box.Extents() != (Vector3f{1, 3, 2.5}) {
+		t.Errorf("Center %v and extents %v are wrong", box.Center(), box.Extents())
+	}
+}
+
+func TestAABBQueries(t *testing.T) {
+	box := AABB{Vector3f{-1, -1, -1}, Vector3f{1, 1, 1}}
+	tests := []struct {
+		name     string
+		got      bool
+		expected bool
+	}{
+		{"ContainsCenter", box.ContainsPoint(Vector3f{}), true},
+		{"ContainsSurface", box.ContainsPoint(Vector3f{1, 0, 0}), true},
+		{"ContainsOutside", box.ContainsPoint(Vector3f{1.1, 0, 0}), false},
+		{"IntersectsOverlap", box.Intersects(AABB{Vector3f{0.5, 0.5, 0.5}, Vector3f{2, 2, 2}}), true},
+		{"IntersectsTouching", box.Intersects(AABB{Vector3f{1, -1, -1}, Vector3f{2, 1, 1}}), true},
+		{"IntersectsApart", box.Intersects(AABB{Vector3f{1.5, -1, -1}, Vector3f{2, 1, 1}}), false},
+		{"IntersectsSphere", box.IntersectsSphere(BoundingSphere{Vector3f{2, 0, 0}, 1.1}), true},
+		{"IntersectsSphereCorner", box.IntersectsSphere(BoundingSphere{Vector3f{2, 2, 2}, 1.5}), false},
+	}
+	for _, test := range tests {
+		if test.got != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+	if closest := box.ClosestPoint(Vector3f{5, 0.5, -3}); closest != (Vector3f{1, 0.5, -1}) {
+		t.Errorf("ClosestPoint got %v", closest)
+	}
+}
+
+func TestAABBTransform(t *testing.T) {
+	box := AABB{Vector3f{-1, -2, -3}, Vector3f{1, 2, 3}}
+	mat := Matrix4x4TRS(Vector3f{10, 0, 0}, QuaternionFromAxisAngle(Vector3f{1, 2, 3}.Normalize(), 30), Vector3f{1, 2, 0.5})
+	transformed := box.Transform(&mat)
+
+	// Must contain every transformed corner and be as tight as the corners allow
+	var corners []Vector3f
+	for _, corner := range box.Corners() {
+		corners = append(corners, mat.TransformPoint(corner))
+	}
+	expected := AABBFromPoints(corners...)
+	if !transformed.Min.ApproxEqual(expected.Min, 1e-4) || !transformed.Max.ApproxEqual(expected.Max, 1e-4) {
+		t.Errorf("Got %v, expected %v", transformed, expected)
+	}
+}
@@ -0,0 +1,48 @@
+package math
+
+// BoundingSphere represents a sphere used for cheap bounds checks
+type BoundingSphere struct {
+	Center Vector3f // The center of the sphere
+	Radius float32  // The radius of the sphere
+}
+
+// BoundingSphereFromPoints returns a sphere containing all the points. It's centered on their bounding box so it's
+// not always the tightest fit, but it's cheap
+func BoundingSphereFromPoints(points ...Vector3f) BoundingSphere {
+	center := AABBFromPoints(points...).Center()
+	var radiusSquared float32
+	for _, point := range points {
+		radiusSquared = Max(radiusSquared, center.DistanceSquared(point))
+	}
+	return BoundingSphere{center, Sqrt(radiusSquared)}
+}
+
+// BoundingSphereFromAABB returns the sphere that passes through the corners of box
+func BoundingSphereFromAABB(box AABB) BoundingSphere {
+	return BoundingSphere{box.Center(), box.Extents().Length()}
+}
+
+// ContainsPoint returns true if point is inside or on the surface of the sphere
+func (sphere BoundingSphere) ContainsPoint(point Vector3f) bool {
+	return sphere.Center.DistanceSquared(point) <= sphere.Radius*sphere.Radius
+}
+
+// Intersects returns true if the two spheres overlap
+func (sphere BoundingSphere) Intersects(other BoundingSphere) bool {
+	radii := sphere.Radius + other.Radius
+	return sphere.Center.DistanceSquared(other.Center) <= radii*radii
+}
+
+// IntersectsAABB returns true if the sphere and box overlap
+func (sphere BoundingSphere) IntersectsAABB(box AABB) bool {
+	return box.IntersectsSphere(sphere)
+}
+
+// Transform returns the sphere after being transformed by mat. Non uniform scale grows the radius by the largest axis
+func (sphere BoundingSphere) Transform(mat *Matrix4x4) BoundingSphere {
+	scaleX := Vector3f{mat[0], mat[1], mat[2]}.LengthSquared()
+	scaleY := Vector3f{mat[4], mat[5], mat[6]}.LengthSquared()
+	scaleZ := Vector3f{mat[8], mat[9], mat[10]}.LengthSquared()
+	maxScale := Sqrt(Max(scaleX, Max(scaleY, scaleZ)))
+	return BoundingSphere{mat.TransformPoint(sphere.Center), sphere.Radius * maxScale}
+}
@@ -0,0 +1,39 @@
+package math
+
+import "testing"
+
+func TestBoundingSphere(t *testing.T) {
+	points := []Vector3f{{1, 0, 0}, {-1, 0, 0}, {0, 2, 0}, {0, -2, 1}}
+	sphere := BoundingSphereFromPoints(points...)
+	for _, point := range points {
+		if !sphere.ContainsPoint(point) && !ApproxEqual(sphere.Center.Distance(point), sphere.Radius, 1e-5) {
+			t.Errorf("Sphere %v doesn't contain %v", sphere, point)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		got      bool
+		expected bool
+	}{
+		{"Intersects", BoundingSphere{Vector3f{}, 1}.Intersects(BoundingSphere{Vector3f{1.5, 0, 0}, 0.6}), true},
+		{"IntersectsApart", BoundingSphere{Vector3f{}, 1}.Intersects(BoundingSphere{Vector3f{1.5, 0, 0}, 0.4}), false},
+		{"IntersectsAABB", BoundingSphere{Vector3f{0, 3, 0}, 2}.IntersectsAABB(AABB{Vector3f{-1, -1, -1}, Vector3f{1, 1, 1}}), true},
+	}
+	for _, test := range tests {
+		if test.got != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+
+	fromBox := BoundingSphereFromAABB(AABB{Vector3f{-1, -1, -1}, Vector3f{1, 1, 1}})
+	if !ApproxEqual(fromBox.Radius, Sqrt(3), 1e-5) {
+		t.Errorf("Sphere from a unit box has radius %v, expected sqrt(3)", fromBox.Radius)
+	}
+
+	mat := Matrix4x4TRS(Vector3f{0, 5, 0}, IdentityQuaternion(), Vector3f{1, 3, 2})
+	transformed := BoundingSphere{Vector3f{1, 0, 0}, 2}.Transform(&mat)
+	if !transformed.Center.ApproxEqual(Vector3f{1, 5, 0}, 1e-5) || !ApproxEqual(transformed.Radius, 6, 1e-5) {
+		t.Errorf("Transformed sphere is %v", transformed)
+	}
+}
@@ -9,4 +9,6 @@ const (
 	Grad2Rad float32 = 0.015707963267948966192313216916397514420985846996876
 	// Rad2Grad conversion factor
 	Rad2Grad float32 = 63.661977236758134307553505349005744813783858296183
+	// Epsilon is the default tolerance used when comparing floats
+	Epsilon float32 = 1e-5
 )
@@ -0,0 +1,95 @@
+package math
+
+// Indices into Frustum.Planes
+const (
+	FrustumLeft = iota
+	FrustumRight
+	FrustumBottom
+	FrustumTop
+	FrustumNear
+	FrustumFar
+)
+
+// Frustum represents the volume visible to a camera as 6 inward facing planes
+type Frustum struct {
+	Planes [6]Plane // Indexed by FrustumLeft, FrustumRight etc.
+}
+
+// FrustumFromMatrix extracts the frustum from a view projection matrix (projection * view).
+// Passing just a projection matrix gives the frustum in view space
+func FrustumFromMatrix(viewProjection *Matrix4x4) Frustum {
+	// Gribb-Hartmann: each plane is the 4th row plus or minus one of the other rows
+	m := viewProjection
+	row := func(i int) Vector4f {
+		return Vector4f{m[i], m[4+i], m[8+i], m[12+i]}
+	}
+	r0, r1, r2, r3 := row(0), row(1), row(2), row(3)
+	planes := [6]Vector4f{
+		r3.Add(r0),
+		r3.Sub(r0),
+		r3.Add(r1),
+		r3.Sub(r1),
+		r3.Add(r2),
+		r3.Sub(r2),
+	}
+
+	var frustum Frustum
+	for i, plane := range planes {
+		frustum.Planes[i] = Plane{plane.XYZ(), plane.W}.Normalize()
+	}
+	return frustum
+}
+
+// ContainsPoint returns true if point is inside the frustum
+func (frustum Frustum) ContainsPoint(point Vector3f) bool {
+	for _, plane := range frustum.Planes {
+		if plane.SignedDistance(point) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsSphere returns true if any part of the sphere may be inside the frustum
+func (frustum Frustum) IntersectsSphere(sphere BoundingSphere) bool {
+	for _, plane := range frustum.Planes {
+		if plane.SignedDistance(sphere.Center) < -sphere.Radius {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsAABB returns true if any part of the box may be inside the frustum.
+// Like all plane based tests it can give false positives for big boxes near the frustum's corners, which is fine for culling
+func (frustum Frustum) IntersectsAABB(box AABB) bool {
+	for _, plane := range frustum.Planes {
+		// Test the corner furthest along the plane's normal. If that's behind the plane the whole box is
+		positive := box.Min
+		if plane.Normal.X >= 0 {
+			positive.X = box.Max.X
+		}
+		if plane.Normal.Y >= 0 {
+			positive.Y = box.Max.Y
+		}
+		if plane.Normal.Z >= 0 {
+			positive.Z = box.Max.Z
+		}
+		if plane.SignedDistance(positive) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Transform returns the frustum after being transformed by mat. Errors if mat is singular
+func (frustum Frustum) Transform(mat *Matrix4x4) (Frustum, error) {
+	var transformed Frustum
+	for i, plane := range frustum.Planes {
+		var err error
+		if transformed.Planes[i], err = plane.Transform(mat); err != nil {
+			return Frustum{}, err
+		}
+	}
+	return transformed, nil
+}
@@ -0,0 +1,76 @@
+package math
+
+import "testing"
+
+func TestFrustumFromPerspective(t *testing.T) {
+	projection := PerspectiveFovY(90, 1, 1, 100)
+	frustum := FrustumFromMatrix(&projection)
+
+	tests := []struct {
+		name     string
+		point    Vector3f
+		expected bool
+	}{
+		{"Center", Vector3f{0, 0, -10}, true},
+		{"BehindCamera", Vector3f{0, 0, 10}, false},
+		{"BeforeNear", Vector3f{0, 0, -0.5}, false},
+		{"PastFar", Vector3f{0, 0, -101}, false},
+		{"InsideEdge", Vector3f{9.9, 0, -10}, true},
+		{"OutsideLeft", Vector3f{-10.1, 0, -10}, false},
+		{"OutsideTop", Vector3f{0, 10.1, -10}, false},
+	}
+	for _, test := range tests {
+		if got := frustum.ContainsPoint(test.point); got != test.expected {
+			t.Errorf("%v: ContainsPoint(%v) got %v, expected %v", test.name, test.point, got, test.expected)
+		}
+	}
+
+	if !frustum.IntersectsSphere(BoundingSphere{Vector3f{-11, 0, -10}, 1}) {
+		t.Error("A sphere poking into the left side should intersect")
+	}
+	if frustum.IntersectsSphere(BoundingSphere{Vector3f{0, 0, 5}, 1}) {
+		t.Error("A sphere behind the camera shouldn't intersect")
+	}
+	if !frustum.IntersectsAABB(AABB{Vector3f{-20, -1, -11}, Vector3f{-9, 1, -9}}) {
+		t.Error("A box poking into the left side should intersect")
+	}
+	if frustum.IntersectsAABB(AABB{Vector3f{-1, -1, -200}, Vector3f{1, 1, -150}}) {
+		t.Error("A box past the far plane shouldn't intersect")
+	}
+}
+
+func TestFrustumWithView(t *testing.T) {
+	view := LookAt(Vector3f{0, 0, 10}, Vector3f{}, UpVector3f())
+	projection := Orthographic(-1, 1, -1, 1, 1, 20)
+	viewProjection := projection.Mul(view)
+	frustum := FrustumFromMatrix(&viewProjection)
+
+	if !frustum.ContainsPoint(Vector3f{0.5, -0.5, 0}) {
+		t.Error("The origin region should be visible from a camera at z = 10")
+	}
+	if frustum.ContainsPoint(Vector3f{0, 0, 10.5}) {
+		t.Error("A point behind the camera shouldn't be visible")
+	}
+
+	// Moving the frustum back into view space should match extracting it from the projection
+	viewSpace, err := frustum.Transform(&view)
+	if err != nil {
+		t.Fatalf("Transforming by the view matrix returned %v", err)
+	}
+	fromProjection := FrustumFromMatrix(&projection)
+	for i := range viewSpace.Planes {
+		if !viewSpace.Planes[i].Normal.ApproxEqual(fromProjection.Planes[i].Normal, 1e-4) ||
+			!ApproxEqual(viewSpace.Planes[i].Distance, fromProjection.Planes[i].Distance, 1e-3) {
+			t.Errorf("Plane %v: got %v, expected %v", i, viewSpace.Planes[i], fromProjection.Planes[i])
+		}
+	}
+}
+
+func TestFrustumTransformSingular(t *testing.T) {
+	projection := PerspectiveFovY(60, 1, 0.1, 100)
+	frustum := FrustumFromMatrix(&projection)
+	flatten := Matrix4x4Scale(Vector3f{1, 1, 0})
+	if _, err := frustum.Transform(&flatten); err != ErrSingularMatrix {
+		t.Errorf("Transforming by a flattening scale returned %v, expected ErrSingularMatrix", err)
+	}
+}
@@ -1,12 +1,40 @@
 package math
 
+import "errors"
+
+// ErrSingularMatrix is returned when attempting to invert a matrix with a determinant of 0
+var ErrSingularMatrix = errors.New("Invalid Operation: Matrix is singular and cannot be inverted")
+
+// ErrNonSquareMatrix is returned when attempting an operation that only makes sense for square matrices
+var ErrNonSquareMatrix = errors.New("Invalid Operation: Matrix must be square")
+
 // Matrix interface represents a standard matrix implementation in the Surreal Engine
 type Matrix interface {
-	Get(row int, col int) float32        // Returns the element at the corresponding row and column. Indexes start at 0 you mongrel. Values too large or too small should wrap.
-	Set(row int, col int, value float32) // Sets the element at the corresponding row and column. Indexes start at 0 you mongrel. Values too large or too small should wrap.
-	// TODO: GetSubMatrix(x1 int, y1 int, x2 int, y2 int) Matrix
-	// TODO: SetSubMatrix(x int, y int, sub *Matrix)
-	NumRows() int             // Returns the number of rows in the matrix
-	NumCols() int             // Returns the number of columns in the matrix
-	ColMajorData() *[]float32 // Returns the data in column major order for use with open gl
+	Get(row int, col int) float32                                   // Returns the element at the corresponding row and column. Indexes start at 0 you mongrel. Values too large or too small should wrap.
+	Set(row int, col int, value float32)                            // Sets the element at the corresponding row and column. Indexes start at 0 you mongrel. Values too large or too small should wrap.
+	GetSubMatrix(row int, col int, numRows int, numCols int) Matrix // Returns a copy of the numRows x numCols block starting at row, col. Indexes wrap like Get.
+	SetSubMatrix(row int, col int, sub Matrix)                      // Copies sub into this matrix starting at row, col. Indexes wrap like Set.
+	NumRows() int                                                   // Returns the number of rows in the matrix
+	NumCols() int                                                   // Returns the number of columns in the matrix
+	ColMajorData() *[]float32                                       // Returns the data in column major order for use with open gl
+}
+
+// Copies a block out of any matrix into a new StandardMatrix. Shared by all Matrix implementations
+func getSubMatrix(mat Matrix, row int, col int, numRows int, numCols int) *StandardMatrix {
+	sub := StandardMatrixZeros(numRows, numCols)
+	for j := 0; j < numCols; j++ {
+		for i := 0; i < numRows; i++ {
+			sub.Set(i, j, mat.Get(row+i, col+j))
+		}
+	}
+	return sub
+}
+
+// Copies sub into any matrix starting at row, col. Shared by all Matrix implementations
+func setSubMatrix(mat Matrix, row int, col int, sub Matrix) {
+	for j := 0; j < sub.NumCols(); j++ {
+		for i := 0; i < sub.NumRows(); i++ {
+			mat.Set(row+i, col+j, sub.Get(i, j))
+		}
+	}
 }
@@ -0,0 +1,124 @@
+package math
+
+// Matrix3x3 is a fixed size 3x3 matrix stored in column major order.
+// Like Matrix4x4 it's a value type and none of it's operations allocate
+type Matrix3x3 [9]float32
+
+// Matrix3x3Identity returns the 3x3 identity matrix
+func Matrix3x3Identity() Matrix3x3 {
+	return Matrix3x3{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	}
+}
+
+// Get is Implementing Matrix Interface
+func (mat *Matrix3x3) Get(row int, col int) float32 {
+	return mat[wrapIndex(col, 3)*3+wrapIndex(row, 3)]
+}
+
+// Set is Implementing Matrix Interface
+func (mat *Matrix3x3) Set(row int, col int, value float32) {
+	mat[wrapIndex(col, 3)*3+wrapIndex(row, 3)] = value
+}
+
+// GetSubMatrix is Implementing Matrix Interface
+func (mat *Matrix3x3) GetSubMatrix(row int, col int, numRows int, numCols int) Matrix {
+	return getSubMatrix(mat, row, col, numRows, numCols)
+}
+
+// SetSubMatrix is Implementing Matrix Interface
+func (mat *Matrix3x3) SetSubMatrix(row int, col int, sub Matrix) {
+	setSubMatrix(mat, row, col, sub)
+}
+
+// NumRows is Implementing Matrix Interface
+func (mat *Matrix3x3) NumRows() int {
+	return 3
+}
+
+// NumCols is Implementing Matrix Interface
+func (mat *Matrix3x3) NumCols() int {
+	return 3
+}
+
+// ColMajorData implements the Matrix interface. Prefer passing &mat[0] to OpenGL since this allocates a slice header
+func (mat *Matrix3x3) ColMajorData() *[]float32 {
+	data := mat[:]
+	return &data
+}
+
+// Mul returns mat * other
+func (mat Matrix3x3) Mul(other Matrix3x3) Matrix3x3 {
+	var result Matrix3x3
+	result.MulInto(&mat, &other)
+	return result
+}
+
+// MulInto stores a * b into mat. mat may be the same matrix as a or b
+func (mat *Matrix3x3) MulInto(a *Matrix3x3, b *Matrix3x3) {
+	var result Matrix3x3
+	for col := 0; col < 3; col++ {
+		b0, b1, b2 := b[col*3], b[col*3+1], b[col*3+2]
+		result[col*3] = a[0]*b0 + a[3]*b1 + a[6]*b2
+		result[col*3+1] = a[1]*b0 + a[4]*b1 + a[7]*b2
+		result[col*3+2] = a[2]*b0 + a[5]*b1 + a[8]*b2
+	}
+	*mat = result
+}
+
+// Transpose returns the transpose of mat
+func (mat Matrix3x3) Transpose() Matrix3x3 {
+	return Matrix3x3{
+		mat[0], mat[3], mat[6],
+		mat[1], mat[4], mat[7],
+		mat[2], mat[5], mat[8],
+	}
+}
+
+// Determinant returns the determinant of mat
+func (mat Matrix3x3) Determinant() float32 {
+	return mat[0]*(mat[4]*mat[8]-mat[7]*mat[5]) -
+		mat[3]*(mat[1]*mat[8]-mat[7]*mat[2]) +
+		mat[6]*(mat[1]*mat[5]-mat[4]*mat[2])
+}
+
+// Inverse returns the inverse of mat, or ErrSingularMatrix if it has none
+func (mat Matrix3x3) Inverse() (Matrix3x3, error) {
+	det := mat.Determinant()
+	if det == 0 {
+		return Matrix3x3{}, ErrSingularMatrix
+	}
+	invDet := 1 / det
+	return Matrix3x3{
+		(mat[4]*mat[8] - mat[7]*mat[5]) * invDet,
+		(mat[7]*mat[2] - mat[1]*mat[8]) * invDet,
+		(mat[1]*mat[5] - mat[4]*mat[2]) * invDet,
+		(mat[6]*mat[5] - mat[3]*mat[8]) * invDet,
+		(mat[0]*mat[8] - mat[6]*mat[2]) * invDet,
+		(mat[3]*mat[2] - mat[0]*mat[5]) * invDet,
+		(mat[3]*mat[7] - mat[6]*mat[4]) * invDet,
+		(mat[6]*mat[1] - mat[0]*mat[7]) * invDet,
+		(mat[0]*mat[4] - mat[3]*mat[1]) * invDet,
+	}, nil
+}
+
+// MulVector returns mat * vec
+func (mat *Matrix3x3) MulVector(vec Vector3f) Vector3f {
+	return Vector3f{
+		mat[0]*vec.X + mat[3]*vec.Y + mat[6]*vec.Z,
+		mat[1]*vec.X + mat[4]*vec.Y + mat[7]*vec.Z,
+		mat[2]*vec.X + mat[5]*vec.Y + mat[8]*vec.Z,
+	}
+}
+
+// ApproxEqual returns true if every element of mat is within tolerance of other
+func (mat *Matrix3x3) ApproxEqual(other *Matrix3x3, tolerance float32) bool {
+	for i := range mat {
+		if !ApproxEqual(mat[i], other[i], tolerance) {
+			return false
+		}
+	}
+	return true
+}
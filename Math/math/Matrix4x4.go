@@ -0,0 +1,226 @@
+package math
+
+// Matrix4x4 is a fixed size 4x4 matrix stored in column major order.
+// It's a value type so it lives on the stack and none of it's operations allocate.
+// Use this over StandardMatrix for anything that runs every frame
+type Matrix4x4 [16]float32
+
+// Matrix4x4Identity returns the 4x4 identity matrix
+func Matrix4x4Identity() Matrix4x4 {
+	return Matrix4x4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Matrix4x4Translation returns a matrix that translates by translation
+func Matrix4x4Translation(translation Vector3f) Matrix4x4 {
+	// Note matrices are column major so we are writing the transpose out
+	return Matrix4x4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		translation.X, translation.Y, translation.Z, 1,
+	}
+}
+
+// Matrix4x4Scale returns a matrix that scales each axis by the corresponding component of scale
+func Matrix4x4Scale(scale Vector3f) Matrix4x4 {
+	return Matrix4x4{
+		scale.X, 0, 0, 0,
+		0, scale.Y, 0, 0,
+		0, 0, scale.Z, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Matrix4x4Rotation returns the rotation matrix for a quaternion. Shortform for rotation.ToMatrix()
+func Matrix4x4Rotation(rotation Quaternion) Matrix4x4 {
+	return rotation.ToMatrix()
+}
+
+// Matrix4x4TRS returns translation * rotation * scale without building the intermediate matrices
+func Matrix4x4TRS(translation Vector3f, rotation Quaternion, scale Vector3f) Matrix4x4 {
+	mat := rotation.ToMatrix()
+	mat[0], mat[1], mat[2] = mat[0]*scale.X, mat[1]*scale.X, mat[2]*scale.X
+	mat[4], mat[5], mat[6] = mat[4]*scale.Y, mat[5]*scale.Y, mat[6]*scale.Y
+	mat[8], mat[9], mat[10] = mat[8]*scale.Z, mat[9]*scale.Z, mat[10]*scale.Z
+	mat[12], mat[13], mat[14] = translation.X, translation.Y, translation.Z
+	return mat
+}
+
+// Get is Implementing Matrix Interface
+func (mat *Matrix4x4) Get(row int, col int) float32 {
+	// & 3 is a cheap wrap for a power of 2 size, and handles negatives thanks to two's complement
+	return mat[(col&3)*4+(row&3)]
+}
+
+// Set is Implementing Matrix Interface
+func (mat *Matrix4x4) Set(row int, col int, value float32) {
+	mat[(col&3)*4+(row&3)] = value
+}
+
+// GetSubMatrix is Implementing Matrix Interface
+func (mat *Matrix4x4) GetSubMatrix(row int, col int, numRows int, numCols int) Matrix {
+	return getSubMatrix(mat, row, col, numRows, numCols)
+}
+
+// SetSubMatrix is Implementing Matrix Interface
+func (mat *Matrix4x4) SetSubMatrix(row int, col int, sub Matrix) {
+	setSubMatrix(mat, row, col, sub)
+}
+
+// NumRows is Implementing Matrix Interface
+func (mat *Matrix4x4) NumRows() int {
+	return 4
+}
+
+// NumCols is Implementing Matrix Interface
+func (mat *Matrix4x4) NumCols() int {
+	return 4
+}
+
+// ColMajorData implements the Matrix interface. Prefer passing &mat[0] to OpenGL since this allocates a slice header
+func (mat *Matrix4x4) ColMajorData() *[]float32 {
+	data := mat[:]
+	return &data
+}
+
+// Mul returns mat * other
+func (mat Matrix4x4) Mul(other Matrix4x4) Matrix4x4 {
+	var result Matrix4x4
+	result.MulInto(&mat, &other)
+	return result
+}
+
+// MulInto stores a * b into mat. mat may be the same matrix as a or b
+func (mat *Matrix4x4) MulInto(a *Matrix4x4, b *Matrix4x4) {
+	var result Matrix4x4
+	for col := 0; col < 4; col++ {
+		b0, b1, b2, b3 := b[col*4], b[col*4+1], b[col*4+2], b[col*4+3]
+		result[col*4] = a[0]*b0 + a[4]*b1 + a[8]*b2 + a[12]*b3
+		result[col*4+1] = a[1]*b0 + a[5]*b1 + a[9]*b2 + a[13]*b3
+		result[col*4+2] = a[2]*b0 + a[6]*b1 + a[10]*b2 + a[14]*b3
+		result[col*4+3] = a[3]*b0 + a[7]*b1 + a[11]*b2 + a[15]*b3
+	}
+	*mat = result
+}
+
+// Transpose returns the transpose of mat
+func (mat Matrix4x4) Transpose() Matrix4x4 {
+	return Matrix4x4{
+		mat[0], mat[4], mat[8], mat[12],
+		mat[1], mat[5], mat[9], mat[13],
+		mat[2], mat[6], mat[10], mat[14],
+		mat[3], mat[7], mat[11], mat[15],
+	}
+}
+
+// Computes the adjugate of mat and returns it along with the determinant
+func (mat *Matrix4x4) adjugate() (inv Matrix4x4, det float32) {
+	m := mat
+	inv[0] = m[5]*m[10]*m[15] - m[5]*m[11]*m[14] - m[9]*m[6]*m[15] + m[9]*m[7]*m[14] + m[13]*m[6]*m[11] - m[13]*m[7]*m[10]
+	inv[4] = -m[4]*m[10]*m[15] + m[4]*m[11]*m[14] + m[8]*m[6]*m[15] - m[8]*m[7]*m[14] - m[12]*m[6]*m[11] + m[12]*m[7]*m[10]
+	inv[8] = m[4]*m[9]*m[15] - m[4]*m[11]*m[13] - m[8]*m[5]*m[15] + m[8]*m[7]*m[13] + m[12]*m[5]*m[11] - m[12]*m[7]*m[9]
+	inv[12] = -m[4]*m[9]*m[14] + m[4]*m[10]*m[13] + m[8]*m[5]*m[14] - m[8]*m[6]*m[13] - m[12]*m[5]*m[10] + m[12]*m[6]*m[9]
+	inv[1] = -m[1]*m[10]*m[15] + m[1]*m[11]*m[14] + m[9]*m[2]*m[15] - m[9]*m[3]*m[14] - m[13]*m[2]*m[11] + m[13]*m[3]*m[10]
+	inv[5] = m[0]*m[10]*m[15] - m[0]*m[11]*m[14] - m[8]*m[2]*m[15] + m[8]*m[3]*m[14] + m[12]*m[2]*m[11] - m[12]*m[3]*m[10]
+	inv[9] = -m[0]*m[9]*m[15] + m[0]*m[11]*m[13] + m[8]*m[1]*m[15] - m[8]*m[3]*m[13] - m[12]*m[1]*m[11] + m[12]*m[3]*m[9]
+	inv[13] = m[0]*m[9]*m[14] - m[0]*m[10]*m[13] - m[8]*m[1]*m[14] + m[8]*m[2]*m[13] + m[12]*m[1]*m[10] - m[12]*m[2]*m[9]
+	inv[2] = m[1]*m[6]*m[15] - m[1]*m[7]*m[14] - m[5]*m[2]*m[15] + m[5]*m[3]*m[14] + m[13]*m[2]*m[7] - m[13]*m[3]*m[6]
+	inv[6] = -m[0]*m[6]*m[15] + m[0]*m[7]*m[14] + m[4]*m[2]*m[15] - m[4]*m[3]*m[14] - m[12]*m[2]*m[7] + m[12]*m[3]*m[6]
+	inv[10] = m[0]*m[5]*m[15] - m[0]*m[7]*m[13] - m[4]*m[1]*m[15] + m[4]*m[3]*m[13] + m[12]*m[1]*m[7] - m[12]*m[3]*m[5]
+	inv[14] = -m[0]*m[5]*m[14] + m[0]*m[6]*m[13] + m[4]*m[1]*m[14] - m[4]*m[2]*m[13] - m[12]*m[1]*m[6] + m[12]*m[2]*m[5]
+	inv[3] = -m[1]*m[6]*m[11] + m[1]*m[7]*m[10] + m[5]*m[2]*m[11] - m[5]*m[3]*m[10] - m[9]*m[2]*m[7] + m[9]*m[3]*m[6]
+	inv[7] = m[0]*m[6]*m[11] - m[0]*m[7]*m[10] - m[4]*m[2]*m[11] + m[4]*m[3]*m[10] + m[8]*m[2]*m[7] - m[8]*m[3]*m[6]
+	inv[11] = -m[0]*m[5]*m[11] + m[0]*m[7]*m[9] + m[4]*m[1]*m[11] - m[4]*m[3]*m[9] - m[8]*m[1]*m[7] + m[8]*m[3]*m[5]
+	inv[15] = m[0]*m[5]*m[10] - m[0]*m[6]*m[9] - m[4]*m[1]*m[10] + m[4]*m[2]*m[9] + m[8]*m[1]*m[6] - m[8]*m[2]*m[5]
+	det = m[0]*inv[0] + m[1]*inv[4] + m[2]*inv[8] + m[3]*inv[12]
+	return
+}
+
+// Determinant returns the determinant of mat
+func (mat Matrix4x4) Determinant() float32 {
+	_, det := mat.adjugate()
+	return det
+}
+
+// Inverse returns the inverse of mat, or ErrSingularMatrix if it has none
+func (mat Matrix4x4) Inverse() (Matrix4x4, error) {
+	inv, det := mat.adjugate()
+	if det == 0 {
+		return Matrix4x4{}, ErrSingularMatrix
+	}
+	invDet := 1 / det
+	for i := range inv {
+		inv[i] *= invDet
+	}
+	return inv, nil
+}
+
+// TransformPoint returns point transformed by mat, including translation and the perspective divide
+func (mat *Matrix4x4) TransformPoint(point Vector3f) Vector3f {
+	x := mat[0]*point.X + mat[4]*point.Y + mat[8]*point.Z + mat[12]
+	y := mat[1]*point.X + mat[5]*point.Y + mat[9]*point.Z + mat[13]
+	z := mat[2]*point.X + mat[6]*point.Y + mat[10]*point.Z + mat[14]
+	w := mat[3]*point.X + mat[7]*point.Y + mat[11]*point.Z + mat[15]
+	if w != 1 && w != 0 {
+		invW := 1 / w
+		return Vector3f{x * invW, y * invW, z * invW}
+	}
+	return Vector3f{x, y, z}
+}
+
+// TransformDirection returns direction transformed by mat ignoring translation. The result is not normalized
+func (mat *Matrix4x4) TransformDirection(direction Vector3f) Vector3f {
+	return Vector3f{
+		mat[0]*direction.X + mat[4]*direction.Y + mat[8]*direction.Z,
+		mat[1]*direction.X + mat[5]*direction.Y + mat[9]*direction.Z,
+		mat[2]*direction.X + mat[6]*direction.Y + mat[10]*direction.Z,
+	}
+}
+
+// TransformVector4f returns mat * vec
+func (mat *Matrix4x4) TransformVector4f(vec Vector4f) Vector4f {
+	return Vector4f{
+		mat[0]*vec.X + mat[4]*vec.Y + mat[8]*vec.Z + mat[12]*vec.W,
+		mat[1]*vec.X + mat[5]*vec.Y + mat[9]*vec.Z + mat[13]*vec.W,
+		mat[2]*vec.X + mat[6]*vec.Y + mat[10]*vec.Z + mat[14]*vec.W,
+		mat[3]*vec.X + mat[7]*vec.Y + mat[11]*vec.Z + mat[15]*vec.W,
+	}
+}
+
+// Translation returns the translation part of an affine transform matrix
+func (mat *Matrix4x4) Translation() Vector3f {
+	return Vector3f{mat[12], mat[13], mat[14]}
+}
+
+// Upper3x3 returns the top left 3x3 portion of mat, which holds rotation and scale for affine transforms
+func (mat *Matrix4x4) Upper3x3() Matrix3x3 {
+	return Matrix3x3{
+		mat[0], mat[1], mat[2],
+		mat[4], mat[5], mat[6],
+		mat[8], mat[9], mat[10],
+	}
+}
+
+// NormalMatrix returns the inverse transpose of the upper 3x3. Use it to transform normals when the matrix has non uniform scale
+func (mat *Matrix4x4) NormalMatrix() (Matrix3x3, error) {
+	inv, err := mat.Upper3x3().Inverse()
+	if err != nil {
+		return Matrix3x3{}, err
+	}
+	return inv.Transpose(), nil
+}
+
+// ApproxEqual returns true if every element of mat is within tolerance of other
+func (mat *Matrix4x4) ApproxEqual(other *Matrix4x4, tolerance float32) bool {
+	for i := range mat {
+		if !ApproxEqual(mat[i], other[i], tolerance) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package math
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomMatrix4x4 returns a matrix of values in [-2, 2). Small values keep float32 products of them accurate
+func randomMatrix4x4(random *rand.Rand) Matrix4x4 {
+	var mat Matrix4x4
+	for i := range mat {
+		mat[i] = random.Float32()*4 - 2
+	}
+	return mat
+}
+
+func TestMatrix4x4Inverse(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	identity := Matrix4x4Identity()
+	for trial := 0; trial < 100; trial++ {
+		mat := randomMatrix4x4(random)
+		inverse, err := mat.Inverse()
+		if err != nil {
+			t.Fatalf("Inverse of %v failed: %v", mat, err)
+		}
+		if product := mat.Mul(inverse); !product.ApproxEqual(&identity, 1e-3) {
+			t.Errorf("M * M^-1 isn't identity for %v: %v", mat, product)
+		}
+		if product := inverse.Mul(mat); !product.ApproxEqual(&identity, 1e-3) {
+			t.Errorf("M^-1 * M isn't identity for %v: %v", mat, product)
+		}
+	}
+}
+
+func TestMatrix4x4InverseTRS(t *testing.T) {
+	mat := Matrix4x4TRS(Vector3f{1, -2, 3}, QuaternionFromAxisAngle(Vector3f{1, 1, 0}.Normalize(), 35), Vector3f{2, 0.5, 3})
+	inverse, err := mat.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	point := Vector3f{4, 5, -6}
+	if got := inverse.TransformPoint(mat.TransformPoint(point)); !got.ApproxEqual(point, 1e-4) {
+		t.Errorf("Inverse didn't undo the transform: got %v, expected %v", got, point)
+	}
+}
+
+func TestMatrix4x4TransposeDeterminant(t *testing.T) {
+	random := rand.New(rand.NewSource(4))
+	for trial := 0; trial < 100; trial++ {
+		a, b := randomMatrix4x4(random), randomMatrix4x4(random)
+		if a.Transpose().Transpose() != a {
+			t.Errorf("(M^T)^T != M for %v", a)
+		}
+		left, right := a.Mul(b).Transpose(), b.Transpose().Mul(a.Transpose())
+		if !left.ApproxEqual(&right, 1e-3) {
+			t.Errorf("(AB)^T != B^T A^T for %v and %v", a, b)
+		}
+
+		detA, detB := a.Determinant(), b.Determinant()
+		if detT := a.Transpose().Determinant(); !ApproxEqual(detT, detA, 1e-3*Max(1, Abs(detA))) {
+			t.Errorf("det(M^T) = %v but det(M) = %v", detT, detA)
+		}
+		if detAB := a.Mul(b).Determinant(); !ApproxEqual(detAB, detA*detB, 1e-3*Max(1, Abs(detA*detB))) {
+			t.Errorf("det(AB) = %v but det(A)det(B) = %v", detAB, detA*detB)
+		}
+
+		// Matches the general implementation
+		standard, _ := CreateStandardMatrix(a[:], 4, 4).Determinant()
+		if !ApproxEqual(detA, standard, 1e-3*Max(1, Abs(detA))) {
+			t.Errorf("Matrix4x4 determinant %v doesn't match StandardMatrix %v", detA, standard)
+		}
+	}
+
+	if det := Matrix4x4Scale(Vector3f{2, 3, 4}).Determinant(); det != 24 {
+		t.Errorf("Determinant of a scale by 2, 3, 4 is %v, expected 24", det)
+	}
+}
+
+func TestMatrix4x4Singular(t *testing.T) {
+	singular := Matrix4x4Scale(Vector3f{1, 0, 1})
+	if _, err := singular.Inverse(); err != ErrSingularMatrix {
+		t.Errorf("Inverting a flattening scale returned %v, expected ErrSingularMatrix", err)
+	}
+	if _, err := (Matrix4x4{}).Inverse(); err != ErrSingularMatrix {
+		t.Errorf("Inverting zeros returned %v, expected ErrSingularMatrix", err)
+	}
+}
+
+func TestMatrix3x3InverseTransposeDeterminant(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	identity := Matrix3x3Identity()
+	for trial := 0; trial < 100; trial++ {
+		full := randomMatrix4x4(random)
+		mat := full.Upper3x3()
+		inverse, err := mat.Inverse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if product := mat.Mul(inverse); !product.ApproxEqual(&identity, 1e-3) {
+			t.Errorf("M * M^-1 isn't identity for %v: %v", mat, product)
+		}
+		det := mat.Determinant()
+		if detT := mat.Transpose().Determinant(); !ApproxEqual(detT, det, 1e-3*Max(1, Abs(det))) {
+			t.Errorf("det(M^T) = %v but det(M) = %v", detT, det)
+		}
+	}
+	if _, err := (Matrix3x3{}).Inverse(); err != ErrSingularMatrix {
+		t.Errorf("Inverting zeros returned %v, expected ErrSingularMatrix", err)
+	}
+}
+
+// benchmarkMatrix4x4 keeps the compiler from optimizing away benchmarked results
+var benchmarkMatrix4x4 Matrix4x4
+
+func BenchmarkMatrix4x4Mul(b *testing.B) {
+	random := rand.New(rand.NewSource(1))
+	left, right := randomMatrix4x4(random), randomMatrix4x4(random)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkMatrix4x4.MulInto(&left, &right)
+	}
+}
+
+func BenchmarkMatrix4x4Inverse(b *testing.B) {
+	mat := randomMatrix4x4(rand.New(rand.NewSource(1)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkMatrix4x4, _ = mat.Inverse()
+	}
+}
+
+func BenchmarkMatrix4x4TRS(b *testing.B) {
+	rotation := QuaternionFromAxisAngle(UpVector3f(), 15)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkMatrix4x4 = Matrix4x4TRS(Vector3f{X: float32(i)}, rotation, Vector3f{X: 1.5, Y: 1.5, Z: 1.5})
+	}
+}
@@ -0,0 +1,149 @@
+package math
+
+// OBB represents an oriented bounding box. Tighter than an AABB for rotated objects but more expensive to test
+type OBB struct {
+	Center  Vector3f    // The center of the box
+	Axes    [3]Vector3f // The box's local X, Y and Z axes in world space. Must be normalized and perpendicular
+	Extents Vector3f    // Half the size of the box along each of it's axes
+}
+
+// OBBFromAABB returns the oriented box that results from transforming box by mat
+func OBBFromAABB(box AABB, mat *Matrix4x4) OBB {
+	var obb OBB
+	obb.Center = mat.TransformPoint(box.Center())
+	extents := box.Extents()
+	columns := [3]Vector3f{{mat[0], mat[1], mat[2]}, {mat[4], mat[5], mat[6]}, {mat[8], mat[9], mat[10]}}
+	scales := [3]float32{extents.X, extents.Y, extents.Z}
+	for i, column := range columns {
+		length := column.Length()
+		obb.Axes[i] = column.Normalize()
+		scales[i] *= length
+	}
+	obb.Extents = Vector3f{scales[0], scales[1], scales[2]}
+	return obb
+}
+
+// Returns the extent along axis i
+func (box OBB) extent(i int) float32 {
+	switch i {
+	case 0:
+		return box.Extents.X
+	case 1:
+		return box.Extents.Y
+	default:
+		return box.Extents.Z
+	}
+}
+
+// ClosestPoint returns the point on or in the box closest to point
+func (box OBB) ClosestPoint(point Vector3f) Vector3f {
+	offset := point.Sub(box.Center)
+	closest := box.Center
+	for i, axis := range box.Axes {
+		extent := box.extent(i)
+		closest = closest.Add(axis.Scale(Clamp(offset.Dot(axis), -extent, extent)))
+	}
+	return closest
+}
+
+// ContainsPoint returns true if point is inside or on the surface of the box
+func (box OBB) ContainsPoint(point Vector3f) bool {
+	offset := point.Sub(box.Center)
+	for i, axis := range box.Axes {
+		if Abs(offset.Dot(axis)) > box.extent(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsSphere returns true if the box and sphere overlap
+func (box OBB) IntersectsSphere(sphere BoundingSphere) bool {
+	return box.ClosestPoint(sphere.Center).DistanceSquared(sphere.Center) <= sphere.Radius*sphere.Radius
+}
+
+// Intersects returns true if the two oriented boxes overlap
+func (box OBB) Intersects(other OBB) bool {
+	// Separating axis test over the 3 + 3 face normals and the 9 edge cross products
+	var rotation, absRotation [3][3]float32
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			rotation[i][j] = box.Axes[i].Dot(other.Axes[j])
+			// Epsilon stops parallel edges producing a near zero cross product that looks separating
+			absRotation[i][j] = Abs(rotation[i][j]) + Epsilon
+		}
+	}
+	offset := other.Center.Sub(box.Center)
+	t := [3]float32{offset.Dot(box.Axes[0]), offset.Dot(box.Axes[1]), offset.Dot(box.Axes[2])}
+	a := [3]float32{box.Extents.X, box.Extents.Y, box.Extents.Z}
+	b := [3]float32{other.Extents.X, other.Extents.Y, other.Extents.Z}
+
+	for i := 0; i < 3; i++ {
+		ra := a[i]
+		rb := b[0]*absRotation[i][0] + b[1]*absRotation[i][1] + b[2]*absRotation[i][2]
+		if Abs(t[i]) > ra+rb {
+			return false
+		}
+	}
+	for j := 0; j < 3; j++ {
+		ra := a[0]*absRotation[0][j] + a[1]*absRotation[1][j] + a[2]*absRotation[2][j]
+		rb := b[j]
+		if Abs(t[0]*rotation[0][j]+t[1]*rotation[1][j]+t[2]*rotation[2][j]) > ra+rb {
+			return false
+		}
+	}
+	for i := 0; i < 3; i++ {
+		i1, i2 := (i+1)%3, (i+2)%3
+		for j := 0; j < 3; j++ {
+			j1, j2 := (j+1)%3, (j+2)%3
+			ra := a[i1]*absRotation[i2][j] + a[i2]*absRotation[i1][j]
+			rb := b[j1]*absRotation[i][j2] + b[j2]*absRotation[i][j1]
+			if Abs(t[i2]*rotation[i1][j]-t[i1]*rotation[i2][j]) > ra+rb {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IntersectsAABB returns true if the oriented box and the axis aligned box overlap
+func (box OBB) IntersectsAABB(other AABB) bool {
+	return box.Intersects(OBB{other.Center(), [3]Vector3f{RightVector3f(), UpVector3f(), {0, 0, 1}}, other.Extents()})
+}
+
+// Transform returns the box after being transformed by mat
+func (box OBB) Transform(mat *Matrix4x4) OBB {
+	var transformed OBB
+	transformed.Center = mat.TransformPoint(box.Center)
+	scales := [3]float32{box.Extents.X, box.Extents.Y, box.Extents.Z}
+	for i, axis := range box.Axes {
+		newAxis := mat.TransformDirection(axis)
+		scales[i] *= newAxis.Length()
+		transformed.Axes[i] = newAxis.Normalize()
+	}
+	transformed.Extents = Vector3f{scales[0], scales[1], scales[2]}
+	return transformed
+}
+
+// Corners returns the 8 corners of the box
+func (box OBB) Corners() [8]Vector3f {
+	var corners [8]Vector3f
+	for i := range corners {
+		corner := box.Center
+		for axis := 0; axis < 3; axis++ {
+			sign := float32(-1)
+			if i&(1<<uint(axis)) != 0 {
+				sign = 1
+			}
+			corner = corner.Add(box.Axes[axis].Scale(sign * box.extent(axis)))
+		}
+		corners[i] = corner
+	}
+	return corners
+}
+
+// AABB returns the smallest axis aligned box containing this box
+func (box OBB) AABB() AABB {
+	corners := box.Corners()
+	return AABBFromPoints(corners[:]...)
+}
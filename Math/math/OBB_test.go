@@ -0,0 +1,60 @@
+package math
+
+import "testing"
+
+func TestOBB(t *testing.T) {
+	unit := AABB{Vector3f{-1, -1, -1}, Vector3f{1, 1, 1}}
+	rotation := Matrix4x4TRS(Vector3f{5, 0, 0}, QuaternionFromAxisAngle(UpVector3f(), 45), Vector3f{2, 1, 1})
+	box := OBBFromAABB(unit, &rotation)
+	if !box.Center.ApproxEqual(Vector3f{5, 0, 0}, 1e-6) || !box.Extents.ApproxEqual(Vector3f{2, 1, 1}, 1e-5) {
+		t.Fatalf("OBB from a TRS is %v", box)
+	}
+
+	// Every transformed corner is on the box and the AABB fits them
+	bounds := box.AABB()
+	for _, corner := range unit.Corners() {
+		point := rotation.TransformPoint(corner)
+		// Pulled slightly towards the center so rounding doesn't put it outside
+		inside := box.Center.Lerp(point, 0.999)
+		if !box.ContainsPoint(inside) {
+			t.Errorf("Corner %v isn't in the box", point)
+		}
+		if !bounds.ContainsPoint(inside) {
+			t.Errorf("Corner %v isn't in the box's AABB %v", point, bounds)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		got      bool
+		expected bool
+	}{
+		{"ContainsCenter", box.ContainsPoint(Vector3f{5, 0, 0}), true},
+		{"ContainsAlongAxis", box.ContainsPoint(Vector3f{5 + 1.9*0.7071, 0, -1.9 * 0.7071}), true},
+		{"ContainsAABBCorner", box.ContainsPoint(Vector3f{5 + 1.5, 0, 1.5}), false},
+		{"IntersectsSphere", box.IntersectsSphere(BoundingSphere{Vector3f{5, 1.5, 0}, 0.6}), true},
+		{"IntersectsSphereApart", box.IntersectsSphere(BoundingSphere{Vector3f{5, 3, 0}, 0.6}), false},
+		{"IntersectsAABB", box.IntersectsAABB(AABB{Vector3f{6, -1, -1}, Vector3f{7, 1, 0}}), true},
+		{"IntersectsAABBApart", box.IntersectsAABB(AABB{Vector3f{0, -1, -1}, Vector3f{1, 1, 1}}), false},
+		{"IntersectsSelf", box.Intersects(box), true},
+		{"IntersectsRotated", box.Intersects(OBBFromAABB(unit, &Matrix4x4{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 3.5, 0, 0, 1})), true},
+		{"IntersectsSeparatedByEdge", box.Intersects(OBBFromAABB(unit, &Matrix4x4{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1})), false},
+	}
+	for _, test := range tests {
+		if test.got != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+}
+
+func TestOBBTransform(t *testing.T) {
+	box := OBB{Vector3f{1, 0, 0}, [3]Vector3f{RightVector3f(), UpVector3f(), {0, 0, 1}}, Vector3f{1, 2, 3}}
+	mat := Matrix4x4TRS(Vector3f{0, 0, 5}, QuaternionFromAxisAngle(Vector3f{0, 0, 1}, 90), Vector3f{2, 2, 2})
+	transformed := box.Transform(&mat)
+	if !transformed.Center.ApproxEqual(Vector3f{0, 2, 5}, 1e-5) || !transformed.Extents.ApproxEqual(Vector3f{2, 4, 6}, 1e-5) {
+		t.Errorf("Transformed box is %v", transformed)
+	}
+	if !transformed.Axes[0].ApproxEqual(UpVector3f(), 1e-5) {
+		t.Errorf("X axis should point up after a 90 degree roll, got %v", transformed.Axes[0])
+	}
+}
@@ -0,0 +1,55 @@
+package math
+
+// Plane represents an infinite plane as the set of points p where Normal.Dot(p) + Distance = 0
+type Plane struct {
+	Normal   Vector3f // The direction the plane faces. Should be normalized
+	Distance float32  // The signed distance from the plane to the origin along the normal
+}
+
+// PlaneFromNormalAndPoint returns the plane facing normal that passes through point
+func PlaneFromNormalAndPoint(normal Vector3f, point Vector3f) Plane {
+	normal = normal.Normalize()
+	return Plane{normal, -normal.Dot(point)}
+}
+
+// PlaneFromPoints returns the plane through three points. It faces the side the points wind counter clockwise around
+func PlaneFromPoints(a Vector3f, b Vector3f, c Vector3f) Plane {
+	return PlaneFromNormalAndPoint(b.Sub(a).Cross(c.Sub(a)), a)
+}
+
+// Normalize returns the same plane with a unit length normal
+func (plane Plane) Normalize() Plane {
+	length := plane.Normal.Length()
+	if length == 0 {
+		return plane
+	}
+	return Plane{plane.Normal.Scale(1 / length), plane.Distance / length}
+}
+
+// SignedDistance returns the distance from the plane to point. Positive when in front of the plane
+func (plane Plane) SignedDistance(point Vector3f) float32 {
+	return plane.Normal.Dot(point) + plane.Distance
+}
+
+// ClosestPoint returns the point on the plane closest to point
+func (plane Plane) ClosestPoint(point Vector3f) Vector3f {
+	return point.Sub(plane.Normal.Scale(plane.SignedDistance(point)))
+}
+
+// Flip returns the plane facing the other way
+func (plane Plane) Flip() Plane {
+	return Plane{plane.Normal.Negate(), -plane.Distance}
+}
+
+// Transform returns the plane after being transformed by mat. Errors if mat is singular, since a plane squashed flat
+// has no single plane to become
+func (plane Plane) Transform(mat *Matrix4x4) (Plane, error) {
+	// Planes transform by the inverse transpose, same as normals
+	inverse, err := mat.Inverse()
+	if err != nil {
+		return Plane{}, err
+	}
+	inverseTranspose := inverse.Transpose()
+	transformed := inverseTranspose.TransformVector4f(plane.Normal.ToVector4f(plane.Distance))
+	return Plane{transformed.XYZ(), transformed.W}.Normalize(), nil
+}
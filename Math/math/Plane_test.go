@@ -0,0 +1,58 @@
+package math
+
+import "testing"
+
+func TestPlane(t *testing.T) {
+	plane := PlaneFromPoints(Vector3f{0, 2, 0}, Vector3f{1, 2, 0}, Vector3f{0, 2, -1})
+	if !plane.Normal.ApproxEqual(UpVector3f(), 1e-6) || !ApproxEqual(plane.Distance, -2, 1e-6) {
+		t.Fatalf("Plane through y = 2 wound counter clockwise from above is %v", plane)
+	}
+
+	tests := []struct {
+		name     string
+		got      float32
+		expected float32
+	}{
+		{"Above", plane.SignedDistance(Vector3f{3, 5, 1}), 3},
+		{"Below", plane.SignedDistance(Vector3f{3, -1, 1}), -3},
+		{"On", plane.SignedDistance(Vector3f{7, 2, -4}), 0},
+		{"Flipped", plane.Flip().SignedDistance(Vector3f{3, 5, 1}), -3},
+		{"Normalized", Plane{Vector3f{0, 2, 0}, -4}.Normalize().SignedDistance(Vector3f{0, 5, 0}), 3},
+	}
+	for _, test := range tests {
+		if !ApproxEqual(test.got, test.expected, 1e-5) {
+			t.Errorf("%v: got %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+
+	if closest := plane.ClosestPoint(Vector3f{3, 5, 1}); !closest.ApproxEqual(Vector3f{3, 2, 1}, 1e-6) {
+		t.Errorf("ClosestPoint got %v", closest)
+	}
+}
+
+func TestPlaneTransform(t *testing.T) {
+	plane := PlaneFromNormalAndPoint(Vector3f{1, 1, 0}, Vector3f{1, 0, 0})
+	mat := Matrix4x4TRS(Vector3f{0, 3, 0}, QuaternionFromAxisAngle(Vector3f{0, 0, 1}, 30), Vector3f{2, 1, 1})
+	transformed, err := plane.Transform(&mat)
+	if err != nil {
+		t.Fatalf("Transforming by an invertible matrix returned %v", err)
+	}
+
+	// Points on the plane must stay on it, points in front must stay in front
+	for _, point := range []Vector3f{{1, 0, 0}, {0, 1, 0}, {0.5, 0.5, 4}} {
+		if distance := transformed.SignedDistance(mat.TransformPoint(point)); !ApproxEqual(distance, 0, 1e-4) {
+			t.Errorf("%v was on the plane but is %v away after transforming", point, distance)
+		}
+	}
+	if transformed.SignedDistance(mat.TransformPoint(Vector3f{2, 2, 0})) <= 0 {
+		t.Error("A point in front of the plane ended up behind it")
+	}
+}
+
+func TestPlaneTransformSingular(t *testing.T) {
+	plane := PlaneFromNormalAndPoint(Vector3f{1, 1, 0}, Vector3f{1, 0, 0})
+	flatten := Matrix4x4Scale(Vector3f{1, 0, 1})
+	if _, err := plane.Transform(&flatten); err != ErrSingularMatrix {
+		t.Errorf("Transforming by a flattening scale returned %v, expected ErrSingularMatrix", err)
+	}
+}
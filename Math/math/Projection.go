@@ -0,0 +1,105 @@
+package math
+
+import gomath "math"
+
+// LookAt returns a view matrix for a camera at eye looking at target. up is used to orient the camera and
+// does not need to be perpendicular to the view direction. The camera looks down -Z in view space like OpenGL expects.
+// If target is eye the camera looks down -Z, and if up is parallel to the view direction a different up is chosen
+// like LookRotation does
+func LookAt(eye Vector3f, target Vector3f, up Vector3f) Matrix4x4 {
+	forward := target.Sub(eye).Normalize()
+	if forward.LengthSquared() == 0 {
+		forward = ForwardVector3f()
+	}
+	right := forward.Cross(up)
+	if right.LengthSquared() < Epsilon {
+		right = forward.Cross(RightVector3f())
+		if right.LengthSquared() < Epsilon {
+			right = forward.Cross(ForwardVector3f())
+		}
+	}
+	right = right.Normalize()
+	newUp := right.Cross(forward)
+
+	// Note matrices are column major so we are writing the transpose out
+	return Matrix4x4{
+		right.X, newUp.X, -forward.X, 0,
+		right.Y, newUp.Y, -forward.Y, 0,
+		right.Z, newUp.Z, -forward.Z, 0,
+		-right.Dot(eye), -newUp.Dot(eye), forward.Dot(eye), 1,
+	}
+}
+
+// PerspectiveOffCenter returns an off center perspective projection for the given clipping planes.
+// left, right, bottom and top are measured on the near plane
+func PerspectiveOffCenter(left float32, right float32, bottom float32, top float32, near float32, far float32) Matrix4x4 {
+	width, height, depth := right-left, top-bottom, far-near
+	return Matrix4x4{
+		2 * near / width, 0, 0, 0,
+		0, 2 * near / height, 0, 0,
+		(right + left) / width, (top + bottom) / height, -(far + near) / depth, -1,
+		0, 0, -2 * far * near / depth, 0,
+	}
+}
+
+// PerspectiveFovY returns a perspective projection from a vertical field of view in degrees
+func PerspectiveFovY(fovy float32, aspectRatio float32, near float32, far float32) Matrix4x4 {
+	top := near * float32(gomath.Tan(float64(fovy*Deg2Rad/2)))
+	right := top * aspectRatio
+	return PerspectiveOffCenter(-right, right, -top, top, near, far)
+}
+
+// PerspectiveFovX returns a perspective projection from a horizontal field of view in degrees
+func PerspectiveFovX(fovx float32, aspectRatio float32, near float32, far float32) Matrix4x4 {
+	right := near * float32(gomath.Tan(float64(fovx*Deg2Rad/2)))
+	top := right / aspectRatio
+	return PerspectiveOffCenter(-right, right, -top, top, near, far)
+}
+
+// InfinitePerspective returns a perspective projection with the far plane pushed out to infinity.
+// Handy for skies and shadow volumes that should never get clipped
+func InfinitePerspective(fovy float32, aspectRatio float32, near float32) Matrix4x4 {
+	f := 1 / float32(gomath.Tan(float64(fovy*Deg2Rad/2)))
+	return Matrix4x4{
+		f / aspectRatio, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, -1, -1,
+		0, 0, -2 * near, 0,
+	}
+}
+
+// ReversedZPerspective returns a perspective projection that maps the near plane to a depth of 1 and the far plane to 0.
+// This spreads float precision evenly across the depth range. It expects a [0, 1] clip space depth
+// (glClipControl / ARB_clip_control), a depth clear of 0 and a GREATER depth test
+func ReversedZPerspective(fovy float32, aspectRatio float32, near float32, far float32) Matrix4x4 {
+	f := 1 / float32(gomath.Tan(float64(fovy*Deg2Rad/2)))
+	depth := far - near
+	return Matrix4x4{
+		f / aspectRatio, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, near / depth, -1,
+		0, 0, far * near / depth, 0,
+	}
+}
+
+// ReversedZInfinitePerspective is ReversedZPerspective with the far plane at infinity. Same clip space requirements apply
+func ReversedZInfinitePerspective(fovy float32, aspectRatio float32, near float32) Matrix4x4 {
+	f := 1 / float32(gomath.Tan(float64(fovy*Deg2Rad/2)))
+	return Matrix4x4{
+		f / aspectRatio, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, 0, -1,
+		0, 0, near, 0,
+	}
+}
+
+// Orthographic returns an orthographic projection for the given clipping planes
+func Orthographic(left float32, right float32, bottom float32, top float32, near float32, far float32) Matrix4x4 {
+	width, height, depth := right-left, top-bottom, far-near
+	return Matrix4x4{
+		2 / width, 0, 0, 0,
+		0, 2 / height, 0, 0,
+		0, 0, -2 / depth, 0,
+		-(right + left) / width, -(top + bottom) / height, -(far + near) / depth, 1,
+	}
+}
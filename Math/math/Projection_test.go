@@ -0,0 +1,142 @@
+package math
+
+import (
+	gomath "math"
+	"testing"
+)
+
+// project returns the normalized device coordinates of point
+func project(projection *Matrix4x4, point Vector3f) Vector3f {
+	clip := projection.TransformVector4f(Vector4f{point.X, point.Y, point.Z, 1})
+	return Vector3f{clip.X / clip.W, clip.Y / clip.W, clip.Z / clip.W}
+}
+
+func TestLookAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		eye    Vector3f
+		target Vector3f
+		up     Vector3f
+	}{
+		{"down -Z", Vector3f{}, Vector3f{0, 0, -5}, UpVector3f()},
+		{"skewed", Vector3f{1, 2, 3}, Vector3f{4, 2, -1}, UpVector3f()},
+		{"tilted up", Vector3f{-3, 0, 2}, Vector3f{1, 5, -2}, Vector3f{0.2, 1, 0}},
+		{"straight up", Vector3f{1, 1, 1}, Vector3f{1, 6, 1}, UpVector3f()},
+		{"straight down", Vector3f{0, 4, 0}, Vector3f{}, UpVector3f()},
+		{"along x with up x", Vector3f{}, Vector3f{3, 0, 0}, RightVector3f()},
+		{"target at eye", Vector3f{2, 3, 4}, Vector3f{2, 3, 4}, UpVector3f()},
+		{"no up", Vector3f{}, Vector3f{1, 0, 0}, Vector3f{}},
+	}
+	for _, test := range tests {
+		view := LookAt(test.eye, test.target, test.up)
+
+		// A rotation and translation, so it's never singular
+		rotation := view.Upper3x3()
+		if det := rotation.Determinant(); !ApproxEqual(det, 1, 1e-5) {
+			t.Errorf("%v: got a view matrix with determinant %v, expected 1: %v", test.name, det, view)
+			continue
+		}
+		if eye := view.TransformPoint(test.eye); !eye.ApproxEqual(Vector3f{}, 1e-5) {
+			t.Errorf("%v: got the eye at %v in view space, expected the origin", test.name, eye)
+		}
+		distance := test.target.Sub(test.eye).Length()
+		if target := view.TransformPoint(test.target); !target.ApproxEqual(Vector3f{0, 0, -distance}, 1e-4) {
+			t.Errorf("%v: got the target at %v in view space, expected straight ahead at %v", test.name, target, -distance)
+		}
+
+		// The inverse of the camera's transform, which LookRotation points the same way
+		direction := test.target.Sub(test.eye)
+		if direction.LengthSquared() == 0 {
+			direction = ForwardVector3f()
+		}
+		camera := LookRotation(direction, test.up)
+		point := Vector3f{3, -1, 2}
+		if got, expected := view.TransformPoint(point), camera.Inverse().RotateVector(point.Sub(test.eye)); !got.ApproxEqual(expected, 1e-4) {
+			t.Errorf("%v: got %v in view space, expected %v like LookRotation", test.name, got, expected)
+		}
+	}
+}
+
+func TestPerspective(t *testing.T) {
+	const near, far = 0.5, 100
+	halfHeight := float32(gomath.Tan(float64(30 * Deg2Rad)))
+	fovY := PerspectiveFovY(60, 2, near, far)
+	fovX := PerspectiveFovX(60, 0.5, near, far)
+	offCenter := PerspectiveOffCenter(-1, 3, -2, 0.5, near, far)
+	infinite := InfinitePerspective(60, 2, near)
+
+	tests := []struct {
+		name       string
+		projection *Matrix4x4
+		point      Vector3f
+		expected   Vector3f
+	}{
+		{"fovy center", &fovY, Vector3f{0, 0, -10}, Vector3f{0, 0, project(&fovY, Vector3f{0, 0, -10}).Z}},
+		{"fovy top", &fovY, Vector3f{0, 10 * halfHeight, -10}, Vector3f{0, 1, project(&fovY, Vector3f{0, 0, -10}).Z}},
+		{"fovy right", &fovY, Vector3f{20 * halfHeight, 0, -10}, Vector3f{1, 0, project(&fovY, Vector3f{0, 0, -10}).Z}},
+		{"fovy near", &fovY, Vector3f{0, 0, -near}, Vector3f{0, 0, -1}},
+		{"fovy far", &fovY, Vector3f{0, 0, -far}, Vector3f{0, 0, 1}},
+		{"fovx right", &fovX, Vector3f{10 * halfHeight, 0, -10}, Vector3f{1, 0, project(&fovX, Vector3f{0, 0, -10}).Z}},
+		{"fovx top", &fovX, Vector3f{0, 20 * halfHeight, -10}, Vector3f{0, 1, project(&fovX, Vector3f{0, 0, -10}).Z}},
+		{"off center bottom left", &offCenter, Vector3f{-1, -2, -near}, Vector3f{-1, -1, -1}},
+		{"off center top right", &offCenter, Vector3f{3, 0.5, -near}, Vector3f{1, 1, -1}},
+		{"off center far", &offCenter, Vector3f{6 * far, far, -far}, Vector3f{1, 1, 1}},
+		{"infinite near", &infinite, Vector3f{0, near * halfHeight, -near}, Vector3f{0, 1, -1}},
+		{"infinite far away", &infinite, Vector3f{0, 0, -1e6}, Vector3f{0, 0, 1}},
+	}
+	for _, test := range tests {
+		if got := project(test.projection, test.point); !got.ApproxEqual(test.expected, 1e-4) {
+			t.Errorf("%v: got %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestReversedZPerspective(t *testing.T) {
+	const near, far = 0.1, 50
+	reversed := ReversedZPerspective(45, 1.5, near, far)
+	infinite := ReversedZInfinitePerspective(45, 1.5, near)
+	regular := PerspectiveFovY(45, 1.5, near, far)
+
+	tests := []struct {
+		name       string
+		projection *Matrix4x4
+		point      Vector3f
+		depth      float32
+	}{
+		{"near", &reversed, Vector3f{0, 0, -near}, 1},
+		{"far", &reversed, Vector3f{0, 0, -far}, 0},
+		{"infinite near", &infinite, Vector3f{0, 0, -near}, 1},
+		{"infinite far away", &infinite, Vector3f{0, 0, -1e6}, 0},
+	}
+	for _, test := range tests {
+		if got := project(test.projection, test.point); !ApproxEqual(got.Z, test.depth, 1e-4) {
+			t.Errorf("%v: got depth %v, expected %v", test.name, got.Z, test.depth)
+		}
+	}
+
+	// Only depth is reversed, x and y match a regular projection
+	point := Vector3f{1, -2, -7}
+	got, expected := project(&reversed, point), project(&regular, point)
+	if !ApproxEqual(got.X, expected.X, 1e-5) || !ApproxEqual(got.Y, expected.Y, 1e-5) {
+		t.Errorf("got %v, expected x and y of %v", got, expected)
+	}
+}
+
+func TestOrthographic(t *testing.T) {
+	ortho := Orthographic(-4, 2, -1, 3, 1, 11)
+	tests := []struct {
+		point    Vector3f
+		expected Vector3f
+	}{
+		{Vector3f{-4, -1, -1}, Vector3f{-1, -1, -1}},
+		{Vector3f{2, 3, -11}, Vector3f{1, 1, 1}},
+		{Vector3f{-1, 1, -6}, Vector3f{0, 0, 0}},
+		// No perspective, so distance doesn't move things towards the center
+		{Vector3f{2, 3, -1}, Vector3f{1, 1, -1}},
+	}
+	for _, test := range tests {
+		if got := project(&ortho, test.point); !got.ApproxEqual(test.expected, 1e-5) {
+			t.Errorf("%v: got %v, expected %v", test.point, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package math
+
+import gomath "math"
+
+// EulerOrder is an enum for the order euler rotations are applied in
+type EulerOrder int
+
+// Enum values for EulerOrder. EulerXYZ rotates about X first, then Y, then Z (all about the parent's fixed axes)
+const (
+	EulerXYZ EulerOrder = iota
+	EulerXZY
+	EulerYXZ
+	EulerYZX
+	EulerZXY
+	EulerZYX
+)
+
+// DefaultEulerOrder is the euler order used by the engine when none is specified
+const DefaultEulerOrder = EulerXYZ
+
+// Returns the axis indices for the order in which they are applied and whether the order is an even permutation of XYZ
+func (order EulerOrder) axes() (first int, second int, third int, even bool) {
+	switch order {
+	case EulerXZY:
+		return 0, 2, 1, false
+	case EulerYXZ:
+		return 1, 0, 2, false
+	case EulerYZX:
+		return 1, 2, 0, true
+	case EulerZXY:
+		return 2, 0, 1, true
+	case EulerZYX:
+		return 2, 1, 0, false
+	default:
+		return 0, 1, 2, true
+	}
+}
+
+// Quaternion represents a rotation in 3d space. Doesn't gimbal lock and interpolates nicely, unlike euler angles
+type Quaternion struct {
+	X float32 // The X component of the imaginary part
+	Y float32 // The Y component of the imaginary part
+	Z float32 // The Z component of the imaginary part
+	W float32 // The real part
+}
+
+// IdentityQuaternion returns the quaternion representing no rotation
+func IdentityQuaternion() Quaternion {
+	return Quaternion{0, 0, 0, 1}
+}
+
+// QuaternionFromAxisAngle returns a rotation of degrees counter clockwise around axis. axis does not need to be normalized
+func QuaternionFromAxisAngle(axis Vector3f, degrees float32) Quaternion {
+	axis = axis.Normalize()
+	halfAngle := float64(degrees*Deg2Rad) / 2
+	s := float32(gomath.Sin(halfAngle))
+	return Quaternion{axis.X * s, axis.Y * s, axis.Z * s, float32(gomath.Cos(halfAngle))}
+}
+
+// QuaternionFromEuler returns the rotation described by euler angles in degrees applied in the given order
+func QuaternionFromEuler(euler Vector3f, order EulerOrder) Quaternion {
+	angles := [3]float32{euler.X, euler.Y, euler.Z}
+	axes := [3]Vector3f{RightVector3f(), UpVector3f(), {0, 0, 1}}
+	first, second, third, _ := order.axes()
+
+	q1 := QuaternionFromAxisAngle(axes[first], angles[first])
+	q2 := QuaternionFromAxisAngle(axes[second], angles[second])
+	q3 := QuaternionFromAxisAngle(axes[third], angles[third])
+	return q3.Mul(q2).Mul(q1)
+}
+
+// QuaternionFromRotationMatrix returns the rotation described by the rotation part of an affine transform matrix.
+// The matrix must not contain scale
+func QuaternionFromRotationMatrix(mat Matrix) Quaternion {
+	m00, m01, m02 := mat.Get(0, 0), mat.Get(0, 1), mat.Get(0, 2)
+	m10, m11, m12 := mat.Get(1, 0), mat.Get(1, 1), mat.Get(1, 2)
+	m20, m21, m22 := mat.Get(2, 0), mat.Get(2, 1), mat.Get(2, 2)
+	return quaternionFromBasis(m00, m01, m02, m10, m11, m12, m20, m21, m22)
+}
+
+// Builds a quaternion from the elements of a 3x3 rotation matrix given in row major order
+func quaternionFromBasis(m00, m01, m02, m10, m11, m12, m20, m21, m22 float32) Quaternion {
+	var q Quaternion
+	trace := m00 + m11 + m22
+	if trace > 0 {
+		s := Sqrt(trace+1) * 2
+		q.W = s / 4
+		q.X = (m21 - m12) / s
+		q.Y = (m02 - m20) / s
+		q.Z = (m10 - m01) / s
+	} else if m00 > m11 && m00 > m22 {
+		s := Sqrt(1+m00-m11-m22) * 2
+		q.W = (m21 - m12) / s
+		q.X = s / 4
+		q.Y = (m01 + m10) / s
+		q.Z = (m02 + m20) / s
+	} else if m11 > m22 {
+		s := Sqrt(1+m11-m00-m22) * 2
+		q.W = (m02 - m20) / s
+		q.X = (m01 + m10) / s
+		q.Y = s / 4
+		q.Z = (m12 + m21) / s
+	} else {
+		s := Sqrt(1+m22-m00-m11) * 2
+		q.W = (m10 - m01) / s
+		q.X = (m02 + m20) / s
+		q.Y = (m12 + m21) / s
+		q.Z = s / 4
+	}
+	return q.Normalize()
+}
+
+// LookRotation returns the rotation that points the local forward axis (-Z) along forward with local +Y as close to up as possible.
+// If forward and up are parallel a different up is chosen
+func LookRotation(forward Vector3f, up Vector3f) Quaternion {
+	forward = forward.Normalize()
+	if forward.LengthSquared() == 0 {
+		return IdentityQuaternion()
+	}
+
+	right := forward.Cross(up)
+	if right.LengthSquared() < Epsilon {
+		// Forward and up are parallel so any perpendicular will do
+		right = forward.Cross(RightVector3f())
+		if right.LengthSquared() < Epsilon {
+			right = forward.Cross(ForwardVector3f())
+		}
+	}
+	right = right.Normalize()
+	newUp := right.Cross(forward)
+	back := forward.Negate()
+
+	// Columns of the rotation matrix are the rotated basis vectors
+	return quaternionFromBasis(
+		right.X, newUp.X, back.X,
+		right.Y, newUp.Y, back.Y,
+		right.Z, newUp.Z, back.Z,
+	)
+}
+
+// Mul returns q * other. The resulting rotation applies other first, then q
+func (q Quaternion) Mul(other Quaternion) Quaternion {
+	return Quaternion{
+		q.W*other.X + q.X*other.W + q.Y*other.Z - q.Z*other.Y,
+		q.W*other.Y - q.X*other.Z + q.Y*other.W + q.Z*other.X,
+		q.W*other.Z + q.X*other.Y - q.Y*other.X + q.Z*other.W,
+		q.W*other.W - q.X*other.X - q.Y*other.Y - q.Z*other.Z,
+	}
+}
+
+// Dot returns the 4d dot product of two quaternions
+func (q Quaternion) Dot(other Quaternion) float32 {
+	return q.X*other.X + q.Y*other.Y + q.Z*other.Z + q.W*other.W
+}
+
+// LengthSquared returns the squared length of the quaternion
+func (q Quaternion) LengthSquared() float32 {
+	return q.Dot(q)
+}
+
+// Length returns the length of the quaternion. Rotations always have length 1
+func (q Quaternion) Length() float32 {
+	return Sqrt(q.LengthSquared())
+}
+
+// Normalize returns q scaled to unit length. A zero quaternion returns the identity
+func (q Quaternion) Normalize() Quaternion {
+	length := q.Length()
+	if length == 0 {
+		return IdentityQuaternion()
+	}
+	inv := 1 / length
+	return Quaternion{q.X * inv, q.Y * inv, q.Z * inv, q.W * inv}
+}
+
+// Conjugate returns the conjugate of q. For unit quaternions this is the same as the inverse
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{-q.X, -q.Y, -q.Z, q.W}
+}
+
+// Inverse returns the rotation that undoes q. A zero quaternion returns the identity
+func (q Quaternion) Inverse() Quaternion {
+	lengthSquared := q.LengthSquared()
+	if lengthSquared == 0 {
+		return IdentityQuaternion()
+	}
+	inv := 1 / lengthSquared
+	return Quaternion{-q.X * inv, -q.Y * inv, -q.Z * inv, q.W * inv}
+}
+
+// RotateVector returns vec rotated by q. q must be normalized
+func (q Quaternion) RotateVector(vec Vector3f) Vector3f {
+	u := Vector3f{q.X, q.Y, q.Z}
+	t := u.Cross(vec).Scale(2)
+	return vec.Add(t.Scale(q.W)).Add(u.Cross(t))
+}
+
+// AxisAngle returns the normalized axis and the angle in degrees that this rotation represents
+func (q Quaternion) AxisAngle() (axis Vector3f, degrees float32) {
+	q = q.Normalize()
+	if q.W < 0 {
+		q = Quaternion{-q.X, -q.Y, -q.Z, -q.W}
+	}
+	s := Sqrt(1 - q.W*q.W)
+	if s < Epsilon {
+		return RightVector3f(), 0
+	}
+	axis = Vector3f{q.X / s, q.Y / s, q.Z / s}
+	degrees = 2 * float32(gomath.Acos(float64(Clamp(q.W, -1, 1)))) * Rad2Deg
+	return
+}
+
+// ToEuler returns the euler angles in degrees that reproduce this rotation when applied in the given order
+func (q Quaternion) ToEuler(order EulerOrder) Vector3f {
+	r := q.Normalize().rotationRows()
+	i, j, k, even := order.axes()
+	var sign float32 = 1
+	if !even {
+		sign = -1
+	}
+
+	var angles [3]float64
+	sj := Clamp(-sign*r[k][i], -1, 1)
+	angles[j] = gomath.Asin(float64(sj))
+	if Abs(sj) < 1-Epsilon {
+		angles[i] = gomath.Atan2(float64(sign*r[k][j]), float64(r[k][k]))
+		angles[k] = gomath.Atan2(float64(sign*r[j][i]), float64(r[i][i]))
+	} else {
+		// Gimbal lock, the first and last axes line up so put everything on the last one
+		angles[i] = 0
+		angles[k] = gomath.Atan2(float64(-sign*r[i][j]), float64(r[j][j]))
+	}
+
+	return Vector3f{
+		float32(angles[0]) * Rad2Deg,
+		float32(angles[1]) * Rad2Deg,
+		float32(angles[2]) * Rad2Deg,
+	}
+}
+
+// Returns the 3x3 rotation matrix for this quaternion in row major order
+func (q Quaternion) rotationRows() [3][3]float32 {
+	xx, yy, zz := q.X*q.X, q.Y*q.Y, q.Z*q.Z
+	xy, xz, yz := q.X*q.Y, q.X*q.Z, q.Y*q.Z
+	wx, wy, wz := q.W*q.X, q.W*q.Y, q.W*q.Z
+	return [3][3]float32{
+		{1 - 2*(yy+zz), 2 * (xy - wz), 2 * (xz + wy)},
+		{2 * (xy + wz), 1 - 2*(xx+zz), 2 * (yz - wx)},
+		{2 * (xz - wy), 2 * (yz + wx), 1 - 2*(xx+yy)},
+	}
+}
+
+// ToMatrix returns the 4x4 rotation matrix for this quaternion. q must be normalized
+func (q Quaternion) ToMatrix() Matrix4x4 {
+	r := q.rotationRows()
+	return Matrix4x4{
+		r[0][0], r[1][0], r[2][0], 0,
+		r[0][1], r[1][1], r[2][1], 0,
+		r[0][2], r[1][2], r[2][2], 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Nlerp normalized-linearly interpolates from q to other along the shortest path. Cheaper than Slerp but not constant speed
+func (q Quaternion) Nlerp(other Quaternion, t float32) Quaternion {
+	if q.Dot(other) < 0 {
+		other = Quaternion{-other.X, -other.Y, -other.Z, -other.W}
+	}
+	return Quaternion{
+		Lerp(q.X, other.X, t),
+		Lerp(q.Y, other.Y, t),
+		Lerp(q.Z, other.Z, t),
+		Lerp(q.W, other.W, t),
+	}.Normalize()
+}
+
+// Slerp spherically interpolates from q to other along the shortest path at constant angular speed
+func (q Quaternion) Slerp(other Quaternion, t float32) Quaternion {
+	cos := q.Dot(other)
+	if cos < 0 {
+		other = Quaternion{-other.X, -other.Y, -other.Z, -other.W}
+		cos = -cos
+	}
+
+	// Nearly identical rotations make sin(theta) blow up, so fall back to nlerp
+	if cos > 1-Epsilon {
+		return q.Nlerp(other, t)
+	}
+
+	theta := gomath.Acos(float64(cos))
+	sinTheta := gomath.Sin(theta)
+	a := float32(gomath.Sin((1-float64(t))*theta) / sinTheta)
+	b := float32(gomath.Sin(float64(t)*theta) / sinTheta)
+	return Quaternion{
+		q.X*a + other.X*b,
+		q.Y*a + other.Y*b,
+		q.Z*a + other.Z*b,
+		q.W*a + other.W*b,
+	}
+}
+
+// ApproxEqual returns true if q and other represent the same rotation within tolerance
+func (q Quaternion) ApproxEqual(other Quaternion, tolerance float32) bool {
+	return Abs(q.Dot(other)) >= 1-tolerance
+}
@@ -0,0 +1,165 @@
+package math
+
+import "testing"
+
+// eulerOrders is every EulerOrder with the axis each of it's angles is applied around, in order
+var eulerOrders = []struct {
+	order EulerOrder
+	axes  [3]Vector3f
+}{
+	{EulerXYZ, [3]Vector3f{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}},
+	{EulerXZY, [3]Vector3f{{1, 0, 0}, {0, 0, 1}, {0, 1, 0}}},
+	{EulerYXZ, [3]Vector3f{{0, 1, 0}, {1, 0, 0}, {0, 0, 1}}},
+	{EulerYZX, [3]Vector3f{{0, 1, 0}, {0, 0, 1}, {1, 0, 0}}},
+	{EulerZXY, [3]Vector3f{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}}},
+	{EulerZYX, [3]Vector3f{{0, 0, 1}, {0, 1, 0}, {1, 0, 0}}},
+}
+
+// eulerAngle returns the angle of euler around axis
+func eulerAngle(euler Vector3f, axis Vector3f) float32 {
+	return euler.Dot(axis)
+}
+
+func TestQuaternionFromEulerOrder(t *testing.T) {
+	euler := Vector3f{30, -50, 70}
+	vector := Vector3f{1, 2, 3}
+	for _, test := range eulerOrders {
+		// Each angle is applied about the fixed axes in turn
+		expected := vector
+		for _, axis := range test.axes {
+			expected = QuaternionFromAxisAngle(axis, eulerAngle(euler, axis)).RotateVector(expected)
+		}
+		if got := QuaternionFromEuler(euler, test.order).RotateVector(vector); !got.ApproxEqual(expected, 1e-4) {
+			t.Errorf("order %v: got %v, expected %v", test.order, got, expected)
+		}
+	}
+}
+
+func TestQuaternionEulerRoundTrip(t *testing.T) {
+	// Within (-90, 90) every angle is the one ToEuler picks
+	canonical := []Vector3f{{0, 0, 0}, {10, 20, 30}, {-45, 60, -70}, {80, -85, 5}, {-89, 1, 89}}
+	// Outside of it, or gimbal locked, only the rotation is the same
+	other := []Vector3f{{170, 10, -150}, {0, 90, 0}, {45, 90, 30}, {20, -90, 60}, {90, 90, 90}, {-180, 180, 360}}
+
+	for _, test := range eulerOrders {
+		for _, euler := range append(canonical, other...) {
+			q := QuaternionFromEuler(euler, test.order)
+			got := q.ToEuler(test.order)
+			if back := QuaternionFromEuler(got, test.order); !back.ApproxEqual(q, 1e-5) {
+				t.Errorf("order %v, %v: got %v, which is a different rotation", test.order, euler, got)
+			}
+		}
+		for _, euler := range canonical {
+			// Gimbal lock on the second axis only happens at 90, so the middle angle being under it is enough
+			if got := QuaternionFromEuler(euler, test.order).ToEuler(test.order); !got.ApproxEqual(euler, 1e-2) {
+				t.Errorf("order %v: got %v back from %v", test.order, got, euler)
+			}
+		}
+	}
+}
+
+func TestQuaternionSlerp(t *testing.T) {
+	identity := IdentityQuaternion()
+	quarter := QuaternionFromAxisAngle(UpVector3f(), 90)
+	tests := []struct {
+		name     string
+		from     Quaternion
+		to       Quaternion
+		t        float32
+		expected Quaternion
+	}{
+		{"start", identity, quarter, 0, identity},
+		{"end", identity, quarter, 1, quarter},
+		{"halfway", identity, quarter, 0.5, QuaternionFromAxisAngle(UpVector3f(), 45)},
+		{"quarter of the way", identity, quarter, 0.25, QuaternionFromAxisAngle(UpVector3f(), 22.5)},
+		// The negated quaternion is the same rotation, so the path mustn't go the long way around
+		{"negated end", identity, Quaternion{-quarter.X, -quarter.Y, -quarter.Z, -quarter.W}, 0.5, QuaternionFromAxisAngle(UpVector3f(), 45)},
+		// 270 degrees one way is 90 the other
+		{"shortest path", identity, QuaternionFromAxisAngle(UpVector3f(), 270), 0.5, QuaternionFromAxisAngle(UpVector3f(), -45)},
+		{"nearly identical", quarter, QuaternionFromAxisAngle(UpVector3f(), 90.001), 0.5, QuaternionFromAxisAngle(UpVector3f(), 90.0005)},
+	}
+	for _, test := range tests {
+		got := test.from.Slerp(test.to, test.t)
+		if !got.ApproxEqual(test.expected, 1e-6) || !ApproxEqual(got.Length(), 1, 1e-5) {
+			t.Errorf("%v: got %v, expected %v", test.name, got, test.expected)
+		}
+	}
+
+	// Constant angular speed, unlike nlerp
+	far := QuaternionFromAxisAngle(RightVector3f(), 160)
+	for _, step := range []float32{0.1, 0.3, 0.7} {
+		_, degrees := identity.Slerp(far, step).AxisAngle()
+		if !ApproxEqual(degrees, 160*step, 1e-2) {
+			t.Errorf("got %v degrees at %v of the way to 160, expected %v", degrees, step, 160*step)
+		}
+	}
+}
+
+func TestLookRotation(t *testing.T) {
+	tests := []struct {
+		name    string
+		forward Vector3f
+		up      Vector3f
+	}{
+		{"identity", ForwardVector3f(), UpVector3f()},
+		{"right", RightVector3f(), UpVector3f()},
+		{"behind", Vector3f{0, 0, 1}, UpVector3f()},
+		{"skewed", Vector3f{1, 2, -3}, UpVector3f()},
+		{"unnormalized up", Vector3f{-4, 0.5, 2}, Vector3f{0, 10, 1}},
+		{"straight up", UpVector3f(), UpVector3f()},
+		{"straight down", Vector3f{0, -2, 0}, UpVector3f()},
+		{"along x with up x", RightVector3f(), RightVector3f()},
+	}
+	for _, test := range tests {
+		q := LookRotation(test.forward, test.up)
+		forward := q.RotateVector(ForwardVector3f())
+		up := q.RotateVector(UpVector3f())
+		if !ApproxEqual(q.Length(), 1, 1e-5) || !forward.ApproxEqual(test.forward.Normalize(), 1e-5) {
+			t.Errorf("%v: got forward %v, expected %v", test.name, forward, test.forward.Normalize())
+			continue
+		}
+		// Local up stays perpendicular, and as close to up as it can be
+		if !ApproxEqual(up.Dot(forward), 0, 1e-5) {
+			t.Errorf("%v: got up %v, which isn't perpendicular to forward %v", test.name, up, forward)
+		}
+		if parallel := Abs(test.forward.Normalize().Dot(test.up.Normalize())) > 1-Epsilon; !parallel {
+			expected := test.up.Sub(forward.Scale(test.up.Dot(forward))).Normalize()
+			if !up.ApproxEqual(expected, 1e-5) {
+				t.Errorf("%v: got up %v, expected %v", test.name, up, expected)
+			}
+		}
+	}
+
+	if q := LookRotation(Vector3f{}, UpVector3f()); q != IdentityQuaternion() {
+		t.Errorf("zero forward: got %v, expected the identity", q)
+	}
+}
+
+func TestQuaternionFromRotationMatrix(t *testing.T) {
+	tests := []struct {
+		name     string
+		rotation Quaternion
+	}{
+		// A positive trace
+		{"identity", IdentityQuaternion()},
+		{"small rotation", QuaternionFromAxisAngle(Vector3f{1, 1, 1}, 30)},
+		// Half turns have a trace of -1, picking the branch of their largest diagonal
+		{"half turn about x", QuaternionFromAxisAngle(RightVector3f(), 180)},
+		{"half turn about y", QuaternionFromAxisAngle(UpVector3f(), 180)},
+		{"half turn about z", QuaternionFromAxisAngle(Vector3f{0, 0, 1}, 180)},
+		{"nearly half a turn mostly about x", QuaternionFromAxisAngle(Vector3f{1, 0.2, -0.1}, 170)},
+		{"nearly half a turn mostly about y", QuaternionFromAxisAngle(Vector3f{-0.1, 1, 0.3}, 175)},
+		{"nearly half a turn mostly about z", QuaternionFromAxisAngle(Vector3f{0.2, 0.1, -1}, 160)},
+	}
+	for _, test := range tests {
+		matrix := test.rotation.ToMatrix()
+		got := QuaternionFromRotationMatrix(&matrix)
+		if !got.ApproxEqual(test.rotation, 1e-6) {
+			t.Errorf("%v: got %v, expected %v", test.name, got, test.rotation)
+		}
+		vector := Vector3f{1, -2, 3}
+		if rotated, expected := matrix.TransformDirection(vector), got.RotateVector(vector); !rotated.ApproxEqual(expected, 1e-4) {
+			t.Errorf("%v: the matrix rotates %v to %v but the quaternion to %v", test.name, vector, rotated, expected)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package math
+
+// Ray represents a half line starting at Origin heading in Direction. Used for picking and line of sight
+type Ray struct {
+	Origin    Vector3f // Where the ray starts
+	Direction Vector3f // The direction the ray travels. Should be normalized so distances come out in world units
+}
+
+// PointAt returns the point distance along the ray
+func (ray Ray) PointAt(distance float32) Vector3f {
+	return ray.Origin.Add(ray.Direction.Scale(distance))
+}
+
+// IntersectsPlane returns the distance along the ray where it crosses the plane
+func (ray Ray) IntersectsPlane(plane Plane) (distance float32, hit bool) {
+	denominator := plane.Normal.Dot(ray.Direction)
+	if Abs(denominator) < Epsilon {
+		return 0, false
+	}
+	distance = -plane.SignedDistance(ray.Origin) / denominator
+	return distance, distance >= 0
+}
+
+// IntersectsAABB returns the distance along the ray to where it enters the box. Rays starting inside hit at 0
+func (ray Ray) IntersectsAABB(box AABB) (distance float32, hit bool) {
+	// Slab method: clip the ray against the pair of planes on each axis
+	origin := [3]float32{ray.Origin.X, ray.Origin.Y, ray.Origin.Z}
+	direction := [3]float32{ray.Direction.X, ray.Direction.Y, ray.Direction.Z}
+	boxMin := [3]float32{box.Min.X, box.Min.Y, box.Min.Z}
+	boxMax := [3]float32{box.Max.X, box.Max.Y, box.Max.Z}
+
+	var tMin float32
+	tMax := float32(3.4e38)
+	for axis := 0; axis < 3; axis++ {
+		if Abs(direction[axis]) < Epsilon {
+			// Parallel to the slab so we have to already be inside it
+			if origin[axis] < boxMin[axis] || origin[axis] > boxMax[axis] {
+				return 0, false
+			}
+			continue
+		}
+		invDir := 1 / direction[axis]
+		t1 := (boxMin[axis] - origin[axis]) * invDir
+		t2 := (boxMax[axis] - origin[axis]) * invDir
+		tMin = Max(tMin, Min(t1, t2))
+		tMax = Min(tMax, Max(t1, t2))
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
+
+// IntersectsOBB returns the distance along the ray to where it enters the oriented box
+func (ray Ray) IntersectsOBB(box OBB) (distance float32, hit bool) {
+	// Move the ray into the box's space where it's just an AABB
+	offset := ray.Origin.Sub(box.Center)
+	localRay := Ray{
+		Vector3f{offset.Dot(box.Axes[0]), offset.Dot(box.Axes[1]), offset.Dot(box.Axes[2])},
+		Vector3f{ray.Direction.Dot(box.Axes[0]), ray.Direction.Dot(box.Axes[1]), ray.Direction.Dot(box.Axes[2])},
+	}
+	return localRay.IntersectsAABB(AABB{box.Extents.Negate(), box.Extents})
+}
+
+// IntersectsSphere returns the distance along the ray to where it enters the sphere. Rays starting inside hit at 0
+func (ray Ray) IntersectsSphere(sphere BoundingSphere) (distance float32, hit bool) {
+	offset := ray.Origin.Sub(sphere.Center)
+	a := ray.Direction.LengthSquared()
+	b := offset.Dot(ray.Direction)
+	c := offset.LengthSquared() - sphere.Radius*sphere.Radius
+
+	// Starting inside the sphere
+	if c <= 0 {
+		return 0, true
+	}
+	// Pointing away from the sphere
+	if b > 0 || a == 0 {
+		return 0, false
+	}
+	discriminant := b*b - a*c
+	if discriminant < 0 {
+		return 0, false
+	}
+	return (-b - Sqrt(discriminant)) / a, true
+}
+
+// IntersectsTriangle returns the distance along the ray to where it hits the triangle a, b, c, along with the
+// barycentric coordinates u and v of the hit (weights for b and c). Both sides of the triangle count
+func (ray Ray) IntersectsTriangle(a Vector3f, b Vector3f, c Vector3f) (distance float32, u float32, v float32, hit bool) {
+	// Möller–Trumbore
+	edge1 := b.Sub(a)
+	edge2 := c.Sub(a)
+	p := ray.Direction.Cross(edge2)
+	determinant := edge1.Dot(p)
+	// Parallel to the triangle. The tolerance is scaled by the edges and direction so small triangles still get hit
+	if Abs(determinant) <= Epsilon*edge1.Length()*edge2.Length()*ray.Direction.Length() {
+		return 0, 0, 0, false
+	}
+	invDet := 1 / determinant
+
+	offset := ray.Origin.Sub(a)
+	u = offset.Dot(p) * invDet
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+	q := offset.Cross(edge1)
+	v = ray.Direction.Dot(q) * invDet
+	if v < 0 || u+v > 1 {
+		return 0, 0, 0, false
+	}
+	distance = edge2.Dot(q) * invDet
+	if distance < 0 {
+		return 0, 0, 0, false
+	}
+	return distance, u, v, true
+}
+
+// Transform returns the ray after being transformed by mat. The direction is renormalized
+func (ray Ray) Transform(mat *Matrix4x4) Ray {
+	return Ray{mat.TransformPoint(ray.Origin), mat.TransformDirection(ray.Direction).Normalize()}
+}
@@ -0,0 +1,83 @@
+package math
+
+import "testing"
+
+func TestRayIntersectsTriangle(t *testing.T) {
+	a, b, c := Vector3f{0, 0, 0}, Vector3f{1, 0, 0}, Vector3f{0, 1, 0}
+	tests := []struct {
+		name     string
+		ray      Ray
+		scale    float32 // Applied to the triangle
+		hit      bool
+		distance float32
+	}{
+		{"Front", Ray{Vector3f{0.25, 0.25, 5}, Vector3f{0, 0, -1}}, 1, true, 5},
+		{"Back", Ray{Vector3f{0.25, 0.25, -2}, Vector3f{0, 0, 1}}, 1, true, 2},
+		{"Miss", Ray{Vector3f{1, 1, 5}, Vector3f{0, 0, -1}}, 1, false, 0},
+		{"Behind", Ray{Vector3f{0.25, 0.25, 5}, Vector3f{0, 0, 1}}, 1, false, 0},
+		{"Parallel", Ray{Vector3f{-1, 0.25, 0}, Vector3f{1, 0, 0}}, 1, false, 0},
+		{"Edge", Ray{Vector3f{0.5, 0.5, 1}, Vector3f{0, 0, -1}}, 1, true, 1},
+		{"SmallTriangle", Ray{Vector3f{0.00025, 0.00025, 1}, Vector3f{0, 0, -1}}, 1e-3, true, 1},
+		{"TinyTriangle", Ray{Vector3f{0.00000025, 0.00000025, 1}, Vector3f{0, 0, -1}}, 1e-6, true, 1},
+		{"Degenerate", Ray{Vector3f{0, 0, 1}, Vector3f{0, 0, -1}}, 0, false, 0},
+	}
+	for _, test := range tests {
+		distance, u, v, hit := test.ray.IntersectsTriangle(a.Scale(test.scale), b.Scale(test.scale), c.Scale(test.scale))
+		if hit != test.hit || (hit && !ApproxEqual(distance, test.distance, 1e-4)) {
+			t.Errorf("%v: got hit %v at %v, expected hit %v at %v", test.name, hit, distance, test.hit, test.distance)
+		}
+		if hit {
+			point := a.Scale(1 - u - v).Add(b.Scale(u)).Add(c.Scale(v)).Scale(test.scale)
+			if !point.ApproxEqual(test.ray.PointAt(distance), 1e-4) {
+				t.Errorf("%v: barycentric point %v doesn't match hit point %v", test.name, point, test.ray.PointAt(distance))
+			}
+		}
+	}
+}
+
+func TestRayIntersectsShapes(t *testing.T) {
+	ray := Ray{Vector3f{0, 0, 10}, Vector3f{0, 0, -1}}
+	box := AABB{Vector3f{-1, -1, -1}, Vector3f{1, 1, 1}}
+	rotation := Matrix4x4Rotation(QuaternionFromAxisAngle(UpVector3f(), 45))
+	rotated := OBBFromAABB(box, &rotation)
+	tests := []struct {
+		name      string
+		intersect func() (float32, bool)
+		hit       bool
+		distance  float32
+	}{
+		{"AABB", func() (float32, bool) { return ray.IntersectsAABB(box) }, true, 9},
+		{"AABBInside", func() (float32, bool) { return Ray{Vector3f{}, Vector3f{0, 0, -1}}.IntersectsAABB(box) }, true, 0},
+		{"AABBParallelMiss", func() (float32, bool) { return Ray{Vector3f{2, 0, 10}, Vector3f{0, 0, -1}}.IntersectsAABB(box) }, false, 0},
+		{"Sphere", func() (float32, bool) { return ray.IntersectsSphere(BoundingSphere{Vector3f{}, 2}) }, true, 8},
+		{"SphereInside", func() (float32, bool) {
+			return Ray{Vector3f{}, Vector3f{0, 0, 1}}.IntersectsSphere(BoundingSphere{Vector3f{}, 2})
+		}, true, 0},
+		{"SphereBehind", func() (float32, bool) {
+			return Ray{Vector3f{0, 0, 10}, Vector3f{0, 0, 1}}.IntersectsSphere(BoundingSphere{Vector3f{}, 2})
+		}, false, 0},
+		{"Plane", func() (float32, bool) {
+			return ray.IntersectsPlane(PlaneFromNormalAndPoint(Vector3f{0, 0, 1}, Vector3f{0, 0, 3}))
+		}, true, 7},
+		{"PlaneParallel", func() (float32, bool) {
+			return ray.IntersectsPlane(PlaneFromNormalAndPoint(Vector3f{1, 0, 0}, Vector3f{}))
+		}, false, 0},
+		{"OBB", func() (float32, bool) { return ray.IntersectsOBB(rotated) }, true, 10 - Sqrt(2)},
+		{"OBBNearCorner", func() (float32, bool) { return Ray{Vector3f{1.2, 0, 10}, Vector3f{0, 0, -1}}.IntersectsOBB(rotated) }, true, 10 - (Sqrt(2) - 1.2)},
+		{"OBBMiss", func() (float32, bool) { return Ray{Vector3f{1.5, 0, 10}, Vector3f{0, 0, -1}}.IntersectsOBB(rotated) }, false, 0},
+	}
+	for _, test := range tests {
+		distance, hit := test.intersect()
+		if hit != test.hit || (hit && !ApproxEqual(distance, test.distance, 1e-3)) {
+			t.Errorf("%v: got hit %v at %v, expected hit %v at %v", test.name, hit, distance, test.hit, test.distance)
+		}
+	}
+}
+
+func TestRayTransform(t *testing.T) {
+	mat := Matrix4x4TRS(Vector3f{1, 2, 3}, QuaternionFromAxisAngle(UpVector3f(), 90), Vector3f{2, 2, 2})
+	ray := Ray{Vector3f{}, Vector3f{1, 0, 0}}.Transform(&mat)
+	if !ray.Origin.ApproxEqual(Vector3f{1, 2, 3}, 1e-5) || !ray.Direction.ApproxEqual(Vector3f{0, 0, -1}, 1e-5) {
+		t.Errorf("Transformed ray is %v", ray)
+	}
+}
@@ -0,0 +1,53 @@
+package math
+
+import gomath "math"
+
+// Sqrt returns the square root of a float32. Saves us casting to float64 every time
+func Sqrt(value float32) float32 {
+	return float32(gomath.Sqrt(float64(value)))
+}
+
+// Abs returns the absolute value of a float32
+func Abs(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// Min returns the smaller of two float32 values
+func Min(a float32, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Max returns the larger of two float32 values
+func Max(a float32, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Clamp restricts value to the range [min, max]
+func Clamp(value float32, min float32, max float32) float32 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// Lerp linearly interpolates between a and b. t is not clamped
+func Lerp(a float32, b float32, t float32) float32 {
+	return a + (b-a)*t
+}
+
+// ApproxEqual returns true if a and b are within tolerance of each other
+func ApproxEqual(a float32, b float32, tolerance float32) bool {
+	return Abs(a-b) <= tolerance
+}
@@ -1,15 +1,17 @@
 package math
 
-// StandardMatrix implements the Matrix interface and represents a uncompressed, unoptimized matrix.
+import gomath "math"
+
+// StandardMatrix implements the Matrix interface and represents a uncompressed, unoptimized matrix of any size.
 // Stored in column major order
-// TODO: Deprecate this garbage
+// NOTE: Every operation allocates. Use Matrix4x4 or Matrix3x3 in hot paths
 type StandardMatrix struct {
 	data    []float32
 	numRows int
 	numCols int
 }
 
-// CreateStandardMatrix is the standard constructor for a Matrix4x4
+// CreateStandardMatrix is the standard constructor for a StandardMatrix. data must be in column major order
 func CreateStandardMatrix(data []float32, numRows int, numCols int) *StandardMatrix {
 	mat := new(StandardMatrix)
 	mat.data = data[:numCols*numRows]
@@ -52,22 +54,30 @@ func (mat *StandardMatrix) Set(row int, col int, value float32) {
 	mat.data[index] = value
 }
 
+// GetSubMatrix is Implementing Matrix Interface
+func (mat *StandardMatrix) GetSubMatrix(row int, col int, numRows int, numCols int) Matrix {
+	return getSubMatrix(mat, row, col, numRows, numCols)
+}
+
+// SetSubMatrix is Implementing Matrix Interface
+func (mat *StandardMatrix) SetSubMatrix(row int, col int, sub Matrix) {
+	setSubMatrix(mat, row, col, sub)
+}
+
 // NumRows is Implementing Matrix Interface
 func (mat *StandardMatrix) NumRows() int {
-	return 4
+	return mat.numRows
 }
 
 // NumCols is Implementing Matrix Interface
 func (mat *StandardMatrix) NumCols() int {
-	return 4
+	return mat.numCols
 }
 
 // MulM multiplies this matrix by another via this * other and returns a pointer to a NEW matrix as the result
-// TODO: Optimize this garbage
-// TODO: Maybe don't return a new matrix because composite operations start to consume fuck loads of memory
 // TODO: Can this be moved to interface and maybe return a Matrix instead of *StandardMatrix?
 func (mat *StandardMatrix) MulM(other Matrix) *StandardMatrix {
-	if mat.NumCols() != other.NumRows() || mat.NumRows() != other.NumCols() {
+	if mat.NumCols() != other.NumRows() {
 		panic("Invalid Operation: Matrix dimensions are incompatible for inner product")
 	}
 	toRet := StandardMatrixZeros(mat.NumRows(), other.NumCols())
@@ -84,6 +94,114 @@ func (mat *StandardMatrix) MulM(other Matrix) *StandardMatrix {
 	return toRet
 }
 
+// Transpose returns a NEW matrix that is the transpose of this one
+func (mat *StandardMatrix) Transpose() *StandardMatrix {
+	toRet := StandardMatrixZeros(mat.NumCols(), mat.NumRows())
+	for i := 0; i < mat.NumRows(); i++ {
+		for j := 0; j < mat.NumCols(); j++ {
+			toRet.Set(j, i, mat.Get(i, j))
+		}
+	}
+	return toRet
+}
+
+// Inverse returns a NEW matrix that is the inverse of this one.
+// Returns ErrNonSquareMatrix or ErrSingularMatrix if no inverse exists
+func (mat *StandardMatrix) Inverse() (*StandardMatrix, error) {
+	if mat.NumRows() != mat.NumCols() {
+		return nil, ErrNonSquareMatrix
+	}
+
+	// Gauss-Jordan elimination on [mat | I] with partial pivoting. Done in float64 to keep error down
+	n := mat.NumRows()
+	work := make([][]float64, n)
+	for i := range work {
+		work[i] = make([]float64, 2*n)
+		for j := 0; j < n; j++ {
+			work[i][j] = float64(mat.Get(i, j))
+		}
+		work[i][n+i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		// Pick the largest pivot for stability
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if gomath.Abs(work[row][col]) > gomath.Abs(work[pivot][col]) {
+				pivot = row
+			}
+		}
+		if gomath.Abs(work[pivot][col]) < 1e-12 {
+			return nil, ErrSingularMatrix
+		}
+		work[col], work[pivot] = work[pivot], work[col]
+
+		invPivot := 1 / work[col][col]
+		for j := range work[col] {
+			work[col][j] *= invPivot
+		}
+		for row := 0; row < n; row++ {
+			if row == col || work[row][col] == 0 {
+				continue
+			}
+			factor := work[row][col]
+			for j := range work[row] {
+				work[row][j] -= factor * work[col][j]
+			}
+		}
+	}
+
+	toRet := StandardMatrixZeros(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			toRet.Set(i, j, float32(work[i][n+j]))
+		}
+	}
+	return toRet, nil
+}
+
+// Determinant returns the determinant of this matrix or ErrNonSquareMatrix
+func (mat *StandardMatrix) Determinant() (float32, error) {
+	if mat.NumRows() != mat.NumCols() {
+		return 0, ErrNonSquareMatrix
+	}
+
+	// Gaussian elimination, the determinant is the product of the pivots
+	n := mat.NumRows()
+	work := make([][]float64, n)
+	for i := range work {
+		work[i] = make([]float64, n)
+		for j := range work[i] {
+			work[i][j] = float64(mat.Get(i, j))
+		}
+	}
+
+	det := 1.0
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if gomath.Abs(work[row][col]) > gomath.Abs(work[pivot][col]) {
+				pivot = row
+			}
+		}
+		if work[pivot][col] == 0 {
+			return 0, nil
+		}
+		if pivot != col {
+			work[col], work[pivot] = work[pivot], work[col]
+			det = -det
+		}
+		det *= work[col][col]
+		for row := col + 1; row < n; row++ {
+			factor := work[row][col] / work[col][col]
+			for j := col; j < n; j++ {
+				work[row][j] -= factor * work[col][j]
+			}
+		}
+	}
+	return float32(det), nil
+}
+
 // ColMajorData implements the Matrix interface
 func (mat *StandardMatrix) ColMajorData() *[]float32 {
 	return &mat.data
@@ -0,0 +1,164 @@
+package math
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomStandardMatrix returns a matrix of values in [-2, 2). Random matrices are almost never singular
+func randomStandardMatrix(random *rand.Rand, numRows int, numCols int) *StandardMatrix {
+	data := make([]float32, numRows*numCols)
+	for i := range data {
+		data[i] = random.Float32()*4 - 2
+	}
+	return CreateStandardMatrix(data, numRows, numCols)
+}
+
+// standardMatricesApproxEqual returns true if every element of a is within tolerance of b
+func standardMatricesApproxEqual(a *StandardMatrix, b *StandardMatrix, tolerance float32) bool {
+	if a.NumRows() != b.NumRows() || a.NumCols() != b.NumCols() {
+		return false
+	}
+	for i := 0; i < a.NumRows(); i++ {
+		for j := 0; j < a.NumCols(); j++ {
+			if !ApproxEqual(a.Get(i, j), b.Get(i, j), tolerance) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestStandardMatrixInverse(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for n := 1; n <= 6; n++ {
+		for trial := 0; trial < 20; trial++ {
+			mat := randomStandardMatrix(random, n, n)
+			inverse, err := mat.Inverse()
+			if err != nil {
+				t.Fatalf("%vx%v inverse failed: %v", n, n, err)
+			}
+			identity := StandardMatrixIdentity(n, n)
+			if product := mat.MulM(inverse); !standardMatricesApproxEqual(product, identity, 1e-3) {
+				t.Errorf("M * M^-1 isn't identity for %v: %v", mat.data, product.data)
+			}
+			if product := inverse.MulM(mat); !standardMatricesApproxEqual(product, identity, 1e-3) {
+				t.Errorf("M^-1 * M isn't identity for %v: %v", mat.data, product.data)
+			}
+		}
+	}
+}
+
+func TestStandardMatrixTransposeDeterminant(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	for n := 1; n <= 5; n++ {
+		for trial := 0; trial < 20; trial++ {
+			a, b := randomStandardMatrix(random, n, n), randomStandardMatrix(random, n, n)
+			if !standardMatricesApproxEqual(a.Transpose().Transpose(), a, 0) {
+				t.Errorf("(M^T)^T != M for %v", a.data)
+			}
+			if !standardMatricesApproxEqual(a.MulM(b).Transpose(), b.Transpose().MulM(a.Transpose()), 1e-3) {
+				t.Errorf("(AB)^T != B^T A^T for %v and %v", a.data, b.data)
+			}
+
+			detA, _ := a.Determinant()
+			detB, _ := b.Determinant()
+			detT, _ := a.Transpose().Determinant()
+			detAB, _ := a.MulM(b).Determinant()
+			if !ApproxEqual(detT, detA, 1e-3*Max(1, Abs(detA))) {
+				t.Errorf("det(M^T) = %v but det(M) = %v", detT, detA)
+			}
+			if !ApproxEqual(detAB, detA*detB, 1e-3*Max(1, Abs(detA*detB))) {
+				t.Errorf("det(AB) = %v but det(A)det(B) = %v", detAB, detA*detB)
+			}
+		}
+	}
+}
+
+func TestStandardMatrixKnownDeterminants(t *testing.T) {
+	tests := []struct {
+		data     []float32
+		n        int
+		expected float32
+	}{
+		{[]float32{5}, 1, 5},
+		{[]float32{1, 3, 2, 4}, 2, -2},
+		{[]float32{2, 0, 0, 0, 3, 0, 0, 0, 4}, 3, 24},
+		{[]float32{0, 1, 0, 1, 0, 0, 0, 0, 1}, 3, -1},
+		{[]float32{1, 2, 3, 2, 4, 6, 0, 0, 1}, 3, 0},
+	}
+	for _, test := range tests {
+		det, err := CreateStandardMatrix(test.data, test.n, test.n).Determinant()
+		if err != nil || !ApproxEqual(det, test.expected, 1e-5) {
+			t.Errorf("Determinant of %v: got %v, %v, expected %v", test.data, det, err, test.expected)
+		}
+	}
+}
+
+func TestStandardMatrixErrors(t *testing.T) {
+	singular := CreateStandardMatrix([]float32{1, 2, 3, 2, 4, 6, 0, 0, 1}, 3, 3)
+	if _, err := singular.Inverse(); err != ErrSingularMatrix {
+		t.Errorf("Inverting a singular matrix returned %v, expected ErrSingularMatrix", err)
+	}
+	if _, err := StandardMatrixZeros(4, 4).Inverse(); err != ErrSingularMatrix {
+		t.Errorf("Inverting zeros returned %v, expected ErrSingularMatrix", err)
+	}
+
+	nonSquare := StandardMatrixIdentity(2, 3)
+	if _, err := nonSquare.Inverse(); err != ErrNonSquareMatrix {
+		t.Errorf("Inverting a 2x3 matrix returned %v, expected ErrNonSquareMatrix", err)
+	}
+	if _, err := nonSquare.Determinant(); err != ErrNonSquareMatrix {
+		t.Errorf("Determinant of a 2x3 matrix returned %v, expected ErrNonSquareMatrix", err)
+	}
+}
+
+// benchmarkStandardMatrix keeps the compiler from optimizing away benchmarked results
+var benchmarkStandardMatrix *StandardMatrix
+
+// standardMatrixTRS builds translation * rotation * scale the way transforms did before Matrix4x4, to compare against
+// Matrix4x4TRS
+func standardMatrixTRS(translation Vector3f, rotation Quaternion, scale Vector3f) *StandardMatrix {
+	rotationMatrix := rotation.ToMatrix()
+	scaleMatrix := CreateStandardMatrix([]float32{
+		scale.X, 0, 0, 0,
+		0, scale.Y, 0, 0,
+		0, 0, scale.Z, 0,
+		0, 0, 0, 1,
+	}, 4, 4)
+	translationMatrix := CreateStandardMatrix([]float32{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		translation.X, translation.Y, translation.Z, 1,
+	}, 4, 4)
+	return translationMatrix.MulM(CreateStandardMatrix(rotationMatrix[:], 4, 4).MulM(scaleMatrix))
+}
+
+func BenchmarkStandardMatrixMulM(b *testing.B) {
+	random := rand.New(rand.NewSource(1))
+	left, right := randomStandardMatrix(random, 4, 4), randomStandardMatrix(random, 4, 4)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkStandardMatrix = left.MulM(right)
+	}
+}
+
+func BenchmarkStandardMatrixInverse(b *testing.B) {
+	mat := randomStandardMatrix(rand.New(rand.NewSource(1)), 4, 4)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkStandardMatrix, _ = mat.Inverse()
+	}
+}
+
+func BenchmarkStandardMatrixTRS(b *testing.B) {
+	rotation := QuaternionFromAxisAngle(UpVector3f(), 15)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkStandardMatrix = standardMatrixTRS(Vector3f{X: float32(i)}, rotation, Vector3f{X: 1.5, Y: 1.5, Z: 1.5})
+	}
+}
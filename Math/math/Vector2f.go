@@ -10,3 +10,117 @@ type Vector2f struct {
 func CreateVector2f(x float32, y float32) *Vector2f {
 	return &Vector2f{x, y}
 }
+
+// ZeroVector2f returns a 0 vector
+func ZeroVector2f() Vector2f {
+	return Vector2f{0, 0}
+}
+
+// OnesVector2f returns a vector with all entries set to 1
+func OnesVector2f() Vector2f {
+	return Vector2f{1, 1}
+}
+
+// Add returns vec + other
+func (vec Vector2f) Add(other Vector2f) Vector2f {
+	return Vector2f{vec.X + other.X, vec.Y + other.Y}
+}
+
+// Sub returns vec - other
+func (vec Vector2f) Sub(other Vector2f) Vector2f {
+	return Vector2f{vec.X - other.X, vec.Y - other.Y}
+}
+
+// Mul returns the component wise product of vec and other
+func (vec Vector2f) Mul(other Vector2f) Vector2f {
+	return Vector2f{vec.X * other.X, vec.Y * other.Y}
+}
+
+// Div returns the component wise quotient of vec and other
+func (vec Vector2f) Div(other Vector2f) Vector2f {
+	return Vector2f{vec.X / other.X, vec.Y / other.Y}
+}
+
+// Scale returns vec with every component multiplied by scalar
+func (vec Vector2f) Scale(scalar float32) Vector2f {
+	return Vector2f{vec.X * scalar, vec.Y * scalar}
+}
+
+// Negate returns -vec
+func (vec Vector2f) Negate() Vector2f {
+	return Vector2f{-vec.X, -vec.Y}
+}
+
+// Dot returns the dot product of vec and other
+func (vec Vector2f) Dot(other Vector2f) float32 {
+	return vec.X*other.X + vec.Y*other.Y
+}
+
+// Cross returns the z component of the 3d cross product of vec and other.
+// Positive when other is counter clockwise from vec
+func (vec Vector2f) Cross(other Vector2f) float32 {
+	return vec.X*other.Y - vec.Y*other.X
+}
+
+// LengthSquared returns the squared length of the vector. Cheaper than Length when comparing
+func (vec Vector2f) LengthSquared() float32 {
+	return vec.Dot(vec)
+}
+
+// Length returns the length (magnitude) of the vector
+func (vec Vector2f) Length() float32 {
+	return Sqrt(vec.LengthSquared())
+}
+
+// Normalize returns a unit vector in the direction of vec. A zero vector returns a zero vector
+func (vec Vector2f) Normalize() Vector2f {
+	length := vec.Length()
+	if length == 0 {
+		return vec
+	}
+	return vec.Scale(1 / length)
+}
+
+// Distance returns the distance between the points vec and other
+func (vec Vector2f) Distance(other Vector2f) float32 {
+	return other.Sub(vec).Length()
+}
+
+// DistanceSquared returns the squared distance between the points vec and other
+func (vec Vector2f) DistanceSquared(other Vector2f) float32 {
+	return other.Sub(vec).LengthSquared()
+}
+
+// Lerp linearly interpolates from vec to other by t. t is not clamped
+func (vec Vector2f) Lerp(other Vector2f, t float32) Vector2f {
+	return Vector2f{Lerp(vec.X, other.X, t), Lerp(vec.Y, other.Y, t)}
+}
+
+// Reflect returns vec reflected off a surface with the given normal. normal must be normalized
+func (vec Vector2f) Reflect(normal Vector2f) Vector2f {
+	return vec.Sub(normal.Scale(2 * vec.Dot(normal)))
+}
+
+// Project returns the projection of vec onto other. Projecting onto a zero vector returns a zero vector
+func (vec Vector2f) Project(other Vector2f) Vector2f {
+	lengthSquared := other.LengthSquared()
+	if lengthSquared == 0 {
+		return ZeroVector2f()
+	}
+	return other.Scale(vec.Dot(other) / lengthSquared)
+}
+
+// Min returns the component wise minimum of vec and other
+func (vec Vector2f) Min(other Vector2f) Vector2f {
+	return Vector2f{Min(vec.X, other.X), Min(vec.Y, other.Y)}
+}
+
+// Max returns the component wise maximum of vec and other
+func (vec Vector2f) Max(other Vector2f) Vector2f {
+	return Vector2f{Max(vec.X, other.X), Max(vec.Y, other.Y)}
+}
+
+// ApproxEqual returns true if every component of vec is within tolerance of other
+func (vec Vector2f) ApproxEqual(other Vector2f, tolerance float32) bool {
+	return ApproxEqual(vec.X, other.X, tolerance) && ApproxEqual(vec.Y, other.Y, tolerance)
+}
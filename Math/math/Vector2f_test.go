@@ -0,0 +1,55 @@
+package math
+
+import "testing"
+
+func TestVector2fOperations(t *testing.T) {
+	a, b := Vector2f{3, 4}, Vector2f{1, -2}
+	tests := []struct {
+		name     string
+		got      Vector2f
+		expected Vector2f
+	}{
+		{"Add", a.Add(b), Vector2f{4, 2}},
+		{"Sub", a.Sub(b), Vector2f{2, 6}},
+		{"Mul", a.Mul(b), Vector2f{3, -8}},
+		{"Div", a.Div(b), Vector2f{3, -2}},
+		{"Scale", a.Scale(2), Vector2f{6, 8}},
+		{"Negate", a.Negate(), Vector2f{-3, -4}},
+		{"Normalize", a.Normalize(), Vector2f{0.6, 0.8}},
+		{"NormalizeZero", ZeroVector2f().Normalize(), Vector2f{}},
+		{"Lerp", a.Lerp(b, 0.5), Vector2f{2, 1}},
+		{"LerpUnclamped", ZeroVector2f().Lerp(OnesVector2f(), 2), Vector2f{2, 2}},
+		{"Reflect", Vector2f{1, -1}.Reflect(Vector2f{0, 1}), Vector2f{1, 1}},
+		{"Project", a.Project(Vector2f{2, 0}), Vector2f{3, 0}},
+		{"ProjectZero", a.Project(Vector2f{}), Vector2f{}},
+		{"Min", a.Min(b), Vector2f{1, -2}},
+		{"Max", a.Max(b), Vector2f{3, 4}},
+	}
+	for _, test := range tests {
+		if !test.got.ApproxEqual(test.expected, 1e-6) {
+			t.Errorf("%v: got %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+}
+
+func TestVector2fScalars(t *testing.T) {
+	a, b := Vector2f{3, 4}, Vector2f{1, -2}
+	tests := []struct {
+		name     string
+		got      float32
+		expected float32
+	}{
+		{"Dot", a.Dot(b), -5},
+		{"Cross", Vector2f{1, 0}.Cross(Vector2f{0, 1}), 1},
+		{"CrossClockwise", Vector2f{0, 1}.Cross(Vector2f{1, 0}), -1},
+		{"LengthSquared", a.LengthSquared(), 25},
+		{"Length", a.Length(), 5},
+		{"Distance", a.Distance(b), Sqrt(40)},
+		{"DistanceSquared", a.DistanceSquared(b), 40},
+	}
+	for _, test := range tests {
+		if !ApproxEqual(test.got, test.expected, 1e-5) {
+			t.Errorf("%v: got %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package math
 
+import gomath "math"
+
 // Vector3f represents a standard 3d vector comprised of 3 floats
 type Vector3f struct {
 	X float32 // The X Coordinate
@@ -21,3 +23,152 @@ func ZeroVector3f() Vector3f {
 func OnesVector3f() Vector3f {
 	return Vector3f{1, 1, 1}
 }
+
+// Add returns vec + other
+func (vec Vector3f) Add(other Vector3f) Vector3f {
+	return Vector3f{vec.X + other.X, vec.Y + other.Y, vec.Z + other.Z}
+}
+
+// Sub returns vec - other
+func (vec Vector3f) Sub(other Vector3f) Vector3f {
+	return Vector3f{vec.X - other.X, vec.Y - other.Y, vec.Z - other.Z}
+}
+
+// Mul returns the component wise product of vec and other
+func (vec Vector3f) Mul(other Vector3f) Vector3f {
+	return Vector3f{vec.X * other.X, vec.Y * other.Y, vec.Z * other.Z}
+}
+
+// Div returns the component wise quotient of vec and other
+func (vec Vector3f) Div(other Vector3f) Vector3f {
+	return Vector3f{vec.X / other.X, vec.Y / other.Y, vec.Z / other.Z}
+}
+
+// Scale returns vec with every component multiplied by scalar
+func (vec Vector3f) Scale(scalar float32) Vector3f {
+	return Vector3f{vec.X * scalar, vec.Y * scalar, vec.Z * scalar}
+}
+
+// Negate returns -vec
+func (vec Vector3f) Negate() Vector3f {
+	return Vector3f{-vec.X, -vec.Y, -vec.Z}
+}
+
+// Reciprocal returns the component wise reciprocal 1 / vec
+func (vec Vector3f) Reciprocal() Vector3f {
+	return Vector3f{1 / vec.X, 1 / vec.Y, 1 / vec.Z}
+}
+
+// Dot returns the dot product of vec and other
+func (vec Vector3f) Dot(other Vector3f) float32 {
+	return vec.X*other.X + vec.Y*other.Y + vec.Z*other.Z
+}
+
+// Cross returns the cross product vec x other (right handed)
+func (vec Vector3f) Cross(other Vector3f) Vector3f {
+	return Vector3f{
+		vec.Y*other.Z - vec.Z*other.Y,
+		vec.Z*other.X - vec.X*other.Z,
+		vec.X*other.Y - vec.Y*other.X,
+	}
+}
+
+// LengthSquared returns the squared length of the vector. Cheaper than Length when comparing
+func (vec Vector3f) LengthSquared() float32 {
+	return vec.Dot(vec)
+}
+
+// Length returns the length (magnitude) of the vector
+func (vec Vector3f) Length() float32 {
+	return Sqrt(vec.LengthSquared())
+}
+
+// Normalize returns a unit vector in the direction of vec. A zero vector returns a zero vector
+func (vec Vector3f) Normalize() Vector3f {
+	length := vec.Length()
+	if length == 0 {
+		return vec
+	}
+	return vec.Scale(1 / length)
+}
+
+// Distance returns the distance between the points vec and other
+func (vec Vector3f) Distance(other Vector3f) float32 {
+	return other.Sub(vec).Length()
+}
+
+// DistanceSquared returns the squared distance between the points vec and other
+func (vec Vector3f) DistanceSquared(other Vector3f) float32 {
+	return other.Sub(vec).LengthSquared()
+}
+
+// Lerp linearly interpolates from vec to other by t. t is not clamped
+func (vec Vector3f) Lerp(other Vector3f, t float32) Vector3f {
+	return Vector3f{Lerp(vec.X, other.X, t), Lerp(vec.Y, other.Y, t), Lerp(vec.Z, other.Z, t)}
+}
+
+// Reflect returns vec reflected off a surface with the given normal. normal must be normalized
+func (vec Vector3f) Reflect(normal Vector3f) Vector3f {
+	return vec.Sub(normal.Scale(2 * vec.Dot(normal)))
+}
+
+// Project returns the projection of vec onto other. Projecting onto a zero vector returns a zero vector
+func (vec Vector3f) Project(other Vector3f) Vector3f {
+	lengthSquared := other.LengthSquared()
+	if lengthSquared == 0 {
+		return ZeroVector3f()
+	}
+	return other.Scale(vec.Dot(other) / lengthSquared)
+}
+
+// ProjectOnPlane returns vec with the component along the plane's normal removed
+func (vec Vector3f) ProjectOnPlane(normal Vector3f) Vector3f {
+	return vec.Sub(vec.Project(normal))
+}
+
+// Angle returns the unsigned angle in degrees between vec and other
+func (vec Vector3f) Angle(other Vector3f) float32 {
+	denominator := Sqrt(vec.LengthSquared() * other.LengthSquared())
+	if denominator == 0 {
+		return 0
+	}
+	cos := Clamp(vec.Dot(other)/denominator, -1, 1)
+	return float32(gomath.Acos(float64(cos))) * Rad2Deg
+}
+
+// Min returns the component wise minimum of vec and other
+func (vec Vector3f) Min(other Vector3f) Vector3f {
+	return Vector3f{Min(vec.X, other.X), Min(vec.Y, other.Y), Min(vec.Z, other.Z)}
+}
+
+// Max returns the component wise maximum of vec and other
+func (vec Vector3f) Max(other Vector3f) Vector3f {
+	return Vector3f{Max(vec.X, other.X), Max(vec.Y, other.Y), Max(vec.Z, other.Z)}
+}
+
+// ApproxEqual returns true if every component of vec is within tolerance of other
+func (vec Vector3f) ApproxEqual(other Vector3f, tolerance float32) bool {
+	return ApproxEqual(vec.X, other.X, tolerance) &&
+		ApproxEqual(vec.Y, other.Y, tolerance) &&
+		ApproxEqual(vec.Z, other.Z, tolerance)
+}
+
+// ToVector4f returns a Vector4f with the same xyz and the provided w
+func (vec Vector3f) ToVector4f(w float32) Vector4f {
+	return Vector4f{vec.X, vec.Y, vec.Z, w}
+}
+
+// RightVector3f returns the unit vector pointing along +X
+func RightVector3f() Vector3f {
+	return Vector3f{1, 0, 0}
+}
+
+// UpVector3f returns the unit vector pointing along +Y
+func UpVector3f() Vector3f {
+	return Vector3f{0, 1, 0}
+}
+
+// ForwardVector3f returns the unit vector pointing along -Z, which is forward in OpenGL's right handed space
+func ForwardVector3f() Vector3f {
+	return Vector3f{0, 0, -1}
+}
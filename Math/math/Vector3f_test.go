@@ -0,0 +1,72 @@
+package math
+
+import "testing"
+
+func TestVector3fOperations(t *testing.T) {
+	a, b := Vector3f{1, 2, 3}, Vector3f{4, -5, 6}
+	tests := []struct {
+		name     string
+		got      Vector3f
+		expected Vector3f
+	}{
+		{"Add", a.Add(b), Vector3f{5, -3, 9}},
+		{"Sub", a.Sub(b), Vector3f{-3, 7, -3}},
+		{"Mul", a.Mul(b), Vector3f{4, -10, 18}},
+		{"Div", b.Div(a), Vector3f{4, -2.5, 2}},
+		{"Scale", a.Scale(-2), Vector3f{-2, -4, -6}},
+		{"Negate", a.Negate(), Vector3f{-1, -2, -3}},
+		{"Reciprocal", Vector3f{2, 4, -0.5}.Reciprocal(), Vector3f{0.5, 0.25, -2}},
+		{"Cross", a.Cross(b), Vector3f{27, 6, -13}},
+		{"CrossBasis", RightVector3f().Cross(UpVector3f()), ForwardVector3f().Negate()},
+		{"Normalize", Vector3f{0, 3, 4}.Normalize(), Vector3f{0, 0.6, 0.8}},
+		{"NormalizeZero", ZeroVector3f().Normalize(), Vector3f{}},
+		{"Lerp", a.Lerp(b, 0.25), Vector3f{1.75, 0.25, 3.75}},
+		{"Reflect", Vector3f{1, -1, 0}.Reflect(UpVector3f()), Vector3f{1, 1, 0}},
+		{"Project", a.Project(Vector3f{0, 0, 2}), Vector3f{0, 0, 3}},
+		{"ProjectZero", a.Project(Vector3f{}), Vector3f{}},
+		{"ProjectOnPlane", a.ProjectOnPlane(UpVector3f()), Vector3f{1, 0, 3}},
+		{"Min", a.Min(b), Vector3f{1, -5, 3}},
+		{"Max", a.Max(b), Vector3f{4, 2, 6}},
+	}
+	for _, test := range tests {
+		if !test.got.ApproxEqual(test.expected, 1e-6) {
+			t.Errorf("%v: got %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+}
+
+func TestVector3fScalars(t *testing.T) {
+	a, b := Vector3f{1, 2, 3}, Vector3f{4, -5, 6}
+	tests := []struct {
+		name     string
+		got      float32
+		expected float32
+	}{
+		{"Dot", a.Dot(b), 12},
+		{"LengthSquared", a.LengthSquared(), 14},
+		{"Length", Vector3f{2, 3, 6}.Length(), 7},
+		{"Distance", ZeroVector3f().Distance(Vector3f{2, 3, 6}), 7},
+		{"DistanceSquared", a.DistanceSquared(b), 67},
+		{"AngleRight", RightVector3f().Angle(UpVector3f()), 90},
+		{"AngleOpposite", RightVector3f().Angle(RightVector3f().Negate()), 180},
+		{"AngleParallel", a.Angle(a.Scale(3)), 0},
+		{"AngleZero", a.Angle(Vector3f{}), 0},
+	}
+	for _, test := range tests {
+		if !ApproxEqual(test.got, test.expected, 1e-3) {
+			t.Errorf("%v: got %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+}
+
+func TestVector3fCrossIsPerpendicular(t *testing.T) {
+	vectors := []Vector3f{{1, 2, 3}, {-4, 0.5, 2}, {0, 0, 1}, {7, -7, 0.25}}
+	for _, a := range vectors {
+		for _, b := range vectors {
+			cross := a.Cross(b)
+			if !ApproxEqual(cross.Dot(a), 0, 1e-3) || !ApproxEqual(cross.Dot(b), 0, 1e-3) {
+				t.Errorf("Cross of %v and %v isn't perpendicular to them: %v", a, b, cross)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package math
+
+// Vector4f represents a standard 4d vector comprised of 4 floats. Mostly used for homogeneous coordinates and colors
+type Vector4f struct {
+	X float32 // The X Coordinate
+	Y float32 // The Y Coordinate
+	Z float32 // The Z Coordinate
+	W float32 // The W Coordinate
+}
+
+// CreateVector4f is the optional constructor for a Vector4f
+func CreateVector4f(x float32, y float32, z float32, w float32) *Vector4f {
+	return &Vector4f{x, y, z, w}
+}
+
+// ZeroVector4f returns a 0 vector
+func ZeroVector4f() Vector4f {
+	return Vector4f{0, 0, 0, 0}
+}
+
+// OnesVector4f returns a vector with all entries set to 1
+func OnesVector4f() Vector4f {
+	return Vector4f{1, 1, 1, 1}
+}
+
+// Add returns vec + other
+func (vec Vector4f) Add(other Vector4f) Vector4f {
+	return Vector4f{vec.X + other.X, vec.Y + other.Y, vec.Z + other.Z, vec.W + other.W}
+}
+
+// Sub returns vec - other
+func (vec Vector4f) Sub(other Vector4f) Vector4f {
+	return Vector4f{vec.X - other.X, vec.Y - other.Y, vec.Z - other.Z, vec.W - other.W}
+}
+
+// Mul returns the component wise product of vec and other
+func (vec Vector4f) Mul(other Vector4f) Vector4f {
+	return Vector4f{vec.X * other.X, vec.Y * other.Y, vec.Z * other.Z, vec.W * other.W}
+}
+
+// Div returns the component wise quotient of vec and other
+func (vec Vector4f) Div(other Vector4f) Vector4f {
+	return Vector4f{vec.X / other.X, vec.Y / other.Y, vec.Z / other.Z, vec.W / other.W}
+}
+
+// Scale returns vec with every component multiplied by scalar
+func (vec Vector4f) Scale(scalar float32) Vector4f {
+	return Vector4f{vec.X * scalar, vec.Y * scalar, vec.Z * scalar, vec.W * scalar}
+}
+
+// Negate returns -vec
+func (vec Vector4f) Negate() Vector4f {
+	return Vector4f{-vec.X, -vec.Y, -vec.Z, -vec.W}
+}
+
+// Dot returns the dot product of vec and other
+func (vec Vector4f) Dot(other Vector4f) float32 {
+	return vec.X*other.X + vec.Y*other.Y + vec.Z*other.Z + vec.W*other.W
+}
+
+// LengthSquared returns the squared length of the vector. Cheaper than Length when comparing
+func (vec Vector4f) LengthSquared() float32 {
+	return vec.Dot(vec)
+}
+
+// Length returns the length (magnitude) of the vector
+func (vec Vector4f) Length() float32 {
+	return Sqrt(vec.LengthSquared())
+}
+
+// Normalize returns a unit vector in the direction of vec. A zero vector returns a zero vector
+func (vec Vector4f) Normalize() Vector4f {
+	length := vec.Length()
+	if length == 0 {
+		return vec
+	}
+	return vec.Scale(1 / length)
+}
+
+// Distance returns the distance between the points vec and other
+func (vec Vector4f) Distance(other Vector4f) float32 {
+	return other.Sub(vec).Length()
+}
+
+// Lerp linearly interpolates from vec to other by t. t is not clamped
+func (vec Vector4f) Lerp(other Vector4f, t float32) Vector4f {
+	return Vector4f{Lerp(vec.X, other.X, t), Lerp(vec.Y, other.Y, t), Lerp(vec.Z, other.Z, t), Lerp(vec.W, other.W, t)}
+}
+
+// Project returns the projection of vec onto other. Projecting onto a zero vector returns a zero vector
+func (vec Vector4f) Project(other Vector4f) Vector4f {
+	lengthSquared := other.LengthSquared()
+	if lengthSquared == 0 {
+		return ZeroVector4f()
+	}
+	return other.Scale(vec.Dot(other) / lengthSquared)
+}
+
+// Min returns the component wise minimum of vec and other
+func (vec Vector4f) Min(other Vector4f) Vector4f {
+	return Vector4f{Min(vec.X, other.X), Min(vec.Y, other.Y), Min(vec.Z, other.Z), Min(vec.W, other.W)}
+}
+
+// Max returns the component wise maximum of vec and other
+func (vec Vector4f) Max(other Vector4f) Vector4f {
+	return Vector4f{Max(vec.X, other.X), Max(vec.Y, other.Y), Max(vec.Z, other.Z), Max(vec.W, other.W)}
+}
+
+// ApproxEqual returns true if every component of vec is within tolerance of other
+func (vec Vector4f) ApproxEqual(other Vector4f, tolerance float32) bool {
+	return ApproxEqual(vec.X, other.X, tolerance) &&
+		ApproxEqual(vec.Y, other.Y, tolerance) &&
+		ApproxEqual(vec.Z, other.Z, tolerance) &&
+		ApproxEqual(vec.W, other.W, tolerance)
+}
+
+// XYZ returns the first three components as a Vector3f
+func (vec Vector4f) XYZ() Vector3f {
+	return Vector3f{vec.X, vec.Y, vec.Z}
+}
+
+// PerspectiveDivide returns xyz / w. Used to go from homogeneous to cartesian coordinates
+func (vec Vector4f) PerspectiveDivide() Vector3f {
+	if vec.W == 0 {
+		return vec.XYZ()
+	}
+	return vec.XYZ().Scale(1 / vec.W)
+}
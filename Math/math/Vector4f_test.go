@@ -0,0 +1,67 @@
+package math
+
+import "testing"
+
+func TestVector4fOperations(t *testing.T) {
+	a, b := Vector4f{1, 2, 3, 4}, Vector4f{-2, 4, 0, 8}
+	tests := []struct {
+		name     string
+		got      Vector4f
+		expected Vector4f
+	}{
+		{"Add", a.Add(b), Vector4f{-1, 6, 3, 12}},
+		{"Sub", a.Sub(b), Vector4f{3, -2, 3, -4}},
+		{"Mul", a.Mul(b), Vector4f{-2, 8, 0, 32}},
+		{"Div", b.Div(a), Vector4f{-2, 2, 0, 2}},
+		{"Scale", a.Scale(0.5), Vector4f{0.5, 1, 1.5, 2}},
+		{"Negate", a.Negate(), Vector4f{-1, -2, -3, -4}},
+		{"Normalize", Vector4f{2, 0, 0, 0}.Normalize(), Vector4f{1, 0, 0, 0}},
+		{"NormalizeZero", ZeroVector4f().Normalize(), Vector4f{}},
+		{"Lerp", a.Lerp(b, 0.5), Vector4f{-0.5, 3, 1.5, 6}},
+		{"Project", a.Project(Vector4f{0, 0, 0, 2}), Vector4f{0, 0, 0, 4}},
+		{"ProjectZero", a.Project(Vector4f{}), Vector4f{}},
+		{"Min", a.Min(b), Vector4f{-2, 2, 0, 4}},
+		{"Max", a.Max(b), Vector4f{1, 4, 3, 8}},
+		{"ToVector4f", Vector3f{1, 2, 3}.ToVector4f(1), Vector4f{1, 2, 3, 1}},
+	}
+	for _, test := range tests {
+		if !test.got.ApproxEqual(test.expected, 1e-6) {
+			t.Errorf("%v: got %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+}
+
+func TestVector4fScalars(t *testing.T) {
+	a, b := Vector4f{1, 2, 3, 4}, Vector4f{-2, 4, 0, 8}
+	tests := []struct {
+		name     string
+		got      float32
+		expected float32
+	}{
+		{"Dot", a.Dot(b), 38},
+		{"LengthSquared", a.LengthSquared(), 30},
+		{"Length", Vector4f{1, 1, 1, 1}.Length(), 2},
+		{"Distance", a.Distance(a.Add(Vector4f{0, 0, 3, 4})), 5},
+	}
+	for _, test := range tests {
+		if !ApproxEqual(test.got, test.expected, 1e-5) {
+			t.Errorf("%v: got %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+}
+
+func TestVector4fPerspectiveDivide(t *testing.T) {
+	tests := []struct {
+		vec      Vector4f
+		expected Vector3f
+	}{
+		{Vector4f{2, 4, 6, 2}, Vector3f{1, 2, 3}},
+		{Vector4f{2, 4, 6, 1}, Vector3f{2, 4, 6}},
+		{Vector4f{2, 4, 6, 0}, Vector3f{2, 4, 6}},
+	}
+	for _, test := range tests {
+		if got := test.vec.PerspectiveDivide(); !got.ApproxEqual(test.expected, 1e-6) {
+			t.Errorf("PerspectiveDivide of %v: got %v, expected %v", test.vec, got, test.expected)
+		}
+	}
+}
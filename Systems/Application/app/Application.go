@@ -0,0 +1,227 @@
+package app
+
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+
+	"github.com/Surreal/Debug/dbg"
+	"github.com/Surreal/Systems/Graphics/gfx"
+	"github.com/Surreal/Systems/Input/input"
+	"github.com/Surreal/Systems/Time/time"
+	"github.com/go-gl/gl/v3.2-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+func init() {
+	// GLFW and GL calls must all come from the main thread
+	runtime.LockOSThread()
+}
+
+// Game is implemented by whatever the Application is running
+type Game interface {
+	Init(app *Application) error // Called once after the window and GL context exist. Load assets and build scenes here
+	Update(app *Application)     // Called once per frame after gfx.MainAssetLoader's uploads. Use app.Timer() for delta time and fixed steps
+	Render(app *Application)     // Called once per frame after Update with the back buffer cleared. Not called when headless
+	Shutdown(app *Application)   // Called once when the loop ends, while the GL context is still valid
+}
+
+// Application owns the window, GL context and main loop
+type Application struct {
+	config   Config
+	window   *glfw.Window
+	timer    *time.Timer
+	stepped  *time.ManualClock // Advanced one fixed step per frame when headless without a clock of it's own
+	cleanups []func()
+	running  bool
+	quit     bool
+}
+
+// CreateApplication is the standard constructor for an Application
+func CreateApplication(config Config) *Application {
+	app := new(Application)
+	app.config = config
+	return app
+}
+
+// Config returns the config this application was created with
+func (app *Application) Config() Config {
+	return app.config
+}
+
+// Window returns the application's window, nil when headless
+func (app *Application) Window() *glfw.Window {
+	return app.window
+}
+
+// Timer returns the timer driving the main loop
+func (app *Application) Timer() *time.Timer {
+	return app.timer
+}
+
+// Headless returns true if the application is running without a window
+func (app *Application) Headless() bool {
+	return app.config.Headless
+}
+
+// Quit asks the main loop to stop at the end of the current frame
+func (app *Application) Quit() {
+	app.quit = true
+}
+
+// AddCleanup registers a function to run on shutdown, after Game.Shutdown but before the GL context is destroyed.
+// Cleanups run in the reverse order they were added
+func (app *Application) AddCleanup(cleanup func()) {
+	app.cleanups = append(app.cleanups, cleanup)
+}
+
+// Run initializes the application, runs game until the window closes, Quit is called or the headless frame count is
+// reached, then shuts everything down in reverse order. Cleanup still happens if game panics
+func (app *Application) Run(game Game) (err error) {
+	if app.running {
+		return errors.New("Invalid Operation: Application is already running")
+	}
+	app.running = true
+	app.quit = false
+	defer func() { app.running = false }()
+
+	if err = app.initialize(); err != nil {
+		app.terminate()
+		return err
+	}
+	defer app.terminate()
+
+	if err = game.Init(app); err != nil {
+		return err
+	}
+	defer game.Shutdown(app)
+
+	for !app.shouldQuit() {
+		if app.stepped != nil {
+			app.stepped.Advance(float64(app.timer.FixedDeltaTime()))
+		}
+		app.timer.Tick()
+		if !app.config.Headless {
+			// Finish the main loader's decoded assets before Update so handles that completed are ready to use
+			gfx.MainAssetLoader.ProcessUploads(gfx.DefaultUploadBudget)
+		}
+		game.Update(app)
+
+		if app.config.Headless {
+			continue
+		}
+
+		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+		game.Render(app)
+		app.window.SwapBuffers()
+		glfw.PollEvents()
+	}
+
+	return nil
+}
+
+// initialize creates the timer, window and GL context
+func (app *Application) initialize() error {
+	clock := app.config.Clock
+	app.stepped = nil
+	if clock == nil && app.config.Headless {
+		// Step exactly one fixed update per frame so headless runs are deterministic
+		app.stepped = time.CreateManualClock(0)
+		clock = app.stepped
+	}
+	app.timer = time.CreateTimer(clock)
+
+	if app.config.Headless {
+		return nil
+	}
+
+	if err := glfw.Init(); err != nil {
+		return err
+	}
+
+	glfw.WindowHint(glfw.ContextVersionMajor, 3)
+	glfw.WindowHint(glfw.ContextVersionMinor, 2)
+	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	glfw.WindowHint(glfw.Samples, app.config.Samples)
+	if app.config.GLDebug {
+		glfw.WindowHint(glfw.OpenGLDebugContext, glfw.True)
+	}
+
+	window, err := glfw.CreateWindow(app.config.Width, app.config.Height, app.config.Title, nil, nil)
+	if err != nil {
+		return err
+	}
+	app.window = window
+	window.MakeContextCurrent()
+
+	if app.config.VSync {
+		glfw.SwapInterval(1)
+	} else {
+		glfw.SwapInterval(0)
+	}
+
+	if err := gl.Init(); err != nil {
+		return err
+	}
+
+	if app.config.GLDebug {
+		gl.Enable(gl.DEBUG_OUTPUT)
+		gl.DebugMessageCallback(glDebugCallback, nil)
+	}
+	if app.config.Samples > 0 {
+		gl.Enable(gl.MULTISAMPLE)
+	}
+	gl.Enable(gl.DEPTH_TEST)
+	gl.Enable(gl.CULL_FACE)
+	gl.FrontFace(gl.CCW)
+	gl.ClearColor(float32(0), float32(0), float32(0.1), float32(1))
+
+	width, height := window.GetFramebufferSize()
+	gl.Viewport(0, 0, int32(width), int32(height))
+	window.SetFramebufferSizeCallback(func(w *glfw.Window, width int, height int) {
+		gl.Viewport(0, 0, int32(width), int32(height))
+	})
+
+	input.TrackWindow(window)
+	return nil
+}
+
+// shouldQuit returns true once the main loop should stop
+func (app *Application) shouldQuit() bool {
+	if app.quit {
+		return true
+	}
+	if app.config.Headless {
+		return app.config.HeadlessFrames > 0 && app.timer.FrameCount() >= uint64(app.config.HeadlessFrames)
+	}
+	return app.window.ShouldClose()
+}
+
+// terminate runs cleanups and tears down the window and GLFW
+func (app *Application) terminate() {
+	for i := len(app.cleanups) - 1; i >= 0; i-- {
+		app.cleanups[i]()
+	}
+	app.cleanups = nil
+
+	if app.config.Headless {
+		return
+	}
+	if app.window != nil {
+		app.window.Destroy()
+		app.window = nil
+	}
+	glfw.Terminate()
+}
+
+func glDebugCallback(
+	source uint32,
+	gltype uint32,
+	id uint32,
+	severity uint32,
+	length int32,
+	message string,
+	userParam unsafe.Pointer) {
+	dbg.LogError(message)
+}
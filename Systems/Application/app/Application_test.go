@@ -0,0 +1,122 @@
+package app
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// recordingGame is a Game that records every call made to it
+type recordingGame struct {
+	calls      []string
+	initErr    error
+	updates    int
+	fixedSteps int
+	quitAt     int // Calls Quit on this update, 0 never does
+}
+
+func (game *recordingGame) Init(app *Application) error {
+	game.calls = append(game.calls, "Init")
+	app.AddCleanup(func() { game.calls = append(game.calls, "Cleanup1") })
+	app.AddCleanup(func() { game.calls = append(game.calls, "Cleanup2") })
+	return game.initErr
+}
+
+func (game *recordingGame) Update(app *Application) {
+	game.updates++
+	for app.Timer().FixedStep() {
+		game.fixedSteps++
+	}
+	if game.updates == game.quitAt {
+		app.Quit()
+	}
+}
+
+func (game *recordingGame) Render(app *Application) {
+	game.calls = append(game.calls, "Render")
+}
+
+func (game *recordingGame) Shutdown(app *Application) {
+	game.calls = append(game.calls, "Shutdown")
+}
+
+func TestHeadlessRun(t *testing.T) {
+	game := new(recordingGame)
+	app := CreateApplication(HeadlessConfig(10))
+	if err := app.Run(game); err != nil {
+		t.Fatal(err)
+	}
+
+	if game.updates != 10 {
+		t.Errorf("Update was called %v times, expected 10", game.updates)
+	}
+	// Render is never called headless and cleanups run after Shutdown, newest first
+	expected := []string{"Init", "Shutdown", "Cleanup2", "Cleanup1"}
+	if !reflect.DeepEqual(game.calls, expected) {
+		t.Errorf("Calls were %v, expected %v", game.calls, expected)
+	}
+	if app.Timer().FrameCount() != 10 {
+		t.Errorf("Timer counted %v frames, expected 10", app.Timer().FrameCount())
+	}
+	// Headless runs step exactly one fixed update per frame
+	if game.fixedSteps != 10 {
+		t.Errorf("Took %v fixed steps in 10 headless frames, expected 10", game.fixedSteps)
+	}
+
+	// The application can be run again once it's finished
+	second := new(recordingGame)
+	if err := app.Run(second); err != nil || second.updates != 10 {
+		t.Errorf("Running again gave %v after %v updates", err, second.updates)
+	}
+}
+
+func TestHeadlessQuit(t *testing.T) {
+	game := &recordingGame{quitAt: 3}
+	if err := CreateApplication(HeadlessConfig(0)).Run(game); err != nil {
+		t.Fatal(err)
+	}
+	if game.updates != 3 {
+		t.Errorf("Update was called %v times, expected the loop to stop at the Quit on update 3", game.updates)
+	}
+}
+
+func TestHeadlessInitError(t *testing.T) {
+	initErr := errors.New("init failed")
+	game := &recordingGame{initErr: initErr}
+	if err := CreateApplication(HeadlessConfig(5)).Run(game); err != initErr {
+		t.Errorf("Run returned %v, expected the Init error", err)
+	}
+	if game.updates != 0 {
+		t.Errorf("Update was called %v times after Init failed", game.updates)
+	}
+	// Shutdown is only for games that initialized, but cleanups registered during Init still run
+	expected := []string{"Init", "Cleanup2", "Cleanup1"}
+	if !reflect.DeepEqual(game.calls, expected) {
+		t.Errorf("Calls were %v, expected %v", game.calls, expected)
+	}
+}
+
+// panickingGame panics in it's first Update
+type panickingGame struct {
+	recordingGame
+}
+
+func (game *panickingGame) Update(app *Application) {
+	panic("update failed")
+}
+
+func TestHeadlessPanicStillCleansUp(t *testing.T) {
+	game := new(panickingGame)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("The panic wasn't passed on")
+			}
+		}()
+		CreateApplication(HeadlessConfig(5)).Run(game)
+	}()
+	expected := []string{"Init", "Shutdown", "Cleanup2", "Cleanup1"}
+	if !reflect.DeepEqual(game.calls, expected) {
+		t.Errorf("Calls were %v, expected %v", game.calls, expected)
+	}
+}
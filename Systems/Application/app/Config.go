@@ -0,0 +1,36 @@
+package app
+
+import "github.com/Surreal/Systems/Time/time"
+
+// Config holds the options an Application is created with
+type Config struct {
+	Width          int        // Window width in screen coordinates
+	Height         int        // Window height in screen coordinates
+	Title          string     // Window title
+	VSync          bool       // Wait for the monitor's refresh before swapping buffers
+	Samples        int        // MSAA samples per pixel, 0 disables multisampling
+	GLDebug        bool       // Request a debug context and route GL debug messages to dbg.LogError
+	Headless       bool       // Run without a window or GL context. Render is never called
+	HeadlessFrames int        // Number of frames to run for in headless mode, 0 runs until Quit is called
+	Clock          time.Clock // Clock that drives the timer. Defaults to the system clock, or a fixed 60hz step when headless
+}
+
+// DefaultConfig returns the config used by surreal.go
+func DefaultConfig() Config {
+	return Config{
+		Width:   1920,
+		Height:  1080,
+		Title:   "Surreal Engine v0.0.0",
+		VSync:   true,
+		Samples: 8,
+		GLDebug: true,
+	}
+}
+
+// HeadlessConfig returns a config that runs frames game loop iterations without opening a window
+func HeadlessConfig(frames int) Config {
+	return Config{
+		Headless:       true,
+		HeadlessFrames: frames,
+	}
+}
@@ -5,11 +5,24 @@ type Component interface {
 	SceneObject() *SceneObject       // Should return the scene object this component is currently attached to
 	Attach(sceneObject *SceneObject) // Should attach this component to a scene object and register it with the SO
 	Detach()                         // Should detach and remove this component from the scene object
+	Enabled() bool                   // Should return false if the component has been switched off
+	SetEnabled(enabled bool)         // Should switch the component on or off. Disabled components get no lifecycle updates
 }
 
 // BaseComponent provides the necessary fields to help implement the Component interface
 type BaseComponent struct {
 	sceneObject *SceneObject
+	owner       Component // The component embedding this one. This is what gets registered with the scene object
+	disabled    bool
+}
+
+// CreateBaseComponent is the standard constructor for a BaseComponent, and the only way to make one that can be
+// attached. owner should be the component that embeds it so the scene object's component list (and the lifecycle
+// callbacks found through it) see the real component
+func CreateBaseComponent(owner Component) *BaseComponent {
+	comp := new(BaseComponent)
+	comp.owner = owner
+	return comp
 }
 
 // SceneObject implements Component interface
@@ -24,7 +37,7 @@ func (comp *BaseComponent) Attach(sceneObject *SceneObject) {
 	}
 
 	comp.sceneObject = sceneObject
-	comp.sceneObject.Components = append(comp.sceneObject.Components, comp)
+	comp.sceneObject.Components = append(comp.sceneObject.Components, comp.self())
 }
 
 // Detach implements the Component interface
@@ -34,9 +47,10 @@ func (comp *BaseComponent) Detach() {
 	}
 
 	// Remove from components list without memory allocation
+	self := comp.self()
 	j := 0
 	for _, c := range comp.sceneObject.Components {
-		if c != comp {
+		if c != self {
 			comp.sceneObject.Components[j] = c
 			j++
 		}
@@ -44,3 +58,22 @@ func (comp *BaseComponent) Detach() {
 	comp.sceneObject.Components = comp.sceneObject.Components[:j]
 	comp.sceneObject = nil
 }
+
+// Enabled implements the Component interface
+func (comp *BaseComponent) Enabled() bool {
+	return !comp.disabled
+}
+
+// SetEnabled implements the Component interface. OnEnable / OnDisable are sent by the scene at the start of the next update
+func (comp *BaseComponent) SetEnabled(enabled bool) {
+	comp.disabled = !enabled
+}
+
+// Returns the component that should be registered with the scene object. Panics without an owner, since registering
+// the BaseComponent itself would silently hide the embedding component's lifecycle callbacks
+func (comp *BaseComponent) self() Component {
+	if comp.owner == nil {
+		panic("Invalid Component: BaseComponent has no owner, make it with CreateBaseComponent")
+	}
+	return comp.owner
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBaseComponentRegistersOwner(t *testing.T) {
+	var events []string
+	so := CreateSceneObject(nil)
+	comp := createLifecycleTestComponent(so, "owner", &events)
+	if last := so.Components[len(so.Components)-1]; last != Component(comp) {
+		t.Errorf("got %T registered with the scene object, expected the embedding component", last)
+	}
+
+	so.RemoveComponent(comp)
+	for _, registered := range so.Components {
+		if registered == Component(comp) {
+			t.Errorf("the component is still registered after being removed")
+		}
+	}
+	if comp.SceneObject() != nil {
+		t.Errorf("got scene object %v after being removed, expected nil", comp.SceneObject())
+	}
+}
+
+func TestBaseComponentWithoutOwnerPanics(t *testing.T) {
+	defer func() {
+		recovered := recover()
+		if message, ok := recovered.(string); !ok || !strings.Contains(message, "CreateBaseComponent") {
+			t.Errorf("got panic %v, expected one pointing at CreateBaseComponent", recovered)
+		}
+	}()
+	comp := &lifecycleTestComponent{BaseComponent: new(BaseComponent)}
+	CreateSceneObject(nil).AddComponent(comp)
+}
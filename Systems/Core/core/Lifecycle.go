@@ -0,0 +1,50 @@
+package core
+
+// The lifecycle interfaces below are optional. Any attached component implementing one will be discovered by the Scene
+// and have it called at the appropriate time. Each frame the scene runs, in order:
+//   1. Awake on components seen for the first time (even if disabled)
+//   2. OnEnable / OnDisable on components whose active state changed since last frame
+//   3. Start on active components that haven't started yet
+//   4. FixedUpdate zero or more times, Update once, then LateUpdate once on every active component
+// A component is active when it's enabled and it's scene object and all of that object's parents are enabled
+
+// Awaker is implemented by components that need to initialize when they first enter a scene
+type Awaker interface {
+	Awake()
+}
+
+// Starter is implemented by components that need to initialize right before their first update, after every
+// component in the scene has been awoken
+type Starter interface {
+	Start()
+}
+
+// Updater is implemented by components that need to run once per frame
+type Updater interface {
+	Update(deltaTime float32)
+}
+
+// LateUpdater is implemented by components that need to run once per frame after every Update, e.g. cameras following something
+type LateUpdater interface {
+	LateUpdate(deltaTime float32)
+}
+
+// FixedUpdater is implemented by components that need to run at a fixed rate independent of frame rate, e.g. physics
+type FixedUpdater interface {
+	FixedUpdate(fixedDeltaTime float32)
+}
+
+// Destroyer is implemented by components that need to clean up when their scene object is destroyed
+type Destroyer interface {
+	OnDestroy()
+}
+
+// Enabler is implemented by components that need to know when they become active
+type Enabler interface {
+	OnEnable()
+}
+
+// Disabler is implemented by components that need to know when they become inactive
+type Disabler interface {
+	OnDisable()
+}
@@ -2,7 +2,26 @@ package core
 
 // Scene represents a collection objects that we wish to logically group together, such as in a level or level section
 type Scene struct {
-	rootObjects []*SceneObject // The scene objects
+	rootObjects []*SceneObject                // The scene objects
+	states      map[Component]*lifecycleState // Tracks which lifecycle callbacks each component has received
+	pending     []pendingComponent            // Scratch buffer reused by refresh
+	active      []Component                   // The components that were active at the last refresh, in hierarchy order
+	refreshID   uint64                        // Incremented on every refresh, used to spot components that left the scene
+}
+
+// lifecycleState tracks a single component's progress through the lifecycle
+type lifecycleState struct {
+	awoken   bool
+	started  bool
+	active   bool
+	lastSeen uint64
+}
+
+// pendingComponent is a component found while walking the hierarchy along with whether it should now be active
+type pendingComponent struct {
+	component Component
+	state     *lifecycleState
+	active    bool
 }
 
 // AddSceneObject adds an object to the scene
@@ -10,7 +29,8 @@ func (sc *Scene) AddSceneObject(sceneObject *SceneObject) {
 	sc.rootObjects = append(sc.rootObjects, sceneObject)
 }
 
-// RemoveSceneObject removes an object from the scene
+// RemoveSceneObject removes an object from the scene. It's components receive OnDisable at the next update and are
+// treated as new (and so awoken again) if the object is ever added back
 func (sc *Scene) RemoveSceneObject(sceneObject *SceneObject) {
 	j := 0
 	for _, so := range sc.rootObjects {
@@ -22,6 +42,57 @@ func (sc *Scene) RemoveSceneObject(sceneObject *SceneObject) {
 	sc.rootObjects = sc.rootObjects[:j]
 }
 
+// Destroy removes an object and all of it's children from the scene. Active components receive OnDisable and every
+// component that was awoken receives OnDestroy
+func (sc *Scene) Destroy(sceneObject *SceneObject) {
+	if sceneObject.Transform != nil && sceneObject.Transform.Parent() != nil {
+		sceneObject.Transform.SetParent(nil, false)
+	} else {
+		sc.RemoveSceneObject(sceneObject)
+	}
+	sc.destroyHierarchy(sceneObject)
+}
+
+// Unload destroys every object in the scene, so components that registered themselves somewhere global (such as
+// lights) get their OnDisable before the scene is dropped. The scene is empty and can be reused afterwards
+func (sc *Scene) Unload() {
+	for _, so := range sc.rootObjects {
+		sc.destroyHierarchy(so)
+	}
+	sc.rootObjects = nil
+	sc.active = sc.active[:0]
+}
+
+// FixedUpdate sends FixedUpdate to every active component. Call it zero or more times per frame before Update
+func (sc *Scene) FixedUpdate(fixedDeltaTime float32) {
+	sc.refresh()
+	for _, comp := range sc.active {
+		if fu, ok := comp.(FixedUpdater); ok && sc.isStillActive(comp) {
+			fu.FixedUpdate(fixedDeltaTime)
+		}
+	}
+}
+
+// Update runs any pending Awake, OnEnable, OnDisable and Start callbacks then sends Update to every active component.
+// Call it once per frame
+func (sc *Scene) Update(deltaTime float32) {
+	sc.refresh()
+	for _, comp := range sc.active {
+		if u, ok := comp.(Updater); ok && sc.isStillActive(comp) {
+			u.Update(deltaTime)
+		}
+	}
+}
+
+// LateUpdate sends LateUpdate to every component that was active during Update. Call it once per frame after Update
+func (sc *Scene) LateUpdate(deltaTime float32) {
+	for _, comp := range sc.active {
+		if lu, ok := comp.(LateUpdater); ok && sc.isStillActive(comp) {
+			lu.LateUpdate(deltaTime)
+		}
+	}
+}
+
 // Render Renders the scene and implements the Renderable interface
 func (sc *Scene) Render() error {
 	for _, so := range sc.rootObjects {
@@ -32,3 +103,116 @@ func (sc *Scene) Render() error {
 	}
 	return nil
 }
+
+// refresh walks the hierarchy and brings every component's lifecycle up to date
+func (sc *Scene) refresh() {
+	if sc.states == nil {
+		sc.states = make(map[Component]*lifecycleState)
+	}
+	sc.refreshID++
+	sc.pending = sc.pending[:0]
+	for _, so := range sc.rootObjects {
+		sc.collect(so, true)
+	}
+
+	// Awake everything new first so Start and OnEnable can rely on every other component being initialized
+	for _, p := range sc.pending {
+		if !p.state.awoken {
+			p.state.awoken = true
+			if a, ok := p.component.(Awaker); ok {
+				a.Awake()
+			}
+		}
+	}
+
+	// Components that have left the scene since the last refresh
+	for comp, state := range sc.states {
+		if state.lastSeen != sc.refreshID {
+			if d, ok := comp.(Disabler); ok && state.active {
+				d.OnDisable()
+			}
+			delete(sc.states, comp)
+		}
+	}
+
+	for _, p := range sc.pending {
+		if p.active == p.state.active {
+			continue
+		}
+		p.state.active = p.active
+		if p.active {
+			if e, ok := p.component.(Enabler); ok {
+				e.OnEnable()
+			}
+		} else if d, ok := p.component.(Disabler); ok {
+			d.OnDisable()
+		}
+	}
+
+	sc.active = sc.active[:0]
+	for _, p := range sc.pending {
+		if !p.active {
+			continue
+		}
+		if !p.state.started {
+			p.state.started = true
+			if s, ok := p.component.(Starter); ok {
+				s.Start()
+			}
+		}
+		sc.active = append(sc.active, p.component)
+	}
+}
+
+// collect appends the components of sceneObject and it's children to the pending list
+func (sc *Scene) collect(sceneObject *SceneObject, parentActive bool) {
+	active := parentActive && sceneObject.Enabled()
+	for _, comp := range sceneObject.Components {
+		state, ok := sc.states[comp]
+		if !ok {
+			state = new(lifecycleState)
+			sc.states[comp] = state
+		}
+		state.lastSeen = sc.refreshID
+		sc.pending = append(sc.pending, pendingComponent{comp, state, active && comp.Enabled()})
+	}
+
+	if sceneObject.Transform == nil {
+		return
+	}
+	for _, child := range sceneObject.Transform.Children() {
+		sc.collect(child.SceneObject(), active)
+	}
+}
+
+// destroyHierarchy sends OnDisable and OnDestroy to the components of sceneObject and it's children
+func (sc *Scene) destroyHierarchy(sceneObject *SceneObject) {
+	for _, comp := range sceneObject.Components {
+		state, ok := sc.states[comp]
+		if !ok {
+			continue
+		}
+		delete(sc.states, comp)
+		if d, ok := comp.(Disabler); ok && state.active {
+			d.OnDisable()
+		}
+		if d, ok := comp.(Destroyer); ok && state.awoken {
+			d.OnDestroy()
+		}
+	}
+
+	if sceneObject.Transform == nil {
+		return
+	}
+	for _, child := range sceneObject.Transform.Children() {
+		sc.destroyHierarchy(child.SceneObject())
+	}
+}
+
+// isStillActive catches components that were disabled, detached or destroyed by an earlier callback in the same pass
+func (sc *Scene) isStillActive(comp Component) bool {
+	if _, ok := sc.states[comp]; !ok {
+		return false
+	}
+	return comp.Enabled() && comp.SceneObject() != nil && comp.SceneObject().ActiveInHierarchy()
+}
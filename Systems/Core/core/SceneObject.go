@@ -5,6 +5,7 @@ type SceneObject struct {
 	Components []Component         // The list of components attached to this scene object
 	Transform  *TransformComponent // The tranform representing this object's location in the world
 	Renderer   RenderableComponent // The renderer associated with this object
+	disabled   bool                // Disabled objects, and all of their children, are neither updated nor rendered
 }
 
 // CreateSceneObject is the standard constructor for a SceneObject
@@ -27,9 +28,37 @@ func (so *SceneObject) RemoveComponent(component Component) {
 	component.Detach()
 }
 
+// Enabled returns false if this object has been switched off. Children of a disabled object are also inactive
+func (so *SceneObject) Enabled() bool {
+	return !so.disabled
+}
+
+// SetEnabled setter for Enabled()
+func (so *SceneObject) SetEnabled(enabled bool) {
+	so.disabled = !enabled
+}
+
+// ActiveInHierarchy returns true if this object and all of it's parents are enabled
+func (so *SceneObject) ActiveInHierarchy() bool {
+	for cur := so; cur != nil; {
+		if cur.disabled {
+			return false
+		}
+		if cur.Transform == nil || cur.Transform.Parent() == nil {
+			break
+		}
+		cur = cur.Transform.Parent().SceneObject()
+	}
+	return true
+}
+
 // Render implements the renderable interface
 func (so *SceneObject) Render() error {
-	if so.Renderer != nil {
+	if so.disabled {
+		return nil
+	}
+
+	if so.Renderer != nil && so.Renderer.Enabled() {
 		err := so.Renderer.Render()
 		if err != nil {
 			return err
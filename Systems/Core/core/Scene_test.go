@@ -0,0 +1,219 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+// lifecycleTestComponent records every lifecycle callback it gets as "<name> <callback>"
+type lifecycleTestComponent struct {
+	*BaseComponent
+	name   string
+	events *[]string
+	update func() // Run during Update if set
+}
+
+// createLifecycleTestComponent attaches a lifecycleTestComponent called name to sceneObject
+func createLifecycleTestComponent(sceneObject *SceneObject, name string, events *[]string) *lifecycleTestComponent {
+	comp := &lifecycleTestComponent{name: name, events: events}
+	comp.BaseComponent = CreateBaseComponent(comp)
+	sceneObject.AddComponent(comp)
+	return comp
+}
+
+func (comp *lifecycleTestComponent) record(callback string) {
+	*comp.events = append(*comp.events, comp.name+" "+callback)
+}
+
+func (comp *lifecycleTestComponent) Awake()     { comp.record("Awake") }
+func (comp *lifecycleTestComponent) Start()     { comp.record("Start") }
+func (comp *lifecycleTestComponent) OnEnable()  { comp.record("OnEnable") }
+func (comp *lifecycleTestComponent) OnDisable() { comp.record("OnDisable") }
+func (comp *lifecycleTestComponent) OnDestroy() { comp.record("OnDestroy") }
+func (comp *lifecycleTestComponent) FixedUpdate(fixedDeltaTime float32) {
+	comp.record("FixedUpdate")
+}
+func (comp *lifecycleTestComponent) LateUpdate(deltaTime float32) { comp.record("LateUpdate") }
+func (comp *lifecycleTestComponent) Update(deltaTime float32) {
+	comp.record("Update")
+	if comp.update != nil {
+		comp.update()
+	}
+}
+
+// runFrame runs one frame of scene and returns the events it caused
+func runFrame(scene *Scene, events *[]string, fixedSteps int) string {
+	*events = (*events)[:0]
+	for i := 0; i < fixedSteps; i++ {
+		scene.FixedUpdate(0.02)
+	}
+	scene.Update(0.016)
+	scene.LateUpdate(0.016)
+	return strings.Join(*events, ", ")
+}
+
+// createChild makes a scene object parented to parent
+func createChild(parent *SceneObject) *SceneObject {
+	child := CreateSceneObject(nil)
+	child.Transform.SetParent(parent.Transform, false)
+	return child
+}
+
+func TestSceneLifecycleOrder(t *testing.T) {
+	var events []string
+	scene := new(Scene)
+	root := CreateSceneObject(nil)
+	createLifecycleTestComponent(root, "a", &events)
+	createLifecycleTestComponent(createChild(root), "b", &events)
+	disabled := createLifecycleTestComponent(root, "c", &events)
+	disabled.SetEnabled(false)
+	scene.AddSceneObject(root)
+
+	frames := []struct {
+		name       string
+		fixedSteps int
+		expected   string
+	}{
+		// Everything is awoken, even disabled components, before anything is enabled or started
+		{"first frame", 1, "a Awake, c Awake, b Awake, a OnEnable, b OnEnable, a Start, b Start, a FixedUpdate, b FixedUpdate, " +
+			"a Update, b Update, a LateUpdate, b LateUpdate"},
+		{"no fixed steps", 0, "a Update, b Update, a LateUpdate, b LateUpdate"},
+		{"two fixed steps", 2, "a FixedUpdate, b FixedUpdate, a FixedUpdate, b FixedUpdate, a Update, b Update, a LateUpdate, b LateUpdate"},
+	}
+	for _, frame := range frames {
+		if got := runFrame(scene, &events, frame.fixedSteps); got != frame.expected {
+			t.Errorf("%v: got %v\nexpected %v", frame.name, got, frame.expected)
+		}
+	}
+
+	// Enabled late, it's started then and only then
+	disabled.SetEnabled(true)
+	if got, expected := runFrame(scene, &events, 0), "c OnEnable, c Start, a Update, c Update, b Update, a LateUpdate, c LateUpdate, b LateUpdate"; got != expected {
+		t.Errorf("enabling c: got %v\nexpected %v", got, expected)
+	}
+	disabled.SetEnabled(false)
+	disabled.SetEnabled(true)
+	if got, expected := runFrame(scene, &events, 0), "a Update, c Update, b Update, a LateUpdate, c LateUpdate, b LateUpdate"; got != expected {
+		t.Errorf("disabling and enabling c in one frame: got %v\nexpected %v", got, expected)
+	}
+}
+
+func TestSceneInactiveParents(t *testing.T) {
+	var events []string
+	scene := new(Scene)
+	root := CreateSceneObject(nil)
+	middle := createChild(root)
+	createLifecycleTestComponent(createChild(middle), "leaf", &events)
+	scene.AddSceneObject(root)
+	middle.SetEnabled(false)
+
+	steps := []struct {
+		name     string
+		change   func()
+		expected string
+	}{
+		// Awoken but not enabled or started while a parent is inactive
+		{"under a disabled parent", func() {}, "leaf Awake"},
+		{"parent enabled", func() { middle.SetEnabled(true) }, "leaf OnEnable, leaf Start, leaf Update, leaf LateUpdate"},
+		{"root disabled", func() { root.SetEnabled(false) }, "leaf OnDisable"},
+		// Not awoken or started a second time
+		{"root enabled", func() { root.SetEnabled(true) }, "leaf OnEnable, leaf Update, leaf LateUpdate"},
+	}
+	for _, step := range steps {
+		step.change()
+		if got := runFrame(scene, &events, 0); got != step.expected {
+			t.Errorf("%v: got %v\nexpected %v", step.name, got, step.expected)
+		}
+	}
+}
+
+func TestSceneRemoveAndDestroy(t *testing.T) {
+	var events []string
+	scene := new(Scene)
+	removed := CreateSceneObject(nil)
+	createLifecycleTestComponent(removed, "removed", &events)
+	destroyed := CreateSceneObject(nil)
+	createLifecycleTestComponent(destroyed, "destroyed", &events)
+	createLifecycleTestComponent(createChild(destroyed), "child", &events)
+	scene.AddSceneObject(removed)
+	scene.AddSceneObject(destroyed)
+	runFrame(scene, &events, 0)
+
+	// Removing is deferred to the next update, destroying happens straight away
+	events = events[:0]
+	scene.RemoveSceneObject(removed)
+	scene.Destroy(destroyed)
+	if got, expected := strings.Join(events, ", "), "destroyed OnDisable, destroyed OnDestroy, child OnDisable, child OnDestroy"; got != expected {
+		t.Errorf("destroying: got %v\nexpected %v", got, expected)
+	}
+	if got, expected := runFrame(scene, &events, 0), "removed OnDisable"; got != expected {
+		t.Errorf("the frame after: got %v\nexpected %v", got, expected)
+	}
+
+	// Added back, a removed object is treated as new
+	scene.AddSceneObject(removed)
+	if got, expected := runFrame(scene, &events, 0), "removed Awake, removed OnEnable, removed Start, removed Update, removed LateUpdate"; got != expected {
+		t.Errorf("adding back: got %v\nexpected %v", got, expected)
+	}
+}
+
+func TestSceneDestroyDuringUpdate(t *testing.T) {
+	var events []string
+	scene := new(Scene)
+	first := CreateSceneObject(nil)
+	second := CreateSceneObject(nil)
+	third := CreateSceneObject(nil)
+	killer := createLifecycleTestComponent(first, "first", &events)
+	createLifecycleTestComponent(second, "second", &events)
+	disabler := createLifecycleTestComponent(third, "third", &events)
+	for _, so := range []*SceneObject{first, second, third} {
+		scene.AddSceneObject(so)
+	}
+	runFrame(scene, &events, 0)
+
+	// Components destroyed or disabled by an earlier callback miss the rest of the frame
+	killer.update = func() {
+		scene.Destroy(second)
+		disabler.SetEnabled(false)
+		killer.update = nil
+	}
+	expected := "first Update, second OnDisable, second OnDestroy, first LateUpdate"
+	if got := runFrame(scene, &events, 0); got != expected {
+		t.Errorf("got %v\nexpected %v", got, expected)
+	}
+	if got, expected := runFrame(scene, &events, 0), "third OnDisable, first Update, first LateUpdate"; got != expected {
+		t.Errorf("the frame after: got %v\nexpected %v", got, expected)
+	}
+	for _, comp := range second.Components {
+		if _, ok := scene.states[comp]; ok {
+			t.Errorf("got %T from the destroyed object still tracked by the scene", comp)
+		}
+	}
+}
+
+func TestSceneUnload(t *testing.T) {
+	var events []string
+	scene := new(Scene)
+	root := CreateSceneObject(nil)
+	createLifecycleTestComponent(root, "root", &events)
+	createLifecycleTestComponent(createChild(root), "child", &events)
+	disabled := createLifecycleTestComponent(CreateSceneObject(nil), "disabled", &events)
+	disabled.SetEnabled(false)
+	scene.AddSceneObject(root)
+	scene.AddSceneObject(disabled.SceneObject())
+	runFrame(scene, &events, 0)
+
+	// Only active components get OnDisable, but everything awoken gets OnDestroy
+	events = events[:0]
+	scene.Unload()
+	expected := "root OnDisable, root OnDestroy, child OnDisable, child OnDestroy, disabled OnDestroy"
+	if got := strings.Join(events, ", "); got != expected {
+		t.Errorf("got %v\nexpected %v", got, expected)
+	}
+	if got := runFrame(scene, &events, 0); got != "" {
+		t.Errorf("the frame after: got %v, expected nothing", got)
+	}
+	if len(scene.states) != 0 {
+		t.Errorf("got %v components still tracked, expected 0", len(scene.states))
+	}
+}
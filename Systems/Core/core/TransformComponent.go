@@ -1,15 +1,15 @@
 package core
 
 import (
-	gomath "math"
+	"errors"
 
 	"github.com/Surreal/Math/math"
 )
 
 // Used internally to logically group a cached matrix
 type cachedMatrix struct {
-	Cache   *math.StandardMatrix // The cached matrix
-	IsDirty bool                 // Does it need recomputing
+	Cache   math.Matrix4x4 // The cached matrix
+	IsDirty bool           // Does it need recomputing
 }
 
 // TransformComponent represents an object's state in the 3D world
@@ -18,7 +18,7 @@ type TransformComponent struct {
 	parent                  *TransformComponent   // The parent to this transform, nil if nothing
 	children                []*TransformComponent // The list of children to this transform
 	position                math.Vector3f         // The 3D local position of this object
-	rotation                math.Vector3f         // The 3D local rotation of this object in euler angles
+	rotation                math.Quaternion       // The 3D local rotation of this object
 	scale                   math.Vector3f         // The 3D local scale of this object % original size
 	cachedModel2WorldMatrix cachedMatrix          // Caches the last known Model2World
 	cachedWorld2ModelMatrix cachedMatrix          // Caches the last known World2Model
@@ -29,18 +29,18 @@ type TransformComponent struct {
 // CreateTransformComponent is the standard constructor for a TransformComponent
 func CreateTransformComponent() *TransformComponent {
 	tc := new(TransformComponent)
-	tc.BaseComponent = new(BaseComponent)
+	tc.BaseComponent = CreateBaseComponent(tc)
 	tc.parent = nil
 	tc.position = math.ZeroVector3f()
-	tc.rotation = math.ZeroVector3f()
+	tc.rotation = math.IdentityQuaternion()
 	tc.scale = math.OnesVector3f()
-	tc.cachedModel2WorldMatrix.Cache = math.StandardMatrixIdentity(4, 4)
+	tc.cachedModel2WorldMatrix.Cache = math.Matrix4x4Identity()
 	tc.cachedModel2WorldMatrix.IsDirty = true
-	tc.cachedWorld2ModelMatrix.Cache = math.StandardMatrixIdentity(4, 4)
+	tc.cachedWorld2ModelMatrix.Cache = math.Matrix4x4Identity()
 	tc.cachedWorld2ModelMatrix.IsDirty = true
-	tc.cachedModel2OtherMatrix.Cache = math.StandardMatrixIdentity(4, 4)
+	tc.cachedModel2OtherMatrix.Cache = math.Matrix4x4Identity()
 	tc.cachedModel2OtherMatrix.IsDirty = true
-	tc.cachedOther2ModelMatrix.Cache = math.StandardMatrixIdentity(4, 4)
+	tc.cachedOther2ModelMatrix.Cache = math.Matrix4x4Identity()
 	tc.cachedOther2ModelMatrix.IsDirty = true
 	return tc
 }
@@ -71,17 +71,39 @@ func (tc *TransformComponent) SetLocalPosition(value math.Vector3f) {
 	tc.markAsDirty()
 }
 
-// LocalRotation returns the object's current local rotation relative to it's parent
+// LocalRotation returns the object's current local rotation relative to it's parent as euler angles in degrees.
+// The angles are recomputed from the stored quaternion so they may differ from what was last set
 func (tc *TransformComponent) LocalRotation() math.Vector3f {
-	return tc.rotation
+	return tc.rotation.ToEuler(math.DefaultEulerOrder)
 }
 
-// SetLocalRotation setter for LocalRotation()
+// SetLocalRotation sets the local rotation from euler angles in degrees applied in math.DefaultEulerOrder
 func (tc *TransformComponent) SetLocalRotation(value math.Vector3f) {
-	tc.rotation = value
+	tc.SetLocalRotationQuaternion(math.QuaternionFromEuler(value, math.DefaultEulerOrder))
+}
+
+// LocalRotationQuaternion returns the object's current local rotation relative to it's parent
+func (tc *TransformComponent) LocalRotationQuaternion() math.Quaternion {
+	return tc.rotation
+}
+
+// SetLocalRotationQuaternion setter for LocalRotationQuaternion()
+func (tc *TransformComponent) SetLocalRotationQuaternion(value math.Quaternion) {
+	tc.rotation = value.Normalize()
 	tc.markAsDirty()
 }
 
+// Rotate applies rotation on top of the current local rotation, relative to the parent's axes
+func (tc *TransformComponent) Rotate(rotation math.Quaternion) {
+	tc.SetLocalRotationQuaternion(rotation.Mul(tc.rotation))
+}
+
+// LookAt rotates the transform so it's forward axis (-Z) points at target with it's up axis as close to up as possible.
+// target and up are in world space
+func (tc *TransformComponent) LookAt(target math.Vector3f, up math.Vector3f) {
+	tc.SetWorldRotation(math.LookRotation(target.Sub(tc.WorldPosition()), up))
+}
+
 // LocalScale returns the object's current local scale relative to it's parent
 func (tc *TransformComponent) LocalScale() math.Vector3f {
 	return tc.scale
@@ -93,15 +115,134 @@ func (tc *TransformComponent) SetLocalScale(value math.Vector3f) {
 	tc.markAsDirty()
 }
 
+// WorldPosition returns the object's position in world space
+func (tc *TransformComponent) WorldPosition() math.Vector3f {
+	return tc.Model2WorldMatrix().Translation()
+}
+
+// SetWorldPosition moves the object to a position in world space
+func (tc *TransformComponent) SetWorldPosition(value math.Vector3f) {
+	if tc.parent != nil {
+		value = tc.parent.InverseTransformPoint(value)
+	}
+	tc.SetLocalPosition(value)
+}
+
+// WorldRotation returns the object's rotation in world space
+func (tc *TransformComponent) WorldRotation() math.Quaternion {
+	if tc.parent != nil {
+		return tc.parent.WorldRotation().Mul(tc.rotation)
+	}
+	return tc.rotation
+}
+
+// SetWorldRotation rotates the object to a rotation in world space
+func (tc *TransformComponent) SetWorldRotation(value math.Quaternion) {
+	if tc.parent != nil {
+		value = tc.parent.WorldRotation().Inverse().Mul(value)
+	}
+	tc.SetLocalRotationQuaternion(value)
+}
+
+// WorldScale returns the object's scale in world space.
+// NOTE: Rotated children of non uniformly scaled parents are skewed, which a scale vector can't describe. This is the closest fit
+func (tc *TransformComponent) WorldScale() math.Vector3f {
+	if tc.parent != nil {
+		return tc.parent.WorldScale().Mul(tc.scale)
+	}
+	return tc.scale
+}
+
+// SetWorldScale scales the object to a scale in world space. Has the same limitations as WorldScale()
+func (tc *TransformComponent) SetWorldScale(value math.Vector3f) {
+	if tc.parent != nil {
+		value = value.Div(tc.parent.WorldScale())
+	}
+	tc.SetLocalScale(value)
+}
+
+// Forward returns the direction the object faces (it's local -Z) in world space
+func (tc *TransformComponent) Forward() math.Vector3f {
+	return tc.WorldRotation().RotateVector(math.ForwardVector3f())
+}
+
+// Right returns the object's local +X axis in world space
+func (tc *TransformComponent) Right() math.Vector3f {
+	return tc.WorldRotation().RotateVector(math.RightVector3f())
+}
+
+// Up returns the object's local +Y axis in world space
+func (tc *TransformComponent) Up() math.Vector3f {
+	return tc.WorldRotation().RotateVector(math.UpVector3f())
+}
+
+// TransformPoint converts a point from this object's model space to world space
+func (tc *TransformComponent) TransformPoint(point math.Vector3f) math.Vector3f {
+	return tc.Model2WorldMatrix().TransformPoint(point)
+}
+
+// InverseTransformPoint converts a point from world space to this object's model space
+func (tc *TransformComponent) InverseTransformPoint(point math.Vector3f) math.Vector3f {
+	return tc.World2ModelMatrix().TransformPoint(point)
+}
+
+// TransformDirection converts a direction from this object's model space to world space. Scale is ignored
+func (tc *TransformComponent) TransformDirection(direction math.Vector3f) math.Vector3f {
+	return tc.WorldRotation().RotateVector(direction)
+}
+
+// InverseTransformDirection converts a direction from world space to this object's model space. Scale is ignored
+func (tc *TransformComponent) InverseTransformDirection(direction math.Vector3f) math.Vector3f {
+	return tc.WorldRotation().Inverse().RotateVector(direction)
+}
+
 // Parent returns the object's current parent
 func (tc *TransformComponent) Parent() *TransformComponent {
 	return tc.parent
 }
 
-// SetParent setter for Parent
-func (tc *TransformComponent) SetParent(parent *TransformComponent) {
-	parent.children = append(parent.children, tc)
+// SetParent moves this transform under parent. Passing nil makes this a root transform.
+// If keepWorldTransform is true the local values are adjusted so the object stays put in the world, otherwise
+// the local values are kept and the object moves with it's new parent
+// Returns an error if parent is this transform or one of it's descendants
+func (tc *TransformComponent) SetParent(parent *TransformComponent, keepWorldTransform bool) error {
+	if parent == tc.parent {
+		return nil
+	}
+
+	for ancestor := parent; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor == tc {
+			return errors.New("Invalid Operation: Cannot parent a transform to itself or one of it's descendants")
+		}
+	}
+
+	worldPosition, worldRotation, worldScale := tc.WorldPosition(), tc.WorldRotation(), tc.WorldScale()
+
+	// Remove from the old parent's children without memory allocation
+	if tc.parent != nil {
+		j := 0
+		for _, child := range tc.parent.children {
+			if child != tc {
+				tc.parent.children[j] = child
+				j++
+			}
+		}
+		tc.parent.children[j] = nil
+		tc.parent.children = tc.parent.children[:j]
+	}
+
 	tc.parent = parent
+	if parent != nil {
+		parent.children = append(parent.children, tc)
+	}
+
+	if keepWorldTransform {
+		tc.SetWorldScale(worldScale)
+		tc.SetWorldRotation(worldRotation)
+		tc.SetWorldPosition(worldPosition)
+	}
+	tc.markAsDirty()
+	return nil
 }
 
 // Children gets all children of this transform
@@ -109,150 +250,77 @@ func (tc *TransformComponent) Children() []*TransformComponent {
 	return tc.children
 }
 
-// AddChild adds a child to this transform. this is shortform for child.SetParent()
-func (tc *TransformComponent) AddChild(child *TransformComponent) {
-	child.SetParent(tc)
+// AddChild adds a child to this transform. this is shortform for child.SetParent(tc, false)
+func (tc *TransformComponent) AddChild(child *TransformComponent) error {
+	return child.SetParent(tc, false)
 }
 
 // Model2OtherMatrix returns the matrix to convert from model space to another space
-// TODO: Precomputed formula for SRT similar to rotation?
-func (tc *TransformComponent) Model2OtherMatrix() *math.StandardMatrix {
+func (tc *TransformComponent) Model2OtherMatrix() *math.Matrix4x4 {
 	// Look for cache
 	if !tc.cachedModel2OtherMatrix.IsDirty {
-		return tc.cachedModel2OtherMatrix.Cache
-	}
-
-	rotationMatrix := math.StandardMatrixZeros(4, 4)
-	cx := gomath.Cos(float64(tc.rotation.X * math.Deg2Rad))
-	cy := gomath.Cos(float64(tc.rotation.Y * math.Deg2Rad))
-	cz := gomath.Cos(float64(tc.rotation.Z * math.Deg2Rad))
-	sx := gomath.Sin(float64(tc.rotation.X * math.Deg2Rad))
-	sy := gomath.Sin(float64(tc.rotation.Y * math.Deg2Rad))
-	sz := gomath.Sin(float64(tc.rotation.Z * math.Deg2Rad))
-	rotationMatrix.Set(0, 0, float32(cy*cz))
-	rotationMatrix.Set(1, 0, float32(-cy*sz))
-	rotationMatrix.Set(2, 0, float32(sy))
-	rotationMatrix.Set(0, 1, float32(sx*sy*cz+cx*sz))
-	rotationMatrix.Set(1, 1, float32(-sx*sy*sz+cx*cz))
-	rotationMatrix.Set(2, 1, float32(-sx*cy))
-	rotationMatrix.Set(0, 2, float32(-cx*sy*cz+sx*sz))
-	rotationMatrix.Set(1, 2, float32(cx*sy*sz+sx*cz))
-	rotationMatrix.Set(2, 2, float32(cx*cy))
-	rotationMatrix.Set(3, 3, 1)
-
-	// Note matrices are column major so we are writing the transpose out
-	scaleData := []float32{
-		tc.scale.X, 0, 0, 0,
-		0, tc.scale.Y, 0, 0,
-		0, 0, tc.scale.Z, 0,
-		0, 0, 0, 1,
-	}
-	scaleMatrix := math.CreateStandardMatrix(scaleData, 4, 4)
-
-	// Transpose because column major
-	transformData := []float32{
-		1, 0, 0, 0,
-		0, 1, 0, 0,
-		0, 0, 1, 0,
-		tc.position.X, tc.position.Y, tc.position.Z, 1,
+		return &tc.cachedModel2OtherMatrix.Cache
 	}
-	transformMatrix := math.CreateStandardMatrix(transformData, 4, 4)
 
 	// Compute model matrix
-	matSRT := transformMatrix.MulM(rotationMatrix.MulM(scaleMatrix))
-
-	tc.cachedModel2OtherMatrix.Cache = matSRT
+	tc.cachedModel2OtherMatrix.Cache = math.Matrix4x4TRS(tc.position, tc.rotation, tc.scale)
 	tc.cachedModel2OtherMatrix.IsDirty = false
-	return matSRT
+	return &tc.cachedModel2OtherMatrix.Cache
 }
 
 // Other2ModelMatrix returns the matrix to convert from another space to model space
-func (tc *TransformComponent) Other2ModelMatrix() *math.StandardMatrix {
+func (tc *TransformComponent) Other2ModelMatrix() *math.Matrix4x4 {
 	// Look for cache
 	if !tc.cachedOther2ModelMatrix.IsDirty {
-		return tc.cachedOther2ModelMatrix.Cache
+		return &tc.cachedOther2ModelMatrix.Cache
 	}
 
-	rotationMatrix := math.StandardMatrixZeros(4, 4)
-	cx := gomath.Cos(float64(tc.rotation.X * math.Deg2Rad))
-	cy := gomath.Cos(float64(tc.rotation.Y * math.Deg2Rad))
-	cz := gomath.Cos(float64(tc.rotation.Z * math.Deg2Rad))
-	sx := gomath.Sin(float64(tc.rotation.X * math.Deg2Rad))
-	sy := gomath.Sin(float64(tc.rotation.Y * math.Deg2Rad))
-	sz := gomath.Sin(float64(tc.rotation.Z * math.Deg2Rad))
-	rotationMatrix.Set(0, 0, float32(cy*cz))
-	rotationMatrix.Set(1, 0, float32(-cy*sz))
-	rotationMatrix.Set(2, 0, float32(sy))
-	rotationMatrix.Set(0, 1, float32(sx*sy*cz+cx*sz))
-	rotationMatrix.Set(1, 1, float32(-sx*sy*sz+cx*cz))
-	rotationMatrix.Set(2, 1, float32(-sx*cy))
-	rotationMatrix.Set(0, 2, float32(-cx*sy*cz+sx*sz))
-	rotationMatrix.Set(1, 2, float32(cx*sy*sz+sx*cz))
-	rotationMatrix.Set(2, 2, float32(cx*cy))
-	rotationMatrix.Set(3, 3, 1)
-
-	// Note matrices are column major so we are writing the transpose out
-	scaleData := []float32{
-		1 / tc.scale.X, 0, 0, 0,
-		0, 1 / tc.scale.Y, 0, 0,
-		0, 0, 1 / tc.scale.Z, 0,
-		0, 0, 0, 1,
-	}
-	scaleMatrix := math.CreateStandardMatrix(scaleData, 4, 4)
-
-	// Transpose because column major
-	transformData := []float32{
-		1, 0, 0, 0,
-		0, 1, 0, 0,
-		0, 0, 1, 0,
-		-tc.position.X, -tc.position.Y, -tc.position.Z, 1,
+	inverse, err := tc.Model2OtherMatrix().Inverse()
+	if err != nil {
+		// A scale of 0 collapses the object so there's no way back into model space
+		inverse = math.Matrix4x4{}
 	}
-	transformMatrix := math.CreateStandardMatrix(transformData, 4, 4)
-
-	// Compute model matrix
-	matSRT := transformMatrix.MulM(rotationMatrix.MulM(scaleMatrix))
 
-	tc.cachedOther2ModelMatrix.Cache = matSRT
+	mat := &tc.cachedOther2ModelMatrix.Cache
+	*mat = inverse
 	tc.cachedOther2ModelMatrix.IsDirty = false
-	return matSRT
+	return mat
 }
 
 // Model2WorldMatrix returns the matrix to convert from model space to world space
-func (tc *TransformComponent) Model2WorldMatrix() *math.StandardMatrix {
+func (tc *TransformComponent) Model2WorldMatrix() *math.Matrix4x4 {
 	// Try to hit the cache
 	if !tc.cachedModel2WorldMatrix.IsDirty {
-		return tc.cachedModel2WorldMatrix.Cache
+		return &tc.cachedModel2WorldMatrix.Cache
 	}
 
-	var mat *math.StandardMatrix
+	mat := &tc.cachedModel2WorldMatrix.Cache
 	// Recursion is overpowered and needs a nerf
 	if tc.Parent() != nil {
-		mat = tc.Parent().Model2WorldMatrix().MulM(tc.Model2OtherMatrix())
+		mat.MulInto(tc.Parent().Model2WorldMatrix(), tc.Model2OtherMatrix())
 	} else {
-		mat = tc.Model2OtherMatrix()
+		*mat = *tc.Model2OtherMatrix()
 	}
 
-	tc.cachedModel2WorldMatrix.Cache = mat
 	tc.cachedModel2WorldMatrix.IsDirty = false
 	return mat
 }
 
 // World2ModelMatrix returns the matrix to convert from world space to model space
-func (tc *TransformComponent) World2ModelMatrix() *math.StandardMatrix {
+func (tc *TransformComponent) World2ModelMatrix() *math.Matrix4x4 {
 	// Try to hit the cache
 	if !tc.cachedWorld2ModelMatrix.IsDirty {
-		return tc.cachedWorld2ModelMatrix.Cache
+		return &tc.cachedWorld2ModelMatrix.Cache
 	}
 
-	var mat *math.StandardMatrix
-	// Recursion is overpowered and needs a nerf
-	if tc.Parent() != nil {
-		mat = tc.World2ModelMatrix().MulM(tc.Parent().Other2ModelMatrix())
-	} else {
-		mat = tc.Other2ModelMatrix()
+	inverse, err := tc.Model2WorldMatrix().Inverse()
+	if err != nil {
+		// A scale of 0 somewhere up the hierarchy collapses the object so there's no way back into model space
+		inverse = math.Matrix4x4{}
 	}
 
-	tc.cachedWorld2ModelMatrix.Cache = mat
+	mat := &tc.cachedWorld2ModelMatrix.Cache
+	*mat = inverse
 	tc.cachedWorld2ModelMatrix.IsDirty = false
 	return mat
 }
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/Surreal/Math/math"
+)
+
+// createTransformChain returns the deepest of depth transforms each parented to the last, all moved, rotated and
+// uniformly scaled
+func createTransformChain(depth int) *TransformComponent {
+	var parent *TransformComponent
+	for i := 0; i < depth; i++ {
+		tc := CreateSceneObject(nil).Transform
+		tc.SetLocalPosition(math.Vector3f{X: 1, Y: float32(i), Z: -2})
+		tc.SetLocalRotationQuaternion(math.QuaternionFromAxisAngle(math.UpVector3f(), 15))
+		tc.SetLocalScale(math.Vector3f{X: 1.5, Y: 1.5, Z: 1.5})
+		if parent != nil {
+			tc.SetParent(parent, false)
+		}
+		parent = tc
+	}
+	return parent
+}
+
+func TestTransformMatrixRebuildDoesNotAllocate(t *testing.T) {
+	tc := createTransformChain(4)
+	position := math.Vector3f{}
+	allocations := testing.AllocsPerRun(100, func() {
+		position.X++
+		tc.SetLocalPosition(position)
+		tc.Model2WorldMatrix()
+		tc.World2ModelMatrix()
+	})
+	if allocations != 0 {
+		t.Errorf("Rebuilding a dirty transform's matrices allocated %v times, expected 0", allocations)
+	}
+}
+
+func BenchmarkTransformDirtyRebuild(b *testing.B) {
+	tc := createTransformChain(4)
+	position := math.Vector3f{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		position.X++
+		tc.SetLocalPosition(position)
+		tc.Model2WorldMatrix()
+	}
+}
+
+func BenchmarkTransformDirtyRebuildInverse(b *testing.B) {
+	tc := createTransformChain(4)
+	position := math.Vector3f{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		position.X++
+		tc.SetLocalPosition(position)
+		tc.World2ModelMatrix()
+	}
+}
+
+func BenchmarkTransformDirtyRoot(b *testing.B) {
+	tc := createTransformChain(4)
+	root := tc
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	rotation := math.QuaternionFromAxisAngle(math.UpVector3f(), 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root.Rotate(rotation)
+		tc.Model2WorldMatrix()
+	}
+}
+
+func BenchmarkTransformCached(b *testing.B) {
+	tc := createTransformChain(4)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tc.Model2WorldMatrix()
+	}
+}
+
+// standardMatrixModel2Other builds tc's local matrix the way transforms did before Matrix4x4, multiplying StandardMatrix
+// scale, rotation and translation matrices
+func standardMatrixModel2Other(tc *TransformComponent) *math.StandardMatrix {
+	position, scale := tc.LocalPosition(), tc.LocalScale()
+	rotation := tc.LocalRotationQuaternion().ToMatrix()
+	scaleMatrix := math.CreateStandardMatrix([]float32{
+		scale.X, 0, 0, 0,
+		0, scale.Y, 0, 0,
+		0, 0, scale.Z, 0,
+		0, 0, 0, 1,
+	}, 4, 4)
+	translationMatrix := math.CreateStandardMatrix([]float32{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		position.X, position.Y, position.Z, 1,
+	}, 4, 4)
+	return translationMatrix.MulM(math.CreateStandardMatrix(rotation[:], 4, 4).MulM(scaleMatrix))
+}
+
+// BenchmarkTransformDirtyRebuildStandardMatrix does the same work as BenchmarkTransformDirtyRebuild with StandardMatrix.
+// Like the old cache, the parent's world matrix is only built once
+func BenchmarkTransformDirtyRebuildStandardMatrix(b *testing.B) {
+	tc := createTransformChain(4)
+	parent := standardMatrixModel2Other(tc.Parent())
+	for ancestor := tc.Parent().Parent(); ancestor != nil; ancestor = ancestor.Parent() {
+		parent = standardMatrixModel2Other(ancestor).MulM(parent)
+	}
+	position := math.Vector3f{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		position.X++
+		tc.SetLocalPosition(position)
+		parent.MulM(standardMatrixModel2Other(tc))
+	}
+}
+
+func TestSetParentKeepsWorldTransformDeep(t *testing.T) {
+	tc := createTransformChain(5)
+	other := createTransformChain(3)
+
+	position, rotation, scale := tc.WorldPosition(), tc.WorldRotation(), tc.WorldScale()
+	point := math.Vector3f{X: 0.5, Y: -1, Z: 2}
+	worldPoint := tc.TransformPoint(point)
+
+	if err := tc.SetParent(other, true); err != nil {
+		t.Fatal(err)
+	}
+	if tc.Parent() != other || len(other.Children()) != 1 || other.Children()[0] != tc {
+		t.Fatal("Reparenting didn't update the parent and children")
+	}
+	if !tc.WorldPosition().ApproxEqual(position, 1e-3) {
+		t.Errorf("World position moved from %v to %v", position, tc.WorldPosition())
+	}
+	if !rotation.ApproxEqual(tc.WorldRotation(), 1e-4) {
+		t.Errorf("World rotation changed from %v to %v", rotation, tc.WorldRotation())
+	}
+	if !tc.WorldScale().ApproxEqual(scale, 1e-3) {
+		t.Errorf("World scale changed from %v to %v", scale, tc.WorldScale())
+	}
+	// Scales are uniform so nothing is skewed and points have to stay put too
+	if got := tc.TransformPoint(point); !got.ApproxEqual(worldPoint, 1e-3) {
+		t.Errorf("Model point moved from %v to %v in the world", worldPoint, got)
+	}
+}
+
+func TestSetParentWithoutKeepingWorldTransform(t *testing.T) {
+	parent := CreateSceneObject(nil).Transform
+	parent.SetLocalPosition(math.Vector3f{X: 10})
+	child := CreateSceneObject(nil).Transform
+	child.SetLocalPosition(math.Vector3f{Y: 1})
+
+	if err := child.SetParent(parent, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := child.WorldPosition(); !got.ApproxEqual(math.Vector3f{X: 10, Y: 1}, 1e-5) {
+		t.Errorf("Child should move with it's new parent, it's at %v", got)
+	}
+	if got := child.LocalPosition(); got != (math.Vector3f{Y: 1}) {
+		t.Errorf("Local position changed to %v", got)
+	}
+}
+
+func TestSetParentRejectsCycles(t *testing.T) {
+	root := CreateSceneObject(nil).Transform
+	middle := CreateSceneObject(nil).Transform
+	leaf := CreateSceneObject(nil).Transform
+	middle.SetParent(root, false)
+	leaf.SetParent(middle, false)
+
+	if err := root.SetParent(leaf, true); err == nil {
+		t.Error("Parenting the root to it's grandchild should fail")
+	}
+	if err := middle.SetParent(middle, true); err == nil {
+		t.Error("Parenting a transform to itself should fail")
+	}
+	if root.Parent() != nil || middle.Parent() != root || leaf.Parent() != middle {
+		t.Error("A rejected SetParent changed the hierarchy")
+	}
+}
+
+func TestSetParentNilUnparents(t *testing.T) {
+	tc := createTransformChain(4)
+	parent := tc.Parent()
+	position := tc.WorldPosition()
+
+	if err := tc.SetParent(nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if tc.Parent() != nil {
+		t.Error("Transform still has a parent")
+	}
+	for _, child := range parent.Children() {
+		if child == tc {
+			t.Error("Transform is still in it's old parent's children")
+		}
+	}
+	if !tc.WorldPosition().ApproxEqual(position, 1e-3) || !tc.LocalPosition().ApproxEqual(position, 1e-3) {
+		t.Errorf("Unparented transform should keep world position %v, it has world %v and local %v", position, tc.WorldPosition(), tc.LocalPosition())
+	}
+}
+
+func TestWorldRoundTrips(t *testing.T) {
+	tc := createTransformChain(6)
+	points := []math.Vector3f{{}, {X: 1, Y: 2, Z: 3}, {X: -4, Y: 0.5, Z: 7}}
+	for _, point := range points {
+		if got := tc.InverseTransformPoint(tc.TransformPoint(point)); !got.ApproxEqual(point, 1e-3) {
+			t.Errorf("InverseTransformPoint(TransformPoint(%v)) = %v", point, got)
+		}
+		direction := point.Normalize()
+		if got := tc.InverseTransformDirection(tc.TransformDirection(direction)); !got.ApproxEqual(direction, 1e-4) {
+			t.Errorf("InverseTransformDirection(TransformDirection(%v)) = %v", direction, got)
+		}
+	}
+
+	target := math.Vector3f{X: 3, Y: -2, Z: 8}
+	tc.SetWorldPosition(target)
+	if got := tc.WorldPosition(); !got.ApproxEqual(target, 1e-3) {
+		t.Errorf("SetWorldPosition(%v) then WorldPosition() = %v", target, got)
+	}
+	rotation := math.QuaternionFromAxisAngle(math.UpVector3f(), 70)
+	tc.SetWorldRotation(rotation)
+	if got := tc.WorldRotation(); !got.ApproxEqual(rotation, 1e-4) {
+		t.Errorf("SetWorldRotation(%v) then WorldRotation() = %v", rotation, got)
+	}
+	scale := math.Vector3f{X: 2, Y: 3, Z: 4}
+	tc.SetWorldScale(scale)
+	if got := tc.WorldScale(); !got.ApproxEqual(scale, 1e-3) {
+		t.Errorf("SetWorldScale(%v) then WorldScale() = %v", scale, got)
+	}
+
+	// Moving an ancestor moves the leaf
+	root := tc
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	before := tc.WorldPosition()
+	root.SetLocalPosition(root.LocalPosition().Add(math.Vector3f{Y: 5}))
+	if got := tc.WorldPosition(); !got.ApproxEqual(before.Add(math.Vector3f{Y: 5}), 1e-3) {
+		t.Errorf("Moving the root up by 5 moved the leaf from %v to %v", before, got)
+	}
+}
@@ -0,0 +1,66 @@
+package gfx
+
+import (
+	"github.com/Surreal/Systems/Core/core"
+)
+
+// AssetHandle tracks an asset an AssetLoader is loading. Everything on a handle should only be used from the main
+// thread, callbacks run there too
+type AssetHandle struct {
+	FilePath  string            // The file being loaded
+	done      bool              // True once the asset has finished loading or failed to
+	err       error             // Why loading failed, nil if it hasn't
+	callbacks []func(err error) // Functions to call when loading finishes
+}
+
+// Ready returns true once the asset has loaded and is safe to use
+func (handle *AssetHandle) Ready() bool {
+	return handle.done && handle.err == nil
+}
+
+// Done returns true once loading has finished, whether or not it succeeded
+func (handle *AssetHandle) Done() bool {
+	return handle.done
+}
+
+// Err returns why the asset failed to load, or nil if it hasn't failed (yet)
+func (handle *AssetHandle) Err() error {
+	return handle.err
+}
+
+// OnComplete registers a function to call on the main thread when loading finishes. err is nil if the asset loaded.
+// If loading has already finished callback is called straight away
+func (handle *AssetHandle) OnComplete(callback func(err error)) {
+	if handle.done {
+		callback(handle.err)
+		return
+	}
+	handle.callbacks = append(handle.callbacks, callback)
+}
+
+// complete marks the handle as finished and runs the callbacks
+func (handle *AssetHandle) complete(err error) {
+	handle.done = true
+	handle.err = err
+	callbacks := handle.callbacks
+	handle.callbacks = nil
+	for _, callback := range callbacks {
+		callback(err)
+	}
+}
+
+// TextureHandle is an AssetHandle for a texture. The texture exists straight away, but isn't loaded until Ready
+type TextureHandle struct {
+	*AssetHandle
+	Texture *Texture
+}
+
+// MeshHandle is an AssetHandle for a mesh file. Meshes, Materials and SceneObject are nil until Ready
+type MeshHandle struct {
+	*AssetHandle
+	Meshes      []*Mesh           // One mesh per submesh in the file, or per primitive for glTF
+	Materials   []*Material       // The material for each mesh
+	SceneObject *core.SceneObject // Renders every mesh with it's material
+	Data        []*MeshData       // The CPU side data the meshes were uploaded from, nil for glTF
+	GLTF        *GLTFScene        // Everything else imported from a glTF file, nil for other formats
+}
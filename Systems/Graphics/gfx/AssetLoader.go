@@ -0,0 +1,220 @@
+package gfx
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/Surreal/Debug/dbg"
+)
+
+// DefaultUploadBudget is how long ProcessUploads should spend sending assets to the GPU each frame
+const DefaultUploadBudget = 4 * time.Millisecond
+
+// MainAssetLoader is the global loader. app.Application calls ProcessUploads on it every frame before Game.Update, so
+// only call it yourself if you drive your own loop
+var MainAssetLoader = CreateAssetLoader(runtime.NumCPU() - 1)
+
+// AssetLoader reads and decodes assets on worker goroutines, then queues the openGL work for the main thread. Files
+// are read in parallel but only finish loading when ProcessUploads is called, so a frame is never blocked by disk or
+// decoding, and only briefly by uploads
+type AssetLoader struct {
+	workers      chan struct{}  // Limits how many jobs decode at once, one token per running job
+	running      sync.WaitGroup // The jobs that haven't finished decoding
+	uploadsMutex sync.Mutex     // Guards uploads, which workers append to
+	uploads      []assetUpload  // Decoded loads waiting for ProcessUploads
+	pending      int            // Loads that haven't completed. Main thread only
+	closed       bool           // Set by Close
+}
+
+// CreateAssetLoader is the standard constructor for an AssetLoader. workers is how many files can be decoded at once,
+// at least 1
+func CreateAssetLoader(workers int) *AssetLoader {
+	if workers < 1 {
+		workers = 1
+	}
+	loader := new(AssetLoader)
+	loader.workers = make(chan struct{}, workers)
+	return loader
+}
+
+// LoadTexture loads the texture's source file in the background, using the texture's Channels and SRGB settings.
+// Change the texture's settings before calling this, not while it's loading
+func (loader *AssetLoader) LoadTexture(tex *Texture) *TextureHandle {
+	handle := &TextureHandle{AssetHandle: &AssetHandle{FilePath: tex.SourceFilePath}, Texture: tex}
+
+	// Already loaded, i.e. a texture shared between materials
+	if tex.IsLoaded && tex.lastLoadedPath == tex.SourceFilePath {
+		handle.complete(nil)
+		return handle
+	}
+
+	filePath, channels, srgb := tex.SourceFilePath, tex.Channels, tex.SRGB
+	loader.start(handle.AssetHandle, func() (func() error, error) {
+		decoded, err := decodeTextureFile(filePath, channels, srgb)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			if err := tex.uploadDecoded(decoded); err != nil {
+				return err
+			}
+			tex.lastLoadedPath = filePath
+			return nil
+		}, nil
+	})
+	return handle
+}
+
+// LoadMesh imports a mesh file in the background, like ImportMesh. The material library's textures, or a glTF's images,
+// are decoded in the background too. The scene object is created on the main thread once its meshes are uploaded
+func (loader *AssetLoader) LoadMesh(filePath string) *MeshHandle {
+	handle := &MeshHandle{AssetHandle: &AssetHandle{FilePath: filePath}}
+
+	loader.start(handle.AssetHandle, func() (func() error, error) {
+		fileData, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if isGLTFFile(filePath) {
+			imported, err := parseGLTF(filePath, fileData)
+			if err != nil {
+				return nil, err
+			}
+			return func() error {
+				scene, err := imported.build()
+				if err != nil {
+					return err
+				}
+				handle.GLTF = scene
+				handle.Meshes, handle.Materials = scene.flatten()
+				handle.SceneObject = scene.Root
+				return nil
+			}, nil
+		}
+
+		data, err := ParseMeshFile(filePath, fileData)
+		if err != nil {
+			return nil, err
+		}
+		textures := decodeImportedTextures(data)
+		return func() error {
+			handle.Data = data
+			handle.Meshes = uploadMeshData(data)
+			handle.Materials = createImportedMaterials(data, textures)
+			handle.SceneObject = CreateMeshSceneObjectWithMaterials(handle.Meshes, handle.Materials)
+			return nil
+		}, nil
+	})
+	return handle
+}
+
+// start runs decode on a worker goroutine. decode returns the main thread upload to finish the load with
+func (loader *AssetLoader) start(handle *AssetHandle, decode func() (func() error, error)) {
+	if loader.closed {
+		handle.complete(errors.New("Invalid Operation: The asset loader has been closed"))
+		return
+	}
+
+	loader.pending++
+	loader.running.Add(1)
+	go func() {
+		defer loader.running.Done()
+		loader.workers <- struct{}{}
+		upload, err := loader.decode(handle.FilePath, decode)
+		<-loader.workers
+
+		loader.uploadsMutex.Lock()
+		loader.uploads = append(loader.uploads, assetUpload{handle: handle, upload: upload, err: err})
+		loader.uploadsMutex.Unlock()
+	}()
+}
+
+// decode runs a decode function, turning panics from bad files into errors so one asset can't take down the game
+func (loader *AssetLoader) decode(filePath string, decode func() (func() error, error)) (upload func() error, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("Invalid Asset: %v could not be decoded: %v", filePath, recovered)
+		}
+	}()
+	return decode()
+}
+
+// upload runs an upload function, turning panics into errors like decode does
+func (loader *AssetLoader) upload(filePath string, upload func() error) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("Invalid Asset: %v could not be uploaded: %v", filePath, recovered)
+		}
+	}()
+	return upload()
+}
+
+// ProcessUploads finishes decoded loads on the main thread, uploading them to the GPU and running their callbacks,
+// until budget has passed. At least one load is finished per call so loading always makes progress. An upload that
+// panics fails it's handle rather than the game. Returns how many loads were finished
+func (loader *AssetLoader) ProcessUploads(budget time.Duration) int {
+	start := time.Now()
+	processed := 0
+	for {
+		loader.uploadsMutex.Lock()
+		if len(loader.uploads) == 0 {
+			loader.uploadsMutex.Unlock()
+			return processed
+		}
+		next := loader.uploads[0]
+		loader.uploads[0] = assetUpload{}
+		loader.uploads = loader.uploads[1:]
+		loader.uploadsMutex.Unlock()
+
+		err := next.err
+		if err == nil {
+			err = loader.upload(next.handle.FilePath, next.upload)
+		}
+		loader.pending--
+		next.handle.complete(err)
+		processed++
+		if time.Since(start) >= budget {
+			return processed
+		}
+	}
+}
+
+// Pending returns how many loads haven't completed yet, including ones waiting for ProcessUploads
+func (loader *AssetLoader) Pending() int {
+	return loader.pending
+}
+
+// Close waits for running decodes to finish and drops everything still waiting to upload. Handles that never
+// finished get an error. The loader can't be used after this
+func (loader *AssetLoader) Close() {
+	if loader.closed {
+		return
+	}
+	loader.closed = true
+	loader.running.Wait()
+
+	loader.uploadsMutex.Lock()
+	dropped := loader.uploads
+	loader.uploads = nil
+	loader.uploadsMutex.Unlock()
+
+	if len(dropped) > 0 {
+		dbg.LogError(fmt.Sprintf("Asset Loader: Closed with %v loads still waiting to upload", len(dropped)))
+	}
+	loader.pending = 0
+	for _, next := range dropped {
+		next.handle.complete(errors.New("Invalid Operation: The asset loader was closed before the asset uploaded"))
+	}
+}
+
+// assetUpload is a decoded load waiting for the main thread
+type assetUpload struct {
+	handle *AssetHandle // The handle to complete
+	upload func() error // Sends the decoded asset to the GPU
+	err    error        // Set if decoding failed, in which case upload is nil
+}
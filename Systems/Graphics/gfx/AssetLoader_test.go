@@ -0,0 +1,172 @@
+package gfx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startTestLoad starts a load on loader whose upload appends "upload <name>" to events, and whose callbacks append
+// "callback <name> <n>"
+func startTestLoad(loader *AssetLoader, name string, events *[]string, decode func() error, upload func() error) *AssetHandle {
+	handle := &AssetHandle{FilePath: name}
+	loader.start(handle, func() (func() error, error) {
+		if decode != nil {
+			if err := decode(); err != nil {
+				return nil, err
+			}
+		}
+		return func() error {
+			*events = append(*events, "upload "+name)
+			if upload != nil {
+				return upload()
+			}
+			return nil
+		}, nil
+	})
+	for n := 1; n <= 2; n++ {
+		n := n
+		handle.OnComplete(func(err error) {
+			*events = append(*events, fmt.Sprintf("callback %v %v", name, n))
+		})
+	}
+	return handle
+}
+
+func TestAssetLoaderProcessUploads(t *testing.T) {
+	loader := CreateAssetLoader(2)
+	var events []string
+	names := []string{"a", "b", "c"}
+	handles := make([]*AssetHandle, len(names))
+	for i, name := range names {
+		handles[i] = startTestLoad(loader, name, &events, nil, nil)
+	}
+	loader.running.Wait()
+
+	// Decoded, but nothing finishes until the main thread asks
+	if loader.Pending() != 3 || len(events) != 0 || handles[0].Done() {
+		t.Fatalf("got %v pending and events %v before ProcessUploads, expected 3 and none", loader.Pending(), events)
+	}
+	if processed := loader.ProcessUploads(time.Hour); processed != 3 || loader.Pending() != 0 {
+		t.Fatalf("got %v processed and %v pending, expected 3 and 0", processed, loader.Pending())
+	}
+
+	// Loads finish in whatever order they decoded, but each uploads before it's callbacks run in the order they were added
+	for i, name := range names {
+		if !handles[i].Ready() {
+			t.Errorf("%v: got a handle that isn't ready, error %v", name, handles[i].Err())
+		}
+		var got []string
+		for _, event := range events {
+			if strings.HasSuffix(event, " "+name) || strings.Contains(event, " "+name+" ") {
+				got = append(got, event)
+			}
+		}
+		expected := []string{"upload " + name, "callback " + name + " 1", "callback " + name + " 2"}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Errorf("%v: got events %v, expected %v", name, got, expected)
+		}
+	}
+
+	// Callbacks added after completion run straight away
+	called := false
+	handles[0].OnComplete(func(err error) { called = err == nil })
+	if !called {
+		t.Errorf("OnComplete after the load finished didn't run it's callback")
+	}
+	if processed := loader.ProcessUploads(time.Hour); processed != 0 {
+		t.Errorf("got %v processed with nothing queued, expected 0", processed)
+	}
+}
+
+func TestAssetLoaderFailures(t *testing.T) {
+	loader := CreateAssetLoader(1)
+	var events []string
+	tests := []struct {
+		name     string
+		decode   func() error
+		upload   func() error
+		contains string
+	}{
+		{"decode error", func() error { return errors.New("Test: Bad file") }, nil, "Bad file"},
+		{"decode panic", func() error { panic("bad decode") }, nil, "could not be decoded: bad decode"},
+		{"upload error", nil, func() error { return errors.New("Test: Bad upload") }, "Bad upload"},
+		{"upload panic", nil, func() error { panic("bad upload") }, "could not be uploaded: bad upload"},
+	}
+	handles := make([]*AssetHandle, len(tests))
+	for i, test := range tests {
+		handles[i] = startTestLoad(loader, test.name, &events, test.decode, test.upload)
+	}
+	loader.running.Wait()
+	loader.ProcessUploads(time.Hour)
+
+	if loader.Pending() != 0 {
+		t.Errorf("got %v pending after every load failed, expected 0", loader.Pending())
+	}
+	for i, test := range tests {
+		handle := handles[i]
+		if !handle.Done() || handle.Ready() || handle.Err() == nil || !strings.Contains(handle.Err().Error(), test.contains) {
+			t.Errorf("%v: got done %v and error %v, expected a failure containing %q", test.name, handle.Done(), handle.Err(), test.contains)
+		}
+	}
+}
+
+func TestAssetLoaderBudget(t *testing.T) {
+	loader := CreateAssetLoader(4)
+	var events []string
+	slow := func() error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+	for i := 0; i < 4; i++ {
+		startTestLoad(loader, fmt.Sprint(i), &events, nil, slow)
+	}
+	loader.running.Wait()
+
+	// Every upload takes longer than the budget, so each call finishes exactly one
+	for expected := 3; expected >= 0; expected-- {
+		if processed := loader.ProcessUploads(time.Millisecond); processed != 1 || loader.Pending() != expected {
+			t.Fatalf("got %v processed and %v pending, expected 1 and %v", processed, loader.Pending(), expected)
+		}
+	}
+
+	// A zero budget still makes progress
+	startTestLoad(loader, "last", &events, nil, nil)
+	loader.running.Wait()
+	if processed := loader.ProcessUploads(0); processed != 1 {
+		t.Errorf("got %v processed with a zero budget, expected 1", processed)
+	}
+}
+
+func TestAssetLoaderClose(t *testing.T) {
+	loader := CreateAssetLoader(2)
+	var events []string
+	queued := []*AssetHandle{
+		startTestLoad(loader, "a", &events, nil, nil),
+		startTestLoad(loader, "b", &events, func() error { time.Sleep(10 * time.Millisecond); return nil }, nil),
+	}
+
+	// Close waits for the running decode then fails both loads without uploading them
+	loader.Close()
+	if loader.Pending() != 0 {
+		t.Errorf("got %v pending after Close, expected 0", loader.Pending())
+	}
+	for _, handle := range queued {
+		if !handle.Done() || handle.Err() == nil || !strings.Contains(handle.Err().Error(), "closed") {
+			t.Errorf("%v: got done %v and error %v, expected an error about the loader closing", handle.FilePath, handle.Done(), handle.Err())
+		}
+	}
+	for _, event := range events {
+		if strings.HasPrefix(event, "upload") {
+			t.Errorf("got %q after Close, expected nothing to upload", event)
+		}
+	}
+
+	late := startTestLoad(loader, "late", &events, nil, nil)
+	if !late.Done() || late.Err() == nil || loader.Pending() != 0 {
+		t.Errorf("starting a load after Close: got done %v and error %v, expected it to fail straight away", late.Done(), late.Err())
+	}
+	loader.Close()
+}
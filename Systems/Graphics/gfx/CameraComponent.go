@@ -29,27 +29,26 @@ const (
 // CameraComponent when attached to a scene object will act as a camera for the scene
 type CameraComponent struct {
 	*core.BaseComponent
-	NearPlane              float32              // The distance from the camera to the render plane
-	FarPlane               float32              // The distance from the camera to the far clipping plane
-	FieldOfView            float32              // The Horizontal FOV in degrees of the camera viewport
-	AspectRatio            float32              // The aspect ratio of the render plane
-	ActiveProjectionMatrix *math.StandardMatrix // The current projection matrix used for this camera
+	NearPlane              float32           // The distance from the camera to the render plane
+	FarPlane               float32           // The distance from the camera to the far clipping plane
+	FieldOfView            float32           // The Horizontal FOV in degrees of the camera viewport
+	AspectRatio            float32           // The aspect ratio of the render plane
+	Projection             ProjectionMode    // The type of projection this camera uses
+	ActiveProjectionMatrix math.Matrix4x4    // The current projection matrix used for this camera
+	Target                 *RenderTarget     // Where the camera draws to. nil draws to the window
+	PostProcess            *PostProcessStack // Effects run over the image before it reaches Target. Can be nil
 }
 
 // CreateCameraComponent is the standard constructor for a CameraComponent
 func CreateCameraComponent(fovx float32, aspectRatio float32, mode ProjectionMode) *CameraComponent {
 	cc := new(CameraComponent)
-	cc.BaseComponent = new(core.BaseComponent)
+	cc.BaseComponent = core.CreateBaseComponent(cc)
 	cc.NearPlane = 5
 	cc.FarPlane = 50
 	cc.FieldOfView = fovx
 	cc.AspectRatio = aspectRatio
-
-	if mode == PerspectiveProjection {
-		cc.ActiveProjectionMatrix = cc.PerspectiveMatrix()
-	} else if mode == OrthographicProjection {
-		cc.ActiveProjectionMatrix = cc.OrthographicMatrix()
-	}
+	cc.Projection = mode
+	cc.UpdateProjectionMatrix()
 
 	if MainCamera == nil {
 		MainCamera = cc
@@ -63,43 +62,54 @@ func (cam *CameraComponent) SetAsMain() {
 	MainCamera = cam
 }
 
+// SetTarget makes the camera draw into target, or back to the window if target is nil. The aspect ratio is changed to
+// match the target
+func (cam *CameraComponent) SetTarget(target *RenderTarget) {
+	cam.Target = target
+	if target != nil && target.Height() > 0 {
+		cam.AspectRatio = float32(target.Width()) / float32(target.Height())
+		cam.UpdateProjectionMatrix()
+	}
+}
+
+// UpdateProjectionMatrix recomputes ActiveProjectionMatrix. Call this after changing any of the camera's settings
+func (cam *CameraComponent) UpdateProjectionMatrix() {
+	if cam.Projection == OrthographicProjection {
+		cam.ActiveProjectionMatrix = cam.OrthographicMatrix()
+	} else {
+		cam.ActiveProjectionMatrix = cam.PerspectiveMatrix()
+	}
+}
+
+// ProjectionMatrixForRange returns the camera's projection matrix with the clipping planes moved to near and far.
+// Useful for working with slices of the view frustum
+func (cam *CameraComponent) ProjectionMatrixForRange(near float32, far float32) math.Matrix4x4 {
+	if cam.Projection == OrthographicProjection {
+		// Keep the same view volume size, which OrthographicMatrix derives from the near plane
+		halfFovRad := (cam.FieldOfView / 2) * math.Deg2Rad
+		halfwidth := cam.NearPlane * float32(gomath.Tan(float64(halfFovRad)))
+		halfheight := halfwidth * (1 / cam.AspectRatio)
+		return math.Orthographic(-halfwidth, halfwidth, -halfheight, halfheight, near, far)
+	}
+	return math.PerspectiveFovX(cam.FieldOfView, cam.AspectRatio, near, far)
+}
+
 // ViewMatrix returns the view matrix for this camera
-// TODO: Cache? Invert ModelMatrix? etc.
-func (cam *CameraComponent) ViewMatrix() *math.StandardMatrix {
+func (cam *CameraComponent) ViewMatrix() *math.Matrix4x4 {
 	return cam.SceneObject().Transform.World2ModelMatrix()
 }
 
 // PerspectiveMatrix returns the perspective matrix of the current camera
-func (cam *CameraComponent) PerspectiveMatrix() *math.StandardMatrix {
-	halfFovRad := (cam.FieldOfView / 2) * math.Deg2Rad
-	halfwidth := cam.NearPlane * float32(gomath.Tan(float64(halfFovRad)))
-	halfheight := halfwidth * (1 / cam.AspectRatio)
-	fminn := cam.FarPlane - cam.NearPlane
-
-	mat := math.StandardMatrixZeros(4, 4)
-	mat.Set(0, 0, cam.NearPlane/halfwidth)
-	mat.Set(1, 1, cam.NearPlane/halfheight)
-	mat.Set(2, 2, -(cam.FarPlane+cam.NearPlane)/fminn)
-	mat.Set(2, 3, (-2*cam.FarPlane*cam.NearPlane)/fminn)
-	mat.Set(3, 2, -1)
-	mat.Set(3, 3, 1)
-
-	return mat
+func (cam *CameraComponent) PerspectiveMatrix() math.Matrix4x4 {
+	return math.PerspectiveFovX(cam.FieldOfView, cam.AspectRatio, cam.NearPlane, cam.FarPlane)
 }
 
-// OrthographicMatrix returns the orthographic matrix for this projection
-func (cam *CameraComponent) OrthographicMatrix() *math.StandardMatrix {
+// OrthographicMatrix returns the orthographic matrix for this projection. The view volume matches the size of the
+// perspective frustum at the near plane
+func (cam *CameraComponent) OrthographicMatrix() math.Matrix4x4 {
 	halfFovRad := (cam.FieldOfView / 2) * math.Deg2Rad
 	halfwidth := cam.NearPlane * float32(gomath.Tan(float64(halfFovRad)))
 	halfheight := halfwidth * (1 / cam.AspectRatio)
-	fminn := cam.FarPlane - cam.NearPlane
-
-	mat := math.StandardMatrixZeros(4, 4)
-	mat.Set(0, 0, 1/halfwidth)
-	mat.Set(1, 1, 1/halfheight)
-	mat.Set(2, 2, -2/fminn)
-	mat.Set(2, 3, -(cam.FarPlane+cam.NearPlane)/fminn)
-	mat.Set(3, 3, 1)
 
-	return mat
+	return math.Orthographic(-halfwidth, halfwidth, -halfheight, halfheight, cam.NearPlane, cam.FarPlane)
 }
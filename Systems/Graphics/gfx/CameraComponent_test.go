@@ -0,0 +1,34 @@
+package gfx
+
+import "testing"
+
+func TestCameraProjectionUpdateDoesNotAllocate(t *testing.T) {
+	cam := CreateCameraComponent(90, Aspect16x9, PerspectiveProjection)
+	allocations := testing.AllocsPerRun(100, func() {
+		cam.FieldOfView++
+		cam.UpdateProjectionMatrix()
+	})
+	if allocations != 0 {
+		t.Errorf("Updating the projection matrix allocated %v times, expected 0", allocations)
+	}
+}
+
+func BenchmarkCameraPerspectiveUpdate(b *testing.B) {
+	cam := CreateCameraComponent(90, Aspect16x9, PerspectiveProjection)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cam.AspectRatio = Aspect16x9 + float32(i%2)
+		cam.UpdateProjectionMatrix()
+	}
+}
+
+func BenchmarkCameraOrthographicUpdate(b *testing.B) {
+	cam := CreateCameraComponent(90, Aspect16x9, OrthographicProjection)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cam.AspectRatio = Aspect16x9 + float32(i%2)
+		cam.UpdateProjectionMatrix()
+	}
+}
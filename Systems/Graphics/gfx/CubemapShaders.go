@@ -0,0 +1,147 @@
+package gfx
+
+// cubeFaceDirectionSource converts a full screen uv on one face of a cubemap into the direction it covers, following
+// the face orientations in the GL spec. It's pasted into the shaders that render cubemap faces
+const cubeFaceDirectionSource = `
+vec3 faceDirection(int face, vec2 uv) {
+	vec2 st = uv * 2.0 - 1.0;
+	if (face == 0) {
+		return vec3(1.0, -st.y, -st.x);
+	} else if (face == 1) {
+		return vec3(-1.0, -st.y, st.x);
+	} else if (face == 2) {
+		return vec3(st.x, 1.0, st.y);
+	} else if (face == 3) {
+		return vec3(st.x, -1.0, -st.y);
+	} else if (face == 4) {
+		return vec3(st.x, -st.y, 1.0);
+	}
+	return vec3(-st.x, -st.y, -1.0);
+}
+`
+
+// equirectangularToCubeFragmentShaderSource samples a latitude/longitude panorama for one face of a cubemap
+const equirectangularToCubeFragmentShaderSource = `
+#version 150 core
+
+in vec2 v_TexUV;
+
+uniform sampler2D u_Panorama;
+uniform int u_Face;
+
+out vec4 o_Color;
+` + cubeFaceDirectionSource + `
+void main() {
+	vec3 direction = normalize(faceDirection(u_Face, v_TexUV));
+
+	// The panorama's top row is straight up and it's center column looks down -Z
+	vec2 uv = vec2(atan(direction.x, -direction.z) / 6.2831853 + 0.5, 0.5 - asin(clamp(direction.y, -1.0, 1.0)) / 3.1415927);
+	o_Color = vec4(texture(u_Panorama, uv).rgb, 1.0);
+}
+`
+
+// prefilterEnvironmentFragmentShaderSource convolves an environment cubemap with the GGX distribution for
+// u_Roughness by importance sampling, so rougher surfaces can read blurrier reflections from lower mips
+const prefilterEnvironmentFragmentShaderSource = `
+#version 150 core
+
+#define SAMPLE_COUNT 128u
+#define PI 3.1415927
+
+in vec2 v_TexUV;
+
+uniform samplerCube u_Environment;
+uniform int u_Face;
+uniform float u_Roughness;
+uniform float u_SourceSize;
+
+out vec4 o_Color;
+` + cubeFaceDirectionSource + `
+float radicalInverse(uint bits) {
+	bits = (bits << 16u) | (bits >> 16u);
+	bits = ((bits & 0x55555555u) << 1u) | ((bits & 0xAAAAAAAAu) >> 1u);
+	bits = ((bits & 0x33333333u) << 2u) | ((bits & 0xCCCCCCCCu) >> 2u);
+	bits = ((bits & 0x0F0F0F0Fu) << 4u) | ((bits & 0xF0F0F0F0u) >> 4u);
+	bits = ((bits & 0x00FF00FFu) << 8u) | ((bits & 0xFF00FF00u) >> 8u);
+	return float(bits) * 2.3283064365386963e-10;
+}
+
+vec3 importanceSampleGGX(vec2 xi, vec3 normal, float alpha) {
+	float phi = 2.0 * PI * xi.x;
+	float cosTheta = sqrt((1.0 - xi.y) / (1.0 + (alpha * alpha - 1.0) * xi.y));
+	float sinTheta = sqrt(1.0 - cosTheta * cosTheta);
+	vec3 halfway = vec3(cos(phi) * sinTheta, sin(phi) * sinTheta, cosTheta);
+
+	vec3 up = abs(normal.z) < 0.999 ? vec3(0.0, 0.0, 1.0) : vec3(1.0, 0.0, 0.0);
+	vec3 tangent = normalize(cross(up, normal));
+	vec3 bitangent = cross(normal, tangent);
+	return normalize(tangent * halfway.x + bitangent * halfway.y + normal * halfway.z);
+}
+
+void main() {
+	vec3 normal = normalize(faceDirection(u_Face, v_TexUV));
+	if (u_Roughness <= 0.0) {
+		o_Color = vec4(textureLod(u_Environment, normal, 0.0).rgb, 1.0);
+		return;
+	}
+
+	// Assume the view is along the normal, the usual split sum simplification
+	float alpha = u_Roughness * u_Roughness;
+	float texelSolidAngle = 4.0 * PI / (6.0 * u_SourceSize * u_SourceSize);
+	vec3 color = vec3(0.0);
+	float totalWeight = 0.0;
+	for (uint i = 0u; i < SAMPLE_COUNT; i++) {
+		vec2 xi = vec2(float(i) / float(SAMPLE_COUNT), radicalInverse(i));
+		vec3 halfway = importanceSampleGGX(xi, normal, alpha);
+		vec3 toLight = normalize(2.0 * dot(normal, halfway) * halfway - normal);
+
+		float normalDotLight = dot(normal, toLight);
+		if (normalDotLight > 0.0) {
+			// Read from a blurrier mip when each sample covers more of the sphere to avoid fireflies
+			float normalDotHalf = max(dot(normal, halfway), 0.0);
+			float denominator = normalDotHalf * normalDotHalf * (alpha * alpha - 1.0) + 1.0;
+			float distribution = alpha * alpha / (PI * denominator * denominator);
+			float pdf = distribution / 4.0 + 0.0001;
+			float sampleSolidAngle = 1.0 / (float(SAMPLE_COUNT) * pdf + 0.0001);
+			float mip = 0.5 * log2(sampleSolidAngle / texelSolidAngle);
+
+			color += textureLod(u_Environment, toLight, max(mip, 0.0)).rgb * normalDotLight;
+			totalWeight += normalDotLight;
+		}
+	}
+
+	o_Color = vec4(color / max(totalWeight, 0.0001), 1.0);
+}
+`
+
+// skyboxVertexShaderSource covers the screen at the far plane and works out the world direction of each corner
+const skyboxVertexShaderSource = `
+#version 150 core
+
+uniform mat4 u_InverseViewProjection;
+
+out vec3 v_Direction;
+
+void main() {
+	vec2 position = vec2((gl_VertexID << 1) & 2, gl_VertexID & 2) * 2.0 - 1.0;
+	vec4 world = u_InverseViewProjection * vec4(position, 1.0, 1.0);
+	v_Direction = world.xyz / world.w;
+	gl_Position = vec4(position, 1.0, 1.0);
+}
+`
+
+// skyboxFragmentShaderSource looks the sky up in a cubemap
+const skyboxFragmentShaderSource = `
+#version 150 core
+
+in vec3 v_Direction;
+
+uniform samplerCube u_Skybox;
+uniform float u_Exposure;
+
+out vec4 o_Color;
+
+void main() {
+	o_Color = vec4(texture(u_Skybox, v_Direction).rgb * u_Exposure, 1.0);
+}
+`
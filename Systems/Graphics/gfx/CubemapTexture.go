@@ -0,0 +1,381 @@
+package gfx
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// Cubemap faces in the order openGL numbers them from gl.TEXTURE_CUBE_MAP_POSITIVE_X
+const (
+	CubeFacePositiveX int = iota
+	CubeFaceNegativeX
+	CubeFacePositiveY
+	CubeFaceNegativeY
+	CubeFacePositiveZ
+	CubeFaceNegativeZ
+	CubeFaceCount
+)
+
+// PrefilterMipLevels is the number of roughness levels Prefilter renders, from mirror at mip 0 to fully rough
+const PrefilterMipLevels int32 = 6
+
+// Shaders used to render into cubemaps, shared by every cubemap
+var equirectangularToCubeShader *Shader
+var prefilterEnvironmentShader *Shader
+
+// CubemapTexture is a texture made of six square faces that is sampled by direction with a samplerCube. Use the
+// embedded Texture with Material.SetTextureParameter like any other texture
+type CubemapTexture struct {
+	*Texture
+	FaceFilePaths    [CubeFaceCount]string // The images for each face in CubeFace order, if loaded from faces
+	PanoramaFilePath string                // The equirectangular image, if loaded from a panorama
+	Size             int32                 // The width and height of each face in pixels
+	MipLevels        int32                 // The number of mip levels with data
+}
+
+// CreateCubemapTexture makes a cubemap that loads from six images, ordered +X, -X, +Y, -Y, +Z, -Z
+func CreateCubemapTexture(faceFilePaths [CubeFaceCount]string) *CubemapTexture {
+	cube := createCubemap()
+	cube.FaceFilePaths = faceFilePaths
+	return cube
+}
+
+// CreateCubemapTextureFromPanorama makes a cubemap that loads from an equirectangular image, such as an HDR
+// environment. size is the width of each face, 0 picks a size to match the panorama's resolution
+func CreateCubemapTextureFromPanorama(filePath string, size int32) *CubemapTexture {
+	cube := createCubemap()
+	cube.PanoramaFilePath = filePath
+	cube.Size = size
+	return cube
+}
+
+// CreateCubemapTextureFromFile makes a cubemap that loads from a .ktx or .dds cubemap file
+func CreateCubemapTextureFromFile(filePath string) *CubemapTexture {
+	cube := createCubemap()
+	cube.SourceFilePath = filePath
+	return cube
+}
+
+// CreateEmptyCubemapTexture makes a cubemap with storage but no contents, i.e. to render into
+func CreateEmptyCubemapTexture(size int32, format AttachmentFormat, mipLevels int32) *CubemapTexture {
+	cube := createCubemap()
+	cube.allocate(size, format, mipLevels)
+	return cube
+}
+
+// createCubemap makes a cubemap texture with the settings that suit cubemaps
+func createCubemap() *CubemapTexture {
+	cube := new(CubemapTexture)
+	cube.Texture = new(Texture)
+	cube.Target = gl.TEXTURE_CUBE_MAP
+	cube.Generate()
+
+	// Without clamping, filtering at the edge of a face would pull in the opposite edge
+	cube.SetHorizontalWrapMode(gl.CLAMP_TO_EDGE)
+	cube.SetVerticalWrapMode(gl.CLAMP_TO_EDGE)
+	cube.BindToSlot(gl.TEXTURE0)
+	gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_WRAP_R, gl.CLAMP_TO_EDGE)
+	cube.UnBindFromSlot(gl.TEXTURE0)
+	cube.SetMinFilterMode(gl.LINEAR)
+	cube.SetMagFilterMode(gl.LINEAR)
+
+	// Core since 3.2, blends across face edges instead of showing seams
+	gl.Enable(gl.TEXTURE_CUBE_MAP_SEAMLESS)
+	return cube
+}
+
+// Load loads the faces or panorama from hard disk into GPU memory
+func (cube *CubemapTexture) Load() error {
+	if cube.IsLoaded {
+		return nil
+	}
+
+	var err error
+	if cube.SourceFilePath != "" {
+		err = cube.loadContainer()
+	} else if cube.PanoramaFilePath != "" {
+		err = cube.loadPanorama()
+	} else {
+		err = cube.loadFaces()
+	}
+	if err != nil {
+		return err
+	}
+
+	if cube.GenerateMipMaps {
+		cube.GenerateMipmaps()
+	}
+	cube.IsLoaded = true
+	return nil
+}
+
+// GenerateMipmaps fills every mip level below the first and switches to trilinear filtering
+func (cube *CubemapTexture) GenerateMipmaps() {
+	cube.BindToSlot(gl.TEXTURE0)
+	// allocate may have capped the chain at fewer levels, which would leave nothing for glGenerateMipmap to fill
+	gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_MAX_LEVEL, mipCount(cube.Size)-1)
+	gl.GenerateMipmap(gl.TEXTURE_CUBE_MAP)
+	cube.UnBindFromSlot(gl.TEXTURE0)
+	cube.SetMinFilterMode(gl.LINEAR_MIPMAP_LINEAR)
+	cube.MipLevels = mipCount(cube.Size)
+}
+
+// Prefilter makes a new cubemap for reflections. Each mip level holds the environment blurred for a rougher surface,
+// from a mirror at mip 0 to fully rough at PrefilterMipLevels - 1. size is the width of the sharpest level
+func (cube *CubemapTexture) Prefilter(size int32) (*CubemapTexture, error) {
+	if !cube.IsLoaded {
+		return nil, errors.New("Invalid Cubemap: Cannot prefilter a cubemap that hasn't been loaded")
+	}
+	if prefilterEnvironmentShader == nil {
+		shader, err := CreateShaderFromSource(fullScreenVertexShaderSource, prefilterEnvironmentFragmentShaderSource)
+		if err != nil {
+			return nil, err
+		}
+		prefilterEnvironmentShader = shader
+	}
+
+	// Blurry levels sample the source's mips to stay smooth
+	if cube.MipLevels <= 1 {
+		cube.GenerateMipmaps()
+	}
+
+	levels := PrefilterMipLevels
+	if maxLevels := mipCount(size); levels > maxLevels {
+		levels = maxLevels
+	}
+	filtered := CreateEmptyCubemapTexture(size, ColorFormatRGBA16F, levels)
+	filtered.SetMinFilterMode(gl.LINEAR_MIPMAP_LINEAR)
+
+	shader := prefilterEnvironmentShader
+	shader.Bind()
+	if param, ok := shader.TextureParameters["u_Environment"]; ok {
+		cube.BindToSlot(param.Slot)
+	}
+	shader.SendParameterValue("u_SourceSize", float32(cube.Size))
+	for level := int32(0); level < levels; level++ {
+		roughness := float32(0)
+		if levels > 1 {
+			roughness = float32(level) / float32(levels-1)
+		}
+		shader.SendParameterValue("u_Roughness", roughness)
+		if err := renderCubemapFaces(filtered, level, shader); err != nil {
+			filtered.Delete()
+			return nil, err
+		}
+	}
+	shader.UnBind()
+
+	filtered.IsLoaded = true
+	return filtered, nil
+}
+
+// loadContainer uploads a cubemap container file
+func (cube *CubemapTexture) loadContainer() error {
+	container, err := ReadTextureContainerFile(cube.SourceFilePath)
+	if err != nil {
+		return err
+	}
+	if container.Target() != gl.TEXTURE_CUBE_MAP {
+		return fmt.Errorf("Invalid Cubemap: %v is not a cubemap", cube.SourceFilePath)
+	}
+	if container.Width != container.Height {
+		return fmt.Errorf("Invalid Cubemap: %v has faces that aren't square", cube.SourceFilePath)
+	}
+	if err := cube.UploadContainer(container); err != nil {
+		return err
+	}
+	cube.Size = container.Width
+	cube.MipLevels = int32(len(container.Levels))
+	return nil
+}
+
+// loadFaces decodes and uploads the six face images
+func (cube *CubemapTexture) loadFaces() error {
+	var faces [CubeFaceCount]image.Image
+	for i, path := range cube.FaceFilePaths {
+		img, err := decodeImageFile(path)
+		if err != nil {
+			return err
+		}
+		bounds := img.Bounds()
+		if bounds.Dx() != bounds.Dy() {
+			return fmt.Errorf("Invalid Cubemap Face: %v is %vx%v but faces must be square", path, bounds.Dx(), bounds.Dy())
+		}
+		if i > 0 && bounds.Dx() != faces[0].Bounds().Dx() {
+			return fmt.Errorf("Invalid Cubemap Face: %v is a different size to the other faces", path)
+		}
+		faces[i] = img
+	}
+
+	cube.Size = int32(faces[0].Bounds().Dx())
+	cube.MipLevels = 1
+	cube.BindToSlot(gl.TEXTURE0)
+	defer cube.UnBindFromSlot(gl.TEXTURE0)
+
+	// Cubemap faces are laid out top row first, which is how images are already stored, so there's no flip
+	for i, img := range faces {
+		data, err := CreateTextureData(img, cube.Channels, cube.SRGB, false)
+		if err != nil {
+			return err
+		}
+		gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+		gl.TexImage2D(uint32(gl.TEXTURE_CUBE_MAP_POSITIVE_X+i), 0, data.InternalFormat, cube.Size, cube.Size, 0, data.Format, data.DataType, gl.Ptr(data.Pixels))
+		gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	}
+	return nil
+}
+
+// loadPanorama uploads the panorama to a temporary texture then renders it onto each face
+func (cube *CubemapTexture) loadPanorama() error {
+	img, err := decodeImageFile(cube.PanoramaFilePath)
+	if err != nil {
+		return err
+	}
+	if equirectangularToCubeShader == nil {
+		shader, err := CreateShaderFromSource(fullScreenVertexShaderSource, equirectangularToCubeFragmentShaderSource)
+		if err != nil {
+			return err
+		}
+		equirectangularToCubeShader = shader
+	}
+
+	// A quarter of the panorama's width keeps roughly the same detail
+	if cube.Size <= 0 {
+		cube.Size = int32(img.Bounds().Dx() / 4)
+		if cube.Size < 1 {
+			cube.Size = 1
+		}
+	}
+
+	panorama := new(Texture)
+	panorama.Generate()
+	defer panorama.Delete()
+	panorama.SetHorizontalWrapMode(gl.REPEAT)
+	panorama.SetVerticalWrapMode(gl.CLAMP_TO_EDGE)
+	panorama.SetMinFilterMode(gl.LINEAR)
+	panorama.SetMagFilterMode(gl.LINEAR)
+
+	data, err := CreateTextureData(img, ChannelsAuto, cube.SRGB, false)
+	if err != nil {
+		return err
+	}
+	if err := panorama.Upload(data); err != nil {
+		return err
+	}
+
+	// Keep HDR panoramas HDR, and sRGB ones from banding once they're converted to linear
+	format := ColorFormatRGBA8
+	if data.DataType != gl.UNSIGNED_BYTE || cube.SRGB {
+		format = ColorFormatRGBA16F
+	}
+
+	cube.allocate(cube.Size, format, 1)
+
+	shader := equirectangularToCubeShader
+	shader.Bind()
+	defer shader.UnBind()
+	if param, ok := shader.TextureParameters["u_Panorama"]; ok {
+		panorama.BindToSlot(param.Slot)
+	}
+	return renderCubemapFaces(cube, 0, shader)
+}
+
+// allocate creates empty storage for every face and mip level
+func (cube *CubemapTexture) allocate(size int32, format AttachmentFormat, mipLevels int32) {
+	if mipLevels < 1 {
+		mipLevels = 1
+	}
+	cube.Size = size
+	cube.MipLevels = mipLevels
+
+	cube.BindToSlot(gl.TEXTURE0)
+	defer cube.UnBindFromSlot(gl.TEXTURE0)
+	for level := int32(0); level < mipLevels; level++ {
+		levelSize := size >> uint(level)
+		if levelSize < 1 {
+			levelSize = 1
+		}
+		for face := 0; face < CubeFaceCount; face++ {
+			gl.TexImage2D(uint32(gl.TEXTURE_CUBE_MAP_POSITIVE_X+face), level, int32(format.InternalFormat), levelSize, levelSize, 0, format.Format, format.DataType, nil)
+		}
+	}
+	gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_BASE_LEVEL, 0)
+	gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_MAX_LEVEL, mipLevels-1)
+}
+
+// renderCubemapFaces draws the bound shader over all six faces of one mip level of cube. The shader is given the face
+// being drawn as u_Face and should use the full screen triangle's v_TexUV
+func renderCubemapFaces(cube *CubemapTexture, level int32, shader *Shader) error {
+	var framebuffer uint32
+	gl.GenFramebuffers(1, &framebuffer)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, framebuffer)
+	defer func() {
+		if CurrentlyBoundRenderTarget != nil {
+			gl.BindFramebuffer(gl.FRAMEBUFFER, CurrentlyBoundRenderTarget.FramebufferID)
+		} else {
+			gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+		}
+		gl.DeleteFramebuffers(1, &framebuffer)
+	}()
+
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	defer gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	levelSize := cube.Size >> uint(level)
+	if levelSize < 1 {
+		levelSize = 1
+	}
+	gl.Viewport(0, 0, levelSize, levelSize)
+
+	depthTest := gl.IsEnabled(gl.DEPTH_TEST)
+	gl.Disable(gl.DEPTH_TEST)
+	defer func() {
+		if depthTest {
+			gl.Enable(gl.DEPTH_TEST)
+		}
+	}()
+
+	triangle := fullScreenTriangleArray()
+	if err := triangle.Bind(); err != nil {
+		return err
+	}
+	defer triangle.UnBind()
+
+	for face := 0; face < CubeFaceCount; face++ {
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, uint32(gl.TEXTURE_CUBE_MAP_POSITIVE_X+face), cube.ID, level)
+		if face == 0 {
+			if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+				return errors.New("Incomplete Framebuffer: Cannot render into cubemap")
+			}
+		}
+		shader.SendParameterValue("u_Face", int32(face))
+		gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	}
+	return nil
+}
+
+// decodeImageFile opens and decodes any registered image format
+func decodeImageFile(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	return img, err
+}
+
+// mipCount returns the number of mip levels in a full chain for a texture size pixels across
+func mipCount(size int32) int32 {
+	count := int32(1)
+	for size > 1 {
+		size >>= 1
+		count++
+	}
+	return count
+}
@@ -0,0 +1,265 @@
+package gfx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// DDS header flags we care about
+const (
+	ddsMagic           = 0x20534444 // "DDS " little endian
+	ddsHeaderSize      = 124
+	ddsDX10HeaderSize  = 20
+	ddsPixelFourCC     = 0x4
+	ddsPixelRGB        = 0x40
+	ddsPixelLuminance  = 0x20000
+	ddsPixelAlpha      = 0x1
+	ddsCaps2Cubemap    = 0x200
+	ddsCaps2Volume     = 0x200000
+	ddsDX10MiscCubemap = 0x4
+)
+
+// ddsPixelFormat is the DDS_PIXELFORMAT structure
+type ddsPixelFormat struct {
+	Size        uint32
+	Flags       uint32
+	FourCC      uint32
+	RGBBitCount uint32
+	RBitMask    uint32
+	GBitMask    uint32
+	BBitMask    uint32
+	ABitMask    uint32
+}
+
+// ddsHeader is the DDS_HEADER structure that follows the magic number
+type ddsHeader struct {
+	Size              uint32
+	Flags             uint32
+	Height            uint32
+	Width             uint32
+	PitchOrLinearSize uint32
+	Depth             uint32
+	MipMapCount       uint32
+	Reserved1         [11]uint32
+	PixelFormat       ddsPixelFormat
+	Caps              uint32
+	Caps2             uint32
+	Caps3             uint32
+	Caps4             uint32
+	Reserved2         uint32
+}
+
+// ddsDX10Header is the DDS_HEADER_DXT10 structure that follows the header when the four CC is DX10
+type ddsDX10Header struct {
+	DXGIFormat        uint32
+	ResourceDimension uint32
+	MiscFlag          uint32
+	ArraySize         uint32
+	MiscFlags2        uint32
+}
+
+// ParseDDS reads a DirectDraw Surface file with BC1-7 compressed or common uncompressed pixels
+func ParseDDS(data []byte) (*TextureContainer, error) {
+	if len(data) < 4+ddsHeaderSize || binary.LittleEndian.Uint32(data) != ddsMagic {
+		return nil, errors.New("Invalid DDS File: Missing DDS magic number")
+	}
+
+	var header ddsHeader
+	if err := binary.Read(bytes.NewReader(data[4:4+ddsHeaderSize]), binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Size != ddsHeaderSize || header.PixelFormat.Size != 32 {
+		return nil, errors.New("Invalid DDS File: Bad header size")
+	}
+	if header.Width == 0 || header.Height == 0 {
+		return nil, errors.New("Invalid DDS File: Zero width or height")
+	}
+	if header.Width > textureContainerMaxSize || header.Height > textureContainerMaxSize {
+		return nil, fmt.Errorf("Invalid DDS File: %vx%v is too large", header.Width, header.Height)
+	}
+	// The smallest formats are half a byte per pixel, so a first level bigger than twice the file can't be real
+	if uint64(header.Width)*uint64(header.Height) > 2*uint64(len(data)) {
+		return nil, fmt.Errorf("Invalid DDS File: A %vx%v image can't fit in the file", header.Width, header.Height)
+	}
+	if header.Caps2&ddsCaps2Volume != 0 {
+		return nil, errors.New("Unsupported DDS File: Volume textures are not supported")
+	}
+
+	container := new(TextureContainer)
+	container.Width = int32(header.Width)
+	container.Height = int32(header.Height)
+	container.Layers = 1
+	container.Faces = 1
+	container.RowAlignment = 1
+	if header.Caps2&ddsCaps2Cubemap != 0 {
+		container.Faces = 6
+	}
+
+	offset := 4 + ddsHeaderSize
+	bytesPerPixel := 0
+	format := header.PixelFormat
+	if format.Flags&ddsPixelFourCC != 0 && format.FourCC == fourCC("DX10") {
+		if len(data) < offset+ddsDX10HeaderSize {
+			return nil, errors.New("Invalid DDS File: Missing DX10 header")
+		}
+		var dx10 ddsDX10Header
+		binary.Read(bytes.NewReader(data[offset:offset+ddsDX10HeaderSize]), binary.LittleEndian, &dx10)
+		offset += ddsDX10HeaderSize
+
+		if dx10.ArraySize > 1 {
+			container.Layers = int(dx10.ArraySize)
+		}
+		if dx10.MiscFlag&ddsDX10MiscCubemap != 0 {
+			container.Faces = 6
+		}
+		var err error
+		bytesPerPixel, err = ddsDXGIFormat(container, dx10.DXGIFormat)
+		if err != nil {
+			return nil, err
+		}
+	} else if format.Flags&ddsPixelFourCC != 0 {
+		switch format.FourCC {
+		case fourCC("DXT1"):
+			container.InternalFormat = compressedRGBAS3TCDXT1
+		case fourCC("DXT2"), fourCC("DXT3"):
+			container.InternalFormat = compressedRGBAS3TCDXT3
+		case fourCC("DXT4"), fourCC("DXT5"):
+			container.InternalFormat = compressedRGBAS3TCDXT5
+		case fourCC("ATI1"), fourCC("BC4U"):
+			container.InternalFormat = gl.COMPRESSED_RED_RGTC1
+		case fourCC("BC4S"):
+			container.InternalFormat = gl.COMPRESSED_SIGNED_RED_RGTC1
+		case fourCC("ATI2"), fourCC("BC5U"):
+			container.InternalFormat = gl.COMPRESSED_RG_RGTC2
+		case fourCC("BC5S"):
+			container.InternalFormat = gl.COMPRESSED_SIGNED_RG_RGTC2
+		default:
+			return nil, fmt.Errorf("Unsupported DDS File: Four CC %q", string([]byte{byte(format.FourCC), byte(format.FourCC >> 8), byte(format.FourCC >> 16), byte(format.FourCC >> 24)}))
+		}
+		container.Compressed = true
+	} else {
+		var err error
+		bytesPerPixel, err = ddsUncompressedFormat(container, format)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Every image of every level takes at least a byte, so bogus counts are caught before allocating
+	levels := containerLevelCount(header.MipMapCount, container.Width, container.Height)
+	remaining := len(data) - offset
+	if container.Layers > remaining || container.Layers*container.Faces*levels > remaining {
+		return nil, fmt.Errorf("Invalid DDS File: %v layers, %v faces and %v mip levels can't fit in the file", container.Layers, container.Faces, levels)
+	}
+	container.Levels = make([]TextureContainerLevel, 0, levels)
+	for level := 0; level < levels; level++ {
+		container.Levels = append(container.Levels, TextureContainerLevel{
+			Width:  mipDimension(container.Width, level),
+			Height: mipDimension(container.Height, level),
+			Images: make([][]byte, container.Layers*container.Faces),
+		})
+	}
+
+	// Unlike KTX, DDS stores each layer and face's whole mip chain together
+	for image := 0; image < container.Layers*container.Faces; image++ {
+		for level := range container.Levels {
+			mip := &container.Levels[level]
+			size := int(mip.Width) * int(mip.Height) * bytesPerPixel
+			if container.Compressed {
+				size = compressedImageSize(container.InternalFormat, mip.Width, mip.Height)
+			}
+			if size > len(data)-offset {
+				return nil, errors.New("Invalid DDS File: Ended before all image data")
+			}
+			mip.Images[image] = data[offset : offset+size]
+			offset += size
+		}
+	}
+
+	return container, nil
+}
+
+// ddsUncompressedFormat works out the GL formats for uncompressed pixels from their bit masks
+func ddsUncompressedFormat(container *TextureContainer, format ddsPixelFormat) (int, error) {
+	container.DataType = gl.UNSIGNED_BYTE
+	masks := [4]uint32{format.RBitMask, format.GBitMask, format.BBitMask, format.ABitMask}
+
+	switch {
+	case format.RGBBitCount == 32 && masks == [4]uint32{0xFF, 0xFF00, 0xFF0000, 0xFF000000}:
+		container.InternalFormat, container.Format = gl.RGBA8, gl.RGBA
+	case format.RGBBitCount == 32 && masks == [4]uint32{0xFF0000, 0xFF00, 0xFF, 0xFF000000}:
+		container.InternalFormat, container.Format = gl.RGBA8, gl.BGRA
+	case format.RGBBitCount == 32 && masks == [4]uint32{0xFF0000, 0xFF00, 0xFF, 0}:
+		// BGRX, the padding byte is read as alpha so force it opaque by storing RGB only
+		container.InternalFormat, container.Format = gl.RGB8, gl.BGRA
+	case format.RGBBitCount == 24 && masks == [4]uint32{0xFF, 0xFF00, 0xFF0000, 0}:
+		container.InternalFormat, container.Format = gl.RGB8, gl.RGB
+	case format.RGBBitCount == 24 && masks == [4]uint32{0xFF0000, 0xFF00, 0xFF, 0}:
+		container.InternalFormat, container.Format = gl.RGB8, gl.BGR
+	case format.RGBBitCount == 8 && format.Flags&(ddsPixelLuminance|ddsPixelAlpha) != 0:
+		container.InternalFormat, container.Format = gl.R8, gl.RED
+	default:
+		return 0, fmt.Errorf("Unsupported DDS File: %v bit pixels with masks %X", format.RGBBitCount, masks)
+	}
+	return int(format.RGBBitCount / 8), nil
+}
+
+// ddsDXGIFormat sets the GL formats for a DX10 header's DXGI_FORMAT. Returns the bytes per pixel, 0 if compressed
+func ddsDXGIFormat(container *TextureContainer, dxgiFormat uint32) (int, error) {
+	compressed := map[uint32]uint32{
+		71: compressedRGBAS3TCDXT1,         // BC1_UNORM
+		72: compressedSRGBAlphaS3TCDXT1,    // BC1_UNORM_SRGB
+		74: compressedRGBAS3TCDXT3,         // BC2_UNORM
+		75: compressedSRGBAlphaS3TCDXT3,    // BC2_UNORM_SRGB
+		77: compressedRGBAS3TCDXT5,         // BC3_UNORM
+		78: compressedSRGBAlphaS3TCDXT5,    // BC3_UNORM_SRGB
+		80: gl.COMPRESSED_RED_RGTC1,        // BC4_UNORM
+		81: gl.COMPRESSED_SIGNED_RED_RGTC1, // BC4_SNORM
+		83: gl.COMPRESSED_RG_RGTC2,         // BC5_UNORM
+		84: gl.COMPRESSED_SIGNED_RG_RGTC2,  // BC5_SNORM
+		95: compressedRGBBPTCFloat,         // BC6H_UF16
+		96: compressedRGBBPTCSignedFloat,   // BC6H_SF16
+		98: compressedRGBABPTCUnorm,        // BC7_UNORM
+		99: compressedSRGBAlphaBPTCUnorm,   // BC7_UNORM_SRGB
+	}
+	if internalFormat, ok := compressed[dxgiFormat]; ok {
+		container.InternalFormat = internalFormat
+		container.Compressed = true
+		return 0, nil
+	}
+
+	switch dxgiFormat {
+	case 2: // R32G32B32A32_FLOAT
+		container.InternalFormat, container.Format, container.DataType = gl.RGBA32F, gl.RGBA, gl.FLOAT
+		return 16, nil
+	case 10: // R16G16B16A16_FLOAT
+		container.InternalFormat, container.Format, container.DataType = gl.RGBA16F, gl.RGBA, gl.HALF_FLOAT
+		return 8, nil
+	case 28: // R8G8B8A8_UNORM
+		container.InternalFormat, container.Format, container.DataType = gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE
+		return 4, nil
+	case 29: // R8G8B8A8_UNORM_SRGB
+		container.InternalFormat, container.Format, container.DataType = gl.SRGB8_ALPHA8, gl.RGBA, gl.UNSIGNED_BYTE
+		return 4, nil
+	case 87: // B8G8R8A8_UNORM
+		container.InternalFormat, container.Format, container.DataType = gl.RGBA8, gl.BGRA, gl.UNSIGNED_BYTE
+		return 4, nil
+	case 91: // B8G8R8A8_UNORM_SRGB
+		container.InternalFormat, container.Format, container.DataType = gl.SRGB8_ALPHA8, gl.BGRA, gl.UNSIGNED_BYTE
+		return 4, nil
+	case 61: // R8_UNORM
+		container.InternalFormat, container.Format, container.DataType = gl.R8, gl.RED, gl.UNSIGNED_BYTE
+		return 1, nil
+	default:
+		return 0, fmt.Errorf("Unsupported DDS File: DXGI format %v", dxgiFormat)
+	}
+}
+
+// fourCC packs a four character code the way DDS stores it
+func fourCC(code string) uint32 {
+	return uint32(code[0]) | uint32(code[1])<<8 | uint32(code[2])<<16 | uint32(code[3])<<24
+}
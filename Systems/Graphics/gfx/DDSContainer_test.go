@@ -0,0 +1,84 @@
+package gfx
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+func TestParseDDS(t *testing.T) {
+	tests := []struct {
+		file           string
+		width, height  int32
+		layers, faces  int
+		levels         int
+		internalFormat uint32
+		compressed     bool
+	}{
+		{"mips.dds", 4, 4, 1, 1, 3, gl.RGBA8, false},
+		{"cube_dxt1.dds", 4, 4, 1, 6, 1, compressedRGBAS3TCDXT1, true},
+		{"dx10_array.dds", 2, 2, 2, 1, 2, gl.RGBA8, false},
+	}
+	for _, test := range tests {
+		container, err := ParseDDS(readTestFile(t, test.file))
+		if err != nil {
+			t.Errorf("%v: got error %v", test.file, err)
+			continue
+		}
+		checkContainerLayout(t, test.file, container, test.width, test.height, test.layers, test.faces, test.levels)
+		if container.InternalFormat != test.internalFormat || container.Compressed != test.compressed {
+			t.Errorf("%v: got format 0x%X compressed %v, expected 0x%X compressed %v", test.file,
+				container.InternalFormat, container.Compressed, test.internalFormat, test.compressed)
+		}
+	}
+}
+
+func TestParseDDSRejectsBadFiles(t *testing.T) {
+	valid := readTestFile(t, "mips.dds")
+	array := readTestFile(t, "dx10_array.dds")
+	// Offsets are from the start of the file, after the 4 byte magic number
+	withField := func(data []byte, offset int, value uint32) []byte {
+		patched := append([]byte{}, data...)
+		binary.LittleEndian.PutUint32(patched[offset:], value)
+		return patched
+	}
+	const (
+		offsetHeight    = 4 + 8
+		offsetWidth     = 4 + 12
+		offsetMipCount  = 4 + 24
+		offsetArraySize = 4 + ddsHeaderSize + 12
+	)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		contains string
+	}{
+		{"empty", nil, "magic"},
+		{"truncated header", valid[:64], "magic"},
+		{"truncated image data", valid[:len(valid)-1], "Ended before"},
+		{"truncated DX10 header", array[:4+ddsHeaderSize+8], "DX10"},
+		{"zero width", withField(valid, offsetWidth, 0), "Zero width"},
+		{"huge width", withField(valid, offsetWidth, 0xFFFFFFFF), "too large"},
+		{"huge height", withField(valid, offsetHeight, 0x80000000), "too large"},
+		{"bigger than the file", withField(withField(valid, offsetWidth, 1<<16), offsetHeight, 1<<16), "can't fit"},
+		{"huge array size", withField(array, offsetArraySize, 0xFFFFFFFF), "can't fit"},
+		{"array size past the data", withField(array, offsetArraySize, 100), "can't fit"},
+	}
+	for _, test := range tests {
+		_, err := ParseDDS(test.data)
+		if err == nil || !strings.Contains(err.Error(), test.contains) {
+			t.Errorf("%v: got error %v, expected one containing %q", test.name, err, test.contains)
+		}
+	}
+
+	// More levels than a full chain are ignored rather than read
+	container, err := ParseDDS(withField(valid, offsetMipCount, 0xFFFFFFFF))
+	if err != nil {
+		t.Errorf("oversized mip count: got error %v", err)
+	} else if len(container.Levels) != 3 {
+		t.Errorf("oversized mip count: got %v levels, expected 3", len(container.Levels))
+	}
+}
@@ -0,0 +1,53 @@
+package gfx
+
+import (
+	"github.com/Surreal/Math/math"
+	"github.com/Surreal/Systems/Core/core"
+)
+
+// DirectionalLightComponent lights everything from the direction it's scene object is facing, like the sun
+type DirectionalLightComponent struct {
+	*core.BaseComponent
+	Color     math.Vector3f  // The color of the light
+	Intensity float32        // Multiplier applied to Color
+	Shadows   ShadowSettings // How this light casts shadows
+}
+
+// CreateDirectionalLightComponent is the standard constructor for a DirectionalLightComponent
+func CreateDirectionalLightComponent(color math.Vector3f, intensity float32) *DirectionalLightComponent {
+	light := new(DirectionalLightComponent)
+	light.BaseComponent = core.CreateBaseComponent(light)
+	light.Color = color
+	light.Intensity = intensity
+	light.Shadows = DefaultShadowSettings()
+	return light
+}
+
+// LightType implements the Light interface
+func (light *DirectionalLightComponent) LightType() LightType {
+	return DirectionalLight
+}
+
+// ShadowSettings implements the Light interface
+func (light *DirectionalLightComponent) ShadowSettings() *ShadowSettings {
+	return &light.Shadows
+}
+
+// OnEnable implements the core.Enabler interface
+func (light *DirectionalLightComponent) OnEnable() {
+	registerLight(light)
+}
+
+// OnDisable implements the core.Disabler interface
+func (light *DirectionalLightComponent) OnDisable() {
+	unregisterLight(light)
+}
+
+func (light *DirectionalLightComponent) lightInfo() lightInfo {
+	return lightInfo{
+		source:    light,
+		lightType: DirectionalLight,
+		direction: light.SceneObject().Transform.Forward(),
+		color:     light.Color.Scale(light.Intensity),
+	}
+}
@@ -0,0 +1,184 @@
+package gfx
+
+import (
+	"fmt"
+
+	"github.com/Surreal/Debug/dbg"
+	"github.com/Surreal/Math/math"
+)
+
+// Properties a GLTFAnimationChannel animates
+const (
+	GLTFPathTranslation = "translation" // Values are the node's local position
+	GLTFPathRotation    = "rotation"    // Values are the node's local rotation as x, y, z, w quaternions
+	GLTFPathScale       = "scale"       // Values are the node's local scale
+	GLTFPathWeights     = "weights"     // Values are morph target weights
+)
+
+// How a GLTFAnimationChannel gets between keyframes
+const (
+	GLTFInterpolationLinear      = "LINEAR"      // Lerp, or slerp for rotations
+	GLTFInterpolationStep        = "STEP"        // Hold each keyframe until the next
+	GLTFInterpolationCubicSpline = "CUBICSPLINE" // Hermite spline. Each keyframe has an in tangent, value and out tangent
+)
+
+// GLTFSkin is the joints a skinned mesh is bound to. A vertex's S_Joints index into Joints
+type GLTFSkin struct {
+	Name                string
+	Joints              []*GLTFNode
+	InverseBindMatrices []math.Matrix4x4 // Takes a vertex from mesh space into each joint's space at bind time
+	Skeleton            *GLTFNode        // The root of the joint hierarchy, nil if the file doesn't say
+	jointIndices        []int
+	skeletonIndex       int // -1 if there isn't one
+}
+
+// GLTFAnimation is a set of channels meant to play together. Nothing plays them yet, they're kept as data
+type GLTFAnimation struct {
+	Name     string
+	Channels []*GLTFAnimationChannel
+	Duration float32 // The time of the last keyframe of any channel in seconds
+}
+
+// GLTFAnimationChannel is the keyframes of one property of one node
+type GLTFAnimationChannel struct {
+	Target        *GLTFNode
+	Path          string    // One of the GLTFPath constants
+	Interpolation string    // One of the GLTFInterpolation constants
+	Times         []float32 // The time of each keyframe in seconds
+	Values        []float32 // Components values per keyframe, three times that for cubic splines
+	Components    int       // 3 for translation and scale, 4 for rotation, the morph target count for weights
+	targetIndex   int
+}
+
+// Keyframes returns how many keyframes the channel has
+func (channel *GLTFAnimationChannel) Keyframes() int {
+	return len(channel.Times)
+}
+
+// readGLTFSkins reads every skin's joints and inverse bind matrices. Nodes are filled in once they exist
+func readGLTFSkins(file *gltfFile) ([]*GLTFSkin, error) {
+	skins := make([]*GLTFSkin, len(file.Skins))
+	for i, source := range file.Skins {
+		skin := &GLTFSkin{Name: source.Name, jointIndices: source.Joints, skeletonIndex: -1}
+		if source.Skeleton != nil {
+			skin.skeletonIndex = *source.Skeleton
+		}
+
+		skin.InverseBindMatrices = make([]math.Matrix4x4, len(source.Joints))
+		if source.InverseBindMatrices == nil {
+			// Without them the joints are already in mesh space at bind time
+			for j := range skin.InverseBindMatrices {
+				skin.InverseBindMatrices[j] = math.Matrix4x4Identity()
+			}
+		} else {
+			values, components, err := file.accessorFloats(*source.InverseBindMatrices)
+			if err != nil {
+				return nil, err
+			}
+			if components != 16 || len(values) < 16*len(source.Joints) {
+				return nil, fmt.Errorf("Invalid glTF File: Skin %v needs a MAT4 inverse bind matrix per joint", i)
+			}
+			// glTF matrices are column major like ours
+			for j := range skin.InverseBindMatrices {
+				copy(skin.InverseBindMatrices[j][:], values[j*16:])
+			}
+		}
+		skins[i] = skin
+	}
+	return skins, nil
+}
+
+// readGLTFAnimations reads every animation's keyframes. Target nodes are filled in once they exist. Channels without a
+// target, which only extensions use, are left out
+func readGLTFAnimations(file *gltfFile) ([]*GLTFAnimation, error) {
+	animations := make([]*GLTFAnimation, len(file.Animations))
+	for i, source := range file.Animations {
+		animation := &GLTFAnimation{Name: source.Name}
+		for _, sourceChannel := range source.Channels {
+			if sourceChannel.Target.Node == nil {
+				continue
+			}
+			if sourceChannel.Sampler < 0 || sourceChannel.Sampler >= len(source.Samplers) {
+				return nil, fmt.Errorf("Invalid glTF File: Animation %v uses sampler %v which doesn't exist", i, sourceChannel.Sampler)
+			}
+			sampler := source.Samplers[sourceChannel.Sampler]
+
+			channel := &GLTFAnimationChannel{
+				Path:          sourceChannel.Target.Path,
+				Interpolation: sampler.Interpolation,
+				targetIndex:   *sourceChannel.Target.Node,
+			}
+			if channel.Interpolation == "" {
+				channel.Interpolation = GLTFInterpolationLinear
+			}
+			switch channel.Path {
+			case GLTFPathTranslation, GLTFPathRotation, GLTFPathScale, GLTFPathWeights:
+			default:
+				dbg.LogError(fmt.Sprintf("glTF Importer: Skipping animation channel with unknown path %v", channel.Path))
+				continue
+			}
+
+			var err error
+			if channel.Times, _, err = file.accessorFloats(sampler.Input); err != nil {
+				return nil, err
+			}
+			var components int
+			if channel.Values, components, err = file.accessorFloats(sampler.Output); err != nil {
+				return nil, err
+			}
+
+			// Weights are scalars with one per morph target per keyframe
+			valuesPerKeyframe := len(channel.Times)
+			if channel.Interpolation == GLTFInterpolationCubicSpline {
+				valuesPerKeyframe *= 3
+			}
+			channel.Components = components
+			if channel.Path == GLTFPathWeights && valuesPerKeyframe > 0 {
+				channel.Components = len(channel.Values) / valuesPerKeyframe
+			}
+			if channel.Components == 0 || len(channel.Values) != valuesPerKeyframe*channel.Components {
+				return nil, fmt.Errorf("Invalid glTF File: Animation %v has a channel whose keyframe times and values don't match", i)
+			}
+
+			if count := len(channel.Times); count > 0 && channel.Times[count-1] > animation.Duration {
+				animation.Duration = channel.Times[count-1]
+			}
+			animation.Channels = append(animation.Channels, channel)
+		}
+		animations[i] = animation
+	}
+	return animations, nil
+}
+
+// resolveGLTFNodes points skins and animation channels at the nodes they use
+func resolveGLTFNodes(nodes []*GLTFNode, skins []*GLTFSkin, animations []*GLTFAnimation) error {
+	node := func(index int) (*GLTFNode, error) {
+		if index < 0 || index >= len(nodes) {
+			return nil, fmt.Errorf("Invalid glTF File: Node %v doesn't exist", index)
+		}
+		return nodes[index], nil
+	}
+
+	var err error
+	for _, skin := range skins {
+		skin.Joints = make([]*GLTFNode, len(skin.jointIndices))
+		for i, index := range skin.jointIndices {
+			if skin.Joints[i], err = node(index); err != nil {
+				return err
+			}
+		}
+		if skin.skeletonIndex >= 0 {
+			if skin.Skeleton, err = node(skin.skeletonIndex); err != nil {
+				return err
+			}
+		}
+	}
+	for _, animation := range animations {
+		for _, channel := range animation.Channels {
+			if channel.Target, err = node(channel.targetIndex); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,552 @@
+package gfx
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	gomath "math"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Magic numbers of the binary glTF container
+const (
+	glbMagic     uint32 = 0x46546C67 // "glTF"
+	glbChunkJSON uint32 = 0x4E4F534A // "JSON"
+	glbChunkBIN  uint32 = 0x004E4942 // "BIN\0"
+)
+
+// Accessor component types, which are the same as the gl enums
+const (
+	gltfByte          = 5120
+	gltfUnsignedByte  = 5121
+	gltfShort         = 5122
+	gltfUnsignedShort = 5123
+	gltfUnsignedInt   = 5125
+	gltfFloat         = 5126
+)
+
+// gltfMaxUnbackedValues is the most values an accessor without a buffer view can have. They're all zeros besides any
+// sparse values, so nothing in the file limits how many there are
+const gltfMaxUnbackedValues = 1 << 24
+
+// gltfTypeComponents is how many components each accessor type has
+var gltfTypeComponents = map[string]int{
+	"SCALAR": 1, "VEC2": 2, "VEC3": 3, "VEC4": 4, "MAT2": 4, "MAT3": 9, "MAT4": 16,
+}
+
+// gltfSupportedExtensions are the extensions a file can require. Quantized meshes are just normalized integer
+// attributes, which accessors already handle
+var gltfSupportedExtensions = map[string]bool{
+	"KHR_mesh_quantization": true,
+}
+
+// gltfDocument is the json part of a glTF file. Only what the importer uses is read
+type gltfDocument struct {
+	Asset struct {
+		Version    string `json:"version"`
+		MinVersion string `json:"minVersion"`
+	} `json:"asset"`
+	ExtensionsRequired []string         `json:"extensionsRequired"`
+	Scene              *int             `json:"scene"`
+	Scenes             []gltfScene      `json:"scenes"`
+	Nodes              []gltfNode       `json:"nodes"`
+	Meshes             []gltfMesh       `json:"meshes"`
+	Materials          []gltfMaterial   `json:"materials"`
+	Textures           []gltfTexture    `json:"textures"`
+	Images             []gltfImage      `json:"images"`
+	Samplers           []gltfSampler    `json:"samplers"`
+	Skins              []gltfSkin       `json:"skins"`
+	Animations         []gltfAnimation  `json:"animations"`
+	Accessors          []gltfAccessor   `json:"accessors"`
+	BufferViews        []gltfBufferView `json:"bufferViews"`
+	Buffers            []gltfBuffer     `json:"buffers"`
+}
+
+type gltfScene struct {
+	Name  string `json:"name"`
+	Nodes []int  `json:"nodes"`
+}
+
+type gltfNode struct {
+	Name        string       `json:"name"`
+	Children    []int        `json:"children"`
+	Mesh        *int         `json:"mesh"`
+	Skin        *int         `json:"skin"`
+	Matrix      *[16]float32 `json:"matrix"`
+	Translation *[3]float32  `json:"translation"`
+	Rotation    *[4]float32  `json:"rotation"`
+	Scale       *[3]float32  `json:"scale"`
+}
+
+type gltfMesh struct {
+	Name       string          `json:"name"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices"`
+	Material   *int           `json:"material"`
+	Mode       *int           `json:"mode"`
+}
+
+type gltfTextureInfo struct {
+	Index    int     `json:"index"`
+	TexCoord int     `json:"texCoord"`
+	Scale    float32 `json:"scale"`    // Only normal textures have this
+	Strength float32 `json:"strength"` // Only occlusion textures have this
+}
+
+type gltfMaterial struct {
+	Name                 string `json:"name"`
+	PbrMetallicRoughness struct {
+		BaseColorFactor          [4]float32       `json:"baseColorFactor"`
+		BaseColorTexture         *gltfTextureInfo `json:"baseColorTexture"`
+		MetallicFactor           float32          `json:"metallicFactor"`
+		RoughnessFactor          float32          `json:"roughnessFactor"`
+		MetallicRoughnessTexture *gltfTextureInfo `json:"metallicRoughnessTexture"`
+	} `json:"pbrMetallicRoughness"`
+	NormalTexture    *gltfTextureInfo `json:"normalTexture"`
+	OcclusionTexture *gltfTextureInfo `json:"occlusionTexture"`
+	EmissiveTexture  *gltfTextureInfo `json:"emissiveTexture"`
+	EmissiveFactor   [3]float32       `json:"emissiveFactor"`
+	AlphaMode        string           `json:"alphaMode"`
+	AlphaCutoff      float32          `json:"alphaCutoff"`
+	DoubleSided      bool             `json:"doubleSided"`
+}
+
+// UnmarshalJSON fills in the spec's defaults for anything the file leaves out
+func (material *gltfMaterial) UnmarshalJSON(data []byte) error {
+	type plain gltfMaterial
+	decoded := plain{AlphaMode: "OPAQUE", AlphaCutoff: 0.5}
+	decoded.PbrMetallicRoughness.BaseColorFactor = [4]float32{1, 1, 1, 1}
+	decoded.PbrMetallicRoughness.MetallicFactor = 1
+	decoded.PbrMetallicRoughness.RoughnessFactor = 1
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*material = gltfMaterial(decoded)
+	return nil
+}
+
+// UnmarshalJSON fills in the spec's defaults for anything the file leaves out
+func (info *gltfTextureInfo) UnmarshalJSON(data []byte) error {
+	type plain gltfTextureInfo
+	decoded := plain{Scale: 1, Strength: 1}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*info = gltfTextureInfo(decoded)
+	return nil
+}
+
+type gltfTexture struct {
+	Sampler *int `json:"sampler"`
+	Source  *int `json:"source"`
+}
+
+type gltfImage struct {
+	Name       string `json:"name"`
+	URI        string `json:"uri"`
+	BufferView *int   `json:"bufferView"`
+	MimeType   string `json:"mimeType"`
+}
+
+type gltfSampler struct {
+	MagFilter int `json:"magFilter"`
+	MinFilter int `json:"minFilter"`
+	WrapS     int `json:"wrapS"`
+	WrapT     int `json:"wrapT"`
+}
+
+type gltfSkin struct {
+	Name                string `json:"name"`
+	InverseBindMatrices *int   `json:"inverseBindMatrices"`
+	Skeleton            *int   `json:"skeleton"`
+	Joints              []int  `json:"joints"`
+}
+
+type gltfAnimation struct {
+	Name     string `json:"name"`
+	Channels []struct {
+		Sampler int `json:"sampler"`
+		Target  struct {
+			Node *int   `json:"node"`
+			Path string `json:"path"`
+		} `json:"target"`
+	} `json:"channels"`
+	Samplers []struct {
+		Input         int    `json:"input"`
+		Output        int    `json:"output"`
+		Interpolation string `json:"interpolation"`
+	} `json:"samplers"`
+}
+
+type gltfAccessor struct {
+	BufferView    *int   `json:"bufferView"`
+	ByteOffset    int    `json:"byteOffset"`
+	ComponentType int    `json:"componentType"`
+	Normalized    bool   `json:"normalized"`
+	Count         int    `json:"count"`
+	Type          string `json:"type"`
+	Sparse        *struct {
+		Count   int `json:"count"`
+		Indices struct {
+			BufferView    int `json:"bufferView"`
+			ByteOffset    int `json:"byteOffset"`
+			ComponentType int `json:"componentType"`
+		} `json:"indices"`
+		Values struct {
+			BufferView int `json:"bufferView"`
+			ByteOffset int `json:"byteOffset"`
+		} `json:"values"`
+	} `json:"sparse"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride"`
+}
+
+type gltfBuffer struct {
+	URI        string `json:"uri"`
+	ByteLength int    `json:"byteLength"`
+}
+
+// gltfFile is a parsed glTF document along with the contents of all of it's buffers
+type gltfFile struct {
+	gltfDocument
+	directory string   // External files are relative to this
+	buffers   [][]byte // The data of each buffer
+}
+
+// readGLTF parses a .gltf or .glb file's contents and loads the buffers it refers to
+func readGLTF(filePath string, fileData []byte) (*gltfFile, error) {
+	file := &gltfFile{directory: filepath.Dir(filePath)}
+
+	jsonData, binChunk := fileData, []byte(nil)
+	if len(fileData) >= 4 && binary.LittleEndian.Uint32(fileData) == glbMagic {
+		var err error
+		if jsonData, binChunk, err = readGLBChunks(fileData); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := json.Unmarshal(jsonData, &file.gltfDocument); err != nil {
+		return nil, errors.New("Invalid glTF File: " + err.Error())
+	}
+	if !strings.HasPrefix(file.Asset.Version, "2.") {
+		return nil, fmt.Errorf("Unsupported glTF File: Version %v, only 2.x is supported", file.Asset.Version)
+	}
+	// Extensions the file only uses optionally can be ignored, but required ones change what the data means
+	for _, extension := range file.ExtensionsRequired {
+		if !gltfSupportedExtensions[extension] {
+			return nil, errors.New("Unsupported glTF File: Requires extension " + extension)
+		}
+	}
+
+	for i, buffer := range file.Buffers {
+		var data []byte
+		var err error
+		if buffer.URI == "" {
+			// Only a glb's first buffer may leave out the uri, it's the BIN chunk
+			if i != 0 || binChunk == nil {
+				return nil, fmt.Errorf("Invalid glTF File: Buffer %v has no uri", i)
+			}
+			data = binChunk
+		} else if data, err = file.readURI(buffer.URI); err != nil {
+			return nil, err
+		}
+		if buffer.ByteLength < 0 {
+			return nil, fmt.Errorf("Invalid glTF File: Buffer %v has a negative byteLength", i)
+		}
+		if len(data) < buffer.ByteLength {
+			return nil, fmt.Errorf("Invalid glTF File: Buffer %v is %v bytes but should be %v", i, len(data), buffer.ByteLength)
+		}
+		file.buffers = append(file.buffers, data[:buffer.ByteLength])
+	}
+
+	return file, nil
+}
+
+// readGLBChunks splits a binary glTF into it's json and binary chunks. The binary chunk is nil if there isn't one
+func readGLBChunks(fileData []byte) ([]byte, []byte, error) {
+	if len(fileData) < 12 {
+		return nil, nil, errors.New("Invalid glb File: Too short for the header")
+	}
+	if version := binary.LittleEndian.Uint32(fileData[4:]); version != 2 {
+		return nil, nil, fmt.Errorf("Unsupported glb File: Container version %v, only 2 is supported", version)
+	}
+	length := int(binary.LittleEndian.Uint32(fileData[8:]))
+	if length > len(fileData) || length < 12 {
+		return nil, nil, errors.New("Invalid glb File: Header length doesn't match the file")
+	}
+
+	var jsonChunk, binChunk []byte
+	offset := 12
+	for offset+8 <= length {
+		chunkLength := int(binary.LittleEndian.Uint32(fileData[offset:]))
+		chunkType := binary.LittleEndian.Uint32(fileData[offset+4:])
+		offset += 8
+		if chunkLength < 0 || chunkLength > length-offset {
+			return nil, nil, errors.New("Invalid glb File: Chunk runs past the end of the file")
+		}
+		chunk := fileData[offset : offset+chunkLength]
+		offset += chunkLength
+
+		switch {
+		case chunkType == glbChunkJSON && jsonChunk == nil:
+			jsonChunk = chunk
+		case chunkType == glbChunkBIN && binChunk == nil && jsonChunk != nil:
+			binChunk = chunk
+		}
+		// Unknown chunks are to be skipped
+	}
+	if jsonChunk == nil {
+		return nil, nil, errors.New("Invalid glb File: No JSON chunk")
+	}
+	return jsonChunk, binChunk, nil
+}
+
+// readURI returns the data behind a buffer or image uri, which is either a base64 data uri or a path relative to the
+// glTF file
+func (file *gltfFile) readURI(uri string) ([]byte, error) {
+	if strings.HasPrefix(uri, "data:") {
+		comma := strings.IndexByte(uri, ',')
+		if comma < 0 || !strings.HasSuffix(uri[:comma], ";base64") {
+			return nil, errors.New("Unsupported glTF File: Data uris must be base64")
+		}
+		data, err := base64.StdEncoding.DecodeString(uri[comma+1:])
+		if err != nil {
+			return nil, errors.New("Invalid glTF File: Bad base64 data uri, " + err.Error())
+		}
+		return data, nil
+	}
+
+	path, err := file.uriPath(uri)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(path)
+}
+
+// uriPath turns a uri that isn't a data uri into a file path
+func (file *gltfFile) uriPath(uri string) (string, error) {
+	// Relative paths are uri encoded, i.e. spaces are %20
+	path, err := url.PathUnescape(uri)
+	if err != nil {
+		return "", errors.New("Invalid glTF File: Bad uri " + uri)
+	}
+	path = filepath.FromSlash(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(file.directory, path)
+	}
+	return path, nil
+}
+
+// bufferView returns the bytes of a buffer view
+func (file *gltfFile) bufferView(index int) ([]byte, gltfBufferView, error) {
+	if index < 0 || index >= len(file.BufferViews) {
+		return nil, gltfBufferView{}, fmt.Errorf("Invalid glTF File: Buffer view %v doesn't exist", index)
+	}
+	view := file.BufferViews[index]
+	if view.Buffer < 0 || view.Buffer >= len(file.buffers) {
+		return nil, view, fmt.Errorf("Invalid glTF File: Buffer %v doesn't exist", view.Buffer)
+	}
+	buffer := file.buffers[view.Buffer]
+	// Compared by subtracting so huge values can't overflow past the check
+	if view.ByteOffset < 0 || view.ByteLength < 0 || view.ByteOffset > len(buffer) || view.ByteLength > len(buffer)-view.ByteOffset {
+		return nil, view, fmt.Errorf("Invalid glTF File: Buffer view %v runs past the end of it's buffer", index)
+	}
+	return buffer[view.ByteOffset : view.ByteOffset+view.ByteLength], view, nil
+}
+
+// accessorFloats reads an accessor as floats. Returns the values and the number of components per element
+func (file *gltfFile) accessorFloats(index int) ([]float32, int, error) {
+	values, components, err := file.accessorValues(index)
+	if err != nil {
+		return nil, 0, err
+	}
+	floats := make([]float32, len(values))
+	for i, value := range values {
+		floats[i] = float32(value)
+	}
+	return floats, components, nil
+}
+
+// accessorValues reads an accessor, applying normalization and sparse substitution. Values are float64 so 32 bit
+// integers come through exactly
+func (file *gltfFile) accessorValues(index int) ([]float64, int, error) {
+	if index < 0 || index >= len(file.Accessors) {
+		return nil, 0, fmt.Errorf("Invalid glTF File: Accessor %v doesn't exist", index)
+	}
+	accessor := file.Accessors[index]
+	components, ok := gltfTypeComponents[accessor.Type]
+	if !ok {
+		return nil, 0, fmt.Errorf("Invalid glTF File: Accessor %v has unknown type %v", index, accessor.Type)
+	}
+	size := gltfComponentSize(accessor.ComponentType)
+	if size == 0 {
+		return nil, 0, fmt.Errorf("Invalid glTF File: Accessor %v has unknown component type %v", index, accessor.ComponentType)
+	}
+	if accessor.Count < 0 {
+		return nil, 0, fmt.Errorf("Invalid glTF File: Accessor %v has a negative count", index)
+	}
+
+	// Without a buffer view everything is 0 until sparse values say otherwise
+	var values []float64
+	if accessor.BufferView != nil {
+		data, view, err := file.bufferView(*accessor.BufferView)
+		if err != nil {
+			return nil, 0, err
+		}
+		stride := view.ByteStride
+		if stride == 0 {
+			stride = size * components
+		}
+		if values, err = readGLTFComponents(data, accessor.ByteOffset, stride, components, accessor); err != nil {
+			return nil, 0, fmt.Errorf("%v (accessor %v)", err, index)
+		}
+	} else {
+		// Nothing backs the count, so cap it before allocating the zeros
+		if accessor.Count > gltfMaxUnbackedValues/components {
+			return nil, 0, fmt.Errorf("Invalid glTF File: Accessor %v has no buffer view and a count of %v, more than the %v values allowed", index, accessor.Count, gltfMaxUnbackedValues)
+		}
+		values = make([]float64, accessor.Count*components)
+	}
+
+	if sparse := accessor.Sparse; sparse != nil {
+		if sparse.Count < 0 || sparse.Count > accessor.Count {
+			return nil, 0, fmt.Errorf("Invalid glTF File: Accessor %v has a bad sparse count", index)
+		}
+		indexData, _, err := file.bufferView(sparse.Indices.BufferView)
+		if err != nil {
+			return nil, 0, err
+		}
+		indexAccessor := gltfAccessor{ComponentType: sparse.Indices.ComponentType, Count: sparse.Count}
+		indexSize := gltfComponentSize(sparse.Indices.ComponentType)
+		switch sparse.Indices.ComponentType {
+		case gltfUnsignedByte, gltfUnsignedShort, gltfUnsignedInt:
+		default:
+			return nil, 0, fmt.Errorf("Invalid glTF File: Sparse indices of accessor %v aren't unsigned integers", index)
+		}
+		indices, err := readGLTFComponents(indexData, sparse.Indices.ByteOffset, indexSize, 1, indexAccessor)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%v (sparse indices of accessor %v)", err, index)
+		}
+
+		valueData, _, err := file.bufferView(sparse.Values.BufferView)
+		if err != nil {
+			return nil, 0, err
+		}
+		valueAccessor := accessor
+		valueAccessor.Count = sparse.Count
+		substitutes, err := readGLTFComponents(valueData, sparse.Values.ByteOffset, size*components, components, valueAccessor)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%v (sparse values of accessor %v)", err, index)
+		}
+
+		for i, element := range indices {
+			if element >= float64(accessor.Count) {
+				return nil, 0, fmt.Errorf("Invalid glTF File: Sparse index %v is out of range (accessor %v)", element, index)
+			}
+			copy(values[int(element)*components:], substitutes[i*components:(i+1)*components])
+		}
+	}
+
+	return values, components, nil
+}
+
+// accessorUints reads an accessor of unsigned integers, i.e. indices or joints
+func (file *gltfFile) accessorUints(index int) ([]uint32, error) {
+	values, _, err := file.accessorValues(index)
+	if err != nil {
+		return nil, err
+	}
+	switch file.Accessors[index].ComponentType {
+	case gltfUnsignedByte, gltfUnsignedShort, gltfUnsignedInt:
+	default:
+		return nil, fmt.Errorf("Invalid glTF File: Accessor %v should be unsigned integers", index)
+	}
+	if file.Accessors[index].Normalized {
+		return nil, fmt.Errorf("Invalid glTF File: Accessor %v should not be normalized", index)
+	}
+
+	uints := make([]uint32, len(values))
+	for i, value := range values {
+		uints[i] = uint32(value)
+	}
+	return uints, nil
+}
+
+// gltfComponentSize returns the size in bytes of a component type, or 0 if it isn't one
+func gltfComponentSize(componentType int) int {
+	switch componentType {
+	case gltfByte, gltfUnsignedByte:
+		return 1
+	case gltfShort, gltfUnsignedShort:
+		return 2
+	case gltfUnsignedInt, gltfFloat:
+		return 4
+	}
+	return 0
+}
+
+// readGLTFComponents reads accessor.Count elements of components each, starting at offset and stride bytes apart
+func readGLTFComponents(data []byte, offset int, stride int, components int, accessor gltfAccessor) ([]float64, error) {
+	size := gltfComponentSize(accessor.ComponentType)
+	if accessor.Count == 0 {
+		return nil, nil
+	}
+	// Checked before allocating so a bad count can't ask for more memory than the file has data
+	// Compared by subtracting so huge offsets, strides or counts can't overflow past the check
+	if offset < 0 || offset > len(data) || stride < size*components || accessor.Count-1 > (len(data)-offset)/stride ||
+		stride*(accessor.Count-1) > len(data)-offset-size*components {
+		return nil, errors.New("Invalid glTF File: Accessor runs past the end of it's buffer view")
+	}
+
+	values := make([]float64, accessor.Count*components)
+
+	for element := 0; element < accessor.Count; element++ {
+		start := offset + element*stride
+		for component := 0; component < components; component++ {
+			at := data[start+component*size:]
+			var value float64
+			switch accessor.ComponentType {
+			case gltfByte:
+				value = float64(int8(at[0]))
+				if accessor.Normalized {
+					value = gomath.Max(value/127, -1)
+				}
+			case gltfUnsignedByte:
+				value = float64(at[0])
+				if accessor.Normalized {
+					value /= 255
+				}
+			case gltfShort:
+				value = float64(int16(binary.LittleEndian.Uint16(at)))
+				if accessor.Normalized {
+					value = gomath.Max(value/32767, -1)
+				}
+			case gltfUnsignedShort:
+				value = float64(binary.LittleEndian.Uint16(at))
+				if accessor.Normalized {
+					value /= 65535
+				}
+			case gltfUnsignedInt:
+				value = float64(binary.LittleEndian.Uint32(at))
+			case gltfFloat:
+				value = float64(gomath.Float32frombits(binary.LittleEndian.Uint32(at)))
+			}
+			values[element*components+component] = value
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,87 @@
+package gfx
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// gltfTestDocument makes a glTF with one buffer holding data as a data uri, then the given json fields
+func gltfTestDocument(data []byte, byteLength int, fields string) []byte {
+	return []byte(fmt.Sprintf(`{"asset": {"version": "2.0"}, "buffers": [{"uri": "data:application/octet-stream;base64,%v", "byteLength": %v}]%v}`,
+		base64.StdEncoding.EncodeToString(data), byteLength, fields))
+}
+
+func TestGLTFAccessorValues(t *testing.T) {
+	// Two unsigned shorts, 1 and 2, then a float of 1.5 at offset 4
+	data := []byte{1, 0, 2, 0, 0, 0, 0xC0, 0x3F}
+	document := gltfTestDocument(data, len(data), `,
+		"bufferViews": [{"buffer": 0, "byteLength": 4}, {"buffer": 0, "byteOffset": 4, "byteLength": 4}],
+		"accessors": [
+			{"bufferView": 0, "componentType": 5123, "count": 2, "type": "SCALAR"},
+			{"bufferView": 1, "componentType": 5126, "count": 1, "type": "SCALAR"},
+			{"componentType": 5126, "count": 3, "type": "VEC2"}
+		]`)
+	file, err := readGLTF("test.gltf", document)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	tests := []struct {
+		accessor int
+		expected []float64
+	}{
+		{0, []float64{1, 2}},
+		{1, []float64{1.5}},
+		{2, []float64{0, 0, 0, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		values, _, err := file.accessorValues(test.accessor)
+		if err != nil {
+			t.Errorf("accessor %v: got error %v", test.accessor, err)
+			continue
+		}
+		if fmt.Sprint(values) != fmt.Sprint(test.expected) {
+			t.Errorf("accessor %v: got %v, expected %v", test.accessor, values, test.expected)
+		}
+	}
+}
+
+func TestGLTFRejectsMalformedSizes(t *testing.T) {
+	data := make([]byte, 16)
+	huge := "9223372036854775807"
+
+	if _, err := readGLTF("test.gltf", gltfTestDocument(data, -1, "")); err == nil || !strings.Contains(err.Error(), "negative") {
+		t.Errorf("negative byteLength: got error %v, expected a negative byteLength error", err)
+	}
+
+	tests := []struct {
+		name     string
+		fields   string
+		contains string
+	}{
+		{"view offset plus length overflows", `, "bufferViews": [{"buffer": 0, "byteOffset": 8, "byteLength": ` + huge + `}],
+			"accessors": [{"bufferView": 0, "componentType": 5121, "count": 1, "type": "SCALAR"}]`, "past the end"},
+		{"view offset past the buffer", `, "bufferViews": [{"buffer": 0, "byteOffset": ` + huge + `, "byteLength": 1}],
+			"accessors": [{"bufferView": 0, "componentType": 5121, "count": 1, "type": "SCALAR"}]`, "past the end"},
+		{"no buffer view and a huge count", `, "accessors": [{"componentType": 5126, "count": ` + huge + `, "type": "MAT4"}]`, "no buffer view"},
+		{"accessor stride overflows", `, "bufferViews": [{"buffer": 0, "byteLength": 16, "byteStride": 4611686018427387904}],
+			"accessors": [{"bufferView": 0, "componentType": 5126, "count": 3, "type": "SCALAR"}]`, "past the end"},
+		{"accessor offset overflows", `, "bufferViews": [{"buffer": 0, "byteLength": 16}],
+			"accessors": [{"bufferView": 0, "byteOffset": ` + huge + `, "componentType": 5126, "count": 2, "type": "SCALAR"}]`, "past the end"},
+		{"accessor count past the view", `, "bufferViews": [{"buffer": 0, "byteLength": 16}],
+			"accessors": [{"bufferView": 0, "componentType": 5126, "count": ` + huge + `, "type": "SCALAR"}]`, "past the end"},
+	}
+	for _, test := range tests {
+		file, err := readGLTF("test.gltf", gltfTestDocument(data, len(data), test.fields))
+		if err != nil {
+			t.Errorf("%v: got error %v reading the document", test.name, err)
+			continue
+		}
+		_, _, err = file.accessorValues(0)
+		if err == nil || !strings.Contains(err.Error(), test.contains) {
+			t.Errorf("%v: got error %v, expected one containing %q", test.name, err, test.contains)
+		}
+	}
+}
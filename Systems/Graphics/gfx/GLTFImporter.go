@@ -0,0 +1,712 @@
+package gfx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/Surreal/Debug/dbg"
+	"github.com/Surreal/Math/math"
+	"github.com/Surreal/Systems/Core/core"
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// gltfAttributes maps glTF vertex attributes onto the MeshData attributes they're read into, position first. Further
+// uv, color and joint sets are left out
+var gltfAttributes = []struct {
+	gltfName   string
+	components int
+	field      func(data *MeshData) *[]float32
+}{
+	{"POSITION", 3, func(data *MeshData) *[]float32 { return &data.Positions }},
+	{"NORMAL", 3, func(data *MeshData) *[]float32 { return &data.Normals }},
+	{"TEXCOORD_0", 2, func(data *MeshData) *[]float32 { return &data.TexCoords }},
+	{"TEXCOORD_1", 2, func(data *MeshData) *[]float32 { return &data.TexCoords2 }},
+	{"TANGENT", 4, func(data *MeshData) *[]float32 { return &data.Tangents }},
+	{"COLOR_0", 4, func(data *MeshData) *[]float32 { return &data.Colors }},
+	{"JOINTS_0", 4, func(data *MeshData) *[]float32 { return &data.Joints }},
+	{"WEIGHTS_0", 4, func(data *MeshData) *[]float32 { return &data.Weights }},
+}
+
+// GLTFScene is everything imported from a .gltf or .glb file
+type GLTFScene struct {
+	Root               *core.SceneObject // Parent of the root nodes of the file's default scene
+	Nodes              []*GLTFNode       // Every node in the file, in file order
+	Meshes             [][]*Mesh         // Meshes[m][p] is primitive p of the file's mesh m, nil if it isn't triangles
+	PrimitiveMaterials [][]*Material     // The lit material each primitive renders with
+	Materials          []*GLTFMaterial   // Every material in file order
+	Textures           []*Texture        // Every texture the materials use
+	Skins              []*GLTFSkin
+	Animations         []*GLTFAnimation
+}
+
+// GLTFNode is one node of a glTF file's hierarchy
+type GLTFNode struct {
+	Name        string
+	SceneObject *core.SceneObject // Has the node's local transform. Each primitive of it's mesh is a child rendering it
+	Parent      *GLTFNode         // nil for root nodes
+	Children    []*GLTFNode
+	Mesh        int       // Index into GLTFScene.Meshes, -1 if the node has none
+	Skin        *GLTFSkin // The skin the node's mesh is bound to, nil if it isn't skinned
+}
+
+// GLTFMaterial is a glTF metallic roughness material. The lit shader isn't physically based so Material is only an
+// approximation, everything else is kept for shaders that are. The lit shader samples every texture with the first uv
+// set, whichever one the file asked for
+type GLTFMaterial struct {
+	Name                     string
+	BaseColor                math.Vector4f
+	BaseColorTexture         *Texture
+	Metallic                 float32
+	Roughness                float32
+	MetallicRoughnessTexture *Texture // Roughness in green, metallic in blue
+	NormalTexture            *Texture
+	NormalScale              float32
+	OcclusionTexture         *Texture // Occlusion in red
+	OcclusionStrength        float32
+	EmissiveColor            math.Vector3f
+	EmissiveTexture          *Texture
+	AlphaMode                string // OPAQUE, MASK or BLEND
+	AlphaCutoff              float32
+	DoubleSided              bool
+	Material                 *Material // For the lit shader
+	vertexColorMaterial      *Material // Material with vertex colors on, made once a primitive with colors uses it
+}
+
+// gltfTextureKey is a texture as a material uses it. Color textures are sRGB, data textures like normal maps aren't
+type gltfTextureKey struct {
+	index int
+	srgb  bool
+}
+
+// importedGLTF is a glTF file decoded on the CPU, ready to be sent to the GPU
+type importedGLTF struct {
+	file        *gltfFile
+	primitives  [][]*importedPrimitive
+	textureKeys []gltfTextureKey // The textures in the order the materials first use them
+	textures    map[gltfTextureKey]importedTexture
+	skins       []*GLTFSkin
+	animations  []*GLTFAnimation
+}
+
+// importedPrimitive is the vertex data of one glTF primitive
+type importedPrimitive struct {
+	data     *MeshData
+	material int // -1 for the default material
+}
+
+// isGLTFFile returns true if filePath is a .gltf or .glb file
+func isGLTFFile(filePath string) bool {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".gltf", ".glb":
+		return true
+	}
+	return false
+}
+
+// ImportGLTF imports a .gltf or .glb file with it's node hierarchy, materials, skins and animations. The scene
+// object of every node in the file's default scene is under the returned scene's Root
+func ImportGLTF(filePath string) (*GLTFScene, error) {
+	fileData, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	imported, err := parseGLTF(filePath, fileData)
+	if err != nil {
+		return nil, err
+	}
+	return imported.build()
+}
+
+// Delete frees every mesh and texture the import made
+func (scene *GLTFScene) Delete() {
+	for _, primitives := range scene.Meshes {
+		for _, mesh := range primitives {
+			if mesh != nil {
+				mesh.Delete()
+			}
+		}
+	}
+	for _, texture := range scene.Textures {
+		texture.Delete()
+	}
+}
+
+// flatten returns every primitive's mesh and material, leaving out the ones that aren't triangles
+func (scene *GLTFScene) flatten() ([]*Mesh, []*Material) {
+	var meshes []*Mesh
+	var materials []*Material
+	for m, primitives := range scene.Meshes {
+		for p, mesh := range primitives {
+			if mesh != nil {
+				meshes = append(meshes, mesh)
+				materials = append(materials, scene.PrimitiveMaterials[m][p])
+			}
+		}
+	}
+	return meshes, materials
+}
+
+// deleteUnlitTextures deletes the textures none of the lit materials use, i.e. metallic roughness maps, for callers
+// that only keep the meshes and materials
+func (scene *GLTFScene) deleteUnlitTextures() {
+	used := make(map[*Texture]bool)
+	for _, materials := range scene.PrimitiveMaterials {
+		for _, material := range materials {
+			if material == nil {
+				continue
+			}
+			for _, texture := range material.ShaderTextures {
+				used[texture] = true
+			}
+		}
+	}
+
+	var kept []*Texture
+	for _, texture := range scene.Textures {
+		if used[texture] {
+			kept = append(kept, texture)
+		} else {
+			texture.Delete()
+		}
+	}
+	scene.Textures = kept
+}
+
+// parseGLTF reads a glTF file's buffers, vertex data, images, skins and animations. It doesn't touch openGL so it can
+// run on any goroutine
+func parseGLTF(filePath string, fileData []byte) (*importedGLTF, error) {
+	file, err := readGLTF(filePath, fileData)
+	if err != nil {
+		return nil, err
+	}
+	imported := &importedGLTF{file: file, textures: make(map[gltfTextureKey]importedTexture)}
+
+	for m, mesh := range file.Meshes {
+		primitives := make([]*importedPrimitive, len(mesh.Primitives))
+		for p, primitive := range mesh.Primitives {
+			if primitives[p], err = readGLTFPrimitive(file, primitive); err != nil {
+				return nil, fmt.Errorf("%v (mesh %v primitive %v)", err, m, p)
+			}
+			if primitives[p] != nil {
+				primitives[p].data.Name = mesh.Name
+			}
+		}
+		imported.primitives = append(imported.primitives, primitives)
+	}
+
+	for _, material := range file.Materials {
+		imported.decodeTexture(material.PbrMetallicRoughness.BaseColorTexture, true)
+		imported.decodeTexture(material.PbrMetallicRoughness.MetallicRoughnessTexture, false)
+		imported.decodeTexture(material.NormalTexture, false)
+		imported.decodeTexture(material.OcclusionTexture, false)
+		imported.decodeTexture(material.EmissiveTexture, true)
+	}
+
+	if imported.skins, err = readGLTFSkins(file); err != nil {
+		return nil, err
+	}
+	if imported.animations, err = readGLTFAnimations(file); err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+// readGLTFPrimitive reads a primitive's vertices and turns it into a triangle list. Returns nil for points, lines and
+// primitives too short to make a triangle, which meshes can't draw
+func readGLTFPrimitive(file *gltfFile, primitive gltfPrimitive) (*importedPrimitive, error) {
+	mode := 4
+	if primitive.Mode != nil {
+		mode = *primitive.Mode
+	}
+	if mode < 4 || mode > 6 {
+		dbg.LogError("glTF Importer: Skipping a primitive of points or lines, only triangles are supported")
+		return nil, nil
+	}
+	if _, ok := primitive.Attributes["POSITION"]; !ok {
+		return nil, errors.New("Invalid glTF File: Primitive has no POSITION")
+	}
+
+	imported := &importedPrimitive{data: new(MeshData), material: -1}
+	data := imported.data
+	if primitive.Material != nil {
+		if *primitive.Material < 0 || *primitive.Material >= len(file.Materials) {
+			return nil, fmt.Errorf("Invalid glTF File: Material %v doesn't exist", *primitive.Material)
+		}
+		imported.material = *primitive.Material
+	}
+
+	vertexCount := 0
+	for _, attribute := range gltfAttributes {
+		index, ok := primitive.Attributes[attribute.gltfName]
+		if !ok {
+			continue
+		}
+		values, components, err := file.accessorFloats(index)
+		if err != nil {
+			return nil, err
+		}
+		if attribute.gltfName == "COLOR_0" && components == 3 {
+			values, components = gltfAddAlpha(values), 4
+		}
+		if components != attribute.components {
+			return nil, fmt.Errorf("Invalid glTF File: %v should have %v components, not %v", attribute.gltfName, attribute.components, components)
+		}
+
+		count := len(values) / components
+		if attribute.gltfName == "POSITION" {
+			vertexCount = count
+		} else if count != vertexCount {
+			return nil, fmt.Errorf("Invalid glTF File: %v has %v values but POSITION has %v", attribute.gltfName, count, vertexCount)
+		}
+
+		switch attribute.gltfName {
+		case "TEXCOORD_0", "TEXCOORD_1":
+			// glTF uvs start at the top of the image, ours at the bottom
+			for i := 1; i < len(values); i += 2 {
+				values[i] = 1 - values[i]
+			}
+		case "TANGENT":
+			// Flipping v mirrors the bitangent too
+			for i := 3; i < len(values); i += 4 {
+				values[i] = -values[i]
+			}
+		}
+		*attribute.field(data) = values
+	}
+
+	var err error
+	if primitive.Indices != nil {
+		if data.Indices, err = file.accessorUints(*primitive.Indices); err != nil {
+			return nil, err
+		}
+		for _, index := range data.Indices {
+			if int(index) >= vertexCount {
+				return nil, fmt.Errorf("Invalid glTF File: Index %v is out of range of %v vertices", index, vertexCount)
+			}
+		}
+	} else {
+		data.Indices = make([]uint32, vertexCount)
+		for i := range data.Indices {
+			data.Indices[i] = uint32(i)
+		}
+	}
+	data.Indices = gltfTriangleList(data.Indices, mode)
+	if len(data.Indices) == 0 {
+		dbg.LogError("glTF Importer: Skipping a primitive without a whole triangle")
+		return nil, nil
+	}
+
+	// Without normals the spec asks for flat shading
+	if data.Normals == nil {
+		data.CalculateFlatNormals()
+	}
+	data.CalculateBounds()
+	return imported, nil
+}
+
+// gltfAddAlpha turns rgb colors into rgba
+func gltfAddAlpha(rgb []float32) []float32 {
+	rgba := make([]float32, len(rgb)/3*4)
+	for i := 0; i < len(rgb)/3; i++ {
+		copy(rgba[i*4:], rgb[i*3:i*3+3])
+		rgba[i*4+3] = 1
+	}
+	return rgba
+}
+
+// gltfTriangleList turns triangle strip (5) and triangle fan (6) indices into a triangle list. Triangle lists (4) lose
+// any trailing indices that don't make a whole triangle
+func gltfTriangleList(indices []uint32, mode int) []uint32 {
+	var triangles []uint32
+	switch mode {
+	case 5:
+		for i := 0; i+2 < len(indices); i++ {
+			// Every other triangle of a strip winds the other way
+			if i%2 == 0 {
+				triangles = append(triangles, indices[i], indices[i+1], indices[i+2])
+			} else {
+				triangles = append(triangles, indices[i+1], indices[i], indices[i+2])
+			}
+		}
+	case 6:
+		for i := 1; i+1 < len(indices); i++ {
+			triangles = append(triangles, indices[0], indices[i], indices[i+1])
+		}
+	default:
+		triangles = indices[:len(indices)-len(indices)%3]
+	}
+	return triangles
+}
+
+// decodeTexture decodes a texture a material uses, unless it already has been
+func (imported *importedGLTF) decodeTexture(info *gltfTextureInfo, srgb bool) {
+	if info == nil {
+		return
+	}
+	key := gltfTextureKey{index: info.Index, srgb: srgb}
+	if _, ok := imported.textures[key]; ok {
+		return
+	}
+	decoded, err := decodeGLTFTexture(imported.file, info.Index, srgb)
+	imported.textureKeys = append(imported.textureKeys, key)
+	imported.textures[key] = importedTexture{decoded: decoded, err: err}
+}
+
+// decodeGLTFTexture decodes a texture's image, which is embedded in a buffer, a data uri or a file next to the glTF
+func decodeGLTFTexture(file *gltfFile, index int, srgb bool) (*decodedTexture, error) {
+	if index < 0 || index >= len(file.Textures) {
+		return nil, fmt.Errorf("Invalid glTF File: Texture %v doesn't exist", index)
+	}
+	source := file.Textures[index].Source
+	if source == nil || *source < 0 || *source >= len(file.Images) {
+		return nil, fmt.Errorf("Invalid glTF File: Texture %v has no image", index)
+	}
+	gltfImage := file.Images[*source]
+
+	var data []byte
+	var err error
+	switch {
+	case gltfImage.BufferView != nil:
+		data, _, err = file.bufferView(*gltfImage.BufferView)
+	case strings.HasPrefix(gltfImage.URI, "data:"):
+		data, err = file.readURI(gltfImage.URI)
+	default:
+		// Files go through the usual path so they can be containers too
+		path, err := file.uriPath(gltfImage.URI)
+		if err != nil {
+			return nil, err
+		}
+		return decodeTextureFile(path, ChannelsRGBA, srgb)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid glTF File: Image %v can't be decoded, %v", *source, err.Error())
+	}
+	textureData, err := CreateTextureData(img, ChannelsRGBA, srgb, true)
+	if err != nil {
+		return nil, err
+	}
+	return &decodedTexture{data: textureData}, nil
+}
+
+// build sends everything to the GPU and makes the scene objects
+func (imported *importedGLTF) build() (*GLTFScene, error) {
+	scene := imported.upload()
+	if err := imported.buildNodes(scene); err != nil {
+		scene.Delete()
+		return nil, err
+	}
+	return scene, nil
+}
+
+// upload sends the textures and meshes to the GPU and makes the materials, without making any scene objects. Textures
+// that fail are logged and left off their materials
+func (imported *importedGLTF) upload() *GLTFScene {
+	file := imported.file
+	scene := new(GLTFScene)
+
+	textures := make(map[gltfTextureKey]*Texture)
+	for _, key := range imported.textureKeys {
+		decoded := imported.textures[key]
+		if decoded.err != nil {
+			dbg.LogError(fmt.Sprintf("glTF Importer: Texture %v: %v", key.index, decoded.err.Error()))
+			continue
+		}
+		texture := CreateTexture("")
+		texture.SRGB = key.srgb
+		texture.Channels = ChannelsRGBA
+		imported.applySampler(texture, key.index)
+		if err := texture.uploadDecoded(decoded.decoded); err != nil {
+			dbg.LogError(fmt.Sprintf("glTF Importer: Texture %v: %v", key.index, err.Error()))
+			texture.Delete()
+			continue
+		}
+		textures[key] = texture
+		scene.Textures = append(scene.Textures, texture)
+	}
+	texture := func(info *gltfTextureInfo, srgb bool) *Texture {
+		if info == nil {
+			return nil
+		}
+		return textures[gltfTextureKey{index: info.Index, srgb: srgb}]
+	}
+
+	for _, source := range file.Materials {
+		pbr := source.PbrMetallicRoughness
+		material := &GLTFMaterial{
+			Name:                     source.Name,
+			BaseColor:                math.Vector4f{X: pbr.BaseColorFactor[0], Y: pbr.BaseColorFactor[1], Z: pbr.BaseColorFactor[2], W: pbr.BaseColorFactor[3]},
+			BaseColorTexture:         texture(pbr.BaseColorTexture, true),
+			Metallic:                 pbr.MetallicFactor,
+			Roughness:                pbr.RoughnessFactor,
+			MetallicRoughnessTexture: texture(pbr.MetallicRoughnessTexture, false),
+			NormalTexture:            texture(source.NormalTexture, false),
+			NormalScale:              1,
+			OcclusionTexture:         texture(source.OcclusionTexture, false),
+			OcclusionStrength:        1,
+			EmissiveColor:            math.Vector3f{X: source.EmissiveFactor[0], Y: source.EmissiveFactor[1], Z: source.EmissiveFactor[2]},
+			EmissiveTexture:          texture(source.EmissiveTexture, true),
+			AlphaMode:                source.AlphaMode,
+			AlphaCutoff:              source.AlphaCutoff,
+			DoubleSided:              source.DoubleSided,
+		}
+		if source.NormalTexture != nil {
+			material.NormalScale = source.NormalTexture.Scale
+		}
+		if source.OcclusionTexture != nil {
+			material.OcclusionStrength = source.OcclusionTexture.Strength
+		}
+		material.Material = material.createLitMaterial(false)
+		scene.Materials = append(scene.Materials, material)
+	}
+
+	var vertexColorMaterial *Material
+	for _, primitives := range imported.primitives {
+		meshes := make([]*Mesh, len(primitives))
+		materials := make([]*Material, len(primitives))
+		for p, primitive := range primitives {
+			if primitive == nil {
+				continue
+			}
+			meshes[p] = primitive.data.Upload()[0]
+
+			colors := primitive.data.Colors != nil
+			switch {
+			case primitive.material >= 0:
+				materials[p] = scene.Materials[primitive.material].litMaterial(colors)
+			case colors:
+				if vertexColorMaterial == nil {
+					vertexColorMaterial = CreateLitMaterial()
+					vertexColorMaterial.SetMaterialParameter("u_UseVertexColor", int32(1))
+				}
+				materials[p] = vertexColorMaterial
+			default:
+				materials[p] = DefaultMeshMaterial()
+			}
+		}
+		scene.Meshes = append(scene.Meshes, meshes)
+		scene.PrimitiveMaterials = append(scene.PrimitiveMaterials, materials)
+	}
+
+	return scene
+}
+
+// applySampler sets a texture's wrapping and filtering from the sampler of the glTF texture. Without one it repeats
+// and is trilinear filtered
+func (imported *importedGLTF) applySampler(texture *Texture, index int) {
+	sampler := gltfSampler{MagFilter: gl.LINEAR, MinFilter: gl.LINEAR_MIPMAP_LINEAR, WrapS: gl.REPEAT, WrapT: gl.REPEAT}
+	if source := imported.file.Textures[index].Sampler; source != nil && *source >= 0 && *source < len(imported.file.Samplers) {
+		// Samplers use the gl enums, 0 means the file left it up to us
+		given := imported.file.Samplers[*source]
+		if given.MagFilter != 0 {
+			sampler.MagFilter = given.MagFilter
+		}
+		if given.MinFilter != 0 {
+			sampler.MinFilter = given.MinFilter
+		}
+		if given.WrapS != 0 {
+			sampler.WrapS = given.WrapS
+		}
+		if given.WrapT != 0 {
+			sampler.WrapT = given.WrapT
+		}
+	}
+
+	texture.GenerateMipMaps = sampler.MinFilter != gl.NEAREST && sampler.MinFilter != gl.LINEAR
+	texture.SetHorizontalWrapMode(sampler.WrapS)
+	texture.SetVerticalWrapMode(sampler.WrapT)
+	texture.SetMinFilterMode(sampler.MinFilter)
+	texture.SetMagFilterMode(sampler.MagFilter)
+}
+
+// litMaterial returns the lit material for a primitive using this material, with vertex colors on if it has them
+func (material *GLTFMaterial) litMaterial(vertexColors bool) *Material {
+	if !vertexColors {
+		return material.Material
+	}
+	if material.vertexColorMaterial == nil {
+		material.vertexColorMaterial = material.createLitMaterial(true)
+	}
+	return material.vertexColorMaterial
+}
+
+// createLitMaterial approximates the material with the lit shader. Rougher surfaces get a wider, dimmer highlight and
+// metals a stronger one, but metals still have a diffuse color and the metallic roughness, occlusion and emissive
+// textures aren't used
+func (material *GLTFMaterial) createLitMaterial(vertexColors bool) *Material {
+	lit := CreateLitMaterial()
+
+	alpha := material.BaseColor.W
+	if material.AlphaMode == "OPAQUE" {
+		alpha = 1
+	}
+	roughness := math.Clamp(material.Roughness, 0, 1)
+	metallic := math.Clamp(material.Metallic, 0, 1)
+	// The Blinn-Phong exponent whose highlight is about as wide as a GGX highlight of this roughness
+	width := math.Max(roughness*roughness, 0.03)
+	useVertexColor := int32(0)
+	if vertexColors {
+		useVertexColor = 1
+	}
+
+	lit.SetMaterialParameters(map[string]interface{}{
+		"u_Tint":              []float32{material.BaseColor.X, material.BaseColor.Y, material.BaseColor.Z, alpha},
+		"u_UseAlbedoMap":      int32(0),
+		"u_UseVertexColor":    useVertexColor,
+		"u_SpecularStrength":  (1 - roughness) * math.Lerp(0.25, 1, metallic),
+		"u_Shininess":         2/(width*width) - 2,
+		"u_NormalMapStrength": material.NormalScale,
+	})
+	if material.BaseColorTexture != nil {
+		lit.SetTextureParameter("u_Albedo", material.BaseColorTexture)
+		lit.SetMaterialParameter("u_UseAlbedoMap", int32(1))
+	}
+	if material.NormalTexture != nil {
+		lit.SetTextureParameter("u_NormalMap", material.NormalTexture)
+		lit.SetMaterialParameter("u_UseNormalMap", int32(1))
+	}
+	return lit
+}
+
+// buildNodes makes a scene object for every node, parents them like the file does and puts the default scene's roots
+// under scene.Root
+func (imported *importedGLTF) buildNodes(scene *GLTFScene) error {
+	file := imported.file
+
+	scene.Nodes = make([]*GLTFNode, len(file.Nodes))
+	for i, source := range file.Nodes {
+		node := &GLTFNode{Name: source.Name, SceneObject: core.CreateSceneObject(nil), Mesh: -1}
+		position, rotation, scale := gltfNodeTransform(source)
+		node.SceneObject.Transform.SetLocalPosition(position)
+		node.SceneObject.Transform.SetLocalRotationQuaternion(rotation)
+		node.SceneObject.Transform.SetLocalScale(scale)
+
+		if source.Mesh != nil {
+			if *source.Mesh < 0 || *source.Mesh >= len(scene.Meshes) {
+				return fmt.Errorf("Invalid glTF File: Node %v uses mesh %v which doesn't exist", i, *source.Mesh)
+			}
+			node.Mesh = *source.Mesh
+			for p, mesh := range scene.Meshes[node.Mesh] {
+				if mesh == nil {
+					continue
+				}
+				renderer := CreateMeshRendererComponent(mesh, scene.PrimitiveMaterials[node.Mesh][p])
+				core.CreateSceneObject(renderer).Transform.SetParent(node.SceneObject.Transform, false)
+			}
+		}
+		if source.Skin != nil {
+			if *source.Skin < 0 || *source.Skin >= len(imported.skins) {
+				return fmt.Errorf("Invalid glTF File: Node %v uses skin %v which doesn't exist", i, *source.Skin)
+			}
+			node.Skin = imported.skins[*source.Skin]
+		}
+		scene.Nodes[i] = node
+	}
+
+	for i, source := range file.Nodes {
+		parent := scene.Nodes[i]
+		for _, childIndex := range source.Children {
+			if childIndex < 0 || childIndex >= len(scene.Nodes) {
+				return fmt.Errorf("Invalid glTF File: Node %v has child %v which doesn't exist", i, childIndex)
+			}
+			child := scene.Nodes[childIndex]
+			if child.Parent != nil {
+				return fmt.Errorf("Invalid glTF File: Node %v has more than one parent", childIndex)
+			}
+			if err := child.SceneObject.Transform.SetParent(parent.SceneObject.Transform, false); err != nil {
+				return fmt.Errorf("Invalid glTF File: Node %v is it's own ancestor", childIndex)
+			}
+			child.Parent = parent
+			parent.Children = append(parent.Children, child)
+		}
+	}
+
+	if err := resolveGLTFNodes(scene.Nodes, imported.skins, imported.animations); err != nil {
+		return err
+	}
+	scene.Skins = imported.skins
+	scene.Animations = imported.animations
+
+	// Without any scenes every root node is shown
+	var roots []int
+	switch {
+	case file.Scene != nil:
+		if *file.Scene < 0 || *file.Scene >= len(file.Scenes) {
+			return fmt.Errorf("Invalid glTF File: Scene %v doesn't exist", *file.Scene)
+		}
+		roots = file.Scenes[*file.Scene].Nodes
+	case len(file.Scenes) > 0:
+		roots = file.Scenes[0].Nodes
+	default:
+		for i, node := range scene.Nodes {
+			if node.Parent == nil {
+				roots = append(roots, i)
+			}
+		}
+	}
+
+	scene.Root = core.CreateSceneObject(nil)
+	for _, index := range roots {
+		if index < 0 || index >= len(scene.Nodes) || scene.Nodes[index].Parent != nil {
+			return fmt.Errorf("Invalid glTF File: Scene root %v isn't a root node", index)
+		}
+		scene.Nodes[index].SceneObject.Transform.SetParent(scene.Root.Transform, false)
+	}
+	return nil
+}
+
+// gltfNodeTransform returns a node's local position, rotation and scale
+func gltfNodeTransform(node gltfNode) (math.Vector3f, math.Quaternion, math.Vector3f) {
+	if node.Matrix != nil {
+		return decomposeGLTFMatrix(*node.Matrix)
+	}
+
+	position, rotation, scale := math.Vector3f{}, math.IdentityQuaternion(), math.OnesVector3f()
+	if t := node.Translation; t != nil {
+		position = math.Vector3f{X: t[0], Y: t[1], Z: t[2]}
+	}
+	if r := node.Rotation; r != nil {
+		rotation = math.Quaternion{X: r[0], Y: r[1], Z: r[2], W: r[3]}.Normalize()
+	}
+	if s := node.Scale; s != nil {
+		scale = math.Vector3f{X: s[0], Y: s[1], Z: s[2]}
+	}
+	return position, rotation, scale
+}
+
+// decomposeGLTFMatrix splits a column major transform into position, rotation and scale. Node matrices can't have
+// shear, so nothing is lost
+func decomposeGLTFMatrix(matrix [16]float32) (math.Vector3f, math.Quaternion, math.Vector3f) {
+	position := math.Vector3f{X: matrix[12], Y: matrix[13], Z: matrix[14]}
+	columns := [3]math.Vector3f{
+		{X: matrix[0], Y: matrix[1], Z: matrix[2]},
+		{X: matrix[4], Y: matrix[5], Z: matrix[6]},
+		{X: matrix[8], Y: matrix[9], Z: matrix[10]},
+	}
+	scales := [3]float32{columns[0].Length(), columns[1].Length(), columns[2].Length()}
+	// A mirrored basis needs a negative scale to leave a rotation behind
+	if columns[0].Cross(columns[1]).Dot(columns[2]) < 0 {
+		scales[0] = -scales[0]
+	}
+
+	basis := math.Matrix3x3Identity()
+	for c, column := range columns {
+		if scales[c] == 0 {
+			continue
+		}
+		column = column.Scale(1 / scales[c])
+		basis.Set(0, c, column.X)
+		basis.Set(1, c, column.Y)
+		basis.Set(2, c, column.Z)
+	}
+	return position, math.QuaternionFromRotationMatrix(&basis), math.Vector3f{X: scales[0], Y: scales[1], Z: scales[2]}
+}
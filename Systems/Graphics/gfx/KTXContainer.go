@@ -0,0 +1,163 @@
+package gfx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ktxIdentifier starts every KTX 1.1 file
+var ktxIdentifier = []byte{0xAB, 'K', 'T', 'X', ' ', '1', '1', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+// ktxHeaderSize is the size of the identifier plus the 13 header fields
+const ktxHeaderSize = 12 + 13*4
+
+// ktxHeader is the fixed part of a KTX file after the identifier
+type ktxHeader struct {
+	Endianness            uint32
+	GLType                uint32
+	GLTypeSize            uint32
+	GLFormat              uint32
+	GLInternalFormat      uint32
+	GLBaseInternalFormat  uint32
+	PixelWidth            uint32
+	PixelHeight           uint32
+	PixelDepth            uint32
+	NumberOfArrayElements uint32
+	NumberOfFaces         uint32
+	NumberOfMipmapLevels  uint32
+	BytesOfKeyValueData   uint32
+}
+
+// ParseKTX reads a KTX 1.1 file. The format stores openGL enums directly so any format the GPU supports will load
+func ParseKTX(data []byte) (*TextureContainer, error) {
+	if len(data) < ktxHeaderSize || !bytes.Equal(data[:12], ktxIdentifier) {
+		return nil, errors.New("Invalid KTX File: Missing KTX 11 identifier")
+	}
+
+	// The writer's endianness is stored as 0x04030201, so reading it backwards means every value needs swapping
+	var order binary.ByteOrder = binary.LittleEndian
+	if binary.LittleEndian.Uint32(data[12:]) != 0x04030201 {
+		order = binary.BigEndian
+		if order.Uint32(data[12:]) != 0x04030201 {
+			return nil, errors.New("Invalid KTX File: Bad endianness marker")
+		}
+	}
+
+	var header ktxHeader
+	if err := binary.Read(bytes.NewReader(data[12:ktxHeaderSize]), order, &header); err != nil {
+		return nil, err
+	}
+	if header.PixelWidth == 0 {
+		return nil, errors.New("Invalid KTX File: Zero width")
+	}
+	if header.PixelWidth > textureContainerMaxSize || header.PixelHeight > textureContainerMaxSize {
+		return nil, fmt.Errorf("Invalid KTX File: %vx%v is too large", header.PixelWidth, header.PixelHeight)
+	}
+	if header.PixelDepth > 1 {
+		return nil, errors.New("Unsupported KTX File: 3D textures are not supported")
+	}
+	if header.NumberOfFaces != 1 && header.NumberOfFaces != 6 {
+		return nil, fmt.Errorf("Invalid KTX File: %v faces, must be 1 or 6", header.NumberOfFaces)
+	}
+
+	container := new(TextureContainer)
+	container.Width = int32(header.PixelWidth)
+	container.Height = int32(header.PixelHeight)
+	if container.Height == 0 {
+		container.Height = 1
+	}
+	container.Layers = int(header.NumberOfArrayElements)
+	if container.Layers == 0 {
+		container.Layers = 1
+	}
+	container.Faces = int(header.NumberOfFaces)
+	container.InternalFormat = header.GLInternalFormat
+	container.Format = header.GLFormat
+	container.DataType = header.GLType
+	container.Compressed = header.GLType == 0
+	container.RowAlignment = 4 // KTX pads rows like openGL's default unpack alignment
+
+	offset := ktxHeaderSize + int(header.BytesOfKeyValueData)
+	if offset > len(data) {
+		return nil, errors.New("Invalid KTX File: Key/value data runs past the end of the file")
+	}
+
+	// 0 levels means the loader should generate the chain, so there's only the first level in the file
+	levels := containerLevelCount(header.NumberOfMipmapLevels, container.Width, container.Height)
+
+	// Every image takes at least a byte and every level a 4 byte size, so bogus counts are caught before allocating
+	remaining := len(data) - offset
+	if container.Layers > remaining || container.Layers*container.Faces > remaining {
+		return nil, fmt.Errorf("Invalid KTX File: %v layers and %v faces can't fit in the file", container.Layers, container.Faces)
+	}
+	if levels > remaining/4 {
+		return nil, fmt.Errorf("Invalid KTX File: %v mip levels can't fit in the file", levels)
+	}
+
+	// Non array cubemaps give the size of each face, everything else gives the size of the whole level
+	perFace := header.NumberOfFaces == 6 && header.NumberOfArrayElements == 0
+	images := container.Layers * container.Faces
+	container.Levels = make([]TextureContainerLevel, 0, levels)
+	for level := 0; level < levels; level++ {
+		if len(data)-offset < 4 {
+			return nil, errors.New("Invalid KTX File: Ended before all mip levels")
+		}
+		imageSize := int(order.Uint32(data[offset:]))
+		offset += 4
+		if imageSize == 0 || imageSize > len(data)-offset {
+			return nil, errors.New("Invalid KTX File: Ended before all image data")
+		}
+
+		mip := TextureContainerLevel{
+			Width:  mipDimension(container.Width, level),
+			Height: mipDimension(container.Height, level),
+			Images: make([][]byte, images),
+		}
+
+		faceSize := imageSize
+		if !perFace {
+			if imageSize%images != 0 {
+				return nil, errors.New("Invalid KTX File: Level size doesn't divide between its layers and faces")
+			}
+			faceSize = imageSize / images
+		}
+		for i := 0; i < images; i++ {
+			if faceSize > len(data)-offset {
+				return nil, errors.New("Invalid KTX File: Ended before all image data")
+			}
+			image := data[offset : offset+faceSize]
+			if order != binary.LittleEndian && !container.Compressed {
+				image = swapKTXEndianness(image, header.GLTypeSize)
+			}
+			mip.Images[i] = image
+			offset += faceSize
+
+			// Each cubemap face is padded to 4 bytes
+			if perFace {
+				offset += 3 - (faceSize+3)%4
+			}
+		}
+		// As is each mip level
+		offset += 3 - (imageSize+3)%4
+		container.Levels = append(container.Levels, mip)
+	}
+
+	return container, nil
+}
+
+// swapKTXEndianness returns a copy of image with every typeSize byte element reversed
+func swapKTXEndianness(image []byte, typeSize uint32) []byte {
+	if typeSize <= 1 {
+		return image
+	}
+	swapped := make([]byte, len(image))
+	size := int(typeSize)
+	for i := 0; i+size <= len(image); i += size {
+		for j := 0; j < size; j++ {
+			swapped[i+j] = image[i+size-1-j]
+		}
+	}
+	return swapped
+}
@@ -0,0 +1,141 @@
+package gfx
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// readTestFile returns a file from testdata, failing the test if it's missing
+func readTestFile(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading %v: %v", name, err)
+	}
+	return data
+}
+
+// checkContainerLayout checks a container's sizes and that every image is filled with level * 16 + image, which is
+// how the test files are made
+func checkContainerLayout(t *testing.T, name string, container *TextureContainer, width int32, height int32, layers int, faces int, levels int) {
+	t.Helper()
+	if container.Width != width || container.Height != height || container.Layers != layers || container.Faces != faces || len(container.Levels) != levels {
+		t.Errorf("%v: got %vx%v with %v layers, %v faces and %v levels, expected %vx%v with %v layers, %v faces and %v levels", name,
+			container.Width, container.Height, container.Layers, container.Faces, len(container.Levels), width, height, layers, faces, levels)
+		return
+	}
+	for level, mip := range container.Levels {
+		if mip.Width != mipDimension(width, level) || mip.Height != mipDimension(height, level) {
+			t.Errorf("%v: got level %v at %vx%v, expected %vx%v", name, level, mip.Width, mip.Height, mipDimension(width, level), mipDimension(height, level))
+		}
+		if len(mip.Images) != layers*faces {
+			t.Errorf("%v: got %v images in level %v, expected %v", name, len(mip.Images), level, layers*faces)
+			continue
+		}
+		for i, image := range mip.Images {
+			if len(image) == 0 || image[0] != byte(level*16+i) {
+				t.Errorf("%v: level %v image %v doesn't start with %v", name, level, i, level*16+i)
+			}
+		}
+	}
+}
+
+func TestParseKTX(t *testing.T) {
+	tests := []struct {
+		file           string
+		width, height  int32
+		layers, faces  int
+		levels         int
+		target         uint32
+		lastLevelBytes int // Bytes in each image of the last level
+	}{
+		{"mips.ktx", 4, 4, 1, 1, 3, gl.TEXTURE_2D, 4},
+		{"cube.ktx", 2, 2, 1, 6, 1, gl.TEXTURE_CUBE_MAP, 16},
+		{"array.ktx", 2, 2, 3, 1, 2, gl.TEXTURE_2D_ARRAY, 4},
+	}
+	for _, test := range tests {
+		container, err := ParseKTX(readTestFile(t, test.file))
+		if err != nil {
+			t.Errorf("%v: got error %v", test.file, err)
+			continue
+		}
+		checkContainerLayout(t, test.file, container, test.width, test.height, test.layers, test.faces, test.levels)
+		if container.Target() != test.target {
+			t.Errorf("%v: got target 0x%X, expected 0x%X", test.file, container.Target(), test.target)
+		}
+		if last := container.Levels[len(container.Levels)-1]; len(last.Images[0]) != test.lastLevelBytes {
+			t.Errorf("%v: got %v bytes in the last level, expected %v", test.file, len(last.Images[0]), test.lastLevelBytes)
+		}
+	}
+}
+
+func TestParseKTXBigEndian(t *testing.T) {
+	container, err := ParseKTX(readTestFile(t, "bigendian.ktx"))
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	image := container.Levels[0].Images[0]
+	if len(image) != 16 {
+		t.Fatalf("got %v bytes, expected 16", len(image))
+	}
+	for i := 0; i < len(image); i += 2 {
+		if value := binary.LittleEndian.Uint16(image[i:]); value != 0x0100 {
+			t.Errorf("got 16 bit value 0x%X at %v, expected 0x0100 after swapping", value, i)
+		}
+	}
+}
+
+func TestParseKTXRejectsBadFiles(t *testing.T) {
+	valid := readTestFile(t, "mips.ktx")
+	// The header fields are 4 bytes each after the 12 byte identifier
+	withField := func(data []byte, field int, value uint32) []byte {
+		patched := append([]byte{}, data...)
+		binary.LittleEndian.PutUint32(patched[12+field*4:], value)
+		return patched
+	}
+	const (
+		fieldWidth  = 6
+		fieldHeight = 7
+		fieldLayers = 9
+		fieldFaces  = 10
+		fieldLevels = 11
+		fieldKV     = 12
+	)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		contains string
+	}{
+		{"empty", nil, "identifier"},
+		{"truncated header", valid[:40], "identifier"},
+		{"truncated image data", valid[:len(valid)-10], "Ended before"},
+		{"truncated level size", valid[:ktxHeaderSize+12+68+2], "Ended before"},
+		{"zero width", withField(valid, fieldWidth, 0), "Zero width"},
+		{"huge width", withField(valid, fieldWidth, 0xFFFFFFFF), "too large"},
+		{"huge height", withField(valid, fieldHeight, 0x80000000), "too large"},
+		{"huge layer count", withField(valid, fieldLayers, 0xFFFFFFFF), "can't fit"},
+		{"bad face count", withField(valid, fieldFaces, 3), "faces"},
+		{"key/value past the end", withField(valid, fieldKV, 0xFFFFFF), "past the end"},
+		{"more levels than the data", withField(withField(valid, fieldWidth, 1<<30), fieldLevels, 31)[:ktxHeaderSize+12+40], "can't fit"},
+	}
+	for _, test := range tests {
+		_, err := ParseKTX(test.data)
+		if err == nil || !strings.Contains(err.Error(), test.contains) {
+			t.Errorf("%v: got error %v, expected one containing %q", test.name, err, test.contains)
+		}
+	}
+
+	// More levels than a full chain are ignored rather than read
+	container, err := ParseKTX(withField(valid, fieldLevels, 0xFFFFFFFF))
+	if err != nil {
+		t.Errorf("oversized level count: got error %v", err)
+	} else if len(container.Levels) != 3 {
+		t.Errorf("oversized level count: got %v levels, expected 3", len(container.Levels))
+	}
+}
@@ -0,0 +1,151 @@
+package gfx
+
+import (
+	gomath "math"
+	"sort"
+
+	"github.com/Surreal/Math/math"
+	"github.com/Surreal/Systems/Core/core"
+)
+
+// MaxLightsPerObject is the most lights the renderer will send for a single draw. Must match MAX_LIGHTS in the shaders
+const MaxLightsPerObject int = 8
+
+// AmbientLightColor is the light added to every lit surface regardless of the lights in the scene
+var AmbientLightColor = math.Vector3f{X: 0.1, Y: 0.1, Z: 0.1}
+
+// MaxShadowCascades is the most cascades a directional light's shadow can be split into. Must match MAX_CASCADES in the shaders
+const MaxShadowCascades int = 4
+
+// ShadowSettings controls how a light casts shadows
+type ShadowSettings struct {
+	CastShadows        bool    // If false the light is not shadowed at all. Only the first directional and first spot light cast
+	Resolution         int32   // Width and height in texels of the shadow map, or of each cascade for directional lights. Lowered to fit GL_MAX_TEXTURE_SIZE
+	Bias               float32 // Depth offset to stop surfaces shadowing themselves (shadow acne)
+	CascadeCount       int     // Directional only. Number of slices the camera frustum is split into, 1 to MaxShadowCascades
+	CascadeSplitLambda float32 // Directional only. 0 splits the frustum evenly, 1 logarithmically. Around 0.5 works well
+	Distance           float32 // Directional only. Shadows are drawn up to this far from the camera
+}
+
+// DefaultShadowSettings returns reasonable shadow settings with shadows switched off
+func DefaultShadowSettings() ShadowSettings {
+	return ShadowSettings{
+		CastShadows:        false,
+		Resolution:         2048,
+		Bias:               0.002,
+		CascadeCount:       MaxShadowCascades,
+		CascadeSplitLambda: 0.5,
+		Distance:           50,
+	}
+}
+
+// LightType enum to tell the shader how to treat a light. Values match the defines in the lit shader
+type LightType int32
+
+// Enum values for LightType
+const (
+	DirectionalLight LightType = iota
+	PointLight
+	SpotLight
+)
+
+// Light is implemented by the light components. Lights register themselves with the renderer while enabled
+type Light interface {
+	core.Component
+	LightType() LightType
+	ShadowSettings() *ShadowSettings // Returns nil for lights that can't cast shadows
+	lightInfo() lightInfo
+}
+
+// lightInfo is a light flattened into the values the shader needs
+type lightInfo struct {
+	source     Light
+	lightType  LightType
+	position   math.Vector3f
+	direction  math.Vector3f
+	color      math.Vector3f // Premultiplied by intensity
+	lightRange float32
+	cosInner   float32
+	cosOuter   float32
+}
+
+// registeredLights are all the enabled lights in active scenes. Lights leave when they're disabled, so scenes must be
+// unloaded with core.Scene.Unload rather than just dropped
+var registeredLights []Light
+
+// RegisteredLights returns all currently enabled lights. Do not modify the returned slice
+func RegisteredLights() []Light {
+	return registeredLights
+}
+
+// registerLight adds a light to the registry if it's not already there
+func registerLight(light Light) {
+	for _, l := range registeredLights {
+		if l == light {
+			return
+		}
+	}
+	registeredLights = append(registeredLights, light)
+}
+
+// unregisterLight removes a light from the registry without memory allocation
+func unregisterLight(light Light) {
+	j := 0
+	for _, l := range registeredLights {
+		if l != light {
+			registeredLights[j] = l
+			j++
+		}
+	}
+	for i := j; i < len(registeredLights); i++ {
+		registeredLights[i] = nil
+	}
+	registeredLights = registeredLights[:j]
+}
+
+// scoredLight is used to rank lights by how much they're likely to affect an object
+type scoredLight struct {
+	info  lightInfo
+	score float32
+}
+
+// nearestLights fills out with up to len(out) lights reaching bounds, which is in world space. Directional lights
+// always come first, then the point and spot lights closest to the surface of bounds. Returns the number of lights
+// written
+func nearestLights(bounds math.BoundingSphere, scratch *[]scoredLight, out []lightInfo) int {
+	candidates := (*scratch)[:0]
+	for _, light := range registeredLights {
+		if light.SceneObject() == nil {
+			continue
+		}
+		info := light.lightInfo()
+		score := float32(-1)
+		if info.lightType != DirectionalLight {
+			// Lights inside the bounds could be touching any part of the object so they're all as close as each other
+			distance := math.Max(bounds.Center.Distance(info.position)-bounds.Radius, 0)
+			if distance > info.lightRange {
+				continue
+			}
+			score = distance
+		}
+		candidates = append(candidates, scoredLight{info, score})
+	}
+	*scratch = candidates
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score < candidates[j].score })
+
+	count := 0
+	for _, candidate := range candidates {
+		if count >= len(out) {
+			break
+		}
+		out[count] = candidate.info
+		count++
+	}
+	return count
+}
+
+// cosHalfAngle converts a cone angle in degrees into the cosine of half of it for the shader
+func cosHalfAngle(degrees float32) float32 {
+	return float32(gomath.Cos(float64(degrees * 0.5 * math.Deg2Rad)))
+}
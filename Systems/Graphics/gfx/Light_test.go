@@ -0,0 +1,79 @@
+package gfx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Surreal/Math/math"
+	"github.com/Surreal/Systems/Core/core"
+)
+
+// createTestLightScene makes a scene with light components placed at positions and enables them. A nil position adds
+// a directional light instead
+func createTestLightScene(lightRange float32, positions ...*math.Vector3f) (*core.Scene, []Light) {
+	scene := new(core.Scene)
+	lights := make([]Light, len(positions))
+	for i, position := range positions {
+		so := core.CreateSceneObject(nil)
+		if position == nil {
+			light := CreateDirectionalLightComponent(math.Vector3f{X: 1, Y: 1, Z: 1}, 1)
+			so.AddComponent(light)
+			lights[i] = light
+		} else {
+			light := CreatePointLightComponent(math.Vector3f{X: 1, Y: 1, Z: 1}, 1, lightRange)
+			so.AddComponent(light)
+			so.Transform.SetWorldPosition(*position)
+			lights[i] = light
+		}
+		scene.AddSceneObject(so)
+	}
+	scene.Update(0)
+	return scene, lights
+}
+
+func TestNearestLights(t *testing.T) {
+	near := math.Vector3f{X: 3}
+	far := math.Vector3f{X: 8}
+	inside := math.Vector3f{Y: 1}
+	scene, lights := createTestLightScene(4, &far, nil, &near, &inside)
+	defer scene.Unload()
+	names := map[Light]string{lights[0]: "far", lights[1]: "directional", lights[2]: "near", lights[3]: "inside"}
+
+	tests := []struct {
+		name     string
+		bounds   math.BoundingSphere
+		slots    int
+		expected []string
+	}{
+		{"point", math.BoundingSphere{}, 4, []string{"directional", "inside", "near"}},
+		// The far light is 8 from the center but only 2 from the surface, and the others are inside so they tie
+		{"large bounds", math.BoundingSphere{Radius: 6}, 4, []string{"directional", "near", "inside", "far"}},
+		{"directional first", math.BoundingSphere{}, 2, []string{"directional", "inside"}},
+		{"closest surface", math.BoundingSphere{Center: math.Vector3f{X: 6}, Radius: 1}, 4, []string{"directional", "far", "near"}},
+		{"out of range", math.BoundingSphere{Center: math.Vector3f{Z: -20}, Radius: 1}, 4, []string{"directional"}},
+	}
+	for _, test := range tests {
+		var scratch []scoredLight
+		out := make([]lightInfo, test.slots)
+		count := nearestLights(test.bounds, &scratch, out)
+		got := make([]string, count)
+		for i := range got {
+			got[i] = names[out[i].source]
+		}
+		if strings.Join(got, ", ") != strings.Join(test.expected, ", ") {
+			t.Errorf("%v: got %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestRegisteredLightsLeaveWithTheirScene(t *testing.T) {
+	origin := math.Vector3f{}
+	scene, _ := createTestLightScene(1, nil, &origin)
+	if got := len(RegisteredLights()); got != 2 {
+		t.Fatalf("got %v registered lights, expected 2", got)
+	}
+	scene.Unload()
+	if got := len(RegisteredLights()); got != 0 {
+		t.Errorf("got %v registered lights after unloading, expected 0", got)
+	}
+}
@@ -0,0 +1,253 @@
+package gfx
+
+// litVertexShaderSource transforms the standard mesh attributes into world space for per pixel lighting
+const litVertexShaderSource = `
+#version 150 core
+
+in vec3 S_Position;
+in vec3 S_Normal;
+in vec2 S_TexUV;
+in vec4 S_Color;
+
+uniform mat4 u_Model;
+uniform mat3 u_NormalMatrix;
+uniform mat4 u_View;
+uniform mat4 u_Projection;
+
+out vec3 v_WorldPosition;
+out vec3 v_WorldNormal;
+out vec2 v_TexUV;
+out vec4 v_Color;
+
+void main() {
+	vec4 worldPosition = u_Model * vec4(S_Position, 1.0);
+	v_WorldPosition = worldPosition.xyz;
+	v_WorldNormal = u_NormalMatrix * S_Normal;
+	v_TexUV = S_TexUV;
+	v_Color = S_Color;
+	gl_Position = u_Projection * u_View * worldPosition;
+}
+`
+
+// litFragmentShaderSource is Blinn-Phong shading with up to MAX_LIGHTS directional, point and spot lights. One
+// directional light may have cascaded shadows and one spot light may have a shadow, both filtered with PCF. Reflections
+// come from a prefiltered environment cubemap when the camera has a skybox with one. Specular and normal maps are
+// optional, the normal map's tangent frame is worked out from screen space derivatives so meshes don't need tangents.
+// Vertex colors multiply the albedo for materials that turn them on
+const litFragmentShaderSource = `
+#version 150 core
+
+#define MAX_LIGHTS 8
+#define DIRECTIONAL_LIGHT 0
+#define POINT_LIGHT 1
+#define SPOT_LIGHT 2
+#define MAX_CASCADES 4
+
+in vec3 v_WorldPosition;
+in vec3 v_WorldNormal;
+in vec2 v_TexUV;
+in vec4 v_Color;
+
+uniform vec4 u_Tint;
+uniform int u_UseVertexColor;
+uniform sampler2D u_Albedo;
+uniform int u_UseAlbedoMap = 1;
+uniform vec3 u_AmbientTint = vec3(1.0);
+uniform float u_SpecularStrength;
+uniform vec3 u_SpecularTint = vec3(1.0);
+uniform float u_Shininess;
+uniform sampler2D u_SpecularMap;
+uniform int u_UseSpecularMap;
+uniform sampler2D u_NormalMap;
+uniform int u_UseNormalMap;
+uniform float u_NormalMapStrength = 1.0;
+
+uniform vec3 u_CameraPosition;
+uniform vec3 u_AmbientColor;
+
+uniform int u_LightCount;
+uniform int u_LightType[MAX_LIGHTS];
+uniform vec3 u_LightPosition[MAX_LIGHTS];
+uniform vec3 u_LightDirection[MAX_LIGHTS];
+uniform vec3 u_LightColor[MAX_LIGHTS];
+uniform float u_LightRange[MAX_LIGHTS];
+uniform vec2 u_LightCone[MAX_LIGHTS];
+
+uniform mat4 u_View;
+uniform int u_DirectionalShadowIndex;
+uniform sampler2DShadow u_DirectionalShadowMap;
+uniform mat4 u_DirectionalShadowMatrices[MAX_CASCADES];
+uniform float u_CascadeSplits[MAX_CASCADES];
+uniform int u_CascadeCount;
+uniform float u_DirectionalShadowBias;
+uniform int u_SpotShadowIndex;
+uniform sampler2DShadow u_SpotShadowMap;
+uniform mat4 u_SpotShadowMatrix;
+uniform float u_SpotShadowBias;
+
+uniform samplerCube u_Environment;
+uniform float u_EnvironmentIntensity;
+uniform float u_EnvironmentMipCount;
+
+out vec4 o_Color;
+
+// 3x3 percentage closer filtering, clamped so we never sample outside of [minUV, maxUV]
+float pcf(sampler2DShadow shadowMap, vec3 coord, vec2 minUV, vec2 maxUV) {
+	vec2 texelSize = 1.0 / vec2(textureSize(shadowMap, 0));
+	float lit = 0.0;
+	for (int x = -1; x <= 1; x++) {
+		for (int y = -1; y <= 1; y++) {
+			vec2 uv = clamp(coord.xy + vec2(x, y) * texelSize, minUV, maxUV);
+			lit += texture(shadowMap, vec3(uv, coord.z));
+		}
+	}
+	return lit / 9.0;
+}
+
+// The cascades are tiled into one texture, up to 2 across, so each cascade matrix maps straight into it's own tile.
+// The layout must match shadowAtlasLayout
+float directionalShadow(vec3 worldPosition) {
+	float viewDepth = -(u_View * vec4(worldPosition, 1.0)).z;
+	if (u_CascadeCount <= 0 || viewDepth > u_CascadeSplits[u_CascadeCount - 1]) {
+		return 1.0;
+	}
+
+	int cascade = u_CascadeCount - 1;
+	for (int i = 0; i < MAX_CASCADES; i++) {
+		if (i < u_CascadeCount && viewDepth <= u_CascadeSplits[i]) {
+			cascade = i;
+			break;
+		}
+	}
+
+	vec4 coord = u_DirectionalShadowMatrices[cascade] * vec4(worldPosition, 1.0);
+	vec2 halfTexel = 0.5 / vec2(textureSize(u_DirectionalShadowMap, 0));
+	int columns = min(u_CascadeCount, 2);
+	int rows = (u_CascadeCount + columns - 1) / columns;
+	vec2 tileSize = 1.0 / vec2(float(columns), float(rows));
+	vec2 tile = vec2(float(cascade % columns), float(cascade / columns));
+	vec2 minUV = tileSize * tile + halfTexel;
+	vec2 maxUV = tileSize * (tile + 1.0) - halfTexel;
+	return pcf(u_DirectionalShadowMap, vec3(coord.xy, coord.z - u_DirectionalShadowBias), minUV, maxUV);
+}
+
+float spotShadow(vec3 worldPosition) {
+	vec4 coord = u_SpotShadowMatrix * vec4(worldPosition, 1.0);
+	coord.xyz /= coord.w;
+	if (coord.w <= 0.0 || coord.z >= 1.0) {
+		return 1.0;
+	}
+	return pcf(u_SpotShadowMap, vec3(coord.xy, coord.z - u_SpotShadowBias), vec2(0.0), vec2(1.0));
+}
+
+// Bends normal by the normal map using a tangent frame built from how the position and uv change across the pixel
+vec3 normalMapped(vec3 normal) {
+	vec3 dp1 = dFdx(v_WorldPosition);
+	vec3 dp2 = dFdy(v_WorldPosition);
+	vec2 duv1 = dFdx(v_TexUV);
+	vec2 duv2 = dFdy(v_TexUV);
+
+	vec3 dp2perp = cross(dp2, normal);
+	vec3 dp1perp = cross(normal, dp1);
+	vec3 tangent = dp2perp * duv1.x + dp1perp * duv2.x;
+	vec3 bitangent = dp2perp * duv1.y + dp1perp * duv2.y;
+	float scale = inversesqrt(max(max(dot(tangent, tangent), dot(bitangent, bitangent)), 1e-12));
+
+	vec3 mapped = texture(u_NormalMap, v_TexUV).xyz * 2.0 - 1.0;
+	mapped.xy *= u_NormalMapStrength;
+	return normalize(mat3(tangent * scale, bitangent * scale, normal) * mapped);
+}
+
+void main() {
+	vec4 albedo = u_Tint;
+	if (u_UseAlbedoMap != 0) {
+		albedo *= texture(u_Albedo, v_TexUV);
+	}
+	if (u_UseVertexColor != 0) {
+		albedo *= v_Color;
+	}
+	vec3 normal = normalize(v_WorldNormal);
+	if (u_UseNormalMap != 0) {
+		normal = normalMapped(normal);
+	}
+	vec3 toCamera = normalize(u_CameraPosition - v_WorldPosition);
+	float shininess = max(u_Shininess, 1.0);
+	vec3 specularColor = u_SpecularTint;
+	if (u_UseSpecularMap != 0) {
+		specularColor *= texture(u_SpecularMap, v_TexUV).rgb;
+	}
+
+	vec3 lighting = u_AmbientColor * u_AmbientTint;
+	for (int i = 0; i < MAX_LIGHTS; i++) {
+		if (i >= u_LightCount) {
+			break;
+		}
+
+		vec3 toLight;
+		float attenuation = 1.0;
+		if (u_LightType[i] == DIRECTIONAL_LIGHT) {
+			toLight = -u_LightDirection[i];
+		} else {
+			vec3 offset = u_LightPosition[i] - v_WorldPosition;
+			float distance = length(offset);
+			toLight = offset / max(distance, 0.0001);
+
+			float falloff = clamp(1.0 - distance / max(u_LightRange[i], 0.0001), 0.0, 1.0);
+			attenuation = falloff * falloff;
+
+			if (u_LightType[i] == SPOT_LIGHT) {
+				float cosAngle = dot(-toLight, u_LightDirection[i]);
+				attenuation *= smoothstep(u_LightCone[i].y, u_LightCone[i].x, cosAngle);
+			}
+		}
+
+		if (i == u_DirectionalShadowIndex) {
+			attenuation *= directionalShadow(v_WorldPosition);
+		} else if (i == u_SpotShadowIndex) {
+			attenuation *= spotShadow(v_WorldPosition);
+		}
+
+		float diffuse = max(dot(normal, toLight), 0.0);
+		float specular = 0.0;
+		if (diffuse > 0.0) {
+			vec3 halfway = normalize(toLight + toCamera);
+			specular = pow(max(dot(normal, halfway), 0.0), shininess) * u_SpecularStrength;
+		}
+		lighting += u_LightColor[i] * (diffuse + specular * specularColor) * attenuation;
+	}
+
+	// Reflect the environment, blurrier for lower shininess and stronger at grazing angles
+	vec3 reflection = vec3(0.0);
+	if (u_EnvironmentIntensity > 0.0) {
+		float roughness = sqrt(2.0 / (shininess + 2.0));
+		float lod = roughness * (u_EnvironmentMipCount - 1.0);
+		float fresnel = 0.04 + 0.96 * pow(1.0 - max(dot(normal, toCamera), 0.0), 5.0);
+		vec3 environment = textureLod(u_Environment, reflect(-toCamera, normal), lod).rgb;
+		reflection = environment * specularColor * fresnel * u_SpecularStrength * u_EnvironmentIntensity;
+	}
+
+	o_Color = vec4(albedo.rgb * lighting + reflection, albedo.a);
+}
+`
+
+// shadowDepthVertexShaderSource renders the standard mesh attributes from a light's point of view
+const shadowDepthVertexShaderSource = `
+#version 150 core
+
+in vec3 S_Position;
+
+uniform mat4 u_Model;
+uniform mat4 u_LightViewProjection;
+
+void main() {
+	gl_Position = u_LightViewProjection * u_Model * vec4(S_Position, 1.0);
+}
+`
+
+// shadowDepthFragmentShaderSource writes nothing, the depth buffer is all we're after
+const shadowDepthFragmentShaderSource = `
+#version 150 core
+
+void main() {
+}
+`
@@ -10,6 +10,9 @@ import (
 var CurrentlyBoundMaterial *Material
 var GlobalDefaultMaterial *Material
 
+// nextMaterialSortID hands out the ids used to group draws by material
+var nextMaterialSortID uint32 = 1
+
 func init() {
 
 }
@@ -19,6 +22,7 @@ type Material struct {
 	MaterialShader         *Shader                // The shader this material will use when bound
 	ShaderParameterPresets map[string]interface{} // Preset values for a shader parameter. Can be pointer or value
 	ShaderTextures         map[string]*Texture    // Preset values for filepaths to a shader texture. Must be value not pointer
+	sortID                 uint32                 // Used by the renderer to keep draws with the same material together
 }
 
 // CreateMaterial is the generic constructor for a Material
@@ -27,11 +31,16 @@ func CreateMaterial(shader *Shader) *Material {
 	material.ShaderParameterPresets = make(map[string]interface{})
 	material.ShaderTextures = make(map[string]*Texture)
 	material.MaterialShader = shader
+	material.sortID = nextMaterialSortID
+	nextMaterialSortID++
 	return material
 }
 
 // Bind binds the material's shader and sets the shader's parameters to the material's values
 func (mat *Material) Bind() {
+	// Bind first so sending the parameters doesn't have to switch programs back and forth
+	mat.MaterialShader.Bind()
+
 	for name, value := range mat.ShaderParameterPresets {
 		err := mat.MaterialShader.SendParameterValue(name, value)
 		if err != nil {
@@ -46,7 +55,6 @@ func (mat *Material) Bind() {
 		}
 	}
 
-	mat.MaterialShader.Bind()
 	CurrentlyBoundMaterial = mat
 }
 
@@ -59,9 +67,13 @@ func (mat *Material) UnBind() {
 // SetTextureParameter sets the texture parameter the material will bind when in use
 func (mat *Material) SetTextureParameter(name string, texture *Texture) error {
 
-	if _, ok := mat.MaterialShader.TextureParameters[name]; !ok {
+	param, ok := mat.MaterialShader.TextureParameters[name]
+	if !ok {
 		return errors.New("Invalid Parameter: Attempting to set a texture parameter that doesn't exist in the material's shader")
 	}
+	if texture != nil && TextureTargetForType(param.UniformType) != texture.textureTarget() {
+		return errors.New("Invalid Parameter: The texture's target doesn't match the sampler type, i.e. a cubemap on a sampler2D")
+	}
 
 	mat.ShaderTextures[name] = texture
 	return nil
@@ -1,10 +1,6 @@
 package gfx
 
-import (
-	"path/filepath"
-
-	"github.com/Surreal/Utility/util"
-)
+import "github.com/Surreal/Math/math"
 
 // defaultMeshShader is the default shader used on imported meshes
 var defaultMeshShader *Shader
@@ -12,15 +8,12 @@ var defaultMeshShader *Shader
 // defaultMeshMaterial is the default material used on imported meshes
 var defaultMeshMaterial *Material
 
-// DefaultMeshShader is a temporary getter for the default shader for meshes
+// DefaultMeshShader is a temporary getter for the default shader for meshes. It's the built in lit shader
 // TODO: Hook into initialization system
 func DefaultMeshShader() *Shader {
 	if defaultMeshShader == nil {
 		// Create Shader
-		vShaderPath := filepath.Join(util.DataRoot(), "Shaders", "vDefault.shader")
-		fShaderPath := filepath.Join(util.DataRoot(), "Shaders", "fDefault.shader")
-
-		shader, err := CreateShader(vShaderPath, fShaderPath)
+		shader, err := CreateShaderFromSource(litVertexShaderSource, litFragmentShaderSource)
 		if err != nil {
 			panic(err.Error())
 		}
@@ -36,15 +29,37 @@ func DefaultMeshShader() *Shader {
 func DefaultMeshMaterial() *Material {
 	// Create if it isn't defined
 	if defaultMeshMaterial == nil {
-		defaultMeshMaterial = CreateMaterial(DefaultMeshShader())
+		defaultMeshMaterial = CreateLitMaterial()
 	}
 	return defaultMeshMaterial
 }
 
+// CreateLitMaterial makes a material for the built in lit shader with every parameter set to it's default. Uniforms
+// belong to the shader program, so a material that skipped one would draw with whatever the last material left there
+func CreateLitMaterial() *Material {
+	material := CreateMaterial(DefaultMeshShader())
+	material.SetMaterialParameters(map[string]interface{}{
+		"u_Tint":              []float32{1, 1, 1, 1},
+		"u_UseAlbedoMap":      int32(1),
+		"u_UseVertexColor":    int32(0),
+		"u_AmbientTint":       []float32{1, 1, 1},
+		"u_SpecularStrength":  float32(0.25),
+		"u_SpecularTint":      []float32{1, 1, 1},
+		"u_Shininess":         float32(32),
+		"u_UseSpecularMap":    int32(0),
+		"u_UseNormalMap":      int32(0),
+		"u_NormalMapStrength": float32(1),
+	})
+	return material
+}
+
 // Mesh represents a simple shape
 type Mesh struct {
 	Verticies      *VertexArray
 	VertexIndicies *VertexIndexArray
+	Bounds         math.BoundingSphere // Around the verticies it draws in model space. Left empty the mesh is treated as a point
+	vertexOwners   *int                // Meshes sharing Verticies that haven't been deleted, nil if the mesh is it's only owner
+	deleted        bool
 }
 
 // CreateMesh is the standard constructor for a Mesh
@@ -54,3 +69,29 @@ func CreateMesh(verticies *VertexArray, vertexIndicies *VertexIndexArray) *Mesh
 	mesh.VertexIndicies = vertexIndicies
 	return mesh
 }
+
+// shareVertices makes meshes own their vertex array together, so it's only freed once every one of them is deleted
+func shareVertices(meshes []*Mesh) {
+	owners := len(meshes)
+	for _, mesh := range meshes {
+		mesh.vertexOwners = &owners
+	}
+}
+
+// Delete frees the mesh's index buffer, and it's vertex buffers unless other meshes still share them
+func (mesh *Mesh) Delete() {
+	if mesh.deleted {
+		return
+	}
+	mesh.deleted = true
+
+	if mesh.vertexOwners != nil {
+		*mesh.vertexOwners--
+	}
+	if mesh.Verticies != nil && (mesh.vertexOwners == nil || *mesh.vertexOwners == 0) {
+		mesh.Verticies.Delete()
+	}
+	if mesh.VertexIndicies != nil {
+		mesh.VertexIndicies.Delete()
+	}
+}
@@ -0,0 +1,209 @@
+package gfx
+
+import (
+	"fmt"
+
+	"github.com/Surreal/Math/math"
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// MeshData is a mesh on the CPU side, the way importers make it. Nothing in it touches openGL so it can be made on any
+// goroutine and looked at or tested without a GL context. Upload sends it to the GPU
+type MeshData struct {
+	Name       string
+	Positions  []float32 // x, y, z for each vertex
+	Normals    []float32 // x, y, z for each vertex
+	TexCoords  []float32 // u, v for each vertex, with v going up the image. nil uploads as all 0
+	TexCoords2 []float32 // A second uv set, nil if there isn't one
+	Colors     []float32 // r, g, b, a for each vertex, nil if there are none
+	Tangents   []float32 // x, y, z and the bitangent's sign for each vertex, nil if there are none
+	Joints     []float32 // The 4 skin joints of each vertex, nil if it isn't skinned
+	Weights    []float32 // How much each of the 4 joints counts
+	Indices    []uint32  // 3 per triangle, counter clockwise
+	Submeshes  []Submesh // Ranges of Indices each drawn with their own material
+	Bounds     math.AABB // Around every position, see CalculateBounds
+}
+
+// Submesh is a range of a MeshData's indices drawn with one material
+type Submesh struct {
+	Start        int          // The first index
+	Count        int          // How many indices
+	MaterialName string       // The material the file asked for, "" if none
+	Material     *MtlMaterial // The material, nil if the file didn't have one
+}
+
+// meshDataAttribute is a vertex attribute of MeshData and the standard attribute it uploads as
+type meshDataAttribute struct {
+	name       string
+	components int
+	values     []float32
+}
+
+// VertexCount returns how many vertices there are
+func (data *MeshData) VertexCount() int {
+	return len(data.Positions) / 3
+}
+
+// attributes returns every vertex attribute, including the ones that are nil
+func (data *MeshData) attributes() []meshDataAttribute {
+	return []meshDataAttribute{
+		{"S_Position", 3, data.Positions},
+		{"S_Normal", 3, data.Normals},
+		{"S_TexUV", 2, data.TexCoords},
+		{"S_TexUV2", 2, data.TexCoords2},
+		{"S_Color", 4, data.Colors},
+		{"S_Tangent", 4, data.Tangents},
+		{"S_Joints", 4, data.Joints},
+		{"S_Weights", 4, data.Weights},
+	}
+}
+
+// Validate checks there's at least one triangle, every attribute has a value per vertex, the indices are whole
+// triangles of vertices that exist and the submeshes are whole triangles within the indices
+func (data *MeshData) Validate() error {
+	if len(data.Positions)%3 != 0 {
+		return fmt.Errorf("Invalid Mesh Data: %v has %v position values, which isn't 3 per vertex", data.Name, len(data.Positions))
+	}
+	vertexCount := data.VertexCount()
+	if vertexCount == 0 || len(data.Indices) == 0 {
+		return fmt.Errorf("Invalid Mesh Data: %v has %v vertices and %v indices, it needs at least one triangle", data.Name, vertexCount, len(data.Indices))
+	}
+	for _, attribute := range data.attributes()[1:] {
+		if attribute.values != nil && len(attribute.values) != vertexCount*attribute.components {
+			return fmt.Errorf("Invalid Mesh Data: %v has %v %v values for %v vertices", data.Name, len(attribute.values), attribute.name, vertexCount)
+		}
+	}
+
+	if len(data.Indices)%3 != 0 {
+		return fmt.Errorf("Invalid Mesh Data: %v has %v indices, which isn't whole triangles", data.Name, len(data.Indices))
+	}
+	for _, index := range data.Indices {
+		if int(index) >= vertexCount {
+			return fmt.Errorf("Invalid Mesh Data: %v has index %v but only %v vertices", data.Name, index, vertexCount)
+		}
+	}
+	for _, submesh := range data.Submeshes {
+		if submesh.Start < 0 || submesh.Count <= 0 || submesh.Start+submesh.Count > len(data.Indices) || submesh.Count%3 != 0 {
+			return fmt.Errorf("Invalid Mesh Data: %v has a submesh that's empty, outside of it's indices or isn't whole triangles", data.Name)
+		}
+	}
+	return nil
+}
+
+// CalculateBounds sets Bounds to fit every position
+func (data *MeshData) CalculateBounds() {
+	data.Bounds = math.AABB{}
+	for i := 0; i+2 < len(data.Positions); i += 3 {
+		position := math.Vector3f{X: data.Positions[i], Y: data.Positions[i+1], Z: data.Positions[i+2]}
+		if i == 0 {
+			data.Bounds = math.AABB{Min: position, Max: position}
+		} else {
+			data.Bounds = data.Bounds.Encapsulate(position)
+		}
+	}
+}
+
+// CalculateNormals sets Normals to smooth normals, each vertex getting the area weighted average of the triangles
+// around it. Indices must be in range
+func (data *MeshData) CalculateNormals() {
+	sums := make([]math.Vector3f, data.VertexCount())
+	for t := 0; t+2 < len(data.Indices); t += 3 {
+		triangle := data.Indices[t : t+3]
+		// The cross product's length is twice the triangle's area, so summing them weights by area
+		normal := data.position(triangle[1]).Sub(data.position(triangle[0])).Cross(data.position(triangle[2]).Sub(data.position(triangle[0])))
+		for _, index := range triangle {
+			sums[index] = sums[index].Add(normal)
+		}
+	}
+
+	data.Normals = make([]float32, 0, len(data.Positions))
+	for _, normal := range sums {
+		normal = normal.Normalize()
+		data.Normals = append(data.Normals, normal.X, normal.Y, normal.Z)
+	}
+}
+
+// CalculateFlatNormals gives every triangle it's own vertices with the triangle's normal. Submesh ranges stay valid
+// since every index still has the same place
+func (data *MeshData) CalculateFlatNormals() {
+	vertexCount := data.VertexCount()
+	if vertexCount == 0 {
+		data.Normals = []float32{}
+		return
+	}
+	for _, attribute := range []*[]float32{&data.Positions, &data.TexCoords, &data.TexCoords2, &data.Colors, &data.Tangents, &data.Joints, &data.Weights} {
+		if *attribute == nil {
+			continue
+		}
+		components := len(*attribute) / vertexCount
+		values := make([]float32, len(data.Indices)*components)
+		for v, index := range data.Indices {
+			copy(values[v*components:], (*attribute)[int(index)*components:(int(index)+1)*components])
+		}
+		*attribute = values
+	}
+
+	data.Normals = make([]float32, len(data.Positions))
+	for i := range data.Indices {
+		data.Indices[i] = uint32(i)
+	}
+	for t := 0; t+2 < len(data.Indices); t += 3 {
+		a, b, c := data.position(uint32(t)), data.position(uint32(t+1)), data.position(uint32(t+2))
+		normal := b.Sub(a).Cross(c.Sub(a)).Normalize()
+		for v := t; v < t+3; v++ {
+			data.Normals[v*3], data.Normals[v*3+1], data.Normals[v*3+2] = normal.X, normal.Y, normal.Z
+		}
+	}
+}
+
+// position returns the position of a vertex
+func (data *MeshData) position(index uint32) math.Vector3f {
+	return math.Vector3f{X: data.Positions[index*3], Y: data.Positions[index*3+1], Z: data.Positions[index*3+2]}
+}
+
+// Upload sends the mesh data to the GPU as one Mesh per submesh, or one Mesh for everything if there are no submeshes.
+// The meshes share a vertex array, which is freed once all of them are deleted. The data must pass Validate
+func (data *MeshData) Upload() []*Mesh {
+	vertexArray := CreateVertexArray()
+	for _, attribute := range data.attributes() {
+		values := attribute.values
+		if values == nil {
+			// Every imported mesh has uvs so shaders can count on them
+			if attribute.name != "S_TexUV" {
+				continue
+			}
+			values = make([]float32, data.VertexCount()*2)
+		}
+		vertexArray.PushVertexAttribute(attribute.name, gl.FLOAT, int32(attribute.components))
+		vertexArray.SetAttributeData(attribute.name, &values, gl.STATIC_DRAW)
+	}
+
+	submeshes := data.submeshes()
+	meshes := make([]*Mesh, len(submeshes))
+	for i, submesh := range submeshes {
+		indices := data.Indices[submesh.Start : submesh.Start+submesh.Count]
+		indexArray := CreateVertexIndexArray()
+		indexArray.SetData(&indices, gl.STATIC_DRAW)
+		meshes[i] = CreateMesh(vertexArray, indexArray)
+		meshes[i].Bounds = data.indexBounds(indices)
+	}
+	shareVertices(meshes)
+	return meshes
+}
+
+// indexBounds returns a sphere around the positions referenced by indices
+func (data *MeshData) indexBounds(indices []uint32) math.BoundingSphere {
+	box := math.AABB{Min: data.position(indices[0]), Max: data.position(indices[0])}
+	for _, index := range indices[1:] {
+		box = box.Encapsulate(data.position(index))
+	}
+	return math.BoundingSphereFromAABB(box)
+}
+
+// submeshes returns Submeshes, or one submesh of every index if there aren't any
+func (data *MeshData) submeshes() []Submesh {
+	if len(data.Submeshes) == 0 {
+		return []Submesh{{Start: 0, Count: len(data.Indices)}}
+	}
+	return data.Submeshes
+}
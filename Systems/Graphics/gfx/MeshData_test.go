@@ -0,0 +1,134 @@
+package gfx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Surreal/Math/math"
+)
+
+// meshDataTestFold is a triangle facing +Z folded along the edge from (0, 0, 0) to (0, 1, 0) onto a 1 by 2 rectangle
+// facing +X
+func meshDataTestFold() *MeshData {
+	return &MeshData{
+		Name:      "fold",
+		Positions: []float32{0, 0, 0, 0, 1, 0, 1, 0, 0, 0, 0, 2, 0, 1, 2},
+		Indices:   []uint32{0, 2, 1, 0, 1, 3, 1, 4, 3},
+	}
+}
+
+// meshDataNormal returns the normal of vertex index
+func meshDataNormal(data *MeshData, index uint32) math.Vector3f {
+	return math.Vector3f{X: data.Normals[index*3], Y: data.Normals[index*3+1], Z: data.Normals[index*3+2]}
+}
+
+func TestMeshDataValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(data *MeshData)
+		contains string // "" if the mesh should be valid
+	}{
+		{"valid", func(data *MeshData) {}, ""},
+		{"valid submeshes", func(data *MeshData) { data.Submeshes = []Submesh{{Start: 0, Count: 3}, {Start: 3, Count: 6}} }, ""},
+		{"no positions", func(data *MeshData) { data.Positions = nil }, "at least one triangle"},
+		{"no indices", func(data *MeshData) { data.Indices = nil }, "at least one triangle"},
+		{"partial position", func(data *MeshData) { data.Positions = data.Positions[:14] }, "3 per vertex"},
+		{"partial triangle", func(data *MeshData) { data.Indices = data.Indices[:8] }, "whole triangles"},
+		{"index out of range", func(data *MeshData) { data.Indices[4] = 5 }, "index 5"},
+		{"short normals", func(data *MeshData) { data.Normals = make([]float32, 12) }, "S_Normal"},
+		{"short colors", func(data *MeshData) { data.Colors = make([]float32, 5*3) }, "S_Color"},
+		{"empty submesh", func(data *MeshData) { data.Submeshes = []Submesh{{Start: 3, Count: 0}} }, "submesh"},
+		{"negative submesh", func(data *MeshData) { data.Submeshes = []Submesh{{Start: -3, Count: 3}} }, "submesh"},
+		{"submesh past the indices", func(data *MeshData) { data.Submeshes = []Submesh{{Start: 6, Count: 6}} }, "submesh"},
+		{"partial submesh", func(data *MeshData) { data.Submeshes = []Submesh{{Start: 0, Count: 4}} }, "submesh"},
+	}
+	for _, test := range tests {
+		data := meshDataTestFold()
+		test.change(data)
+		err := data.Validate()
+		if test.contains == "" {
+			if err != nil {
+				t.Errorf("%v: got error %v, expected none", test.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.contains) {
+			t.Errorf("%v: got error %v, expected one containing %q", test.name, err, test.contains)
+		}
+	}
+}
+
+func TestMeshDataCalculateNormals(t *testing.T) {
+	data := meshDataTestFold()
+	data.CalculateNormals()
+	if len(data.Normals) != len(data.Positions) {
+		t.Fatalf("got %v normal values for %v position values", len(data.Normals), len(data.Positions))
+	}
+
+	// The +X side has four times the area, so the fold's vertices lean towards it. Vertex 1 is in both of it's triangles
+	tests := []struct {
+		index    uint32
+		expected math.Vector3f
+	}{
+		{0, math.Vector3f{X: 2, Z: 1}.Normalize()},
+		{1, math.Vector3f{X: 4, Z: 1}.Normalize()},
+		{2, math.Vector3f{Z: 1}},
+		{3, math.Vector3f{X: 1}},
+		{4, math.Vector3f{X: 1}},
+	}
+	for _, test := range tests {
+		if got := meshDataNormal(data, test.index); !got.ApproxEqual(test.expected, 1e-5) {
+			t.Errorf("vertex %v: got normal %v, expected %v", test.index, got, test.expected)
+		}
+	}
+}
+
+func TestMeshDataCalculateFlatNormals(t *testing.T) {
+	data := meshDataTestFold()
+	data.Colors = []float32{0, 0, 0, 1, 1, 1, 1, 1, 2, 2, 2, 1, 3, 3, 3, 1, 4, 4, 4, 1}
+	data.Submeshes = []Submesh{{Start: 0, Count: 3}, {Start: 3, Count: 6}}
+	original := meshDataTestFold()
+	data.CalculateFlatNormals()
+
+	if err := data.Validate(); err != nil {
+		t.Fatalf("got an invalid mesh: %v", err)
+	}
+	if data.VertexCount() != len(original.Indices) {
+		t.Fatalf("got %v vertices, expected one per index, %v", data.VertexCount(), len(original.Indices))
+	}
+	for v, index := range data.Indices {
+		if index != uint32(v) {
+			t.Fatalf("got indices %v, expected them in order", data.Indices)
+		}
+		// Every vertex keeps the position and color of the corner it came from
+		source := original.Indices[v]
+		if data.position(index) != original.position(source) || data.Colors[v*4] != float32(source) {
+			t.Errorf("vertex %v: got position %v and color %v, expected corner %v's", v, data.position(index), data.Colors[v*4], source)
+		}
+		expected := math.Vector3f{Z: 1}
+		if v >= 3 {
+			expected = math.Vector3f{X: 1}
+		}
+		if got := meshDataNormal(data, index); !got.ApproxEqual(expected, 1e-5) {
+			t.Errorf("vertex %v: got normal %v, expected %v", v, got, expected)
+		}
+	}
+}
+
+func TestMeshDataIndexBounds(t *testing.T) {
+	data := meshDataTestFold()
+	tests := []struct {
+		name     string
+		indices  []uint32
+		expected math.BoundingSphere
+	}{
+		{"triangle", data.Indices[:3], math.BoundingSphere{Center: math.Vector3f{X: 0.5, Y: 0.5}, Radius: math.Sqrt(0.5)}},
+		{"rectangle", data.Indices[3:], math.BoundingSphere{Center: math.Vector3f{Y: 0.5, Z: 1}, Radius: math.Sqrt(1.25)}},
+	}
+	for _, test := range tests {
+		got := data.indexBounds(test.indices)
+		if !got.Center.ApproxEqual(test.expected.Center, 1e-5) || !math.ApproxEqual(got.Radius, test.expected.Radius, 1e-5) {
+			t.Errorf("%v: got %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
@@ -1,325 +1,355 @@
 package gfx
 
 import (
-	"bufio"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
-	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/Surreal/Debug/dbg"
 	"github.com/Surreal/Systems/Core/core"
-	"github.com/go-gl/gl/v3.2-core/gl"
 )
 
-// TODO: Right now obj are limited by vertices. A unique vertex is a unique combination of v/vt/vn and not just on v
+// MeshImporterFunc turns the contents of a mesh file into mesh data. filePath is where the file was read from, for
+// finding files next to it like material libraries. Importers run on the asset loader's workers so they mustn't touch
+// openGL. Normals, Submeshes and Bounds can be left empty, they're filled in after
+type MeshImporterFunc func(filePath string, fileData []byte) ([]*MeshData, error)
+
+// meshImporters are the importers for each file extension, see RegisterImporter
+var meshImporters = map[string]MeshImporterFunc{
+	".obj":  importObj,
+	".stl":  importStl,
+	".ply":  importPly,
+	".gltf": importGLTF,
+	".glb":  importGLTF,
+}
+
+// meshImportersLock guards meshImporters, which workers read while importers may still be registered
+var meshImportersLock sync.RWMutex
+
+// RegisterImporter makes ImportMesh, ImportMeshes, ParseMeshFile and the asset loader use importer for files with the
+// extension, replacing any importer it already had. The extension is case insensitive and the dot is optional.
+// ImportMesh, ImportMeshes and the asset loader always send glTF files to ImportGLTF to keep their materials, so an
+// importer for .gltf or .glb only changes ParseMeshFile
+func RegisterImporter(extension string, importer MeshImporterFunc) {
+	extension = strings.ToLower(extension)
+	if !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+
+	meshImportersLock.Lock()
+	defer meshImportersLock.Unlock()
+	meshImporters[extension] = importer
+}
 
 // ImportMesh is the generic function to turn a mesh file into a scene object
-// If multiple groups are defined in a mesh, they are children to the returned scene object
+// If multiple groups are defined in a mesh, they are children to the returned scene object. glTF files keep their node
+// hierarchy, use ImportGLTF to get at their nodes, skins and animations
 func ImportMesh(filePath string) (*core.SceneObject, error) {
-	fileData, err := ioutil.ReadFile(filePath)
+	if isGLTFFile(filePath) {
+		scene, err := ImportGLTF(filePath)
+		if err != nil {
+			return nil, err
+		}
+		return scene.Root, nil
+	}
+
+	meshes, materials, err := ImportMeshes(filePath)
 	if err != nil {
 		return nil, err
 	}
+	return CreateMeshSceneObjectWithMaterials(meshes, materials), nil
+}
 
-	switch filepath.Ext(filePath) {
-	case ".obj":
-		return parseObjString(string(fileData))
-	default:
-		return nil, errors.New("Invalid File Format: Mesh Importer does not recognize or support the provided file format")
+// ImportMeshes reads a mesh file and uploads one Mesh per submesh, without making scene objects for them.
+// materials[i] is made from the file's material library for meshes[i], or is DefaultMeshMaterial() if it has none.
+// Meshes with vertex colors and no material share a lit material with vertex colors on.
+// glTF files give one Mesh per primitive and lose their node transforms
+func ImportMeshes(filePath string) ([]*Mesh, []*Material, error) {
+	fileData, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
 	}
+
+	if isGLTFFile(filePath) {
+		imported, err := parseGLTF(filePath, fileData)
+		if err != nil {
+			return nil, nil, err
+		}
+		scene := imported.upload()
+		scene.deleteUnlitTextures()
+		meshes, materials := scene.flatten()
+		return meshes, materials, nil
+	}
+
+	data, err := ParseMeshFile(filePath, fileData)
+	if err != nil {
+		return nil, nil, err
+	}
+	meshes, materials := UploadMeshData(data)
+	return meshes, materials, nil
 }
 
-type objFace struct {
-	vertices  []uint32
-	normals   []uint32
-	texCoords []uint32
+// ReadMeshFile reads a mesh file into CPU side mesh data without touching openGL. glTF files give a MeshData per
+// primitive without their node transforms, use ImportGLTF for the hierarchy
+func ReadMeshFile(filePath string) ([]*MeshData, error) {
+	fileData, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMeshFile(filePath, fileData)
 }
 
-// WARNING: Parsing code blow. Enter all ye who dare \_( . . )_/
-// NOTE: I acknowledge all the inefficiencies in here. Also notice this is a NOTE not a TODO. I.e. atm i have no fucks to give
-func parseObjString(raw string) (*core.SceneObject, error) {
-	// Setup the scanner object
-	reader := strings.NewReader(raw)
-	scanner := bufio.NewScanner(reader)
-
-	// First pass get meta data
-	vertexCount, groupCount, normalCount, texCoordsCount := 0, 0, 0, 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		if len(line) <= 0 {
-			continue
+// ParseMeshFile turns the contents of a mesh file into CPU side mesh data with the importer registered for it's
+// extension. The data is validated and missing normals, submeshes and bounds are filled in. It doesn't touch openGL so
+// it can run on any goroutine
+func ParseMeshFile(filePath string, fileData []byte) ([]*MeshData, error) {
+	meshImportersLock.RLock()
+	importer, ok := meshImporters[strings.ToLower(filepath.Ext(filePath))]
+	meshImportersLock.RUnlock()
+	if !ok {
+		return nil, errors.New("Invalid File Format: Mesh Importer does not recognize or support the provided file format")
+	}
+
+	meshes, err := importer(filePath, fileData)
+	if err != nil {
+		return nil, err
+	}
+	if len(meshes) == 0 {
+		return nil, errors.New("Invalid File Format: Mesh file has no meshes")
+	}
+	for i, data := range meshes {
+		if data == nil {
+			return nil, fmt.Errorf("Invalid Mesh Data: Mesh importer returned nil for mesh %v", i)
 		}
-		splitStrings := strings.Split(line, " ")
-		if len(splitStrings) <= 0 {
-			continue
+		if err := data.Validate(); err != nil {
+			return nil, err
 		}
-		switch splitStrings[0] {
-		case "v":
-			vertexCount++
-		case "g":
-			groupCount++
-		case "vn":
-			normalCount++
-		case "vt":
-			texCoordsCount++
+		if data.Normals == nil {
+			data.CalculateNormals()
 		}
+		if len(data.Submeshes) == 0 {
+			data.Submeshes = data.submeshes()
+		}
+		data.CalculateBounds()
 	}
+	return meshes, nil
+}
+
+// UploadMeshData sends the mesh data to the GPU and makes it's materials, loading their textures from disk. There is a
+// Mesh and Material for each submesh, materials[i] being for meshes[i], see createImportedMaterials
+func UploadMeshData(data []*MeshData) ([]*Mesh, []*Material) {
+	return uploadMeshData(data), createImportedMaterials(data, nil)
+}
 
-	// Second pass is for parsing data out
-	var gIndex int
-	if groupCount <= 0 {
-		groupCount = 1
-		gIndex = 0
-	} else {
-		// Because it will be incremented at the first sighting of g
-		gIndex = -1
+// uploadMeshData uploads every mesh, giving a Mesh per submesh
+func uploadMeshData(data []*MeshData) []*Mesh {
+	var meshes []*Mesh
+	for _, mesh := range data {
+		meshes = append(meshes, mesh.Upload()...)
 	}
-	groups := make([][]objFace, groupCount, groupCount)
-	vertices, vIndex := make([]float32, vertexCount*3, vertexCount*3), 0
-	normals, nIndex := make([]float32, normalCount*3, normalCount*3), 0
-	texCoords, tcIndex := make([]float32, texCoordsCount*2, texCoordsCount*2), 0
-
-	reader = strings.NewReader(raw)
-	scanner = bufio.NewScanner(reader)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if len(line) <= 0 {
-			continue
-		}
-		splitStrings := strings.Split(line, " ")
-		if len(splitStrings) <= 0 {
-			continue
+	return meshes
+}
+
+// CreateMeshSceneObject parents a scene object rendering each mesh with material to the returned scene object. The
+// meshes are shared, not copied
+func CreateMeshSceneObject(meshes []*Mesh, material *Material) *core.SceneObject {
+	materials := make([]*Material, len(meshes))
+	for i := range materials {
+		materials[i] = material
+	}
+	return CreateMeshSceneObjectWithMaterials(meshes, materials)
+}
+
+// CreateMeshSceneObjectWithMaterials is like CreateMeshSceneObject but renders meshes[i] with materials[i]
+func CreateMeshSceneObjectWithMaterials(meshes []*Mesh, materials []*Material) *core.SceneObject {
+	meshParent := core.CreateSceneObject(nil)
+
+	for i, mesh := range meshes {
+		material := DefaultMeshMaterial()
+		if i < len(materials) && materials[i] != nil {
+			material = materials[i]
 		}
-		switch splitStrings[0] {
-		// Vertex Command: Add vertex to global list
-		case "v":
-			if len(splitStrings) < 4 {
-				return nil, errors.New("Invalid File Format: Obj File has vertex with less than 3 position values")
-			}
-			x, err := strconv.ParseFloat(splitStrings[1], 32)
-			if err != nil {
-				return nil, errors.New("Invalid Vertex Format: " + err.Error())
-			}
-			y, err := strconv.ParseFloat(splitStrings[2], 32)
-			if err != nil {
-				return nil, errors.New("Invalid Vertex Format: " + err.Error())
-			}
-			z, err := strconv.ParseFloat(splitStrings[3], 32)
-			if err != nil {
-				return nil, errors.New("Invalid Vertex Format: " + err.Error())
-			}
-			vertices[vIndex] = float32(x)
-			vIndex++
-			vertices[vIndex] = float32(y)
-			vIndex++
-			vertices[vIndex] = float32(z)
-			vIndex++
-		// Vertex Normal Command: Add normal to global list
-		case "vn":
-			if len(splitStrings) < 4 {
-				return nil, errors.New("Invalid File Format: Obj File has vertex normal with less than 3 values")
-			}
-			x, err := strconv.ParseFloat(splitStrings[1], 32)
-			if err != nil {
-				return nil, errors.New("Invalid Vertex Normal Format: " + err.Error())
-			}
-			y, err := strconv.ParseFloat(splitStrings[2], 32)
-			if err != nil {
-				return nil, errors.New("Invalid Vertex Normal Format: " + err.Error())
-			}
-			z, err := strconv.ParseFloat(splitStrings[3], 32)
+		renderer := CreateMeshRendererComponent(mesh, material)
+		so := core.CreateSceneObject(renderer)
+		so.Transform.SetParent(meshParent.Transform, false)
+	}
+
+	return meshParent
+}
+
+// importedTexture is a texture a material uses, decoded off the main thread
+type importedTexture struct {
+	decoded *decodedTexture
+	err     error
+}
+
+// importObj imports an obj file and the materials from it's material libraries
+func importObj(filePath string, fileData []byte) ([]*MeshData, error) {
+	meshes, libraries, err := parseObjString(string(fileData))
+	if err != nil {
+		return nil, err
+	}
+	resolveObjMaterials(meshes, libraries, filepath.Dir(filePath))
+	return meshes, nil
+}
+
+// importStl imports an ascii or binary stl file
+func importStl(filePath string, fileData []byte) ([]*MeshData, error) {
+	return parseStl(fileData)
+}
+
+// importPly imports an ascii or binary ply file
+func importPly(filePath string, fileData []byte) ([]*MeshData, error) {
+	return parsePly(fileData)
+}
+
+// importGLTF imports the triangle primitives of a .gltf or .glb file as a MeshData each, in file order. Node transforms,
+// textures, skins and animations are left out. Each primitive's submesh is named after it's material
+func importGLTF(filePath string, fileData []byte) ([]*MeshData, error) {
+	file, err := readGLTF(filePath, fileData)
+	if err != nil {
+		return nil, err
+	}
+
+	var meshes []*MeshData
+	for m, mesh := range file.Meshes {
+		for p, primitive := range mesh.Primitives {
+			imported, err := readGLTFPrimitive(file, primitive)
 			if err != nil {
-				return nil, errors.New("Invalid Vertex Normal Format: " + err.Error())
+				return nil, fmt.Errorf("%v (mesh %v primitive %v)", err, m, p)
 			}
-			normals[nIndex] = float32(x)
-			nIndex++
-			normals[nIndex] = float32(y)
-			nIndex++
-			normals[nIndex] = float32(z)
-			nIndex++
-
-		// Vertex Texture Command: Add coordinate to global list
-		case "vt":
-			if len(splitStrings) < 3 {
-				return nil, errors.New("Invalid File Format: Obj File has texture coordinate with less than 2 values")
+			if imported == nil {
+				continue
 			}
-			x, err := strconv.ParseFloat(splitStrings[1], 32)
-			if err != nil {
-				return nil, errors.New("Invalid Vertex Texture Coordinate Format: " + err.Error())
+			data := imported.data
+			data.Name = mesh.Name
+			if imported.material >= 0 {
+				data.Submeshes = []Submesh{{Start: 0, Count: len(data.Indices), MaterialName: file.Materials[imported.material].Name}}
 			}
-			y, err := strconv.ParseFloat(splitStrings[2], 32)
-			if err != nil {
-				return nil, errors.New("Invalid Vertex Texture Coordinate Format: " + err.Error())
+			meshes = append(meshes, data)
+		}
+	}
+	return meshes, nil
+}
+
+// resolveObjMaterials reads an obj file's material libraries and gives each submesh it's material. Missing libraries and
+// materials are logged rather than failing the import, the meshes just use the default material
+func resolveObjMaterials(meshes []*MeshData, libraries []string, directory string) {
+	materials := make(map[string]*MtlMaterial)
+	for _, library := range libraries {
+		path := filepath.FromSlash(strings.Replace(library, "\\", "/", -1))
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(directory, path)
+		}
+		libraryMaterials, err := ReadMtlFile(path)
+		if err != nil {
+			dbg.LogError(err.Error())
+			continue
+		}
+		for name, material := range libraryMaterials {
+			materials[name] = material
+		}
+	}
+
+	for _, mesh := range meshes {
+		for i := range mesh.Submeshes {
+			submesh := &mesh.Submeshes[i]
+			if submesh.MaterialName == "" {
+				continue
 			}
-			texCoords[tcIndex] = float32(x)
-			tcIndex++
-			texCoords[tcIndex] = float32(y)
-			tcIndex++
-
-		// Group Command: increment current group
-		case "g":
-			gIndex++
-		// Face Command: extract data into current group
-		case "f":
-			if len(splitStrings) != 4 {
-				return nil, errors.New("Unsupported Element: Currently Surreal Engine only supports triangle meshes")
+			material, ok := materials[submesh.MaterialName]
+			if !ok {
+				dbg.LogError("Missing Material: " + submesh.MaterialName + " isn't in any of the obj's material libraries")
+				continue
 			}
+			submesh.Material = material
+		}
+	}
+}
 
-			face := new(objFace)
-			for i := 1; i < 4; i++ {
-				faceProperties := strings.Split(splitStrings[i], "/")
-				// If the document has normals and texcoords
-				// NOTE: Indexes start at 1 in obj files so we have to -1 for arrays in go
-				if normalCount > 0 && texCoordsCount > 0 {
-					if len(faceProperties) < 3 {
-						return nil, errors.New("Invalid Face Format: Faces must have normals and texture coordinates")
-					}
-					vert, err := strconv.ParseUint(faceProperties[0], 10, 32)
-					if err != nil {
-						return nil, errors.New("Invalid Face Format: " + err.Error())
-					}
-					tex, err := strconv.ParseUint(faceProperties[1], 10, 32)
-					if err != nil {
-						return nil, errors.New("Invalid Face Format: " + err.Error())
-					}
-					norm, err := strconv.ParseUint(faceProperties[2], 10, 32)
-					if err != nil {
-						return nil, errors.New("Invalid Face Format: " + err.Error())
-					}
-					face.vertices = append(face.vertices, uint32(vert)-1)
-					face.texCoords = append(face.texCoords, uint32(tex)-1)
-					face.normals = append(face.normals, uint32(norm)-1)
-				} else if normalCount <= 0 && texCoordsCount > 0 {
-					if len(faceProperties) < 2 {
-						return nil, errors.New("Invalid Face Format: Faces must have texture coordinates")
-					}
-					vert, err := strconv.ParseUint(faceProperties[0], 10, 32)
-					if err != nil {
-						return nil, errors.New("Invalid Face Format: " + err.Error())
-					}
-					tex, err := strconv.ParseUint(faceProperties[1], 10, 32)
-					if err != nil {
-						return nil, errors.New("Invalid Face Format: " + err.Error())
-					}
-					face.vertices = append(face.vertices, uint32(vert)-1)
-					face.texCoords = append(face.texCoords, uint32(tex)-1)
-				} else if normalCount > 0 && texCoordsCount <= 0 {
-					if len(faceProperties) < 3 {
-						return nil, errors.New("Invalid Face Format: Faces must have normals coordinates")
-					}
-					vert, err := strconv.ParseUint(faceProperties[0], 10, 32)
-					if err != nil {
-						return nil, errors.New("Invalid Face Format: " + err.Error())
-					}
-					norm, err := strconv.ParseUint(faceProperties[2], 10, 32)
-					if err != nil {
-						return nil, errors.New("Invalid Face Format: " + err.Error())
-					}
-					face.vertices = append(face.vertices, uint32(vert)-1)
-					face.normals = append(face.normals, uint32(norm)-1)
-				} else {
-					if len(faceProperties) < 1 {
-						return nil, errors.New("Invalid Face Format: Did you even try?")
-					}
-					vert, err := strconv.ParseUint(faceProperties[0], 10, 32)
-					if err != nil {
-						return nil, errors.New("Invalid Face Format: " + err.Error())
-					}
-					face.vertices = append(face.vertices, uint32(vert)-1)
+// decodeImportedTextures decodes every texture the submeshes' materials use so only the upload is left for the main
+// thread
+func decodeImportedTextures(meshes []*MeshData) map[string]importedTexture {
+	textures := make(map[string]importedTexture)
+	for _, mesh := range meshes {
+		for _, submesh := range mesh.submeshes() {
+			if submesh.Material == nil {
+				continue
+			}
+			for _, filePath := range []string{submesh.Material.DiffuseMap, submesh.Material.SpecularMap, submesh.Material.NormalMap} {
+				if _, ok := textures[filePath]; ok || filePath == "" {
+					continue
 				}
+				decoded, err := decodeTextureFile(filePath, ChannelsRGBA, false)
+				textures[filePath] = importedTexture{decoded: decoded, err: err}
 			}
-			groups[gIndex] = append(groups[gIndex], *face)
 		}
 	}
+	return textures
+}
 
-	var meshes []*Mesh
+// createImportedMaterials makes a Material for each submesh, in the order Upload makes their meshes. Submeshes with the
+// same MtlMaterial share a Material and materials share textures. Submeshes without one get DefaultMeshMaterial(), or a
+// shared lit material with vertex colors on if their mesh has colors. Textures in decoded are uploaded, others are
+// loaded from disk. decoded may be nil
+func createImportedMaterials(meshes []*MeshData, decoded map[string]importedTexture) []*Material {
+	textures := make(map[string]*Texture)
+	failed := make(map[string]error)
+	loadTexture := func(filePath string) (*Texture, error) {
+		if texture, ok := textures[filePath]; ok {
+			return texture, nil
+		}
+		if err, ok := failed[filePath]; ok {
+			return nil, err
+		}
 
-	for _, group := range groups {
-		var mVertexData, mNormalData, mTextureData []float32
-		var mIndices []uint32
-		vIndexMap := make(map[string]uint32)
-
-		for _, face := range group {
-			// If normals aren't defined, infer normals for this face
-			var nx, ny, nz float32
-			if len(face.normals) <= 0 {
-				v1x, v1y, v1z := vertices[face.vertices[0]*3], vertices[face.vertices[0]*3+1], vertices[face.vertices[0]*3+2]
-				v2x, v2y, v2z := vertices[face.vertices[1]*3], vertices[face.vertices[1]*3+1], vertices[face.vertices[1]*3+2]
-				v3x, v3y, v3z := vertices[face.vertices[2]*3], vertices[face.vertices[2]*3+1], vertices[face.vertices[2]*3+2]
-				ux, uy, uz := v2x-v1x, v2y-v1y, v2z-v1z
-				vx, vy, vz := v3x-v1x, v3y-v1y, v3z-v1z
-				nx = uy*vz - uz*vy
-				ny = uz*vx - ux*vz
-				nz = ux*vy - uy*vx
+		texture := CreateTexture(filePath)
+		texture.GenerateMipMaps = true
+		var err error
+		if imported, ok := decoded[filePath]; ok {
+			err = imported.err
+			if err == nil {
+				err = texture.uploadDecoded(imported.decoded)
 			}
+		} else {
+			err = texture.Load()
+		}
+		if err != nil {
+			texture.Delete()
+			failed[filePath] = err
+			return nil, err
+		}
+		texture.lastLoadedPath = filePath
+		textures[filePath] = texture
+		return texture, nil
+	}
 
-			for ind, vInd := range face.vertices {
-				vxInd, vyInd, vzInd := vInd*3, vInd*3+1, vInd*3+2
-				indexKey := strconv.Itoa(int(vInd)) + "/"
-				if len(face.texCoords) > 0 {
-					indexKey += strconv.Itoa(int(face.texCoords[ind]))
-				}
-				indexKey += "/"
-				if len(face.normals) > 0 {
-					indexKey += strconv.Itoa(int(face.normals[ind]))
+	created := make(map[*MtlMaterial]*Material)
+	var vertexColorMaterial *Material
+	var materials []*Material
+	for _, mesh := range meshes {
+		for _, submesh := range mesh.submeshes() {
+			switch {
+			case submesh.Material == nil && mesh.Colors != nil:
+				if vertexColorMaterial == nil {
+					vertexColorMaterial = CreateLitMaterial()
+					vertexColorMaterial.SetMaterialParameter("u_UseVertexColor", int32(1))
 				}
-				// Check if we've already mapped this vertex for this mesh
-				mInd, ok := vIndexMap[indexKey]
-				// If not, then map it
+				materials = append(materials, vertexColorMaterial)
+			case submesh.Material == nil:
+				materials = append(materials, DefaultMeshMaterial())
+			default:
+				material, ok := created[submesh.Material]
 				if !ok {
-					mInd = uint32(len(vIndexMap))
-					vIndexMap[indexKey] = mInd
-					// We formatted these lists, so if there's an invalid index, we deserve to panic
-					mVertexData = append(mVertexData, vertices[vxInd])
-					mVertexData = append(mVertexData, vertices[vyInd])
-					mVertexData = append(mVertexData, vertices[vzInd])
-					if len(face.normals) > 0 {
-						// If normals are defined add them
-						nxInd, nyInd, nzInd := face.normals[ind]*3, face.normals[ind]*3+1, face.normals[ind]*3+2
-						mNormalData = append(mNormalData, normals[nxInd])
-						mNormalData = append(mNormalData, normals[nyInd])
-						mNormalData = append(mNormalData, normals[nzInd])
-					} else {
-						// else we use infered normals
-						mNormalData = append(mNormalData, nx)
-						mNormalData = append(mNormalData, ny)
-						mNormalData = append(mNormalData, nz)
-					}
-					if len(face.texCoords) > 0 {
-						txInd, tyInd := face.texCoords[ind]*2, face.texCoords[ind]*2+1
-						mTextureData = append(mTextureData, texCoords[txInd])
-						mTextureData = append(mTextureData, texCoords[tyInd])
-					} else {
-						mTextureData = append(mTextureData, 0)
-						mTextureData = append(mTextureData, 0)
-					}
+					material = submesh.Material.createMaterial(loadTexture)
+					created[submesh.Material] = material
 				}
-
-				mIndices = append(mIndices, mInd)
+				materials = append(materials, material)
 			}
 		}
-
-		vertexArray := CreateVertexArray()
-		vertexArray.PushVertexAttribute("S_Position", gl.FLOAT, 3)
-		vertexArray.PushVertexAttribute("S_Normal", gl.FLOAT, 3)
-		vertexArray.PushVertexAttribute("S_TexUV", gl.FLOAT, 2)
-		vertexArray.SetAttributeData("S_Position", &mVertexData, gl.STATIC_DRAW)
-		vertexArray.SetAttributeData("S_Normal", &mNormalData, gl.STATIC_DRAW)
-		vertexArray.SetAttributeData("S_TexUV", &mTextureData, gl.STATIC_DRAW)
-		indexArray := CreateVertexIndexArray()
-		indexArray.SetData(&mIndices, gl.STATIC_DRAW)
-		mesh := CreateMesh(vertexArray, indexArray)
-		meshes = append(meshes, mesh)
 	}
-
-	meshParent := core.CreateSceneObject(nil)
-
-	for _, mesh := range meshes {
-		renderer := CreateMeshRendererComponent(mesh, DefaultMeshMaterial())
-		so := core.CreateSceneObject(renderer)
-		so.Transform.SetParent(meshParent.Transform)
-	}
-
-	return meshParent, nil
+	return materials
 }
@@ -0,0 +1,141 @@
+package gfx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Surreal/Math/math"
+)
+
+func TestParseMeshFileGLTF(t *testing.T) {
+	var buffer bytes.Buffer
+	binary.Write(&buffer, binary.LittleEndian, []float32{0, 0, 0, 1, 0, 0, 0, 1, 0})
+	document := gltfTestDocument(buffer.Bytes(), buffer.Len(), `,
+		"bufferViews": [{"buffer": 0, "byteLength": 36}],
+		"accessors": [{"bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC3"}],
+		"materials": [{"name": "red"}],
+		"meshes": [{"name": "triangle", "primitives": [
+			{"attributes": {"POSITION": 0}, "material": 0},
+			{"attributes": {"POSITION": 0}, "mode": 1},
+			{"attributes": {"POSITION": 0}}
+		]}]`)
+
+	for _, filePath := range []string{"test.gltf", "TEST.GLTF"} {
+		meshes, err := ParseMeshFile(filePath, document)
+		if err != nil {
+			t.Errorf("%v: got error %v", filePath, err)
+			continue
+		}
+		// The line primitive is skipped
+		if len(meshes) != 2 {
+			t.Errorf("%v: got %v meshes, expected 2", filePath, len(meshes))
+			continue
+		}
+		for i, mesh := range meshes {
+			if mesh.Name != "triangle" || mesh.VertexCount() != 3 || len(mesh.Indices) != 3 || len(mesh.Normals) != 9 {
+				t.Errorf("%v: got mesh %v %q with %v vertices, %v indices and %v normal values, expected \"triangle\" with 3, 3 and 9",
+					filePath, i, mesh.Name, mesh.VertexCount(), len(mesh.Indices), len(mesh.Normals))
+			}
+		}
+		if name := meshes[0].Submeshes[0].MaterialName; name != "red" {
+			t.Errorf("%v: got material %q, expected \"red\"", filePath, name)
+		}
+		if name := meshes[1].Submeshes[0].MaterialName; name != "" {
+			t.Errorf("%v: got material %q for a primitive without one, expected none", filePath, name)
+		}
+	}
+
+	if _, err := ParseMeshFile("test.glb", []byte("not a glb")); err == nil {
+		t.Errorf("bad glb: got no error, expected one")
+	}
+}
+
+// registerTestImporter registers importer for extension until the test ends
+func registerTestImporter(t *testing.T, extension string, importer MeshImporterFunc) {
+	RegisterImporter(extension, importer)
+	t.Cleanup(func() {
+		meshImportersLock.Lock()
+		delete(meshImporters, extension)
+		meshImportersLock.Unlock()
+	})
+}
+
+func TestRegisterImporter(t *testing.T) {
+	var importedPath string
+	registerTestImporter(t, ".testmesh", func(filePath string, fileData []byte) ([]*MeshData, error) {
+		importedPath = filePath
+		return []*MeshData{{Name: string(fileData), Positions: []float32{0, 0, 0, 1, 0, 0, 0, 1, 0}, Indices: []uint32{0, 1, 2}}}, nil
+	})
+
+	// Registered without the dot and in any case
+	registerTestImporter(t, ".othermesh", nil)
+	RegisterImporter("OtherMesh", importStl)
+	meshImportersLock.RLock()
+	importer := meshImporters[".othermesh"]
+	meshImportersLock.RUnlock()
+	if importer == nil {
+		t.Errorf("registering \"OtherMesh\" didn't replace the importer for \".othermesh\"")
+	}
+
+	meshes, err := ParseMeshFile("models/Triangle.TESTMESH", []byte("triangle"))
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if importedPath != "models/Triangle.TESTMESH" {
+		t.Errorf("the importer got path %q, expected the one given to ParseMeshFile", importedPath)
+	}
+	mesh := meshes[0]
+	// Normals, submeshes and bounds are filled in after the importer
+	if len(meshes) != 1 || mesh.Name != "triangle" || len(mesh.Normals) != 9 || len(mesh.Submeshes) != 1 ||
+		mesh.Bounds != (math.AABB{Max: math.Vector3f{X: 1, Y: 1}}) {
+		t.Errorf("got meshes %+v, expected the importer's triangle with normals, a submesh and bounds", meshes)
+	}
+
+	if _, err := ParseMeshFile("model.unknownmesh", nil); err == nil {
+		t.Errorf("unregistered extension: got no error, expected one")
+	}
+}
+
+func TestParseMeshFileRejectsBadImports(t *testing.T) {
+	tests := []struct {
+		name   string
+		meshes []*MeshData
+		err    error
+	}{
+		{"importer error", nil, errors.New("Test: Importer failed")},
+		{"no meshes", nil, nil},
+		{"nil mesh", []*MeshData{nil}, nil},
+		{"empty mesh", []*MeshData{{}}, nil},
+		{"no indices", []*MeshData{{Positions: []float32{0, 0, 0, 1, 0, 0, 0, 1, 0}}}, nil},
+		{"index out of range", []*MeshData{{Positions: []float32{0, 0, 0, 1, 0, 0, 0, 1, 0}, Indices: []uint32{0, 1, 3}}}, nil},
+		{"empty submesh", []*MeshData{{Positions: []float32{0, 0, 0, 1, 0, 0, 0, 1, 0}, Indices: []uint32{0, 1, 2}, Submeshes: []Submesh{{Count: 0}}}}, nil},
+	}
+	for _, test := range tests {
+		test := test
+		registerTestImporter(t, ".badmesh", func(filePath string, fileData []byte) ([]*MeshData, error) {
+			return test.meshes, test.err
+		})
+		if _, err := ParseMeshFile("model.badmesh", nil); err == nil {
+			t.Errorf("%v: got no error, expected one", test.name)
+		}
+	}
+}
+
+func TestParseMeshFileSkipsShortGLTFPrimitives(t *testing.T) {
+	var buffer bytes.Buffer
+	binary.Write(&buffer, binary.LittleEndian, []float32{0, 0, 0, 1, 0, 0, 0, 1, 0})
+	binary.Write(&buffer, binary.LittleEndian, []uint16{0, 1})
+	document := gltfTestDocument(buffer.Bytes(), buffer.Len(), `,
+		"bufferViews": [{"buffer": 0, "byteLength": 36}, {"buffer": 0, "byteOffset": 36, "byteLength": 4}],
+		"accessors": [
+			{"bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC3"},
+			{"bufferView": 1, "componentType": 5123, "count": 2, "type": "SCALAR"}
+		],
+		"meshes": [{"primitives": [{"attributes": {"POSITION": 0}, "indices": 1}]}]`)
+	if _, err := ParseMeshFile("short.gltf", document); err == nil || !strings.Contains(err.Error(), "no meshes") {
+		t.Errorf("got error %v, expected one about the file having no meshes", err)
+	}
+}
@@ -1,9 +1,7 @@
 package gfx
 
 import (
-	"github.com/Surreal/Debug/dbg"
 	"github.com/Surreal/Systems/Core/core"
-	"github.com/go-gl/gl/v3.2-core/gl"
 )
 
 // MeshRendererComponent will render a mesh to the screen
@@ -16,44 +14,24 @@ type MeshRendererComponent struct {
 // CreateMeshRendererComponent is the standard constructor for a MeshRenderer
 func CreateMeshRendererComponent(model *Mesh, material *Material) *MeshRendererComponent {
 	mrend := new(MeshRendererComponent)
-	mrend.BaseComponent = new(core.BaseComponent)
+	mrend.BaseComponent = core.CreateBaseComponent(mrend)
 	mrend.Model = model
 	mrend.RenderMaterial = material
 	return mrend
 }
 
-// Render implements the Renderer interface allowing this object to draw itself to the screen
+// Render implements the Renderer interface by submitting this mesh to the MainRenderer. Nothing is drawn until
+// MainRenderer.Flush is called
 func (mren *MeshRendererComponent) Render() error {
-	// Set the model matrix
-	// NOTE: Should this be here?
-	if mren.SceneObject() != nil {
-		err := mren.RenderMaterial.SetMaterialParameter("u_Model", mren.SceneObject().Transform.Model2WorldMatrix().ColMajorData())
-		if err != nil {
-			dbg.LogError(err.Error())
-		}
+	if mren.SceneObject() == nil {
+		return nil
 	}
 
-	// Set the camera matrices
-	// NOTE: Should this be here?
-	if MainCamera != nil {
-		err := mren.RenderMaterial.SetMaterialParameter("u_View", MainCamera.ViewMatrix().ColMajorData())
-		if err != nil {
-			dbg.LogError(err.Error())
-		}
-		err = mren.RenderMaterial.SetMaterialParameter("u_Projection", MainCamera.ActiveProjectionMatrix.ColMajorData())
-		if err != nil {
-			dbg.LogError(err.Error())
-		}
-	}
-
-	mren.Model.Verticies.Bind()
-	defer mren.Model.Verticies.UnBind()
-	mren.Model.VertexIndicies.Bind()
-	defer mren.Model.VertexIndicies.UnBind()
-	mren.RenderMaterial.Bind()
-	defer mren.RenderMaterial.UnBind()
-	gl.DrawElements(gl.TRIANGLES, int32(mren.Model.VertexIndicies.Count), uint32(gl.UNSIGNED_INT), gl.PtrOffset(0))
-	return nil
+	return MainRenderer.Submit(DrawCommand{
+		Mesh:     mren.Model,
+		Material: mren.RenderMaterial,
+		Model:    *mren.SceneObject().Transform.Model2WorldMatrix(),
+	})
 }
 
 // Attach implements the component interface
@@ -0,0 +1,31 @@
+package gfx
+
+import "testing"
+
+func TestMeshDeleteSharedVertices(t *testing.T) {
+	// IDs of 0 keep openGL out of it, Count shows whether the vertex array has been deleted
+	vertexArray := &VertexArray{Attributes: map[string]*VertexAttribute{}, Count: 3}
+	meshes := []*Mesh{
+		CreateMesh(vertexArray, &VertexIndexArray{}),
+		CreateMesh(vertexArray, &VertexIndexArray{}),
+		CreateMesh(vertexArray, &VertexIndexArray{}),
+	}
+	shareVertices(meshes)
+
+	meshes[0].Delete()
+	meshes[0].Delete()
+	meshes[1].Delete()
+	if vertexArray.Count != 3 {
+		t.Fatalf("deleting 2 of 3 meshes, one twice, freed the vertex array they share")
+	}
+	meshes[2].Delete()
+	if vertexArray.Count != 0 {
+		t.Errorf("deleting every mesh didn't free the vertex array they share")
+	}
+
+	alone := &VertexArray{Attributes: map[string]*VertexAttribute{}, Count: 3}
+	CreateMesh(alone, nil).Delete()
+	if alone.Count != 0 {
+		t.Errorf("deleting a mesh didn't free it's own vertex array")
+	}
+}
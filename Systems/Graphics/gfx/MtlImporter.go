@@ -0,0 +1,261 @@
+package gfx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Surreal/Debug/dbg"
+	"github.com/Surreal/Math/math"
+)
+
+// MtlMaterial is one newmtl entry of a Wavefront material library
+type MtlMaterial struct {
+	Name             string        // The name obj files use it by
+	AmbientColor     math.Vector3f // Ka
+	DiffuseColor     math.Vector3f // Kd
+	SpecularColor    math.Vector3f // Ks
+	SpecularExponent float32       // Ns
+	Opacity          float32       // d, or 1 - Tr
+	IlluminationMode int           // illum. 0 and 1 have no specular highlights
+	DiffuseMap       string        // map_Kd, as a path relative to the working directory
+	SpecularMap      string        // map_Ks
+	NormalMap        string        // norm, a tangent space normal map
+	NormalStrength   float32       // The -bm option of the normal map
+	BumpMap          string        // map_Bump or bump. Usually a height map, which the lit shader can't use, so it's ignored
+	BumpStrength     float32       // The -bm option of the bump map
+}
+
+// mtlTextureOptions is how many values each texture map option takes. -o, -s and -t take up to 3
+var mtlTextureOptions = map[string]int{
+	"-blendu": 1, "-blendv": 1, "-bm": 1, "-boost": 1, "-cc": 1, "-clamp": 1, "-imfchan": 1, "-texres": 1,
+	"-mm": 2, "-o": 3, "-s": 3, "-t": 3,
+}
+
+// ReadMtlFile reads a .mtl file. Texture paths are relative to the file
+func ReadMtlFile(filePath string) (map[string]*MtlMaterial, error) {
+	fileData, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMtl(string(fileData), filepath.Dir(filePath))
+}
+
+// ParseMtl parses the contents of a material library. Relative texture paths are joined onto directory
+func ParseMtl(raw string, directory string) (map[string]*MtlMaterial, error) {
+	materials := make(map[string]*MtlMaterial)
+	var current *MtlMaterial
+
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if comment := strings.IndexByte(line, '#'); comment >= 0 {
+			line = line[:comment]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "newmtl" {
+			current = &MtlMaterial{
+				Name:             strings.Join(fields[1:], " "),
+				DiffuseColor:     math.OnesVector3f(),
+				SpecularExponent: 32,
+				Opacity:          1,
+				IlluminationMode: 2,
+				NormalStrength:   1,
+				BumpStrength:     1,
+			}
+			materials[current.Name] = current
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("Invalid Mtl File: %v before the first newmtl (mtl line %v)", fields[0], lineNumber)
+		}
+
+		var err error
+		switch strings.ToLower(fields[0]) {
+		case "ka":
+			current.AmbientColor, err = parseMtlColor(fields[1:])
+		case "kd":
+			current.DiffuseColor, err = parseMtlColor(fields[1:])
+		case "ks":
+			current.SpecularColor, err = parseMtlColor(fields[1:])
+		case "ns":
+			current.SpecularExponent, err = parseMtlFloat(fields[1:])
+		case "d":
+			// Some exporters write "d -halo 0.5"
+			values := fields[1:]
+			if len(values) > 0 && values[0] == "-halo" {
+				values = values[1:]
+			}
+			current.Opacity, err = parseMtlFloat(values)
+		case "tr":
+			var transparency float32
+			transparency, err = parseMtlFloat(fields[1:])
+			current.Opacity = 1 - transparency
+		case "illum":
+			var mode float32
+			mode, err = parseMtlFloat(fields[1:])
+			current.IlluminationMode = int(mode)
+		case "map_kd":
+			current.DiffuseMap, _, err = parseMtlTexture(fields[1:], directory)
+		case "map_ks":
+			current.SpecularMap, _, err = parseMtlTexture(fields[1:], directory)
+		case "map_bump", "bump":
+			current.BumpMap, current.BumpStrength, err = parseMtlTexture(fields[1:], directory)
+		case "norm":
+			current.NormalMap, current.NormalStrength, err = parseMtlTexture(fields[1:], directory)
+		}
+		// Anything else, i.e. reflection maps or PBR extensions, isn't something the lit shader can use
+
+		if err != nil {
+			return nil, fmt.Errorf("%v (mtl line %v)", err, lineNumber)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New("Invalid Mtl File: " + err.Error())
+	}
+
+	return materials, nil
+}
+
+// parseMtlColor parses an rgb color. A single value is used for all three, spectral and xyz colors aren't supported
+func parseMtlColor(fields []string) (math.Vector3f, error) {
+	if len(fields) > 0 && (fields[0] == "spectral" || fields[0] == "xyz") {
+		return math.Vector3f{}, errors.New("Unsupported Mtl Color: " + fields[0])
+	}
+	values, err := parseObjFloats(fields, 1, "Mtl Color")
+	if err != nil {
+		return math.Vector3f{}, err
+	}
+	if len(values) < 3 {
+		return math.Vector3f{X: values[0], Y: values[0], Z: values[0]}, nil
+	}
+	return math.Vector3f{X: values[0], Y: values[1], Z: values[2]}, nil
+}
+
+// parseMtlFloat parses a single value
+func parseMtlFloat(fields []string) (float32, error) {
+	values, err := parseObjFloats(fields, 1, "Mtl Value")
+	if err != nil {
+		return 0, err
+	}
+	return values[0], nil
+}
+
+// parseMtlTexture parses a texture map statement, skipping it's options. Returns the path joined onto directory and
+// the -bm bump multiplier, which is 1 if not given
+func parseMtlTexture(fields []string, directory string) (string, float32, error) {
+	bumpStrength := float32(1)
+	i := 0
+	for i < len(fields) {
+		count, ok := mtlTextureOptions[strings.ToLower(fields[i])]
+		if !ok {
+			break
+		}
+		option := strings.ToLower(fields[i])
+		i++
+		for taken := 0; taken < count && i < len(fields); taken++ {
+			value, err := strconv.ParseFloat(fields[i], 32)
+			if err != nil {
+				// Options like -clamp take on/off, and -o, -s and -t can stop early
+				if taken == 0 && count == 1 {
+					i++
+				}
+				break
+			}
+			if option == "-bm" {
+				bumpStrength = float32(value)
+			}
+			i++
+		}
+	}
+	if i >= len(fields) {
+		return "", bumpStrength, errors.New("Invalid Mtl File: Texture map without a file name")
+	}
+
+	// Exporters on windows write backslashes
+	name := strings.Replace(strings.Join(fields[i:], " "), "\\", "/", -1)
+	path := filepath.FromSlash(name)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(directory, path)
+	}
+	return path, bumpStrength, nil
+}
+
+// CreateMaterial makes a Material for the lit shader that looks like the entry, loading it's texture maps. Maps that
+// fail to load are logged and left off
+func (mtl *MtlMaterial) CreateMaterial() *Material {
+	return mtl.createMaterial(func(filePath string) (*Texture, error) {
+		texture := CreateTexture(filePath)
+		texture.GenerateMipMaps = true
+		if err := texture.Load(); err != nil {
+			texture.Delete()
+			return nil, err
+		}
+		return texture, nil
+	})
+}
+
+// createMaterial is CreateMaterial with the texture loading left to loadTexture so textures can be shared
+func (mtl *MtlMaterial) createMaterial(loadTexture func(filePath string) (*Texture, error)) *Material {
+	material := CreateLitMaterial()
+
+	// The lit shader has one specular strength, which the brightest channel of Ks is a good stand in for
+	specular := math.Max(mtl.SpecularColor.X, math.Max(mtl.SpecularColor.Y, mtl.SpecularColor.Z))
+	specularTint := math.OnesVector3f()
+	if specular > 0 {
+		specularTint = mtl.SpecularColor.Scale(1 / specular)
+	}
+	if mtl.IlluminationMode < 2 {
+		specular = 0
+	}
+
+	// Without an ambient color exporters mean "use the scene's ambient", which a tint of 0 would black out
+	ambient := mtl.AmbientColor
+	if ambient == (math.Vector3f{}) {
+		ambient = math.OnesVector3f()
+	}
+
+	material.SetMaterialParameters(map[string]interface{}{
+		"u_Tint":              []float32{mtl.DiffuseColor.X, mtl.DiffuseColor.Y, mtl.DiffuseColor.Z, mtl.Opacity},
+		"u_AmbientTint":       []float32{ambient.X, ambient.Y, ambient.Z},
+		"u_SpecularStrength":  specular,
+		"u_SpecularTint":      []float32{specularTint.X, specularTint.Y, specularTint.Z},
+		"u_Shininess":         mtl.SpecularExponent,
+		"u_UseAlbedoMap":      int32(0),
+		"u_NormalMapStrength": mtl.NormalStrength,
+	})
+
+	maps := []struct {
+		filePath string
+		sampler  string
+		toggle   string
+	}{
+		{mtl.DiffuseMap, "u_Albedo", "u_UseAlbedoMap"},
+		{mtl.SpecularMap, "u_SpecularMap", "u_UseSpecularMap"},
+		{mtl.NormalMap, "u_NormalMap", "u_UseNormalMap"},
+	}
+	for _, textureMap := range maps {
+		if textureMap.filePath == "" {
+			continue
+		}
+		texture, err := loadTexture(textureMap.filePath)
+		if err != nil {
+			dbg.LogError(fmt.Sprintf("Material %v: %v", mtl.Name, err.Error()))
+			continue
+		}
+		material.SetTextureParameter(textureMap.sampler, texture)
+		material.SetMaterialParameter(textureMap.toggle, int32(1))
+	}
+
+	return material
+}
@@ -0,0 +1,139 @@
+package gfx
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Surreal/Math/math"
+)
+
+func TestParseMtl(t *testing.T) {
+	source := `# Exported library
+newmtl red paint
+Ka 0.1 0.1 0.1
+Kd 1 0 0
+Ks 0.5
+Ns 96
+d 0.75
+illum 1
+map_Kd -clamp on -o 0.5 0.5 0 -s 2 2 1 textures\red paint.png   # comment after a path
+
+newmtl glass
+Kd 0.2 0.4 0.6
+Tr 0.9
+map_Ks -blendu off specular.png
+map_Bump -bm 0.3 height.png
+norm -bm 2 -o 0.25 normal.png
+`
+	directory := filepath.Join("models", "car")
+	materials, err := ParseMtl(source, directory)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if len(materials) != 2 {
+		t.Fatalf("got %v materials, expected 2", len(materials))
+	}
+
+	red := materials["red paint"]
+	if red == nil {
+		t.Fatalf("got materials %v, expected one called \"red paint\"", materials)
+	}
+	expectedRed := MtlMaterial{
+		Name:             "red paint",
+		AmbientColor:     math.Vector3f{X: 0.1, Y: 0.1, Z: 0.1},
+		DiffuseColor:     math.Vector3f{X: 1},
+		SpecularColor:    math.Vector3f{X: 0.5, Y: 0.5, Z: 0.5},
+		SpecularExponent: 96,
+		Opacity:          0.75,
+		IlluminationMode: 1,
+		DiffuseMap:       filepath.Join(directory, "textures", "red paint.png"),
+		NormalStrength:   1,
+		BumpStrength:     1,
+	}
+	if *red != expectedRed {
+		t.Errorf("got red paint %+v\nexpected %+v", *red, expectedRed)
+	}
+
+	// Defaults are kept for anything the material doesn't set
+	glass := materials["glass"]
+	expectedGlass := MtlMaterial{
+		Name:             "glass",
+		DiffuseColor:     math.Vector3f{X: 0.2, Y: 0.4, Z: 0.6},
+		SpecularExponent: 32,
+		Opacity:          1 - 0.9,
+		IlluminationMode: 2,
+		SpecularMap:      filepath.Join(directory, "specular.png"),
+		NormalMap:        filepath.Join(directory, "normal.png"),
+		NormalStrength:   2,
+		BumpMap:          filepath.Join(directory, "height.png"),
+		BumpStrength:     0.3,
+	}
+	if glass == nil || !math.ApproxEqual(glass.Opacity, expectedGlass.Opacity, 1e-6) {
+		t.Fatalf("got glass %+v, expected %+v", glass, expectedGlass)
+	}
+	glass.Opacity = expectedGlass.Opacity
+	if *glass != expectedGlass {
+		t.Errorf("got glass %+v\nexpected %+v", *glass, expectedGlass)
+	}
+}
+
+func TestParseMtlTexture(t *testing.T) {
+	absolute, _ := filepath.Abs(filepath.Join("textures", "wood.png"))
+	tests := []struct {
+		name     string
+		fields   string
+		path     string
+		strength float32
+	}{
+		{"plain", "wood.png", filepath.Join("lib", "wood.png"), 1},
+		{"subdirectory", "textures/wood.png", filepath.Join("lib", "textures", "wood.png"), 1},
+		{"backslashes", `textures\wood.png`, filepath.Join("lib", "textures", "wood.png"), 1},
+		{"parent directory", "../wood.png", "wood.png", 1},
+		{"absolute", absolute, absolute, 1},
+		{"spaces", "old wood.png", filepath.Join("lib", "old wood.png"), 1},
+		{"bump multiplier", "-bm 0.5 wood.png", filepath.Join("lib", "wood.png"), 0.5},
+		{"option case", "-BM 0.5 wood.png", filepath.Join("lib", "wood.png"), 0.5},
+		{"clamp on", "-clamp on wood.png", filepath.Join("lib", "wood.png"), 1},
+		{"offset with 3 values", "-o 1 2 3 wood.png", filepath.Join("lib", "wood.png"), 1},
+		{"offset with 1 value", "-o 1 wood.png", filepath.Join("lib", "wood.png"), 1},
+		{"range", "-mm 0 1 wood.png", filepath.Join("lib", "wood.png"), 1},
+		{"every option", "-blendu on -blendv off -boost 2 -cc off -imfchan l -texres 512 -s 1 1 -t 0 0 0 -bm 3 wood.png", filepath.Join("lib", "wood.png"), 3},
+	}
+	for _, test := range tests {
+		path, strength, err := parseMtlTexture(strings.Fields(test.fields), "lib")
+		if err != nil {
+			t.Errorf("%v: got error %v", test.name, err)
+			continue
+		}
+		if path != test.path || strength != test.strength {
+			t.Errorf("%v: got %q with strength %v, expected %q with %v", test.name, path, strength, test.path, test.strength)
+		}
+	}
+
+	for _, fields := range []string{"", "-bm 2", "-clamp on"} {
+		if _, _, err := parseMtlTexture(strings.Fields(fields), "lib"); err == nil {
+			t.Errorf("%q: got no error for a texture map without a file name", fields)
+		}
+	}
+}
+
+func TestParseMtlErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		contains string
+	}{
+		{"statement before newmtl", "Kd 1 1 1\nnewmtl a\n", "before the first newmtl"},
+		{"bad color", "newmtl a\nKd red\n", "line 2"},
+		{"spectral color", "newmtl a\nKd spectral file.rfl\n", "Unsupported"},
+		{"map without a file", "newmtl a\nmap_Kd -bm 1\n", "without a file name"},
+		{"missing value", "newmtl a\nNs\n", "line 2"},
+	}
+	for _, test := range tests {
+		_, err := ParseMtl(test.source, "")
+		if err == nil || !strings.Contains(err.Error(), test.contains) {
+			t.Errorf("%v: got error %v, expected one containing %q", test.name, err, test.contains)
+		}
+	}
+}
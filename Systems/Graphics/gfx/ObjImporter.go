@@ -0,0 +1,492 @@
+package gfx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Surreal/Math/math"
+)
+
+// objCorner is one corner of a face as 0 based indices into the file's lists. -1 means the corner doesn't have one
+type objCorner struct {
+	position int
+	texCoord int
+	normal   int
+}
+
+// objFace is one polygon of an obj file
+type objFace struct {
+	corners   []objCorner
+	smoothing int    // The smoothing group, 0 means flat shaded
+	material  string // The name from the last usemtl statement
+}
+
+// objGroup is the faces under one g or o statement. Each group becomes a mesh with a submesh per material it uses
+type objGroup struct {
+	name  string
+	faces []objFace
+}
+
+// objParser holds the state of an obj file as it's read line by line
+type objParser struct {
+	positions  []math.Vector3f
+	texCoords  []math.Vector2f
+	normals    []math.Vector3f
+	groups     []*objGroup
+	current    *objGroup // The group faces are added to, nil until the first g, o or f
+	objectName string    // The name of the last o statement
+	smoothing  int       // The smoothing group of the last s statement
+	material   string    // The material of the last usemtl statement
+	libraries  []string  // The files named by mtllib statements
+}
+
+// objVertexKey identifies a unique vertex of a mesh. Corners with the same key share a vertex
+type objVertexKey struct {
+	position  int
+	texCoord  int
+	normal    int // -1 if the normal is generated
+	smoothing int // The smoothing group for smooth generated normals
+	face      int // The face for flat generated normals, otherwise -1
+}
+
+// objSmoothKey identifies a position's generated normal within a smoothing group
+type objSmoothKey struct {
+	position  int
+	smoothing int
+}
+
+// parseObjString parses a Wavefront obj file into one mesh per group with a submesh per material. Polygons are
+// triangulated and missing normals are generated, smooth within a smoothing group and flat otherwise. Also returns the
+// material libraries the file uses, which are left for the caller to read
+func parseObjString(raw string) ([]*MeshData, []string, error) {
+	parser := new(objParser)
+
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNumber, continued := 0, ""
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		// A trailing backslash joins the next line onto this one
+		if strings.HasSuffix(line, "\\") {
+			continued += line[:len(line)-1] + " "
+			continue
+		}
+		line, continued = continued+line, ""
+
+		if err := parser.parseLine(line); err != nil {
+			return nil, nil, fmt.Errorf("%v (obj line %v)", err, lineNumber)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errors.New("Invalid File Format: " + err.Error())
+	}
+	if continued != "" {
+		if err := parser.parseLine(continued); err != nil {
+			return nil, nil, fmt.Errorf("%v (obj line %v)", err, lineNumber)
+		}
+	}
+
+	var meshes []*MeshData
+	for _, group := range parser.groups {
+		if len(group.faces) == 0 {
+			continue
+		}
+		mesh, err := parser.buildMesh(group)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v (obj group %q)", err, group.name)
+		}
+		meshes = append(meshes, mesh)
+	}
+	if len(meshes) == 0 {
+		return nil, nil, errors.New("Invalid File Format: Obj file has no faces")
+	}
+
+	return meshes, parser.libraries, nil
+}
+
+// parseLine handles one statement
+func (parser *objParser) parseLine(line string) error {
+	if comment := strings.IndexByte(line, '#'); comment >= 0 {
+		line = line[:comment]
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	switch fields[0] {
+	// Vertex Command: Add vertex to global list. Anything after xyz, like w or vertex colors, is ignored
+	case "v":
+		values, err := parseObjFloats(fields[1:], 3, "Vertex")
+		if err != nil {
+			return err
+		}
+		parser.positions = append(parser.positions, math.Vector3f{X: values[0], Y: values[1], Z: values[2]})
+
+	// Vertex Normal Command: Add normal to global list
+	case "vn":
+		values, err := parseObjFloats(fields[1:], 3, "Vertex Normal")
+		if err != nil {
+			return err
+		}
+		parser.normals = append(parser.normals, math.Vector3f{X: values[0], Y: values[1], Z: values[2]})
+
+	// Vertex Texture Command: Add coordinate to global list. v is optional and defaults to 0
+	case "vt":
+		values, err := parseObjFloats(fields[1:], 1, "Vertex Texture Coordinate")
+		if err != nil {
+			return err
+		}
+		texCoord := math.Vector2f{X: values[0]}
+		if len(values) > 1 {
+			texCoord.Y = values[1]
+		}
+		parser.texCoords = append(parser.texCoords, texCoord)
+
+	// Group and Object Commands: start a new mesh
+	case "g":
+		name := strings.Join(fields[1:], " ")
+		if name == "" {
+			name = "default"
+		}
+		if parser.objectName != "" {
+			name = parser.objectName + "/" + name
+		}
+		parser.startGroup(name)
+	case "o":
+		parser.objectName = strings.Join(fields[1:], " ")
+		parser.startGroup(parser.objectName)
+
+	// Smoothing Group Command: faces after this share normals with faces in the same group
+	case "s":
+		if len(fields) < 2 || fields[1] == "off" {
+			parser.smoothing = 0
+			return nil
+		}
+		smoothing, err := strconv.Atoi(fields[1])
+		if err != nil || smoothing < 0 {
+			return errors.New("Invalid Smoothing Group: " + fields[1])
+		}
+		parser.smoothing = smoothing
+
+	// Material Commands: name the material library files and pick the material for the faces after this
+	case "mtllib":
+		// Names with spaces are ambiguous, but far more common than several libraries on one line
+		if len(fields) > 1 {
+			parser.libraries = append(parser.libraries, strings.Join(fields[1:], " "))
+		}
+	case "usemtl":
+		parser.material = strings.Join(fields[1:], " ")
+
+	// Face Command: extract data into current group
+	case "f":
+		if len(fields) < 4 {
+			return errors.New("Invalid Face Format: Faces need at least 3 corners")
+		}
+		face := objFace{corners: make([]objCorner, 0, len(fields)-1), smoothing: parser.smoothing, material: parser.material}
+		for _, field := range fields[1:] {
+			corner, err := parser.parseCorner(field)
+			if err != nil {
+				return err
+			}
+			face.corners = append(face.corners, corner)
+		}
+		if parser.current == nil {
+			parser.startGroup("default")
+		}
+		parser.current.faces = append(parser.current.faces, face)
+	}
+
+	// Anything else, i.e. lines, points or curves, isn't something we can draw so it's skipped
+	return nil
+}
+
+// startGroup makes faces go into a new group. An empty current group is reused rather than left empty
+func (parser *objParser) startGroup(name string) {
+	if parser.current != nil && len(parser.current.faces) == 0 {
+		parser.current.name = name
+		return
+	}
+	parser.current = &objGroup{name: name}
+	parser.groups = append(parser.groups, parser.current)
+}
+
+// parseCorner parses a face corner in any of the forms v, v/vt, v//vn or v/vt/vn
+func (parser *objParser) parseCorner(field string) (objCorner, error) {
+	corner := objCorner{position: -1, texCoord: -1, normal: -1}
+	parts := strings.Split(field, "/")
+	if len(parts) > 3 || parts[0] == "" {
+		return corner, errors.New("Invalid Face Format: " + field)
+	}
+
+	var err error
+	if corner.position, err = resolveObjIndex(parts[0], len(parser.positions)); err != nil {
+		return corner, err
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		if corner.texCoord, err = resolveObjIndex(parts[1], len(parser.texCoords)); err != nil {
+			return corner, err
+		}
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		if corner.normal, err = resolveObjIndex(parts[2], len(parser.normals)); err != nil {
+			return corner, err
+		}
+	}
+	return corner, nil
+}
+
+// resolveObjIndex turns a 1 based or negative relative index into a 0 based one. count is how many elements have been
+// defined so far
+func resolveObjIndex(token string, count int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, errors.New("Invalid Face Format: " + err.Error())
+	}
+	switch {
+	case index > 0:
+		index--
+	case index < 0:
+		// Relative to the end of the list so far, -1 is the last one defined
+		index += count
+	default:
+		return 0, errors.New("Invalid Face Format: Indices start at 1")
+	}
+	if index < 0 || index >= count {
+		return 0, fmt.Errorf("Invalid Face Format: Index %v is out of range, only %v defined", token, count)
+	}
+	return index, nil
+}
+
+// parseObjFloats parses at least min floats
+func parseObjFloats(fields []string, min int, kind string) ([]float32, error) {
+	if len(fields) < min {
+		return nil, fmt.Errorf("Invalid %v Format: Expected at least %v values", kind, min)
+	}
+	values := make([]float32, len(fields))
+	for i, field := range fields {
+		value, err := strconv.ParseFloat(field, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %v Format: %v", kind, err.Error())
+		}
+		values[i] = float32(value)
+	}
+	return values, nil
+}
+
+// buildMesh triangulates a group's faces and flattens them into vertex data. Faces are sorted into a submesh per
+// material, keeping the order materials first appear in, and share vertices across them
+func (parser *objParser) buildMesh(group *objGroup) (*MeshData, error) {
+	mesh := &MeshData{Name: group.name}
+
+	var materials []string
+	byMaterial := make(map[string][]objFace)
+	for _, face := range group.faces {
+		if _, ok := byMaterial[face.material]; !ok {
+			materials = append(materials, face.material)
+		}
+		byMaterial[face.material] = append(byMaterial[face.material], face)
+	}
+	faces := make([]objFace, 0, len(group.faces))
+	for _, material := range materials {
+		faces = append(faces, byMaterial[material]...)
+	}
+
+	// Work out face normals first, smooth normals need all of them before any vertex can be written
+	faceNormals := make([]math.Vector3f, len(faces))
+	smoothNormals := make(map[objSmoothKey]math.Vector3f)
+	for i, face := range faces {
+		points := make([]math.Vector3f, len(face.corners))
+		for c, corner := range face.corners {
+			points[c] = parser.positions[corner.position]
+		}
+		// The length is twice the face's area, so summing these unnormalized weights smooth normals by area
+		normal := polygonNormal(points)
+		faceNormals[i] = normal.Normalize()
+		if face.smoothing == 0 {
+			continue
+		}
+		for _, corner := range face.corners {
+			if corner.normal < 0 {
+				key := objSmoothKey{position: corner.position, smoothing: face.smoothing}
+				smoothNormals[key] = smoothNormals[key].Add(normal)
+			}
+		}
+	}
+
+	vertexMap := make(map[objVertexKey]uint32)
+	for i, face := range faces {
+		if i == 0 || face.material != faces[i-1].material {
+			mesh.Submeshes = append(mesh.Submeshes, Submesh{Start: len(mesh.Indices), MaterialName: face.material})
+		}
+
+		points := make([]math.Vector3f, len(face.corners))
+		for c, corner := range face.corners {
+			points[c] = parser.positions[corner.position]
+		}
+
+		for _, triangle := range triangulatePolygon(points, faceNormals[i]) {
+			for _, c := range triangle {
+				corner := face.corners[c]
+				key := objVertexKey{position: corner.position, texCoord: corner.texCoord, normal: corner.normal, face: -1}
+				if corner.normal < 0 {
+					if face.smoothing != 0 {
+						key.smoothing = face.smoothing
+					} else {
+						key.face = i
+					}
+				}
+
+				index, ok := vertexMap[key]
+				if !ok {
+					index = uint32(len(vertexMap))
+					vertexMap[key] = index
+
+					position := parser.positions[corner.position]
+					mesh.Positions = append(mesh.Positions, position.X, position.Y, position.Z)
+
+					var normal math.Vector3f
+					switch {
+					case corner.normal >= 0:
+						normal = parser.normals[corner.normal]
+					case face.smoothing != 0:
+						normal = smoothNormals[objSmoothKey{position: corner.position, smoothing: face.smoothing}].Normalize()
+					default:
+						normal = faceNormals[i]
+					}
+					mesh.Normals = append(mesh.Normals, normal.X, normal.Y, normal.Z)
+
+					var texCoord math.Vector2f
+					if corner.texCoord >= 0 {
+						texCoord = parser.texCoords[corner.texCoord]
+					}
+					mesh.TexCoords = append(mesh.TexCoords, texCoord.X, texCoord.Y)
+				}
+				mesh.Indices = append(mesh.Indices, index)
+			}
+		}
+		submesh := &mesh.Submeshes[len(mesh.Submeshes)-1]
+		submesh.Count = len(mesh.Indices) - submesh.Start
+	}
+
+	// Materials whose faces were all degenerate have nothing to draw
+	submeshes := mesh.Submeshes[:0]
+	for _, submesh := range mesh.Submeshes {
+		if submesh.Count > 0 {
+			submeshes = append(submeshes, submesh)
+		}
+	}
+	mesh.Submeshes = submeshes
+
+	if len(mesh.Indices) == 0 {
+		return nil, errors.New("Invalid File Format: Obj group has no triangles")
+	}
+	return mesh, nil
+}
+
+// polygonNormal returns the normal of a polygon using Newell's method, which works for concave and slightly non planar
+// polygons. It isn't normalized, it's length is twice the polygon's area
+func polygonNormal(points []math.Vector3f) math.Vector3f {
+	var normal math.Vector3f
+	for i, current := range points {
+		next := points[(i+1)%len(points)]
+		normal.X += (current.Y - next.Y) * (current.Z + next.Z)
+		normal.Y += (current.Z - next.Z) * (current.X + next.X)
+		normal.Z += (current.X - next.X) * (current.Y + next.Y)
+	}
+	return normal
+}
+
+// triangulatePolygon splits a polygon into triangles by ear clipping, returning indices into points in the polygon's
+// winding order. Convex and concave polygons both work, self intersecting ones fall back to a fan
+func triangulatePolygon(points []math.Vector3f, normal math.Vector3f) [][3]int {
+	if len(points) == 3 {
+		return [][3]int{{0, 1, 2}}
+	}
+
+	// Flatten onto the plane the normal faces most, which keeps the shape without needing a full projection
+	flat := make([]math.Vector2f, len(points))
+	x, y, z := math.Abs(normal.X), math.Abs(normal.Y), math.Abs(normal.Z)
+	for i, point := range points {
+		switch {
+		case x >= y && x >= z:
+			flat[i] = math.Vector2f{X: point.Y, Y: point.Z}
+		case y >= z:
+			flat[i] = math.Vector2f{X: point.Z, Y: point.X}
+		default:
+			flat[i] = math.Vector2f{X: point.X, Y: point.Y}
+		}
+	}
+
+	// Ears turn the same way as the polygon, whichever way that ended up after flattening
+	var area float32
+	for i, current := range flat {
+		area += current.Cross(flat[(i+1)%len(flat)])
+	}
+	winding := float32(1)
+	if area < 0 {
+		winding = -1
+	}
+
+	remaining := make([]int, len(points))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	triangles := make([][3]int, 0, len(points)-2)
+	for len(remaining) > 3 {
+		clipped := false
+		for i := range remaining {
+			previous := remaining[(i+len(remaining)-1)%len(remaining)]
+			current := remaining[i]
+			next := remaining[(i+1)%len(remaining)]
+			if !isEar(flat, remaining, previous, current, next, winding) {
+				continue
+			}
+			triangles = append(triangles, [3]int{previous, current, next})
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			clipped = true
+			break
+		}
+
+		// No ears means the polygon is degenerate or intersects itself. Fan what's left so it at least draws
+		if !clipped {
+			for i := 1; i < len(remaining)-1; i++ {
+				triangles = append(triangles, [3]int{remaining[0], remaining[i], remaining[i+1]})
+			}
+			return triangles
+		}
+	}
+	return append(triangles, [3]int{remaining[0], remaining[1], remaining[2]})
+}
+
+// isEar returns true if the corner at current can be clipped off: it's convex and no other corner is inside it
+func isEar(flat []math.Vector2f, remaining []int, previous int, current int, next int, winding float32) bool {
+	a, b, c := flat[previous], flat[current], flat[next]
+	if b.Sub(a).Cross(c.Sub(b))*winding <= 0 {
+		return false
+	}
+	for _, other := range remaining {
+		if other == previous || other == current || other == next {
+			continue
+		}
+		if pointInTriangle(flat[other], a, b, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// pointInTriangle returns true if point is inside or on the edge of triangle abc, whichever way it winds
+func pointInTriangle(point math.Vector2f, a math.Vector2f, b math.Vector2f, c math.Vector2f) bool {
+	d1 := b.Sub(a).Cross(point.Sub(a))
+	d2 := c.Sub(b).Cross(point.Sub(b))
+	d3 := a.Sub(c).Cross(point.Sub(c))
+	hasNegative := d1 < 0 || d2 < 0 || d3 < 0
+	hasPositive := d1 > 0 || d2 > 0 || d3 > 0
+	return !(hasNegative && hasPositive)
+}
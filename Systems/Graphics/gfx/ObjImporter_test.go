@@ -0,0 +1,230 @@
+package gfx
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/Surreal/Math/math"
+)
+
+// objTestSources are small files covering most of the format, used as seeds for the fuzz and mutation tests
+var objTestSources = []string{
+	"v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\nf 1 2 3 4\n",
+	"v 0 0 0\nv 2 0 0\nv 2 2 0\nv 1 1 0\nv 0 2 0\nvt 0 0\nvn 0 0 1\nf 1/1/1 2/1/1 3/1/1 4/1/1 5/1/1\n",
+	"mtllib scene.mtl\no box\nv 0 0 0\nv 1 0 0\nv 0 1 0\nusemtl red\nf -3 -2 -1\ns 1\nusemtl blue\nf 1//1 2//1 3//1\n",
+	"v 0 0 0 \\\n\nv 1 0 0\nv 0 1 0 # comment\ng a b\nf 1/1 2 3\n",
+}
+
+// checkObjMeshes fails the test if any mesh isn't valid MeshData with a normal and uv per vertex
+func checkObjMeshes(t *testing.T, source string, meshes []*MeshData) {
+	t.Helper()
+	for _, mesh := range meshes {
+		if err := mesh.Validate(); err != nil {
+			t.Fatalf("parsing %q gave an invalid mesh: %v", source, err)
+		}
+		if len(mesh.Normals) != len(mesh.Positions) || len(mesh.TexCoords) != mesh.VertexCount()*2 {
+			t.Fatalf("parsing %q gave %v normal and %v uv values for %v vertices", source, len(mesh.Normals), len(mesh.TexCoords), mesh.VertexCount())
+		}
+	}
+}
+
+// objTriangleArea returns the area of triangle t of mesh
+func objTriangleArea(mesh *MeshData, t int) float32 {
+	a, b, c := mesh.position(mesh.Indices[t]), mesh.position(mesh.Indices[t+1]), mesh.position(mesh.Indices[t+2])
+	return b.Sub(a).Cross(c.Sub(a)).Length() / 2
+}
+
+func TestParseObj(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		names     []string // The name of each mesh
+		vertices  []int    // Vertices in each mesh
+		triangles []int    // Triangles in each mesh
+	}{
+		{"triangle", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\n", []string{"default"}, []int{3}, []int{1}},
+		{"quad", "v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\nf 1 2 3 4\n", []string{"default"}, []int{4}, []int{2}},
+		{"negative indices", "v 9 9 9\nv 0 0 0\nv 1 0 0\nv 0 1 0\nf -3 -2 -1\n", []string{"default"}, []int{3}, []int{1}},
+		{"tabs and double spaces", "v\t0  0\t0\nv  1 0  0\nv 0\t\t1 0\nf\t1  2\t3\n", []string{"default"}, []int{3}, []int{1}},
+		{"f before g", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\ng named\nf 3 2 1\n", []string{"default", "named"}, []int{3, 3}, []int{1, 1}},
+		{"object and group", "o thing\nv 0 0 0\nv 1 0 0\nv 0 1 0\ng part\nf 1 2 3\n", []string{"thing/part"}, []int{3}, []int{1}},
+		{"position, uv and normal", "v 0 0 0\nv 1 0 0\nv 0 1 0\nvt 0 0\nvt 1 0\nvn 0 0 1\nf 1/1/1 2/2/1 3/1/1\n", []string{"default"}, []int{3}, []int{1}},
+		{"line continuation", "v 0 0 \\\n0\nv 1 0 0\nv 0 1 0\nf 1 2 \\\n3\n", []string{"default"}, []int{3}, []int{1}},
+		{"lines and points skipped", "v 0 0 0\nv 1 0 0\nv 0 1 0\nl 1 2\np 3\nf 1 2 3\n", []string{"default"}, []int{3}, []int{1}},
+	}
+	for _, test := range tests {
+		meshes, _, err := parseObjString(test.source)
+		if err != nil {
+			t.Errorf("%v: got error %v", test.name, err)
+			continue
+		}
+		checkObjMeshes(t, test.source, meshes)
+		if len(meshes) != len(test.names) {
+			t.Errorf("%v: got %v meshes, expected %v", test.name, len(meshes), len(test.names))
+			continue
+		}
+		for i, mesh := range meshes {
+			if mesh.Name != test.names[i] || mesh.VertexCount() != test.vertices[i] || len(mesh.Indices)/3 != test.triangles[i] {
+				t.Errorf("%v: got mesh %q with %v vertices and %v triangles, expected %q with %v and %v", test.name,
+					mesh.Name, mesh.VertexCount(), len(mesh.Indices)/3, test.names[i], test.vertices[i], test.triangles[i])
+			}
+		}
+	}
+}
+
+func TestParseObjErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"no faces", "v 0 0 0\nv 1 0 0\nv 0 1 0\n"},
+		{"zero index", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 0 1 2\n"},
+		{"index out of range", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 4\n"},
+		{"negative index out of range", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf -4 -2 -1\n"},
+		{"face before its vertices", "v 0 0 0\nf 1 2 3\nv 1 0 0\nv 0 1 0\n"},
+		{"two corners", "v 0 0 0\nv 1 0 0\nf 1 2\n"},
+		{"bad float", "v 0 zero 0\n"},
+		{"bad corner", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1/2/3/4 2 3\n"},
+		{"bad smoothing group", "s -1\n"},
+	}
+	for _, test := range tests {
+		if _, _, err := parseObjString(test.source); err == nil {
+			t.Errorf("%v: got no error, expected one", test.name)
+		}
+	}
+}
+
+func TestParseObjConcavePolygons(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		area   float32
+	}{
+		// An arrow head, convex everywhere but the notch at (1, 1)
+		{"arrow", "v 0 0 0\nv 2 1 0\nv 0 2 0\nv 1 1 0\nf 1 2 3 4\n", 1},
+		// An L with the reflex corner at (1, 1), wound clockwise
+		{"L clockwise", "v 0 0 0\nv 0 2 0\nv 1 2 0\nv 1 1 0\nv 2 1 0\nv 2 0 0\nf 1 2 3 4 5 6\n", 3},
+		// A U shape on the XZ plane, whose fan from the first corner would cover the gap
+		{"U on XZ", "v 0 0 0\nv 3 0 0\nv 3 0 3\nv 2 0 3\nv 2 0 1\nv 1 0 1\nv 1 0 3\nv 0 0 3\nf 1 2 3 4 5 6 7 8\n", 7},
+	}
+	for _, test := range tests {
+		meshes, _, err := parseObjString(test.source)
+		if err != nil {
+			t.Errorf("%v: got error %v", test.name, err)
+			continue
+		}
+		mesh := meshes[0]
+		if expected := mesh.VertexCount() - 2; len(mesh.Indices)/3 != expected {
+			t.Errorf("%v: got %v triangles, expected %v", test.name, len(mesh.Indices)/3, expected)
+		}
+		// Overlapping or flipped triangles would cover more than the polygon
+		var area float32
+		for i := 0; i < len(mesh.Indices); i += 3 {
+			area += objTriangleArea(mesh, i)
+		}
+		if !math.ApproxEqual(area, test.area, 1e-4) {
+			t.Errorf("%v: got triangles covering %v, expected %v", test.name, area, test.area)
+		}
+	}
+}
+
+func TestParseObjSmoothingGroups(t *testing.T) {
+	// Two triangles folded along the edge from (0, 0, 0) to (0, 1, 0)
+	const fold = "v 0 0 0\nv 0 1 0\nv 1 0 0\nv 0 0 1\n"
+	tests := []struct {
+		name     string
+		source   string
+		vertices int
+		shared   bool // If the fold's vertices share one normal between both triangles
+	}{
+		{"flat", fold + "s off\nf 1 3 2\nf 1 2 4\n", 6, false},
+		{"one group", fold + "s 1\nf 1 3 2\nf 1 2 4\n", 4, true},
+		{"different groups", fold + "s 1\nf 1 3 2\ns 2\nf 1 2 4\n", 6, false},
+		{"group then off", fold + "s 1\nf 1 3 2\ns 0\nf 1 2 4\n", 6, false},
+	}
+	for _, test := range tests {
+		meshes, _, err := parseObjString(test.source)
+		if err != nil {
+			t.Errorf("%v: got error %v", test.name, err)
+			continue
+		}
+		mesh := meshes[0]
+		checkObjMeshes(t, test.source, meshes)
+		if mesh.VertexCount() != test.vertices {
+			t.Errorf("%v: got %v vertices, expected %v", test.name, mesh.VertexCount(), test.vertices)
+		}
+
+		// The first corner of each triangle is the fold's (0, 0, 0)
+		first, second := mesh.Indices[0], mesh.Indices[3]
+		normal := func(index uint32) math.Vector3f {
+			return math.Vector3f{X: mesh.Normals[index*3], Y: mesh.Normals[index*3+1], Z: mesh.Normals[index*3+2]}
+		}
+		if shared := first == second; shared != test.shared {
+			t.Errorf("%v: got the fold shared %v, expected %v", test.name, shared, test.shared)
+		}
+		if test.shared {
+			// Halfway between the first triangle's +Z and the second's +X
+			expected := math.Vector3f{X: 1, Z: 1}.Normalize()
+			if got := normal(first); !got.ApproxEqual(expected, 1e-4) {
+				t.Errorf("%v: got the shared normal %v, expected the average %v", test.name, got, expected)
+			}
+		}
+	}
+}
+
+func TestParseObjMaterials(t *testing.T) {
+	source := "mtllib a.mtl\nmtllib b c.mtl\nv 0 0 0\nv 1 0 0\nv 0 1 0\nv 1 1 0\nusemtl red\nf 1 2 3\nusemtl blue\nf 2 4 3\nusemtl red\nf 3 2 1\n"
+	meshes, libraries, err := parseObjString(source)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if len(libraries) != 2 || libraries[0] != "a.mtl" || libraries[1] != "b c.mtl" {
+		t.Errorf("got libraries %q, expected [a.mtl b c.mtl]", libraries)
+	}
+	submeshes := meshes[0].Submeshes
+	if len(submeshes) != 2 || submeshes[0].MaterialName != "red" || submeshes[0].Count != 6 || submeshes[1].MaterialName != "blue" || submeshes[1].Count != 3 {
+		t.Errorf("got submeshes %+v, expected red with 6 indices then blue with 3", submeshes)
+	}
+}
+
+// mutateObj returns source with a few random bytes replaced, removed or duplicated, biased towards characters that mean
+// something in an obj file
+func mutateObj(random *rand.Rand, source string) string {
+	const interesting = "0123456789-./ \t\n\\#fvsgo"
+	data := []byte(source)
+	for edits := 1 + random.Intn(4); edits > 0 && len(data) > 0; edits-- {
+		at := random.Intn(len(data))
+		switch random.Intn(3) {
+		case 0:
+			data[at] = interesting[random.Intn(len(interesting))]
+		case 1:
+			data = append(data[:at], data[at+1:]...)
+		default:
+			data = append(data[:at], append([]byte{data[at]}, data[at:]...)...)
+		}
+	}
+	return string(data)
+}
+
+func TestParseObjMutations(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		source := mutateObj(random, objTestSources[i%len(objTestSources)])
+		meshes, _, err := parseObjString(source)
+		if err == nil {
+			checkObjMeshes(t, source, meshes)
+		}
+	}
+}
+
+func FuzzParseObj(f *testing.F) {
+	for _, source := range objTestSources {
+		f.Add(source)
+	}
+	f.Fuzz(func(t *testing.T, source string) {
+		meshes, _, err := parseObjString(source)
+		if err == nil {
+			checkObjMeshes(t, source, meshes)
+		}
+	})
+}
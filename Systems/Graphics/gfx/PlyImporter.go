@@ -0,0 +1,398 @@
+package gfx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	gomath "math"
+	"strconv"
+	"strings"
+
+	"github.com/Surreal/Math/math"
+)
+
+// plyTypes is the size in bytes of each ply scalar type, under both of it's names
+var plyTypes = map[string]int{
+	"char": 1, "int8": 1, "uchar": 1, "uint8": 1,
+	"short": 2, "int16": 2, "ushort": 2, "uint16": 2,
+	"int": 4, "int32": 4, "uint": 4, "uint32": 4,
+	"float": 4, "float32": 4, "double": 8, "float64": 8,
+}
+
+// plyColorScales is what integer color channels are divided by to get 0 to 1
+var plyColorScales = map[string]float64{
+	"char": 127, "int8": 127, "uchar": 255, "uint8": 255,
+	"short": 32767, "int16": 32767, "ushort": 65535, "uint16": 65535,
+	"int": 2147483647, "int32": 2147483647, "uint": 4294967295, "uint32": 4294967295,
+}
+
+// plyProperty is one property of a ply element. Lists have a countType, scalars don't
+type plyProperty struct {
+	name      string
+	valueType string
+	countType string
+}
+
+// plyElement is an element declared in a ply header, i.e. vertex or face
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+// plyReader reads the values of a ply file's body one after another
+type plyReader struct {
+	ascii  bool
+	order  binary.ByteOrder // Byte order of a binary body
+	data   []byte           // A binary body
+	offset int
+	fields []string // An ascii body split into values
+	field  int
+}
+
+// parsePly parses an ascii or binary ply file's vertices and faces into a mesh. Faces are triangulated and normals the
+// file doesn't have are made smooth. Any other elements are skipped
+func parsePly(fileData []byte) ([]*MeshData, error) {
+	elements, reader, err := readPlyHeader(fileData)
+	if err != nil {
+		return nil, err
+	}
+
+	var positions, normals, texCoords, colors []float32
+	var faces [][]uint32
+	for _, element := range elements {
+		switch element.name {
+		case "vertex":
+			positions, normals, texCoords, colors, err = readPlyVertices(reader, element)
+		case "face":
+			faces, err = readPlyFaces(reader, element)
+		default:
+			err = reader.skip(element)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(faces) == 0 {
+		return nil, errors.New("Invalid File Format: Ply file has no faces, point clouds can't be imported as meshes")
+	}
+
+	mesh, err := buildPlyMesh(positions, normals, faces)
+	if err != nil {
+		return nil, err
+	}
+	mesh.TexCoords = texCoords
+	mesh.Colors = colors
+	return []*MeshData{mesh}, nil
+}
+
+// readPlyHeader reads the elements declared in the header and returns a reader positioned at the start of the body
+func readPlyHeader(fileData []byte) ([]plyElement, *plyReader, error) {
+	end := bytes.Index(fileData, []byte("end_header"))
+	if !bytes.HasPrefix(fileData, []byte("ply")) || end < 0 {
+		return nil, nil, errors.New("Invalid File Format: Not a ply file")
+	}
+	// The body starts on the line after end_header
+	bodyStart := bytes.IndexByte(fileData[end:], '\n')
+	if bodyStart < 0 {
+		bodyStart = len(fileData)
+	} else {
+		bodyStart += end + 1
+	}
+
+	var elements []plyElement
+	reader := new(plyReader)
+	format := ""
+	for lineNumber, line := range strings.Split(string(fileData[:end]), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var err error
+		switch fields[0] {
+		case "format":
+			if len(fields) < 2 {
+				err = errors.New("Invalid File Format: Ply format is missing")
+				break
+			}
+			format = fields[1]
+		case "element":
+			if len(fields) < 3 {
+				err = errors.New("Invalid File Format: Ply element needs a name and count")
+				break
+			}
+			count, parseErr := strconv.Atoi(fields[2])
+			if parseErr != nil || count < 0 {
+				err = errors.New("Invalid File Format: Bad ply element count " + fields[2])
+				break
+			}
+			elements = append(elements, plyElement{name: fields[1], count: count})
+		case "property":
+			err = parsePlyProperty(fields[1:], elements)
+		}
+		// comment and obj_info lines don't matter
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v (ply line %v)", err, lineNumber+1)
+		}
+	}
+
+	body := fileData[bodyStart:]
+	switch format {
+	case "ascii":
+		reader.ascii = true
+		reader.fields = strings.Fields(string(body))
+	case "binary_little_endian":
+		reader.order, reader.data = binary.LittleEndian, body
+	case "binary_big_endian":
+		reader.order, reader.data = binary.BigEndian, body
+	default:
+		return nil, nil, errors.New("Unsupported File Format: Unknown ply format " + format)
+	}
+	return elements, reader, nil
+}
+
+// parsePlyProperty adds a property statement's property to the last element
+func parsePlyProperty(fields []string, elements []plyElement) error {
+	if len(elements) == 0 {
+		return errors.New("Invalid File Format: Ply property before any element")
+	}
+	property := plyProperty{}
+	if len(fields) == 4 && fields[0] == "list" {
+		property = plyProperty{countType: fields[1], valueType: fields[2], name: fields[3]}
+		if _, ok := plyTypes[property.countType]; !ok {
+			return errors.New("Invalid File Format: Unknown ply type " + property.countType)
+		}
+	} else if len(fields) == 2 {
+		property = plyProperty{valueType: fields[0], name: fields[1]}
+	} else {
+		return errors.New("Invalid File Format: Bad ply property")
+	}
+	if _, ok := plyTypes[property.valueType]; !ok {
+		return errors.New("Invalid File Format: Unknown ply type " + property.valueType)
+	}
+
+	element := &elements[len(elements)-1]
+	element.properties = append(element.properties, property)
+	return nil
+}
+
+// read reads the next value as a valueType
+func (reader *plyReader) read(valueType string) (float64, error) {
+	if reader.ascii {
+		if reader.field >= len(reader.fields) {
+			return 0, errors.New("Invalid File Format: Ply file ends early")
+		}
+		value, err := strconv.ParseFloat(reader.fields[reader.field], 64)
+		if err != nil {
+			return 0, errors.New("Invalid File Format: Bad ply value " + reader.fields[reader.field])
+		}
+		reader.field++
+		return value, nil
+	}
+
+	size := plyTypes[valueType]
+	if reader.offset+size > len(reader.data) {
+		return 0, errors.New("Invalid File Format: Ply file ends early")
+	}
+	at := reader.data[reader.offset:]
+	reader.offset += size
+	switch valueType {
+	case "char", "int8":
+		return float64(int8(at[0])), nil
+	case "uchar", "uint8":
+		return float64(at[0]), nil
+	case "short", "int16":
+		return float64(int16(reader.order.Uint16(at))), nil
+	case "ushort", "uint16":
+		return float64(reader.order.Uint16(at)), nil
+	case "int", "int32":
+		return float64(int32(reader.order.Uint32(at))), nil
+	case "uint", "uint32":
+		return float64(reader.order.Uint32(at)), nil
+	case "float", "float32":
+		return float64(gomath.Float32frombits(reader.order.Uint32(at))), nil
+	default:
+		return gomath.Float64frombits(reader.order.Uint64(at)), nil
+	}
+}
+
+// readList reads a list property's values
+func (reader *plyReader) readList(property plyProperty) ([]float64, error) {
+	count, err := reader.read(property.countType)
+	if err != nil {
+		return nil, err
+	}
+	if !(count >= 0 && count <= 1<<16) {
+		return nil, fmt.Errorf("Invalid File Format: Bad ply list length %v", count)
+	}
+	values := make([]float64, int(count))
+	for i := range values {
+		if values[i], err = reader.read(property.valueType); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// readElement reads one instance of an element. Scalars are returned by property index, lists are read and dropped
+// unless they're the list property named list, which is returned separately
+func (reader *plyReader) readElement(element plyElement, list string) ([]float64, []float64, error) {
+	values := make([]float64, len(element.properties))
+	var listValues []float64
+	for p, property := range element.properties {
+		var err error
+		if property.countType == "" {
+			values[p], err = reader.read(property.valueType)
+		} else if property.name == list {
+			listValues, err = reader.readList(property)
+		} else {
+			_, err = reader.readList(property)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return values, listValues, nil
+}
+
+// skip reads past every instance of an element
+func (reader *plyReader) skip(element plyElement) error {
+	// Nothing to read, and a huge count would take forever to loop over
+	if len(element.properties) == 0 {
+		return nil
+	}
+	for i := 0; i < element.count; i++ {
+		if _, _, err := reader.readElement(element, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPlyVertices reads positions along with the normals, uvs and rgba colors if the file has them, nil if it doesn't
+func readPlyVertices(reader *plyReader, element plyElement) ([]float32, []float32, []float32, []float32, error) {
+	find := func(names ...string) int {
+		for _, name := range names {
+			for p, property := range element.properties {
+				if property.name == name && property.countType == "" {
+					return p
+				}
+			}
+		}
+		return -1
+	}
+	position := [3]int{find("x"), find("y"), find("z")}
+	normal := [3]int{find("nx"), find("ny"), find("nz")}
+	texCoord := [2]int{find("u", "s", "texture_u", "texture_s"), find("v", "t", "texture_v", "texture_t")}
+	color := [4]int{find("red", "diffuse_red", "r"), find("green", "diffuse_green", "g"), find("blue", "diffuse_blue", "b"), find("alpha", "a")}
+	if position[0] < 0 || position[1] < 0 || position[2] < 0 {
+		return nil, nil, nil, nil, errors.New("Invalid File Format: Ply vertices have no x, y and z")
+	}
+	hasNormals := normal[0] >= 0 && normal[1] >= 0 && normal[2] >= 0
+	hasTexCoords := texCoord[0] >= 0 && texCoord[1] >= 0
+	hasColors := color[0] >= 0 && color[1] >= 0 && color[2] >= 0
+
+	// Integer colors are scaled to 0 to 1, float ones already are
+	var colorScales [4]float64
+	for c, property := range color {
+		colorScales[c] = 1
+		if property >= 0 {
+			if scale, ok := plyColorScales[element.properties[property].valueType]; ok {
+				colorScales[c] = scale
+			}
+		}
+	}
+
+	var positions, normals, texCoords, colors []float32
+	for i := 0; i < element.count; i++ {
+		values, _, err := reader.readElement(element, "")
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		for _, p := range position {
+			positions = append(positions, float32(values[p]))
+		}
+		if hasNormals {
+			for _, p := range normal {
+				normals = append(normals, float32(values[p]))
+			}
+		}
+		if hasTexCoords {
+			texCoords = append(texCoords, float32(values[texCoord[0]]), float32(values[texCoord[1]]))
+		}
+		if hasColors {
+			for c, p := range color {
+				value := float32(1)
+				if p >= 0 {
+					value = float32(values[p] / colorScales[c])
+				}
+				colors = append(colors, value)
+			}
+		}
+	}
+	return positions, normals, texCoords, colors, nil
+}
+
+// readPlyFaces reads every face's vertex indices
+func readPlyFaces(reader *plyReader, element plyElement) ([][]uint32, error) {
+	list := ""
+	for _, property := range element.properties {
+		if property.countType != "" && (property.name == "vertex_indices" || property.name == "vertex_index") {
+			list = property.name
+		}
+	}
+	if list == "" {
+		return nil, errors.New("Invalid File Format: Ply faces have no vertex_indices")
+	}
+
+	var faces [][]uint32
+	for i := 0; i < element.count; i++ {
+		_, listValues, err := reader.readElement(element, list)
+		if err != nil {
+			return nil, err
+		}
+		face := make([]uint32, len(listValues))
+		for c, value := range listValues {
+			if value < 0 {
+				return nil, fmt.Errorf("Invalid File Format: Negative ply vertex index %v", value)
+			}
+			face[c] = uint32(value)
+		}
+		faces = append(faces, face)
+	}
+	return faces, nil
+}
+
+// buildPlyMesh triangulates the faces. Without normals in the file, each vertex gets the area weighted average of the
+// faces around it since ply files are mostly scans of smooth surfaces
+func buildPlyMesh(positions []float32, normals []float32, faces [][]uint32) (*MeshData, error) {
+	mesh := &MeshData{Positions: positions, Normals: normals}
+	vertexCount := mesh.VertexCount()
+	for _, face := range faces {
+		if len(face) < 3 {
+			continue
+		}
+		points := make([]math.Vector3f, len(face))
+		for c, index := range face {
+			if int(index) >= vertexCount {
+				return nil, fmt.Errorf("Invalid File Format: Ply vertex index %v is out of range of %v vertices", index, vertexCount)
+			}
+			points[c] = mesh.position(index)
+		}
+
+		for _, triangle := range triangulatePolygon(points, polygonNormal(points).Normalize()) {
+			mesh.Indices = append(mesh.Indices, face[triangle[0]], face[triangle[1]], face[triangle[2]])
+		}
+	}
+	if len(mesh.Indices) == 0 {
+		return nil, errors.New("Invalid File Format: Ply file has no faces with 3 or more vertices")
+	}
+
+	if mesh.Normals == nil {
+		mesh.CalculateNormals()
+	}
+	return mesh, nil
+}
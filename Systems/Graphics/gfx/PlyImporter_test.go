@@ -0,0 +1,193 @@
+package gfx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Surreal/Math/math"
+)
+
+// plyTestSquare is a unit square on the XY plane colored red, green, blue and white, split into a triangle and a quad
+var plyTestSquare = struct {
+	positions [5][3]float32
+	colors    [5][3]float32
+	faces     [][]int32
+}{
+	positions: [5][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}, {0.5, 0, 0}},
+	colors:    [5][3]float32{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {1, 1, 1}, {1, 1, 1}},
+	faces:     [][]int32{{0, 4, 3}, {4, 1, 2, 3}},
+}
+
+// plyTestFile writes plyTestSquare in format, with colors stored as colorType
+func plyTestFile(format string, colorType string) []byte {
+	square := plyTestSquare
+	var body bytes.Buffer
+	var order binary.ByteOrder = binary.LittleEndian
+	if format == "binary_big_endian" {
+		order = binary.BigEndian
+	}
+	scale := float32(1)
+	if colorType == "uchar" {
+		scale = 255
+	}
+
+	for v, position := range square.positions {
+		if format == "ascii" {
+			fmt.Fprintf(&body, "%v %v %v", position[0], position[1], position[2])
+			for _, channel := range square.colors[v] {
+				fmt.Fprintf(&body, " %v", channel*scale)
+			}
+			body.WriteString("\n")
+			continue
+		}
+		binary.Write(&body, order, position)
+		for _, channel := range square.colors[v] {
+			if colorType == "uchar" {
+				body.WriteByte(byte(channel * scale))
+			} else {
+				binary.Write(&body, order, channel)
+			}
+		}
+	}
+	for _, face := range square.faces {
+		if format == "ascii" {
+			fmt.Fprintf(&body, "%v", len(face))
+			for _, index := range face {
+				fmt.Fprintf(&body, " %v", index)
+			}
+			body.WriteString("\n")
+			continue
+		}
+		body.WriteByte(byte(len(face)))
+		binary.Write(&body, order, face)
+	}
+
+	header := fmt.Sprintf(`ply
+format %v 1.0
+comment made for a test
+element vertex %v
+property float x
+property float y
+property float z
+property %v red
+property %v green
+property %v blue
+element face %v
+property list uchar int vertex_indices
+end_header
+`, format, len(square.positions), colorType, colorType, colorType, len(square.faces))
+	return append([]byte(header), body.Bytes()...)
+}
+
+func TestParsePly(t *testing.T) {
+	tests := []struct {
+		format    string
+		colorType string
+	}{
+		{"ascii", "uchar"},
+		{"ascii", "float"},
+		{"binary_little_endian", "uchar"},
+		{"binary_little_endian", "float"},
+		{"binary_big_endian", "uchar"},
+		{"binary_big_endian", "float"},
+	}
+	for _, test := range tests {
+		name := test.format + " with " + test.colorType + " colors"
+		meshes, err := parsePly(plyTestFile(test.format, test.colorType))
+		if err != nil {
+			t.Errorf("%v: got error %v", name, err)
+			continue
+		}
+		mesh := meshes[0]
+		if err := mesh.Validate(); err != nil {
+			t.Errorf("%v: got an invalid mesh: %v", name, err)
+			continue
+		}
+		if mesh.VertexCount() != 5 || len(mesh.Indices) != 9 {
+			t.Errorf("%v: got %v vertices and %v indices, expected 5 and 9", name, mesh.VertexCount(), len(mesh.Indices))
+			continue
+		}
+
+		for v, expected := range plyTestSquare.positions {
+			if got := mesh.position(uint32(v)); got != (math.Vector3f{X: expected[0], Y: expected[1], Z: expected[2]}) {
+				t.Errorf("%v: got vertex %v at %v, expected %v", name, v, got, expected)
+			}
+			color := plyTestSquare.colors[v]
+			expectedColor := math.Vector4f{X: color[0], Y: color[1], Z: color[2], W: 1}
+			if got := (math.Vector4f{X: mesh.Colors[v*4], Y: mesh.Colors[v*4+1], Z: mesh.Colors[v*4+2], W: mesh.Colors[v*4+3]}); got != expectedColor {
+				t.Errorf("%v: got vertex %v colored %v, expected %v", name, v, got, expectedColor)
+			}
+			// Flat, so the generated smooth normals all face +Z
+			if normal := (math.Vector3f{X: mesh.Normals[v*3], Y: mesh.Normals[v*3+1], Z: mesh.Normals[v*3+2]}); !normal.ApproxEqual(math.Vector3f{Z: 1}, 1e-5) {
+				t.Errorf("%v: got vertex %v's normal %v, expected +Z", name, v, normal)
+			}
+		}
+	}
+}
+
+func TestParsePlyNormalsAndTexCoords(t *testing.T) {
+	source := `ply
+format ascii 1.0
+element vertex 3
+property float x
+property float y
+property float z
+property float nx
+property float ny
+property float nz
+property float s
+property float t
+element face 1
+property list uchar uint vertex_index
+end_header
+0 0 0 0 1 0 0 0
+1 0 0 0 1 0 1 0
+0 1 0 0 1 0 0 1
+3 0 1 2
+`
+	meshes, err := parsePly([]byte(source))
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	mesh := meshes[0]
+	if err := mesh.Validate(); err != nil {
+		t.Fatalf("got an invalid mesh: %v", err)
+	}
+	// The file's normals are kept even though they don't match the face
+	if mesh.Normals[1] != 1 || mesh.Normals[4] != 1 || mesh.Normals[7] != 1 {
+		t.Errorf("got normals %v, expected the file's +Y", mesh.Normals)
+	}
+	if fmt.Sprint(mesh.TexCoords) != fmt.Sprint([]float32{0, 0, 1, 0, 0, 1}) {
+		t.Errorf("got uvs %v, expected [0 0 1 0 0 1]", mesh.TexCoords)
+	}
+	if mesh.Colors != nil {
+		t.Errorf("got colors %v, expected none", mesh.Colors)
+	}
+}
+
+func TestParsePlyErrors(t *testing.T) {
+	binaryFile := plyTestFile("binary_little_endian", "float")
+	ascii := string(plyTestFile("ascii", "uchar"))
+	tests := []struct {
+		name     string
+		data     []byte
+		contains string
+	}{
+		{"not a ply file", []byte("solid cube\n"), "Not a ply"},
+		{"unknown format", []byte(strings.Replace(ascii, "format ascii", "format binary_middle_endian", 1)), "Unknown ply format"},
+		{"truncated binary", binaryFile[:len(binaryFile)-3], ""},
+		{"truncated ascii", []byte(ascii[:len(ascii)-4]), ""},
+		{"index out of range", []byte(strings.Replace(ascii, "4 4 1 2 3", "4 4 1 2 9", 1)), "out of range"},
+		{"no faces", []byte(strings.Replace(ascii, "element face 2", "element face 0", 1)), "no faces"},
+		{"no positions", []byte(strings.Replace(ascii, "property float x", "property float w", 1)), "no x, y and z"},
+	}
+	for _, test := range tests {
+		_, err := parsePly(test.data)
+		if err == nil || !strings.Contains(err.Error(), test.contains) {
+			t.Errorf("%v: got error %v, expected one containing %q", test.name, err, test.contains)
+		}
+	}
+}
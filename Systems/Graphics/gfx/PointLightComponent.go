@@ -0,0 +1,54 @@
+package gfx
+
+import (
+	"github.com/Surreal/Math/math"
+	"github.com/Surreal/Systems/Core/core"
+)
+
+// PointLightComponent lights everything within Range of it's scene object's position equally in all directions
+type PointLightComponent struct {
+	*core.BaseComponent
+	Color     math.Vector3f // The color of the light
+	Intensity float32       // Multiplier applied to Color
+	Range     float32       // The distance at which the light has faded to nothing
+}
+
+// CreatePointLightComponent is the standard constructor for a PointLightComponent
+func CreatePointLightComponent(color math.Vector3f, intensity float32, lightRange float32) *PointLightComponent {
+	light := new(PointLightComponent)
+	light.BaseComponent = core.CreateBaseComponent(light)
+	light.Color = color
+	light.Intensity = intensity
+	light.Range = lightRange
+	return light
+}
+
+// LightType implements the Light interface
+func (light *PointLightComponent) LightType() LightType {
+	return PointLight
+}
+
+// ShadowSettings implements the Light interface. Point lights don't cast shadows
+func (light *PointLightComponent) ShadowSettings() *ShadowSettings {
+	return nil
+}
+
+// OnEnable implements the core.Enabler interface
+func (light *PointLightComponent) OnEnable() {
+	registerLight(light)
+}
+
+// OnDisable implements the core.Disabler interface
+func (light *PointLightComponent) OnDisable() {
+	unregisterLight(light)
+}
+
+func (light *PointLightComponent) lightInfo() lightInfo {
+	return lightInfo{
+		source:     light,
+		lightType:  PointLight,
+		position:   light.SceneObject().Transform.WorldPosition(),
+		color:      light.Color.Scale(light.Intensity),
+		lightRange: light.Range,
+	}
+}
@@ -0,0 +1,136 @@
+package gfx
+
+// postProcessShaders caches the compiled effect shaders by fragment source so every effect of a kind shares one program
+var postProcessShaders = make(map[string]*Shader)
+
+// Names of the built in effects
+const (
+	ToneMappingEffectName     = "ToneMapping"
+	GammaCorrectionEffectName = "GammaCorrection"
+	FXAAEffectName            = "FXAA"
+	BloomEffectName           = "Bloom"
+	VignetteEffectName        = "Vignette"
+	ColorGradingEffectName    = "ColorGrading"
+	DepthOfFieldEffectName    = "DepthOfField"
+)
+
+// PostProcessEffect is one full screen pass of a PostProcessStack. The material's shader is run over every pixel with
+// these parameters filled in by the stack if the shader declares them:
+//
+//	u_Source     sampler2D  The output of the previous effect, or the scene for the first
+//	u_Depth      sampler2D  The scene's depth buffer
+//	u_TexelSize  vec2       1 / the size in pixels of u_Source
+//	u_NearPlane  float      The camera's near plane
+//	u_FarPlane   float      The camera's far plane
+//
+// Everything else is set through the material like any other
+type PostProcessEffect struct {
+	Name     string    // Used to find the effect in a stack
+	Material *Material // The material drawn over the screen
+	Enabled  bool      // Disabled effects are skipped
+}
+
+// CreatePostProcessEffect is the standard constructor for a PostProcessEffect. fragmentSource is compiled against the
+// full screen triangle vertex shader, which passes the screen position to v_TexUV
+func CreatePostProcessEffect(name string, fragmentSource string) (*PostProcessEffect, error) {
+	shader, ok := postProcessShaders[fragmentSource]
+	if !ok {
+		var err error
+		shader, err = CreateShaderFromSource(fullScreenVertexShaderSource, fragmentSource)
+		if err != nil {
+			return nil, err
+		}
+		postProcessShaders[fragmentSource] = shader
+	}
+	return CreatePostProcessEffectWithMaterial(name, CreateMaterial(shader)), nil
+}
+
+// CreatePostProcessEffectWithMaterial makes an effect from an existing material
+func CreatePostProcessEffectWithMaterial(name string, material *Material) *PostProcessEffect {
+	effect := new(PostProcessEffect)
+	effect.Name = name
+	effect.Material = material
+	effect.Enabled = true
+	return effect
+}
+
+// SetParameter is a shortcut for Material.SetMaterialParameter
+func (effect *PostProcessEffect) SetParameter(name string, value interface{}) error {
+	return effect.Material.SetMaterialParameter(name, value)
+}
+
+// CreateToneMappingEffect maps HDR color to displayable values. Parameters: u_Exposure
+func CreateToneMappingEffect() (*PostProcessEffect, error) {
+	return createBuiltInEffect(ToneMappingEffectName, toneMappingFragmentShaderSource, map[string]interface{}{
+		"u_Exposure": float32(1),
+	})
+}
+
+// CreateGammaCorrectionEffect converts linear color for display. Parameters: u_Gamma
+func CreateGammaCorrectionEffect() (*PostProcessEffect, error) {
+	return createBuiltInEffect(GammaCorrectionEffectName, gammaCorrectionFragmentShaderSource, map[string]interface{}{
+		"u_Gamma": float32(2.2),
+	})
+}
+
+// CreateFXAAEffect smooths jagged edges. Parameters: u_SpanMax, u_ReduceMul, u_ReduceMin
+func CreateFXAAEffect() (*PostProcessEffect, error) {
+	return createBuiltInEffect(FXAAEffectName, fxaaFragmentShaderSource, map[string]interface{}{
+		"u_SpanMax":   float32(8),
+		"u_ReduceMul": float32(1.0 / 8.0),
+		"u_ReduceMin": float32(1.0 / 128.0),
+	})
+}
+
+// CreateBloomEffect makes bright areas glow. Parameters: u_Threshold, u_Intensity, u_Radius (in pixels)
+func CreateBloomEffect() (*PostProcessEffect, error) {
+	return createBuiltInEffect(BloomEffectName, bloomFragmentShaderSource, map[string]interface{}{
+		"u_Threshold": float32(1),
+		"u_Intensity": float32(0.8),
+		"u_Radius":    float32(12),
+	})
+}
+
+// CreateVignetteEffect darkens the edges of the screen. Parameters: u_VignetteColor, u_Intensity, u_Smoothness
+func CreateVignetteEffect() (*PostProcessEffect, error) {
+	return createBuiltInEffect(VignetteEffectName, vignetteFragmentShaderSource, map[string]interface{}{
+		"u_VignetteColor": []float32{0, 0, 0},
+		"u_Intensity":     float32(1),
+		"u_Smoothness":    float32(0.35),
+	})
+}
+
+// CreateColorGradingEffect remaps colors through lut, a strip of lutSize slices each lutSize pixels square, i.e.
+// 256x16 for a 16 point LUT. Parameters: u_Lut, u_LutSize, u_Contribution
+func CreateColorGradingEffect(lut *Texture, lutSize int) (*PostProcessEffect, error) {
+	effect, err := createBuiltInEffect(ColorGradingEffectName, colorGradingFragmentShaderSource, map[string]interface{}{
+		"u_LutSize":      float32(lutSize),
+		"u_Contribution": float32(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := effect.Material.SetTextureParameter("u_Lut", lut); err != nil {
+		return nil, err
+	}
+	return effect, nil
+}
+
+// CreateDepthOfFieldEffect blurs what is out of focus. Parameters: u_FocusDistance, u_FocusRange, u_MaxBlur (in pixels)
+func CreateDepthOfFieldEffect() (*PostProcessEffect, error) {
+	return createBuiltInEffect(DepthOfFieldEffectName, depthOfFieldFragmentShaderSource, map[string]interface{}{
+		"u_FocusDistance": float32(10),
+		"u_FocusRange":    float32(10),
+		"u_MaxBlur":       float32(8),
+	})
+}
+
+// createBuiltInEffect makes an effect and presets its parameters
+func createBuiltInEffect(name string, fragmentSource string, parameters map[string]interface{}) (*PostProcessEffect, error) {
+	effect, err := CreatePostProcessEffect(name, fragmentSource)
+	if err != nil {
+		return nil, err
+	}
+	effect.Material.SetMaterialParameters(parameters)
+	return effect, nil
+}
@@ -0,0 +1,267 @@
+package gfx
+
+// fullScreenVertexShaderSource draws a triangle that covers the whole screen from three verticies without any vertex
+// data, which avoids the seam and extra overdraw of a two triangle quad
+const fullScreenVertexShaderSource = `
+#version 150 core
+
+out vec2 v_TexUV;
+
+void main() {
+	vec2 position = vec2((gl_VertexID << 1) & 2, gl_VertexID & 2);
+	v_TexUV = position;
+	gl_Position = vec4(position * 2.0 - 1.0, 0.0, 1.0);
+}
+`
+
+// toneMappingFragmentShaderSource maps HDR color into [0, 1] with the ACES filmic curve fit by Krzysztof Narkowicz
+const toneMappingFragmentShaderSource = `
+#version 150 core
+
+in vec2 v_TexUV;
+
+uniform sampler2D u_Source;
+uniform float u_Exposure;
+
+out vec4 o_Color;
+
+void main() {
+	vec4 source = texture(u_Source, v_TexUV);
+	vec3 color = source.rgb * u_Exposure;
+	color = clamp((color * (2.51 * color + 0.03)) / (color * (2.43 * color + 0.59) + 0.14), 0.0, 1.0);
+	o_Color = vec4(color, source.a);
+}
+`
+
+// gammaCorrectionFragmentShaderSource converts linear color for display
+const gammaCorrectionFragmentShaderSource = `
+#version 150 core
+
+in vec2 v_TexUV;
+
+uniform sampler2D u_Source;
+uniform float u_Gamma;
+
+out vec4 o_Color;
+
+void main() {
+	vec4 source = texture(u_Source, v_TexUV);
+	o_Color = vec4(pow(max(source.rgb, vec3(0.0)), vec3(1.0 / u_Gamma)), source.a);
+}
+`
+
+// fxaaFragmentShaderSource is a compact version of Timothy Lottes' FXAA. It works best on gamma corrected color so
+// belongs after tone mapping and gamma correction
+const fxaaFragmentShaderSource = `
+#version 150 core
+
+in vec2 v_TexUV;
+
+uniform sampler2D u_Source;
+uniform vec2 u_TexelSize;
+uniform float u_SpanMax;
+uniform float u_ReduceMul;
+uniform float u_ReduceMin;
+
+out vec4 o_Color;
+
+float luma(vec3 color) {
+	return dot(color, vec3(0.299, 0.587, 0.114));
+}
+
+void main() {
+	float lumaNW = luma(texture(u_Source, v_TexUV + vec2(-1.0, -1.0) * u_TexelSize).rgb);
+	float lumaNE = luma(texture(u_Source, v_TexUV + vec2(1.0, -1.0) * u_TexelSize).rgb);
+	float lumaSW = luma(texture(u_Source, v_TexUV + vec2(-1.0, 1.0) * u_TexelSize).rgb);
+	float lumaSE = luma(texture(u_Source, v_TexUV + vec2(1.0, 1.0) * u_TexelSize).rgb);
+	vec4 center = texture(u_Source, v_TexUV);
+	float lumaM = luma(center.rgb);
+
+	float lumaMin = min(lumaM, min(min(lumaNW, lumaNE), min(lumaSW, lumaSE)));
+	float lumaMax = max(lumaM, max(max(lumaNW, lumaNE), max(lumaSW, lumaSE)));
+
+	// Blur along the edge, which runs perpendicular to the luma gradient
+	vec2 direction = vec2(-((lumaNW + lumaNE) - (lumaSW + lumaSE)), (lumaNW + lumaSW) - (lumaNE + lumaSE));
+	float reduce = max((lumaNW + lumaNE + lumaSW + lumaSE) * 0.25 * u_ReduceMul, u_ReduceMin);
+	float scale = 1.0 / (min(abs(direction.x), abs(direction.y)) + reduce);
+	direction = clamp(direction * scale, vec2(-u_SpanMax), vec2(u_SpanMax)) * u_TexelSize;
+
+	vec3 colorA = 0.5 * (
+		texture(u_Source, v_TexUV + direction * (1.0 / 3.0 - 0.5)).rgb +
+		texture(u_Source, v_TexUV + direction * (2.0 / 3.0 - 0.5)).rgb);
+	vec3 colorB = colorA * 0.5 + 0.25 * (
+		texture(u_Source, v_TexUV + direction * -0.5).rgb +
+		texture(u_Source, v_TexUV + direction * 0.5).rgb);
+
+	// If the wider sample strayed outside the local contrast range it crossed another edge, so use the narrow one
+	float lumaB = luma(colorB);
+	if (lumaB < lumaMin || lumaB > lumaMax) {
+		o_Color = vec4(colorA, center.a);
+	} else {
+		o_Color = vec4(colorB, center.a);
+	}
+}
+`
+
+// bloomFragmentShaderSource adds a glow around bright areas in a single pass. Pixels over the threshold are gathered
+// on rings around each pixel, so it belongs before tone mapping while the color is still HDR
+const bloomFragmentShaderSource = `
+#version 150 core
+
+#define RINGS 3
+#define SAMPLES_PER_RING 12
+
+in vec2 v_TexUV;
+
+uniform sampler2D u_Source;
+uniform vec2 u_TexelSize;
+uniform float u_Threshold;
+uniform float u_Intensity;
+uniform float u_Radius;
+
+out vec4 o_Color;
+
+vec3 bright(vec2 uv) {
+	vec3 color = texture(u_Source, uv).rgb;
+	float brightness = max(color.r, max(color.g, color.b));
+	float contribution = max(brightness - u_Threshold, 0.0) / max(brightness, 0.0001);
+	return color * contribution;
+}
+
+void main() {
+	vec4 source = texture(u_Source, v_TexUV);
+
+	vec3 glow = bright(v_TexUV);
+	float totalWeight = 1.0;
+	for (int ring = 1; ring <= RINGS; ring++) {
+		float distance = float(ring) / float(RINGS);
+		float weight = exp(-4.0 * distance * distance);
+		for (int i = 0; i < SAMPLES_PER_RING; i++) {
+			// Offset every other ring so the samples don't line up into streaks
+			float angle = (float(i) + 0.5 * float(ring & 1)) * 6.2831853 / float(SAMPLES_PER_RING);
+			vec2 offset = vec2(cos(angle), sin(angle)) * distance * u_Radius * u_TexelSize;
+			glow += bright(v_TexUV + offset) * weight;
+			totalWeight += weight;
+		}
+	}
+
+	o_Color = vec4(source.rgb + glow / totalWeight * u_Intensity, source.a);
+}
+`
+
+// vignetteFragmentShaderSource darkens the corners of the screen
+const vignetteFragmentShaderSource = `
+#version 150 core
+
+in vec2 v_TexUV;
+
+uniform sampler2D u_Source;
+uniform vec2 u_TexelSize;
+uniform vec3 u_VignetteColor;
+uniform float u_Intensity;
+uniform float u_Smoothness;
+
+out vec4 o_Color;
+
+void main() {
+	vec4 source = texture(u_Source, v_TexUV);
+
+	// Keep the vignette round whatever the aspect ratio
+	vec2 offset = v_TexUV - 0.5;
+	offset.x *= u_TexelSize.y / u_TexelSize.x;
+	float distance = length(offset);
+
+	float amount = smoothstep(0.5, 0.5 - max(u_Smoothness, 0.0001), distance * u_Intensity);
+	o_Color = vec4(mix(u_VignetteColor, source.rgb, amount), source.a);
+}
+`
+
+// colorGradingFragmentShaderSource looks the color up in a LUT stored as a strip of u_LutSize square slices laid out
+// left to right with blue increasing, the layout most image editors export
+const colorGradingFragmentShaderSource = `
+#version 150 core
+
+in vec2 v_TexUV;
+
+uniform sampler2D u_Source;
+uniform sampler2D u_Lut;
+uniform float u_LutSize;
+uniform float u_Contribution;
+
+out vec4 o_Color;
+
+vec3 lookup(vec3 color) {
+	float maxIndex = u_LutSize - 1.0;
+	float blue = color.b * maxIndex;
+	float sliceA = floor(blue);
+	float sliceB = min(sliceA + 1.0, maxIndex);
+
+	// Sample texel centers so neighbouring slices don't bleed into each other
+	vec2 inSlice = (color.rg * maxIndex + 0.5) / vec2(u_LutSize * u_LutSize, u_LutSize);
+	vec3 a = texture(u_Lut, inSlice + vec2(sliceA / u_LutSize, 0.0)).rgb;
+	vec3 b = texture(u_Lut, inSlice + vec2(sliceB / u_LutSize, 0.0)).rgb;
+	return mix(a, b, blue - sliceA);
+}
+
+void main() {
+	vec4 source = texture(u_Source, v_TexUV);
+	vec3 color = clamp(source.rgb, 0.0, 1.0);
+	o_Color = vec4(mix(color, lookup(color), u_Contribution), source.a);
+}
+`
+
+// depthOfFieldFragmentShaderSource blurs pixels by how far they are from the focus distance using a disc of samples.
+// Samples nearer the camera than the pixel are allowed to bleed over it so foreground blur spreads past edges
+const depthOfFieldFragmentShaderSource = `
+#version 150 core
+
+#define SAMPLES 24
+
+in vec2 v_TexUV;
+
+uniform sampler2D u_Source;
+uniform sampler2D u_Depth;
+uniform vec2 u_TexelSize;
+uniform float u_NearPlane;
+uniform float u_FarPlane;
+uniform float u_FocusDistance;
+uniform float u_FocusRange;
+uniform float u_MaxBlur;
+
+out vec4 o_Color;
+
+float linearDepth(vec2 uv) {
+	float depth = texture(u_Depth, uv).r * 2.0 - 1.0;
+	return 2.0 * u_NearPlane * u_FarPlane / (u_FarPlane + u_NearPlane - depth * (u_FarPlane - u_NearPlane));
+}
+
+float circleOfConfusion(float depth) {
+	return clamp(abs(depth - u_FocusDistance) / max(u_FocusRange, 0.0001), 0.0, 1.0) * u_MaxBlur;
+}
+
+void main() {
+	vec4 source = texture(u_Source, v_TexUV);
+	float depth = linearDepth(v_TexUV);
+	float radius = circleOfConfusion(depth);
+
+	vec3 color = source.rgb;
+	float totalWeight = 1.0;
+	for (int i = 0; i < SAMPLES; i++) {
+		// Golden angle spiral gives an even disc without a pattern
+		float t = (float(i) + 0.5) / float(SAMPLES);
+		float angle = float(i) * 2.3999632;
+		vec2 offset = vec2(cos(angle), sin(angle)) * sqrt(t) * u_MaxBlur * u_TexelSize;
+		vec2 uv = v_TexUV + offset;
+
+		float sampleDepth = linearDepth(uv);
+		float sampleRadius = circleOfConfusion(sampleDepth);
+		float reach = sampleDepth < depth ? sampleRadius : min(sampleRadius, radius);
+		float weight = clamp(reach - sqrt(t) * u_MaxBlur + 1.0, 0.0, 1.0);
+
+		color += texture(u_Source, uv).rgb * weight;
+		totalWeight += weight;
+	}
+
+	o_Color = vec4(color / totalWeight, source.a);
+}
+`
@@ -0,0 +1,233 @@
+package gfx
+
+import (
+	"github.com/Surreal/Debug/dbg"
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// fullScreenTriangle is an empty vertex array. The full screen vertex shader builds the triangle from gl_VertexID but
+// core profiles still need something bound to draw
+var fullScreenTriangle *VertexArray
+
+// fullScreenTriangleArray returns the vertex array to bind when drawing a full screen triangle
+func fullScreenTriangleArray() *VertexArray {
+	if fullScreenTriangle == nil {
+		fullScreenTriangle = CreateVertexArray()
+	}
+	return fullScreenTriangle
+}
+
+// PostProcessStack runs an ordered list of full screen effects over what a camera renders. The scene is drawn into the
+// stack's own color and depth buffers, then each enabled effect reads the previous one's output and writes into the
+// next of two ping pong buffers, with the last effect writing to the camera's real target.
+//
+// A typical order is depth of field and bloom while the color is still HDR, then tone mapping, color grading, gamma
+// correction, vignette and finally FXAA
+type PostProcessStack struct {
+	Effects []*PostProcessEffect // Run in order
+	Samples int32                // MSAA samples for the scene buffer. Changing it recreates the buffer on the next SceneTarget call
+
+	scene    *RenderTarget    // HDR color and sampleable depth the camera renders into
+	pingPong [2]*RenderTarget // Intermediate results between effects
+}
+
+// CreatePostProcessStack is the standard constructor for a PostProcessStack
+func CreatePostProcessStack(effects ...*PostProcessEffect) *PostProcessStack {
+	stack := new(PostProcessStack)
+	stack.Effects = append(stack.Effects, effects...)
+	return stack
+}
+
+// Add appends effects to the end of the stack
+func (stack *PostProcessStack) Add(effects ...*PostProcessEffect) {
+	stack.Effects = append(stack.Effects, effects...)
+}
+
+// Effect returns the first effect called name, or nil if there isn't one
+func (stack *PostProcessStack) Effect(name string) *PostProcessEffect {
+	for _, effect := range stack.Effects {
+		if effect.Name == name {
+			return effect
+		}
+	}
+	return nil
+}
+
+// SetEnabled turns every effect called name on or off. Returns false if there were none
+func (stack *PostProcessStack) SetEnabled(name string, enabled bool) bool {
+	found := false
+	for _, effect := range stack.Effects {
+		if effect.Name == name {
+			effect.Enabled = enabled
+			found = true
+		}
+	}
+	return found
+}
+
+// Active returns true if any effect is enabled. An inactive stack is skipped and the camera renders directly
+func (stack *PostProcessStack) Active() bool {
+	for _, effect := range stack.Effects {
+		if effect.Enabled && effect.Material != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// SceneTarget returns the render target the camera draws into before the effects run, sized to width by height
+func (stack *PostProcessStack) SceneTarget(width int32, height int32) (*RenderTarget, error) {
+	if stack.scene != nil && stack.scene.Description.Samples != stack.Samples {
+		stack.scene.Delete()
+		stack.scene = nil
+	}
+	if stack.scene == nil {
+		target, err := CreateRenderTarget(RenderTargetDescription{
+			Width:        width,
+			Height:       height,
+			Samples:      stack.Samples,
+			ColorFormats: []AttachmentFormat{ColorFormatRGBA16F},
+			DepthFormat:  DepthFormat24,
+			SampleDepth:  true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		stack.scene = target
+	}
+	if err := stack.scene.Resize(width, height); err != nil {
+		return nil, err
+	}
+	return stack.scene, nil
+}
+
+// Apply runs the enabled effects over the scene target and writes the result into output, or the window if nil. The
+// scene target must already be resolved, which Renderer.Flush does when it finishes drawing into it
+func (stack *PostProcessStack) Apply(output *RenderTarget, camera *CameraComponent) error {
+	if stack.scene == nil {
+		return nil
+	}
+
+	var effects []*PostProcessEffect
+	for _, effect := range stack.Effects {
+		if effect.Enabled && effect.Material != nil {
+			effects = append(effects, effect)
+		}
+	}
+	if len(effects) == 0 {
+		return nil
+	}
+
+	width, height := stack.scene.Width(), stack.scene.Height()
+	for i := range stack.pingPong {
+		if err := stack.ensurePingPong(i, width, height); err != nil {
+			return err
+		}
+	}
+
+	// Full screen passes overwrite every pixel so depth only gets in the way
+	depthTest := gl.IsEnabled(gl.DEPTH_TEST)
+	gl.Disable(gl.DEPTH_TEST)
+	defer func() {
+		if depthTest {
+			gl.Enable(gl.DEPTH_TEST)
+		}
+	}()
+
+	source := stack.scene.ColorTexture(0)
+	for i, effect := range effects {
+		last := i == len(effects)-1
+		destination := stack.pingPong[i%2]
+		if last {
+			destination = output
+		}
+
+		if destination != nil {
+			if err := destination.Bind(); err != nil {
+				return err
+			}
+		} else if CurrentlyBoundRenderTarget != nil {
+			CurrentlyBoundRenderTarget.UnBind()
+		}
+
+		if err := stack.drawEffect(effect, source, width, height, camera); err != nil {
+			dbg.LogError(err.Error())
+		}
+		if destination != nil {
+			source = destination.ColorTexture(0)
+		}
+	}
+
+	if CurrentlyBoundRenderTarget != nil {
+		CurrentlyBoundRenderTarget.UnBind()
+	}
+	if output != nil {
+		output.Resolve()
+	}
+	return nil
+}
+
+// Delete frees the stack's render targets. The effects' materials are left alone
+func (stack *PostProcessStack) Delete() {
+	if stack.scene != nil {
+		stack.scene.Delete()
+		stack.scene = nil
+	}
+	for i, target := range stack.pingPong {
+		if target != nil {
+			target.Delete()
+			stack.pingPong[i] = nil
+		}
+	}
+}
+
+// drawEffect draws one effect over the bound target with source as u_Source
+func (stack *PostProcessStack) drawEffect(effect *PostProcessEffect, source *Texture, width int32, height int32, camera *CameraComponent) error {
+	material := effect.Material
+	shader := material.MaterialShader
+	material.Bind()
+
+	if param, ok := shader.TextureParameters["u_Source"]; ok {
+		source.BindToSlot(param.Slot)
+	}
+	if param, ok := shader.TextureParameters["u_Depth"]; ok && stack.scene.DepthTexture != nil {
+		stack.scene.DepthTexture.BindToSlot(param.Slot)
+	}
+	if _, ok := shader.Parameters["u_TexelSize"]; ok {
+		shader.SendParameterValue("u_TexelSize", []float32{1 / float32(width), 1 / float32(height)})
+	}
+	if camera != nil {
+		if _, ok := shader.Parameters["u_NearPlane"]; ok {
+			shader.SendParameterValue("u_NearPlane", camera.NearPlane)
+		}
+		if _, ok := shader.Parameters["u_FarPlane"]; ok {
+			shader.SendParameterValue("u_FarPlane", camera.FarPlane)
+		}
+	}
+
+	triangle := fullScreenTriangleArray()
+	if err := triangle.Bind(); err != nil {
+		return err
+	}
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	triangle.UnBind()
+	material.UnBind()
+	return nil
+}
+
+// ensurePingPong creates or resizes one of the intermediate buffers
+func (stack *PostProcessStack) ensurePingPong(index int, width int32, height int32) error {
+	if stack.pingPong[index] == nil {
+		target, err := CreateRenderTarget(RenderTargetDescription{
+			Width:        width,
+			Height:       height,
+			ColorFormats: []AttachmentFormat{ColorFormatRGBA16F},
+		})
+		if err != nil {
+			return err
+		}
+		stack.pingPong[index] = target
+		return nil
+	}
+	return stack.pingPong[index].Resize(width, height)
+}
@@ -0,0 +1,218 @@
+package gfx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	gomath "math"
+	"strings"
+)
+
+// radianceMaxSize is the widest or tallest Radiance image that will be decoded. Larger is either bogus or too big for
+// a texture anyway
+const radianceMaxSize = 1 << 15
+
+func init() {
+	image.RegisterFormat("hdr", "#?", DecodeRadiance, DecodeRadianceConfig)
+}
+
+// RGBFloatImage is an image of linear, unclamped float32 RGB pixels, i.e. HDR images
+type RGBFloatImage struct {
+	Pix    []float32       // R, G, B for each pixel, top row first
+	Stride int             // Number of floats between vertically adjacent pixels
+	Rect   image.Rectangle // The image bounds
+}
+
+// NewRGBFloatImage returns a black RGBFloatImage with the given bounds
+func NewRGBFloatImage(rect image.Rectangle) *RGBFloatImage {
+	return &RGBFloatImage{
+		Pix:    make([]float32, 3*rect.Dx()*rect.Dy()),
+		Stride: 3 * rect.Dx(),
+		Rect:   rect,
+	}
+}
+
+// ColorModel implements image.Image. Colors are clamped to [0, 1] on the way out
+func (img *RGBFloatImage) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+// Bounds implements image.Image
+func (img *RGBFloatImage) Bounds() image.Rectangle {
+	return img.Rect
+}
+
+// At implements image.Image
+func (img *RGBFloatImage) At(x, y int) color.Color {
+	if !(image.Point{X: x, Y: y}).In(img.Rect) {
+		return color.RGBA64{}
+	}
+	i := img.PixOffset(x, y)
+	toChannel := func(value float32) uint16 {
+		if value <= 0 {
+			return 0
+		}
+		if value >= 1 {
+			return 0xFFFF
+		}
+		return uint16(value * 0xFFFF)
+	}
+	return color.RGBA64{R: toChannel(img.Pix[i]), G: toChannel(img.Pix[i+1]), B: toChannel(img.Pix[i+2]), A: 0xFFFF}
+}
+
+// PixOffset returns the index of the first element of Pix for the pixel at (x, y)
+func (img *RGBFloatImage) PixOffset(x, y int) int {
+	return (y-img.Rect.Min.Y)*img.Stride + (x-img.Rect.Min.X)*3
+}
+
+// DecodeRadiance reads a Radiance RGBE (.hdr) image into an *RGBFloatImage
+func DecodeRadiance(r io.Reader) (image.Image, error) {
+	reader := bufio.NewReader(r)
+	width, height, flipY, err := readRadianceHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Grow the pixels a row at a time so a header claiming a huge image can't allocate more than the data holds
+	img := &RGBFloatImage{Stride: 3 * width, Rect: image.Rect(0, 0, width, height)}
+	img.Pix = make([]float32, 0, 3*width)
+	blankRow := make([]float32, 3*width)
+	scanline := make([]byte, 4*width)
+	for y := 0; y < height; y++ {
+		if err := readRadianceScanline(reader, scanline); err != nil {
+			return nil, err
+		}
+
+		img.Pix = append(img.Pix, blankRow...)
+		pix := img.Pix[y*img.Stride:]
+		for x := 0; x < width; x++ {
+			rgbe := scanline[x*4 : x*4+4]
+			if rgbe[3] == 0 {
+				continue
+			}
+			scale := float32(gomath.Ldexp(1, int(rgbe[3])-(128+8)))
+			pix[x*3] = (float32(rgbe[0]) + 0.5) * scale
+			pix[x*3+1] = (float32(rgbe[1]) + 0.5) * scale
+			pix[x*3+2] = (float32(rgbe[2]) + 0.5) * scale
+		}
+	}
+
+	if flipY {
+		for top, bottom := 0, height-1; top < bottom; top, bottom = top+1, bottom-1 {
+			copy(blankRow, img.Pix[top*img.Stride:(top+1)*img.Stride])
+			copy(img.Pix[top*img.Stride:], img.Pix[bottom*img.Stride:(bottom+1)*img.Stride])
+			copy(img.Pix[bottom*img.Stride:], blankRow)
+		}
+	}
+	return img, nil
+}
+
+// DecodeRadianceConfig returns the size of a Radiance image without decoding the pixels
+func DecodeRadianceConfig(r io.Reader) (image.Config, error) {
+	width, height, _, err := readRadianceHeader(bufio.NewReader(r))
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{ColorModel: color.RGBA64Model, Width: width, Height: height}, nil
+}
+
+// readRadianceHeader reads up to the start of the pixel data. flipY is true if the rows are stored bottom first
+func readRadianceHeader(reader *bufio.Reader) (width int, height int, flipY bool, err error) {
+	magic, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(magic, "#?") {
+		return 0, 0, false, errors.New("Invalid Radiance File: Missing #? signature")
+	}
+
+	// Header lines run until a blank line
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return 0, 0, false, errors.New("Invalid Radiance File: Header ended unexpectedly")
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "FORMAT=") && line != "FORMAT=32-bit_rle_rgbe" {
+			return 0, 0, false, fmt.Errorf("Unsupported Radiance File: Format %v, only 32-bit_rle_rgbe is supported", line[len("FORMAT="):])
+		}
+	}
+
+	resolution, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, 0, false, errors.New("Invalid Radiance File: Missing resolution")
+	}
+	var yAxis, xAxis string
+	if _, err := fmt.Sscanf(resolution, "%s %d %s %d", &yAxis, &height, &xAxis, &width); err != nil {
+		return 0, 0, false, errors.New("Invalid Radiance File: Malformed resolution")
+	}
+	if xAxis != "+X" || (yAxis != "-Y" && yAxis != "+Y") {
+		return 0, 0, false, fmt.Errorf("Unsupported Radiance File: Orientation %v %v", yAxis, xAxis)
+	}
+	if width <= 0 || height <= 0 {
+		return 0, 0, false, errors.New("Invalid Radiance File: Image has no pixels")
+	}
+	if width > radianceMaxSize || height > radianceMaxSize {
+		return 0, 0, false, fmt.Errorf("Unsupported Radiance File: %vx%v is larger than %v pixels across", width, height, radianceMaxSize)
+	}
+	return width, height, yAxis == "+Y", nil
+}
+
+// readRadianceScanline reads one row of RGBE pixels into scanline, which is 4 * width long
+func readRadianceScanline(reader *bufio.Reader, scanline []byte) error {
+	width := len(scanline) / 4
+
+	// New style run length encoded rows start with 2, 2 then the width. Anything else is stored flat
+	start, err := reader.Peek(4)
+	if err != nil {
+		return errors.New("Invalid Radiance File: Pixel data ended unexpectedly")
+	}
+	if width < 8 || width > 0x7FFF || start[0] != 2 || start[1] != 2 || start[2]&0x80 != 0 {
+		if _, err := io.ReadFull(reader, scanline); err != nil {
+			return errors.New("Invalid Radiance File: Pixel data ended unexpectedly")
+		}
+		return nil
+	}
+	if int(start[2])<<8|int(start[3]) != width {
+		return errors.New("Invalid Radiance File: Scanline width doesn't match the image")
+	}
+	reader.Discard(4)
+
+	// Each channel is encoded separately as runs and literal spans
+	for channel := 0; channel < 4; channel++ {
+		for x := 0; x < width; {
+			count, err := reader.ReadByte()
+			if err != nil {
+				return errors.New("Invalid Radiance File: Pixel data ended unexpectedly")
+			}
+			if count > 128 {
+				run := int(count - 128)
+				value, err := reader.ReadByte()
+				if err != nil || x+run > width {
+					return errors.New("Invalid Radiance File: Bad run length")
+				}
+				for ; run > 0; run-- {
+					scanline[x*4+channel] = value
+					x++
+				}
+			} else {
+				literal := int(count)
+				if literal == 0 || x+literal > width {
+					return errors.New("Invalid Radiance File: Bad run length")
+				}
+				for ; literal > 0; literal-- {
+					value, err := reader.ReadByte()
+					if err != nil {
+						return errors.New("Invalid Radiance File: Pixel data ended unexpectedly")
+					}
+					scanline[x*4+channel] = value
+					x++
+				}
+			}
+		}
+	}
+	return nil
+}
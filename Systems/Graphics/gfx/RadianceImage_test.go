@@ -0,0 +1,60 @@
+package gfx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// radianceFile builds a flat (not run length encoded) Radiance file from a resolution line and pixel bytes
+func radianceFile(resolution string, pixels ...byte) []byte {
+	return append([]byte("#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n"+resolution+"\n"), pixels...)
+}
+
+func TestDecodeRadiance(t *testing.T) {
+	// 2x2, top row red then green, bottom row blue then black. Exponent 129 makes a mantissa of 128 roughly 1
+	pixels := []byte{128, 0, 0, 129, 0, 128, 0, 129, 0, 0, 128, 129, 0, 0, 0, 0}
+	tests := []struct {
+		name       string
+		resolution string
+		topLeft    [3]bool // Which channels of the top left pixel are lit
+	}{
+		{"top down", "-Y 2 +X 2", [3]bool{true, false, false}},
+		{"bottom up", "+Y 2 +X 2", [3]bool{false, false, true}},
+	}
+	for _, test := range tests {
+		img, err := DecodeRadiance(bytes.NewReader(radianceFile(test.resolution, pixels...)))
+		if err != nil {
+			t.Errorf("%v: got error %v", test.name, err)
+			continue
+		}
+		hdr := img.(*RGBFloatImage)
+		for c, lit := range test.topLeft {
+			if (hdr.Pix[c] > 0.9) != lit {
+				t.Errorf("%v: got top left %v, expected channels lit %v", test.name, hdr.Pix[:3], test.topLeft)
+				break
+			}
+		}
+		if black := hdr.Pix[hdr.PixOffset(1, 1)]; test.name == "top down" && black != 0 {
+			t.Errorf("%v: got bottom right red %v, expected 0", test.name, black)
+		}
+	}
+}
+
+func TestDecodeRadianceRejectsBadSizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		contains string
+	}{
+		{"too wide", radianceFile("-Y 1 +X 100000"), "larger"},
+		{"huge but truncated", radianceFile("-Y 30000 +X 30000", 1, 2, 3, 4), "ended unexpectedly"},
+		{"no pixels", radianceFile("-Y 0 +X 4"), "no pixels"},
+	}
+	for _, test := range tests {
+		_, err := DecodeRadiance(bytes.NewReader(test.data))
+		if err == nil || !strings.Contains(err.Error(), test.contains) {
+			t.Errorf("%v: got error %v, expected one containing %q", test.name, err, test.contains)
+		}
+	}
+}
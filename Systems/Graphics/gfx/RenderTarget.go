@@ -0,0 +1,389 @@
+package gfx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// MaxColorAttachments is the most color attachments a RenderTarget may have. GL 3.2 guarantees at least 8
+const MaxColorAttachments int = 8
+
+// CurrentlyBoundRenderTarget tracks the currently bound render target. nil is the window's framebuffer
+var CurrentlyBoundRenderTarget *RenderTarget
+
+// AttachmentFormat describes the storage of one render target attachment
+type AttachmentFormat struct {
+	InternalFormat uint32 // The sized GPU format, i.e. gl.RGBA8. 0 means no attachment
+	Format         uint32 // The pixel format of the data, i.e. gl.RGBA
+	DataType       uint32 // The type of each component of the data, i.e. gl.UNSIGNED_BYTE
+}
+
+// Common attachment formats
+var (
+	NoAttachment             = AttachmentFormat{}
+	ColorFormatRGBA8         = AttachmentFormat{gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE}
+	ColorFormatRGBA16F       = AttachmentFormat{gl.RGBA16F, gl.RGBA, gl.HALF_FLOAT}
+	ColorFormatRGBA32F       = AttachmentFormat{gl.RGBA32F, gl.RGBA, gl.FLOAT}
+	DepthFormat24            = AttachmentFormat{gl.DEPTH_COMPONENT24, gl.DEPTH_COMPONENT, gl.FLOAT}
+	DepthFormat32F           = AttachmentFormat{gl.DEPTH_COMPONENT32F, gl.DEPTH_COMPONENT, gl.FLOAT}
+	DepthStencilFormat24And8 = AttachmentFormat{gl.DEPTH24_STENCIL8, gl.DEPTH_STENCIL, gl.UNSIGNED_INT_24_8}
+)
+
+// RenderTargetDescription is everything needed to create a RenderTarget
+type RenderTargetDescription struct {
+	Width        int32              // Width in pixels
+	Height       int32              // Height in pixels
+	Samples      int32              // MSAA samples per pixel. 0 for a regular target
+	ColorFormats []AttachmentFormat // One entry per color attachment, in attachment order
+	DepthFormat  AttachmentFormat   // Depth or depth/stencil format, NoAttachment for none
+	SampleDepth  bool               // Store depth in a texture that can be sampled rather than a renderbuffer
+	DepthCompare bool               // Turn on depth comparison for the depth texture so it works with sampler2DShadow
+	FilterMode   int                // Min and mag filter for the color textures. Defaults to gl.LINEAR
+}
+
+// RenderTarget is an offscreen framebuffer that can be drawn into and then sampled from like any other texture.
+// Multisampled targets render into renderbuffers and must be resolved before their textures hold anything
+type RenderTarget struct {
+	FramebufferID uint32                  // The openGL framebuffer
+	Description   RenderTargetDescription // What this target was created with. Width and Height follow Resize
+	ColorTextures []*Texture              // The color attachments. For multisampled targets these are the resolved results
+	DepthTexture  *Texture                // The depth attachment if SampleDepth was set, the resolved depth if multisampled
+
+	colorRenderbuffers []uint32      // Multisampled color storage
+	depthRenderbuffer  uint32        // Depth storage when it isn't sampled or the target is multisampled
+	resolveTarget      *RenderTarget // The single sampled target multisampled targets resolve into
+	previousViewport   [4]int32      // The viewport to restore on UnBind
+}
+
+// CreateRenderTarget is the standard constructor for a RenderTarget
+func CreateRenderTarget(description RenderTargetDescription) (*RenderTarget, error) {
+	if len(description.ColorFormats) > MaxColorAttachments {
+		return nil, fmt.Errorf("Invalid Render Target: Cannot have more than %v color attachments", MaxColorAttachments)
+	}
+	if description.FilterMode == 0 {
+		description.FilterMode = gl.LINEAR
+	}
+
+	target := new(RenderTarget)
+	target.Description = description
+	target.Description.ColorFormats = append([]AttachmentFormat(nil), description.ColorFormats...)
+	gl.GenFramebuffers(1, &target.FramebufferID)
+
+	if err := target.allocate(); err != nil {
+		target.Delete()
+		return nil, err
+	}
+	return target, nil
+}
+
+// Width returns the width in pixels
+func (target *RenderTarget) Width() int32 {
+	return target.Description.Width
+}
+
+// Height returns the height in pixels
+func (target *RenderTarget) Height() int32 {
+	return target.Description.Height
+}
+
+// IsMultisampled returns true if this target needs Resolve before its textures can be used
+func (target *RenderTarget) IsMultisampled() bool {
+	return target.Description.Samples > 0
+}
+
+// ColorTexture returns the texture for color attachment index, ready to set on a TextureShaderParameter
+func (target *RenderTarget) ColorTexture(index int) *Texture {
+	if index < 0 || index >= len(target.ColorTextures) {
+		return nil
+	}
+	return target.ColorTextures[index]
+}
+
+// Bind makes this the target of all drawing and sets the viewport to cover it
+func (target *RenderTarget) Bind() error {
+	if target.FramebufferID <= 0 {
+		return errors.New("Attempted to bind render target that has been deleted")
+	}
+	if CurrentlyBoundRenderTarget == target {
+		return nil
+	}
+	if CurrentlyBoundRenderTarget == nil {
+		gl.GetIntegerv(gl.VIEWPORT, &target.previousViewport[0])
+	} else {
+		target.previousViewport = CurrentlyBoundRenderTarget.previousViewport
+	}
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target.FramebufferID)
+	gl.Viewport(0, 0, target.Description.Width, target.Description.Height)
+	CurrentlyBoundRenderTarget = target
+	return nil
+}
+
+// UnBind goes back to drawing to the window and restores the viewport from before Bind
+func (target *RenderTarget) UnBind() {
+	if CurrentlyBoundRenderTarget != target {
+		return
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Viewport(target.previousViewport[0], target.previousViewport[1], target.previousViewport[2], target.previousViewport[3])
+	CurrentlyBoundRenderTarget = nil
+}
+
+// Clear clears the target's color attachments to the current clear color and/or its depth and stencil
+func (target *RenderTarget) Clear(color bool, depth bool) {
+	previous := CurrentlyBoundRenderTarget
+	target.Bind()
+
+	var mask uint32
+	if color && len(target.Description.ColorFormats) > 0 {
+		mask |= gl.COLOR_BUFFER_BIT
+	}
+	if depth && target.Description.DepthFormat.InternalFormat != 0 {
+		mask |= gl.DEPTH_BUFFER_BIT
+		if target.Description.DepthFormat.Format == gl.DEPTH_STENCIL {
+			mask |= gl.STENCIL_BUFFER_BIT
+		}
+	}
+	if mask != 0 {
+		gl.Clear(mask)
+	}
+
+	if previous != nil {
+		previous.Bind()
+	} else {
+		target.UnBind()
+	}
+}
+
+// Resize reallocates every attachment at the new size. The contents are lost
+func (target *RenderTarget) Resize(width int32, height int32) error {
+	if width == target.Description.Width && height == target.Description.Height {
+		return nil
+	}
+	target.Description.Width = width
+	target.Description.Height = height
+	return target.allocate()
+}
+
+// Resolve copies a multisampled target's samples into its textures. Does nothing for regular targets
+func (target *RenderTarget) Resolve() {
+	if target.resolveTarget == nil {
+		return
+	}
+	width, height := target.Description.Width, target.Description.Height
+
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, target.FramebufferID)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, target.resolveTarget.FramebufferID)
+	for i := range target.colorRenderbuffers {
+		attachment := uint32(gl.COLOR_ATTACHMENT0 + i)
+		gl.ReadBuffer(attachment)
+		gl.DrawBuffer(attachment)
+		gl.BlitFramebuffer(0, 0, width, height, 0, 0, width, height, gl.COLOR_BUFFER_BIT, gl.NEAREST)
+	}
+	if target.resolveTarget.DepthTexture != nil {
+		gl.BlitFramebuffer(0, 0, width, height, 0, 0, width, height, gl.DEPTH_BUFFER_BIT, gl.NEAREST)
+	}
+
+	// Put the draw buffers back the way they were and restore whatever was bound
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target.FramebufferID)
+	target.setDrawBuffers()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target.resolveTarget.FramebufferID)
+	target.resolveTarget.setDrawBuffers()
+	if CurrentlyBoundRenderTarget != nil {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, CurrentlyBoundRenderTarget.FramebufferID)
+	} else {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	}
+}
+
+// CheckComplete returns an error describing why the framebuffer can't be drawn to, or nil if it can
+func (target *RenderTarget) CheckComplete() error {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target.FramebufferID)
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	if CurrentlyBoundRenderTarget != nil {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, CurrentlyBoundRenderTarget.FramebufferID)
+	} else {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	}
+
+	switch status {
+	case gl.FRAMEBUFFER_COMPLETE:
+		return nil
+	case gl.FRAMEBUFFER_INCOMPLETE_ATTACHMENT:
+		return errors.New("Incomplete Framebuffer: An attachment is incomplete")
+	case gl.FRAMEBUFFER_INCOMPLETE_MISSING_ATTACHMENT:
+		return errors.New("Incomplete Framebuffer: No attachments")
+	case gl.FRAMEBUFFER_INCOMPLETE_DRAW_BUFFER:
+		return errors.New("Incomplete Framebuffer: A draw buffer has no attachment")
+	case gl.FRAMEBUFFER_INCOMPLETE_READ_BUFFER:
+		return errors.New("Incomplete Framebuffer: The read buffer has no attachment")
+	case gl.FRAMEBUFFER_UNSUPPORTED:
+		return errors.New("Incomplete Framebuffer: This combination of formats is not supported")
+	case gl.FRAMEBUFFER_INCOMPLETE_MULTISAMPLE:
+		return errors.New("Incomplete Framebuffer: Attachments have different sample counts")
+	default:
+		return fmt.Errorf("Incomplete Framebuffer: Unknown status 0x%X", status)
+	}
+}
+
+// Delete frees the GPU resources used by the render target
+func (target *RenderTarget) Delete() {
+	if CurrentlyBoundRenderTarget == target {
+		target.UnBind()
+	}
+	target.releaseAttachments()
+	if target.FramebufferID > 0 {
+		gl.DeleteFramebuffers(1, &target.FramebufferID)
+		target.FramebufferID = 0
+	}
+}
+
+// allocate (re)creates every attachment at the current size and attaches them to the framebuffer
+func (target *RenderTarget) allocate() error {
+	description := &target.Description
+	if description.Width <= 0 || description.Height <= 0 {
+		return errors.New("Invalid Size: Render targets must be at least 1x1")
+	}
+	target.releaseAttachments()
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target.FramebufferID)
+
+	if target.IsMultisampled() {
+		// Renderbuffers can be multisampled without needing sampler2DMS in every shader. The textures live on a
+		// second, regular target that we blit into on Resolve
+		for i, format := range description.ColorFormats {
+			target.colorRenderbuffers = append(target.colorRenderbuffers, createRenderbuffer(format, description.Width, description.Height, description.Samples))
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, uint32(gl.COLOR_ATTACHMENT0+i), gl.RENDERBUFFER, target.colorRenderbuffers[i])
+		}
+		if description.DepthFormat.InternalFormat != 0 {
+			target.depthRenderbuffer = createRenderbuffer(description.DepthFormat, description.Width, description.Height, description.Samples)
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, depthAttachmentPoint(description.DepthFormat), gl.RENDERBUFFER, target.depthRenderbuffer)
+		}
+	} else {
+		for i, format := range description.ColorFormats {
+			texture := createAttachmentTexture(format, description.Width, description.Height, description.FilterMode, false)
+			target.ColorTextures = append(target.ColorTextures, texture)
+			gl.FramebufferTexture2D(gl.FRAMEBUFFER, uint32(gl.COLOR_ATTACHMENT0+i), gl.TEXTURE_2D, texture.ID, 0)
+		}
+		if description.DepthFormat.InternalFormat != 0 {
+			if description.SampleDepth {
+				target.DepthTexture = createAttachmentTexture(description.DepthFormat, description.Width, description.Height, gl.LINEAR, description.DepthCompare)
+				gl.FramebufferTexture2D(gl.FRAMEBUFFER, depthAttachmentPoint(description.DepthFormat), gl.TEXTURE_2D, target.DepthTexture.ID, 0)
+			} else {
+				target.depthRenderbuffer = createRenderbuffer(description.DepthFormat, description.Width, description.Height, 0)
+				gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, depthAttachmentPoint(description.DepthFormat), gl.RENDERBUFFER, target.depthRenderbuffer)
+			}
+		}
+	}
+	target.setDrawBuffers()
+
+	if CurrentlyBoundRenderTarget != nil {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, CurrentlyBoundRenderTarget.FramebufferID)
+	} else {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	}
+	if err := target.CheckComplete(); err != nil {
+		return err
+	}
+
+	if target.IsMultisampled() {
+		resolveDescription := *description
+		resolveDescription.Samples = 0
+		resolve, err := CreateRenderTarget(resolveDescription)
+		if err != nil {
+			return err
+		}
+		target.resolveTarget = resolve
+		target.ColorTextures = resolve.ColorTextures
+		target.DepthTexture = resolve.DepthTexture
+	}
+	return nil
+}
+
+// setDrawBuffers points the fragment outputs at the color attachments. Must be called with the framebuffer bound
+func (target *RenderTarget) setDrawBuffers() {
+	count := len(target.Description.ColorFormats)
+	if count == 0 {
+		gl.DrawBuffer(gl.NONE)
+		gl.ReadBuffer(gl.NONE)
+		return
+	}
+
+	var buffers [MaxColorAttachments]uint32
+	for i := 0; i < count; i++ {
+		buffers[i] = uint32(gl.COLOR_ATTACHMENT0 + i)
+	}
+	gl.DrawBuffers(int32(count), &buffers[0])
+	gl.ReadBuffer(gl.COLOR_ATTACHMENT0)
+}
+
+// releaseAttachments deletes every texture and renderbuffer attached to the target
+func (target *RenderTarget) releaseAttachments() {
+	if target.resolveTarget != nil {
+		target.resolveTarget.Delete()
+		target.resolveTarget = nil
+	} else {
+		for _, texture := range target.ColorTextures {
+			texture.Delete()
+		}
+		target.DepthTexture.Delete()
+	}
+	target.ColorTextures = nil
+	target.DepthTexture = nil
+
+	if len(target.colorRenderbuffers) > 0 {
+		gl.DeleteRenderbuffers(int32(len(target.colorRenderbuffers)), &target.colorRenderbuffers[0])
+		target.colorRenderbuffers = nil
+	}
+	if target.depthRenderbuffer > 0 {
+		gl.DeleteRenderbuffers(1, &target.depthRenderbuffer)
+		target.depthRenderbuffer = 0
+	}
+}
+
+// createAttachmentTexture makes an empty texture suitable for attaching to a framebuffer
+func createAttachmentTexture(format AttachmentFormat, width int32, height int32, filterMode int, compare bool) *Texture {
+	texture := new(Texture)
+	texture.Generate()
+	texture.SetHorizontalWrapMode(gl.CLAMP_TO_EDGE)
+	texture.SetVerticalWrapMode(gl.CLAMP_TO_EDGE)
+	texture.SetMinFilterMode(filterMode)
+	texture.SetMagFilterMode(filterMode)
+	texture.Width = width
+	texture.Height = height
+	texture.IsLoaded = true
+
+	texture.BindToSlot(gl.TEXTURE0)
+	defer texture.UnBindFromSlot(gl.TEXTURE0)
+	if compare {
+		// With compare mode on, LINEAR filtering gives a free 2x2 PCF on top of whatever the shader does
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_COMPARE_MODE, gl.COMPARE_REF_TO_TEXTURE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_COMPARE_FUNC, gl.LEQUAL)
+	}
+	gl.TexImage2D(gl.TEXTURE_2D, 0, int32(format.InternalFormat), width, height, 0, format.Format, format.DataType, nil)
+	return texture
+}
+
+// createRenderbuffer makes renderbuffer storage, multisampled if samples > 0
+func createRenderbuffer(format AttachmentFormat, width int32, height int32, samples int32) uint32 {
+	var id uint32
+	gl.GenRenderbuffers(1, &id)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, id)
+	if samples > 0 {
+		gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, samples, format.InternalFormat, width, height)
+	} else {
+		gl.RenderbufferStorage(gl.RENDERBUFFER, format.InternalFormat, width, height)
+	}
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	return id
+}
+
+// depthAttachmentPoint returns where a depth format attaches to a framebuffer
+func depthAttachmentPoint(format AttachmentFormat) uint32 {
+	if format.Format == gl.DEPTH_STENCIL {
+		return gl.DEPTH_STENCIL_ATTACHMENT
+	}
+	return gl.DEPTH_ATTACHMENT
+}
@@ -0,0 +1,336 @@
+package gfx
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/Surreal/Debug/dbg"
+	"github.com/Surreal/Math/math"
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// MainRenderer is the global renderer that mesh renderers submit to during scene traversal
+var MainRenderer = CreateRenderer()
+
+// DrawCommand is a request to draw a mesh with a material at some location in the world
+type DrawCommand struct {
+	Mesh     *Mesh
+	Material *Material
+	Model    math.Matrix4x4 // The model to world matrix
+	SortKey  uint64         // Commands are drawn in ascending order. Leave as 0 to have one generated by Submit
+}
+
+// RenderStats records how much work the renderer did since the last ResetStats
+type RenderStats struct {
+	DrawCalls        int // Number of glDraw* calls
+	Triangles        int // Number of triangles submitted
+	ShaderBinds      int // Number of times the active shader program changed
+	MaterialBinds    int // Number of times a material's parameters were sent
+	TextureBinds     int // Number of times a texture was bound to a slot
+	VertexArrayBinds int // Number of times the active vertex array changed
+	ShadowPasses     int // Number of shadow maps or cascades rendered
+}
+
+// Renderer collects draw commands and draws them in an order that minimises GL state changes
+type Renderer struct {
+	commands   []DrawCommand
+	stats      RenderStats
+	shadowMaps map[Light]*ShadowMap // Shadow maps of the lights casting shadows
+	shadows    shadowState          // The shadows rendered during the current flush
+
+	warnedShadowCasters bool // Set once the renderer has logged about lights whose shadows it can't draw
+
+	// Scratch space for uploading lights, kept around to avoid allocating every draw
+	lightScratch    []scoredLight
+	lights          [MaxLightsPerObject]lightInfo
+	lightTypes      []int32
+	lightPositions  []float32
+	lightDirections []float32
+	lightColors     []float32
+	lightRanges     []float32
+	lightCones      []float32
+}
+
+// CreateRenderer is the standard constructor for a Renderer
+func CreateRenderer() *Renderer {
+	renderer := new(Renderer)
+	renderer.commands = make([]DrawCommand, 0, 256)
+	renderer.shadowMaps = make(map[Light]*ShadowMap)
+	renderer.lightTypes = make([]int32, MaxLightsPerObject)
+	renderer.lightPositions = make([]float32, 3*MaxLightsPerObject)
+	renderer.lightDirections = make([]float32, 3*MaxLightsPerObject)
+	renderer.lightColors = make([]float32, 3*MaxLightsPerObject)
+	renderer.lightRanges = make([]float32, MaxLightsPerObject)
+	renderer.lightCones = make([]float32, 2*MaxLightsPerObject)
+	return renderer
+}
+
+// Submit queues a draw command to be drawn on the next Flush. Commands for meshes that can't be drawn are rejected
+func (renderer *Renderer) Submit(command DrawCommand) error {
+	if command.Mesh == nil || command.Material == nil || command.Material.MaterialShader == nil {
+		return errors.New("Invalid Draw Command: A draw command needs a mesh and a material with a shader")
+	}
+	mesh := command.Mesh
+	if mesh.deleted || mesh.Verticies == nil || mesh.Verticies.ID == 0 || mesh.VertexIndicies == nil || mesh.VertexIndicies.ID == 0 {
+		return errors.New("Invalid Draw Command: The mesh has been deleted or it's vertex or index array hasn't been made")
+	}
+	if command.SortKey == 0 {
+		command.SortKey = MakeSortKey(command.Mesh, command.Material)
+	}
+	renderer.commands = append(renderer.commands, command)
+	return nil
+}
+
+// Flush draws every queued command from the point of view of MainCamera, sorted by SortKey, then clears the queue.
+// If the camera has a Target the commands are drawn into it instead of the window, and a skybox on the camera is drawn
+// behind everything
+func (renderer *Renderer) Flush() error {
+	defer func() { renderer.commands = renderer.commands[:0] }()
+
+	var target *RenderTarget
+	var sky *SkyboxComponent
+	if MainCamera != nil {
+		target = MainCamera.Target
+		sky = MainCamera.Skybox()
+	}
+	if len(renderer.commands) == 0 && sky == nil {
+		if target != nil {
+			target.Clear(true, true)
+		}
+		return nil
+	}
+
+	// With post processing the scene goes into the stack's buffers first and the effects write to the real target
+	output := target
+	var postProcess *PostProcessStack
+	if MainCamera != nil && MainCamera.PostProcess != nil && MainCamera.PostProcess.Active() {
+		postProcess = MainCamera.PostProcess
+		width, height := renderer.outputSize(output)
+		sceneTarget, err := postProcess.SceneTarget(width, height)
+		if err != nil {
+			dbg.LogError(err.Error())
+			postProcess = nil
+		} else {
+			target = sceneTarget
+		}
+	}
+
+	sort.SliceStable(renderer.commands, func(i, j int) bool {
+		return renderer.commands[i].SortKey < renderer.commands[j].SortKey
+	})
+
+	renderer.renderShadows()
+
+	if target != nil {
+		if err := target.Bind(); err != nil {
+			return err
+		}
+		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+		defer func() {
+			target.UnBind()
+			target.Resolve()
+			if postProcess != nil {
+				if err := postProcess.Apply(output, MainCamera); err != nil {
+					dbg.LogError(err.Error())
+				}
+			}
+		}()
+	}
+
+	var lastShader *Shader
+	var lastMaterial *Material
+	for i := range renderer.commands {
+		command := &renderer.commands[i]
+		shader := command.Material.MaterialShader
+
+		if command.Material != lastMaterial {
+			if shader != CurrentlyBoundShader {
+				renderer.stats.ShaderBinds++
+			}
+			renderer.countTextureBinds(command.Material)
+			command.Material.Bind()
+			renderer.stats.MaterialBinds++
+			lastMaterial = command.Material
+		}
+
+		// The camera and shadows only need sending once per shader per flush
+		if shader != lastShader {
+			renderer.sendCameraParameters(shader)
+			renderer.sendShadowParameters(shader)
+			renderer.sendEnvironmentParameters(shader, sky)
+			lastShader = shader
+		}
+
+		if _, ok := shader.Parameters["u_Model"]; ok {
+			if err := shader.SendParameterValue("u_Model", &command.Model); err != nil {
+				return err
+			}
+		}
+		if _, ok := shader.Parameters["u_NormalMatrix"]; ok {
+			// A singular model matrix has squashed the object flat, so any normal will do
+			normalMatrix, _ := command.Model.NormalMatrix()
+			if err := shader.SendParameterValue("u_NormalMatrix", &normalMatrix); err != nil {
+				return err
+			}
+		}
+		if _, ok := shader.Parameters["u_LightCount"]; ok {
+			renderer.sendLights(shader, command.Mesh.Bounds.Transform(&command.Model))
+		}
+
+		if command.Mesh.Verticies != CurrentlyBoundVertexArray {
+			renderer.stats.VertexArrayBinds++
+		}
+		if err := command.Mesh.Verticies.Bind(); err != nil {
+			return err
+		}
+		if err := command.Mesh.VertexIndicies.Bind(); err != nil {
+			return err
+		}
+
+		gl.DrawElements(gl.TRIANGLES, int32(command.Mesh.VertexIndicies.Count), uint32(gl.UNSIGNED_INT), gl.PtrOffset(0))
+		renderer.stats.DrawCalls++
+		renderer.stats.Triangles += command.Mesh.VertexIndicies.Count / 3
+	}
+
+	// The sky goes last so the depth test skips every pixel already covered
+	if sky != nil {
+		if err := renderer.drawSkybox(sky); err != nil {
+			dbg.LogError(err.Error())
+		}
+	}
+
+	if CurrentlyBoundVertexArray != nil {
+		CurrentlyBoundVertexArray.UnBind()
+	}
+	if lastMaterial != nil {
+		lastMaterial.UnBind()
+	}
+	if CurrentlyBoundShader != nil {
+		CurrentlyBoundShader.UnBind()
+	}
+	return nil
+}
+
+// outputSize returns the size of target, or of the viewport when drawing to the window
+func (renderer *Renderer) outputSize(target *RenderTarget) (int32, int32) {
+	if target != nil {
+		return target.Width(), target.Height()
+	}
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	return viewport[2], viewport[3]
+}
+
+// Stats returns the work done since the last call to ResetStats
+func (renderer *Renderer) Stats() RenderStats {
+	return renderer.stats
+}
+
+// ResetStats zeroes the stats. Call this once at the start of each frame for per frame stats
+func (renderer *Renderer) ResetStats() {
+	renderer.stats = RenderStats{}
+}
+
+// PendingCount returns the number of commands waiting to be flushed
+func (renderer *Renderer) PendingCount() int {
+	return len(renderer.commands)
+}
+
+// sendCameraParameters sends the main camera's matrices and the ambient light to shader if it uses them
+func (renderer *Renderer) sendCameraParameters(shader *Shader) {
+	if _, ok := shader.Parameters["u_AmbientColor"]; ok {
+		shader.SendParameterValue("u_AmbientColor", []float32{AmbientLightColor.X, AmbientLightColor.Y, AmbientLightColor.Z})
+	}
+
+	if MainCamera == nil || MainCamera.SceneObject() == nil {
+		return
+	}
+	if _, ok := shader.Parameters["u_CameraPosition"]; ok {
+		position := MainCamera.SceneObject().Transform.WorldPosition()
+		shader.SendParameterValue("u_CameraPosition", []float32{position.X, position.Y, position.Z})
+	}
+	if _, ok := shader.Parameters["u_View"]; ok {
+		shader.SendParameterValue("u_View", MainCamera.ViewMatrix())
+	}
+	if _, ok := shader.Parameters["u_Projection"]; ok {
+		shader.SendParameterValue("u_Projection", &MainCamera.ActiveProjectionMatrix)
+	}
+}
+
+// sendLights sends the lights nearest to bounds to shader's standard light uniforms
+func (renderer *Renderer) sendLights(shader *Shader, bounds math.BoundingSphere) {
+	count := nearestLights(bounds, &renderer.lightScratch, renderer.lights[:])
+	for i := 0; i < count; i++ {
+		light := &renderer.lights[i]
+		renderer.lightTypes[i] = int32(light.lightType)
+		renderer.lightPositions[i*3], renderer.lightPositions[i*3+1], renderer.lightPositions[i*3+2] = light.position.X, light.position.Y, light.position.Z
+		renderer.lightDirections[i*3], renderer.lightDirections[i*3+1], renderer.lightDirections[i*3+2] = light.direction.X, light.direction.Y, light.direction.Z
+		renderer.lightColors[i*3], renderer.lightColors[i*3+1], renderer.lightColors[i*3+2] = light.color.X, light.color.Y, light.color.Z
+		renderer.lightRanges[i] = light.lightRange
+		renderer.lightCones[i*2], renderer.lightCones[i*2+1] = light.cosInner, light.cosOuter
+	}
+
+	directionalShadow, spotShadow := renderer.shadowIndices(count)
+
+	// The compiler strips uniforms the shader doesn't use, so only send what's there
+	uniforms := []struct {
+		name  string
+		value interface{}
+	}{
+		{"u_LightCount", int32(count)},
+		{"u_DirectionalShadowIndex", directionalShadow},
+		{"u_SpotShadowIndex", spotShadow},
+		{"u_LightType", renderer.lightTypes},
+		{"u_LightPosition", renderer.lightPositions},
+		{"u_LightDirection", renderer.lightDirections},
+		{"u_LightColor", renderer.lightColors},
+		{"u_LightRange", renderer.lightRanges},
+		{"u_LightCone", renderer.lightCones},
+	}
+	for _, uniform := range uniforms {
+		if _, ok := shader.Parameters[uniform.name]; ok {
+			if err := shader.SendParameterValue(uniform.name, uniform.value); err != nil {
+				dbg.LogError(err.Error())
+			}
+		}
+	}
+}
+
+// countTextureBinds counts the textures that binding material will actually change
+func (renderer *Renderer) countTextureBinds(material *Material) {
+	for name, texture := range material.ShaderTextures {
+		param, ok := material.MaterialShader.TextureParameters[name]
+		if ok && CurrentlyBoundTextures[param.Slot-gl.TEXTURE0] != texture {
+			renderer.stats.TextureBinds++
+		}
+	}
+}
+
+// MakeSortKey builds a key that groups draws by shader, then texture, then material, then mesh. Texture comes before
+// material since binding textures costs more than setting uniforms, and materials often share textures. Each is packed
+// into 16 bits so ids past 65535 may share a bucket, which only costs some extra state changes
+func MakeSortKey(mesh *Mesh, material *Material) uint64 {
+	var shaderID, textureID, meshID uint64
+	if material.MaterialShader != nil {
+		shaderID = uint64(material.MaterialShader.ProgramID)
+
+		// Use the texture in the lowest slot as the representative texture. Slots set to nil are unbound so skip them
+		lowestSlot := ^uint32(0)
+		for name, texture := range material.ShaderTextures {
+			if texture == nil {
+				continue
+			}
+			param, ok := material.MaterialShader.TextureParameters[name]
+			if ok && param.Slot < lowestSlot {
+				lowestSlot = param.Slot
+				textureID = uint64(texture.ID)
+			}
+		}
+	}
+
+	if mesh.Verticies != nil {
+		meshID = uint64(mesh.Verticies.ID)
+	}
+
+	return (shaderID&0xFFFF)<<48 | (textureID&0xFFFF)<<32 | (uint64(material.sortID)&0xFFFF)<<16 | (meshID & 0xFFFF)
+}
@@ -0,0 +1,95 @@
+package gfx
+
+import "testing"
+
+// createSortTestMaterial makes a material without touching openGL, with a texture slot called "Albedo"
+func createSortTestMaterial(programID uint32, texture *Texture) *Material {
+	shader := &Shader{
+		ProgramID:         programID,
+		Parameters:        map[string]ShaderParameter{},
+		TextureParameters: map[string]TextureShaderParameter{"Albedo": {Slot: 0}},
+	}
+	material := CreateMaterial(shader)
+	material.ShaderTextures["Albedo"] = texture
+	return material
+}
+
+func TestMakeSortKeySkipsNilTextures(t *testing.T) {
+	material := createSortTestMaterial(1, nil)
+	mesh := &Mesh{}
+	key := MakeSortKey(mesh, material)
+	if textureID := (key >> 32) & 0xFFFF; textureID != 0 {
+		t.Errorf("got texture id %v for a nil texture, expected 0", textureID)
+	}
+
+	noShader := CreateMaterial(nil)
+	noShader.ShaderTextures["Albedo"] = &Texture{ID: 3}
+	if key := MakeSortKey(mesh, noShader); key>>48 != 0 {
+		t.Errorf("got shader id %v for a material without a shader, expected 0", key>>48)
+	}
+}
+
+func TestMakeSortKeyOrder(t *testing.T) {
+	mesh := &Mesh{}
+	// Created first so it has the lower material id, but it's texture should still sort it after second
+	first := createSortTestMaterial(1, &Texture{ID: 9})
+	second := createSortTestMaterial(1, &Texture{ID: 2})
+	otherShader := createSortTestMaterial(2, &Texture{ID: 1})
+
+	tests := []struct {
+		name   string
+		before *Material
+		after  *Material
+	}{
+		{"texture before material", second, first},
+		{"shader before texture", first, otherShader},
+		{"shader before material", second, otherShader},
+	}
+	for _, test := range tests {
+		if MakeSortKey(mesh, test.before) >= MakeSortKey(mesh, test.after) {
+			t.Errorf("%v: got keys %x and %x, expected the first to be smaller", test.name,
+				MakeSortKey(mesh, test.before), MakeSortKey(mesh, test.after))
+		}
+	}
+
+	// Same shader and texture, so the material decides
+	third := createSortTestMaterial(1, &Texture{ID: 9})
+	if MakeSortKey(mesh, first) >= MakeSortKey(mesh, third) {
+		t.Errorf("material: got keys %x and %x, expected the first to be smaller", MakeSortKey(mesh, first), MakeSortKey(mesh, third))
+	}
+}
+
+func TestSubmitRejectsUndrawableMeshes(t *testing.T) {
+	material := createSortTestMaterial(1, nil)
+	drawable := func() *Mesh {
+		return CreateMesh(&VertexArray{ID: 1, Attributes: map[string]*VertexAttribute{}}, &VertexIndexArray{ID: 1})
+	}
+	deleted := drawable()
+	deleted.deleted = true
+
+	tests := []struct {
+		name     string
+		command  DrawCommand
+		accepted bool
+	}{
+		{"drawable", DrawCommand{Mesh: drawable(), Material: material}, true},
+		{"no mesh", DrawCommand{Material: material}, false},
+		{"no material", DrawCommand{Mesh: drawable()}, false},
+		{"no shader", DrawCommand{Mesh: drawable(), Material: CreateMaterial(nil)}, false},
+		{"no vertex array", DrawCommand{Mesh: CreateMesh(nil, &VertexIndexArray{ID: 1}), Material: material}, false},
+		{"no index array", DrawCommand{Mesh: CreateMesh(&VertexArray{ID: 1}, nil), Material: material}, false},
+		{"vertex array never made", DrawCommand{Mesh: CreateMesh(&VertexArray{}, &VertexIndexArray{ID: 1}), Material: material}, false},
+		{"index array never made", DrawCommand{Mesh: CreateMesh(&VertexArray{ID: 1}, &VertexIndexArray{}), Material: material}, false},
+		{"deleted", DrawCommand{Mesh: deleted, Material: material}, false},
+	}
+	for _, test := range tests {
+		renderer := CreateRenderer()
+		err := renderer.Submit(test.command)
+		if accepted := err == nil; accepted != test.accepted {
+			t.Errorf("%v: got error %v, expected accepted %v", test.name, err, test.accepted)
+		}
+		if queued := len(renderer.commands) == 1; queued != test.accepted {
+			t.Errorf("%v: got %v commands queued, expected queued %v", test.name, len(renderer.commands), test.accepted)
+		}
+	}
+}
@@ -6,12 +6,27 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"github.com/Surreal/Math/math"
 	"github.com/go-gl/gl/v3.2-core/gl"
 )
 
 // CurrentlyBoundShader is used to track the currently bound OpenGL shader program
 var CurrentlyBoundShader *Shader
 
+// StandardAttributeLocations are the vertex attribute locations bound before linking every shader. VertexArray pushes
+// attributes with these names to the same locations so any shader using these names works with any imported mesh.
+// S_Joints and S_Weights are the 4 joints each vertex is skinned to and how much each one counts
+var StandardAttributeLocations = map[string]uint32{
+	"S_Position": 0,
+	"S_Normal":   1,
+	"S_TexUV":    2,
+	"S_Tangent":  3,
+	"S_Color":    4,
+	"S_TexUV2":   5,
+	"S_Joints":   6,
+	"S_Weights":  7,
+}
+
 // Shader represents a GLSL Shader for use with OpenGL.
 type Shader struct {
 	ProgramID                    uint32                            // The program ID for use with glProgram instructions
@@ -19,6 +34,8 @@ type Shader struct {
 	TextureParameters            map[string]TextureShaderParameter // The textures this shader supports to be set by an external user
 	vertexShaderSourceFilePath   string
 	fragmentShaderSourceFilePath string
+	vertexShaderSource           string // Used instead of the file when the shader was created from source
+	fragmentShaderSource         string // Used instead of the file when the shader was created from source
 }
 
 // CreateShader is the main constructor for a Shader. This will give you a compiled shader ready to go
@@ -38,6 +55,23 @@ func CreateShader(vertexFilePath string, fragmentFilePath string) (*Shader, erro
 	return shader, nil
 }
 
+// CreateShaderFromSource is like CreateShader but takes the GLSL source directly rather than reading it from files
+func CreateShaderFromSource(vertexSource string, fragmentSource string) (*Shader, error) {
+	shader := new(Shader)
+	shader.vertexShaderSource = vertexSource
+	shader.fragmentShaderSource = fragmentSource
+	shader.Parameters = make(map[string]ShaderParameter)
+	shader.TextureParameters = make(map[string]TextureShaderParameter)
+
+	shader.Generate()
+	err := shader.CompileShaders()
+	if err != nil {
+		return nil, err
+	}
+
+	return shader, nil
+}
+
 // Generate generates an ID and registers the shader program with open GL
 func (shader *Shader) Generate() {
 	if shader.ProgramID > 0 {
@@ -46,6 +80,19 @@ func (shader *Shader) Generate() {
 	shader.ProgramID = gl.CreateProgram()
 }
 
+// Delete frees the openGL program. The shader can't be used again afterwards
+func (shader *Shader) Delete() {
+	if shader.ProgramID <= 0 {
+		return
+	}
+	if CurrentlyBoundShader == shader {
+		gl.UseProgram(0)
+		CurrentlyBoundShader = nil
+	}
+	gl.DeleteProgram(shader.ProgramID)
+	shader.ProgramID = 0
+}
+
 // Bind makes the call to glUseProgram to bind this shader as the active shader
 func (shader *Shader) Bind() (err error) {
 	if CurrentlyBoundShader == shader {
@@ -87,11 +134,21 @@ func (shader *Shader) UnBind() {
 // SUBTODO: Replace copy paste errors with printf and variables
 // TODO: Find a better way to handle boolean types. gl.TRUE is type (int), go bool can't convert to int, gl function takes int32
 func (shader *Shader) SendParameterValue(name string, value interface{}) error {
+	// glUniform only affects the bound program, so bind ourselves and put back whatever was bound before
+	previous := CurrentlyBoundShader
 	err := shader.Bind()
 	if err != nil {
 		return err
 	}
-	defer shader.UnBind()
+	if previous != shader {
+		defer func() {
+			if previous != nil {
+				previous.Bind()
+			} else {
+				shader.UnBind()
+			}
+		}()
+	}
 
 	param, ok := shader.Parameters[name]
 	if !ok {
@@ -481,8 +538,10 @@ func (shader *Shader) SendParameterValue(name string, value interface{}) error {
 			dataPtr = &typedValue[0]
 		case (*float32):
 			dataPtr = typedValue
+		case (*math.Matrix3x3):
+			dataPtr = &typedValue[0]
 		default:
-			return errors.New("Invalid Type to SetParameterValue(mat3): Expected *[]float32, []float32, or *float32")
+			return errors.New("Invalid Type to SetParameterValue(mat3): Expected *[]float32, []float32, *float32 or *math.Matrix3x3")
 		}
 		gl.UniformMatrix3fv(int32(param.Location), param.ArraySize, false, dataPtr)
 	case gl.FLOAT_MAT4:
@@ -500,8 +559,10 @@ func (shader *Shader) SendParameterValue(name string, value interface{}) error {
 			dataPtr = &typedValue[0]
 		case (*float32):
 			dataPtr = typedValue
+		case (*math.Matrix4x4):
+			dataPtr = &typedValue[0]
 		default:
-			return errors.New("Invalid Type to SetParameterValue(mat4): Expected *[]float32, []float32, or *float32")
+			return errors.New("Invalid Type to SetParameterValue(mat4): Expected *[]float32, []float32, *float32 or *math.Matrix4x4")
 		}
 		gl.UniformMatrix4fv(int32(param.Location), param.ArraySize, false, dataPtr)
 	case gl.FLOAT_MAT2x3:
@@ -625,19 +686,24 @@ func (shader *Shader) SendParameterValue(name string, value interface{}) error {
 // CompileShaders reads the contents of the shader files, compiles the shaders, and attaches them to the openGL program. This is a heavy operation and should be done once if possible.
 func (shader *Shader) CompileShaders() error {
 	// Read in shader sources
-	vShaderFileData, err := ioutil.ReadFile(shader.vertexShaderSourceFilePath)
-	if err != nil {
-		return err
+	vShaderSource := shader.vertexShaderSource
+	if vShaderSource == "" {
+		vShaderFileData, err := ioutil.ReadFile(shader.vertexShaderSourceFilePath)
+		if err != nil {
+			return err
+		}
+		vShaderSource = string(vShaderFileData)
 	}
 
-	fShaderFileData, err := ioutil.ReadFile(shader.fragmentShaderSourceFilePath)
-	if err != nil {
-		return err
+	fShaderSource := shader.fragmentShaderSource
+	if fShaderSource == "" {
+		fShaderFileData, err := ioutil.ReadFile(shader.fragmentShaderSourceFilePath)
+		if err != nil {
+			return err
+		}
+		fShaderSource = string(fShaderFileData)
 	}
 
-	vShaderSource := string(vShaderFileData)
-	fShaderSource := string(fShaderFileData)
-
 	// Compile each shader
 	vShaderID, err := compileShader(vShaderSource, gl.VERTEX_SHADER)
 	if err != nil {
@@ -651,6 +717,11 @@ func (shader *Shader) CompileShaders() error {
 
 	gl.AttachShader(shader.ProgramID, vShaderID)
 	gl.AttachShader(shader.ProgramID, fShaderID)
+
+	// Attribute locations only take effect on link
+	for name, location := range StandardAttributeLocations {
+		gl.BindAttribLocation(shader.ProgramID, location, gl.Str(name+"\x00"))
+	}
 	gl.LinkProgram(shader.ProgramID)
 
 	var status int32
@@ -681,6 +752,18 @@ func (shader *Shader) CompileShaders() error {
 		shader.TextureParameters[tex.Name] = tex
 	}
 
+	// Point each sampler at the slot we assigned it. Uniform values belong to the program so this only needs doing once
+	previous := CurrentlyBoundShader
+	shader.Bind()
+	for _, tex := range textures {
+		gl.Uniform1i(int32(tex.Location), int32(tex.Slot-gl.TEXTURE0))
+	}
+	if previous != nil {
+		previous.Bind()
+	} else {
+		shader.UnBind()
+	}
+
 	return nil
 }
 
@@ -738,14 +821,22 @@ func (shader *Shader) getShaderParameters() ([]ShaderParameter, []TextureShaderP
 	var curTexSlot uint32 = gl.TEXTURE0
 	for i := 0; i < int(uniformCount); i++ {
 		gl.GetActiveUniform(shader.ProgramID, uint32(i), maxUniformName, &uniLength, &uniSize, &uniType, gl.Str(nameBuff))
+		name := string([]byte(nameBuff[:uniLength])) // We assure this is a copy, not the original buffer
+
+		// Arrays are reported as "name[0]" but we want them set by their plain name
+		name = strings.TrimSuffix(name, "[0]")
+
+		// The active uniform index is not the same thing as the uniform's location
+		location := uint32(gl.GetUniformLocation(shader.ProgramID, gl.Str(name+"\x00")))
+
 		// Check if it's a texture or not
 		if IsTextureType(uniType) {
 			if curTexSlot-gl.TEXTURE0 > 31 {
 				return nil, nil, errors.New("Too many textures, cannot support more than 32 slots")
 			}
 			texture := new(TextureShaderParameter)
-			texture.Name = string([]byte(nameBuff[:uniLength])) // We assure this is a copy, not the original buffer
-			texture.Location = uint32(i)
+			texture.Name = name
+			texture.Location = location
 			texture.UniformType = uniType
 			texture.ArraySize = uniSize
 			texture.Slot = curTexSlot
@@ -753,8 +844,8 @@ func (shader *Shader) getShaderParameters() ([]ShaderParameter, []TextureShaderP
 			curTexSlot++
 		} else {
 			param := new(ShaderParameter)
-			param.Name = string([]byte(nameBuff[:uniLength])) // We assure this is a copy, not the original buffer
-			param.Location = uint32(i)
+			param.Name = name
+			param.Location = location
 			param.UniformType = uniType
 			param.ArraySize = uniSize
 			retParams = append(retParams, *param)
@@ -0,0 +1,22 @@
+package gfx
+
+// ShadowMap is a depth only render target that lights render the scene into. The depth texture has comparison
+// turned on for use with sampler2DShadow parameters
+type ShadowMap struct {
+	*RenderTarget
+}
+
+// CreateShadowMap is the standard constructor for a ShadowMap
+func CreateShadowMap(width int32, height int32) (*ShadowMap, error) {
+	target, err := CreateRenderTarget(RenderTargetDescription{
+		Width:        width,
+		Height:       height,
+		DepthFormat:  DepthFormat24,
+		SampleDepth:  true,
+		DepthCompare: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ShadowMap{target}, nil
+}
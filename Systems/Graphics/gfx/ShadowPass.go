@@ -0,0 +1,360 @@
+package gfx
+
+import (
+	"fmt"
+	gomath "math"
+
+	"github.com/Surreal/Debug/dbg"
+	"github.com/Surreal/Math/math"
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// shadowDepthShader is shared by every shadow pass
+var shadowDepthShader *Shader
+
+// shadowState is what the main pass needs to know about the shadows rendered this flush
+type shadowState struct {
+	directional      Light
+	directionalMap   *ShadowMap
+	directionalBias  float32
+	cascadeCount     int32
+	cascadeMatrices  [MaxShadowCascades]math.Matrix4x4 // World to shadow map texture space for each cascade
+	cascadeSplits    [MaxShadowCascades]float32        // View space depth at which each cascade ends
+	spot             Light
+	spotMap          *ShadowMap
+	spotBias         float32
+	spotShadowMatrix math.Matrix4x4
+}
+
+// ShadowDepthShader returns the depth only shader used to render shadow maps
+func ShadowDepthShader() (*Shader, error) {
+	if shadowDepthShader == nil {
+		shader, err := CreateShaderFromSource(shadowDepthVertexShaderSource, shadowDepthFragmentShaderSource)
+		if err != nil {
+			return nil, err
+		}
+		shadowDepthShader = shader
+	}
+	return shadowDepthShader, nil
+}
+
+// renderShadows renders the shadow maps for the first shadow casting directional and spot lights using the queued
+// commands as casters. Only those two lights get shadows, any other casters are lit unshadowed and logged once
+func (renderer *Renderer) renderShadows() {
+	renderer.shadows = shadowState{}
+
+	var directional, spot Light
+	ignored := 0
+	for _, light := range registeredLights {
+		settings := light.ShadowSettings()
+		if settings == nil || !settings.CastShadows || light.SceneObject() == nil {
+			continue
+		}
+		if light.LightType() == DirectionalLight && directional == nil {
+			directional = light
+		} else if light.LightType() == SpotLight && spot == nil {
+			spot = light
+		} else {
+			ignored++
+		}
+	}
+	if ignored > 0 && !renderer.warnedShadowCasters {
+		renderer.warnedShadowCasters = true
+		dbg.LogError(fmt.Sprintf("Shadows: Only the first directional and first spot light cast shadows, %v other lights with CastShadows set are unshadowed", ignored))
+	}
+	renderer.releaseShadowMaps(directional, spot)
+	if directional == nil && spot == nil {
+		return
+	}
+
+	depthShader, err := ShadowDepthShader()
+	if err != nil {
+		dbg.LogError(err.Error())
+		return
+	}
+	depthShader.Bind()
+
+	// Unbinding the last shadow map puts back the viewport the main pass expects
+	defer func() {
+		if CurrentlyBoundRenderTarget != nil {
+			CurrentlyBoundRenderTarget.UnBind()
+		}
+	}()
+
+	// Push depth back a little to fight acne on top of the shader bias
+	gl.Enable(gl.POLYGON_OFFSET_FILL)
+	gl.PolygonOffset(1.5, 4)
+	defer gl.Disable(gl.POLYGON_OFFSET_FILL)
+
+	if directional != nil && MainCamera != nil && MainCamera.SceneObject() != nil {
+		renderer.renderDirectionalShadow(directional, depthShader)
+	}
+	if spot != nil {
+		renderer.renderSpotShadow(spot, depthShader)
+	}
+}
+
+// renderDirectionalShadow splits the main camera's frustum into cascades and renders each into it's own tile of one map
+func (renderer *Renderer) renderDirectionalShadow(light Light, depthShader *Shader) {
+	settings := light.ShadowSettings()
+	count := settings.CascadeCount
+	if count < 1 {
+		count = 1
+	}
+	if count > MaxShadowCascades {
+		count = MaxShadowCascades
+	}
+	columns, rows, resolution := shadowAtlasLayout(count, settings.Resolution, MaxTextureSize())
+
+	shadowMap := renderer.shadowMapFor(light, resolution*columns, resolution*rows)
+	if shadowMap == nil {
+		return
+	}
+	shadowMap.Bind()
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+
+	camera := MainCamera
+	near := camera.NearPlane
+	far := camera.FarPlane
+	if settings.Distance > near && settings.Distance < far {
+		far = settings.Distance
+	}
+	cameraView := camera.ViewMatrix()
+
+	direction := light.SceneObject().Transform.Forward()
+	lightRotation := math.LookAt(math.ZeroVector3f(), direction, stableUp(direction))
+
+	splitNear := near
+	for i := 0; i < count; i++ {
+		// Blend between even and logarithmic splits
+		p := float32(i+1) / float32(count)
+		logSplit := near * float32(gomath.Pow(float64(far/near), float64(p)))
+		evenSplit := near + (far-near)*p
+		splitFar := math.Lerp(evenSplit, logSplit, settings.CascadeSplitLambda)
+
+		projection := camera.ProjectionMatrixForRange(splitNear, splitFar)
+		inverseViewProjection, err := projection.Mul(*cameraView).Inverse()
+		if err != nil {
+			return
+		}
+
+		// Fit a sphere around the slice so the cascade doesn't change size as the camera turns
+		var corners [8]math.Vector3f
+		center := math.ZeroVector3f()
+		for c := 0; c < 8; c++ {
+			ndc := math.Vector3f{X: float32(c&1)*2 - 1, Y: float32((c>>1)&1)*2 - 1, Z: float32((c>>2)&1)*2 - 1}
+			corners[c] = inverseViewProjection.TransformPoint(ndc)
+			center = center.Add(corners[c])
+		}
+		center = center.Scale(1.0 / 8.0)
+		radius := float32(0)
+		for _, corner := range corners {
+			radius = math.Max(radius, corner.Distance(center))
+		}
+		radius = float32(gomath.Ceil(float64(radius*16))) / 16
+
+		// Snap to whole texels so the shadow edges don't shimmer as the camera moves
+		texel := 2 * radius / float32(resolution)
+		lightCenter := lightRotation.TransformPoint(center)
+		lightCenter.X = float32(gomath.Floor(float64(lightCenter.X/texel))) * texel
+		lightCenter.Y = float32(gomath.Floor(float64(lightCenter.Y/texel))) * texel
+		depth := -lightCenter.Z
+
+		// Extend towards the light so casters outside the slice still land in the map
+		projection = math.Orthographic(lightCenter.X-radius, lightCenter.X+radius, lightCenter.Y-radius, lightCenter.Y+radius, depth-3*radius, depth+radius)
+		lightViewProjection := projection.Mul(lightRotation)
+
+		column, row := int32(i)%columns, int32(i)/columns
+		gl.Viewport(column*resolution, row*resolution, resolution, resolution)
+		renderer.drawShadowCasters(depthShader, &lightViewProjection)
+
+		// Maps clip space into this cascade's tile of the texture
+		tile := math.Matrix4x4TRS(
+			math.Vector3f{X: (float32(column) + 0.5) / float32(columns), Y: (float32(row) + 0.5) / float32(rows), Z: 0.5},
+			math.IdentityQuaternion(),
+			math.Vector3f{X: 0.5 / float32(columns), Y: 0.5 / float32(rows), Z: 0.5})
+		renderer.shadows.cascadeMatrices[i] = tile.Mul(lightViewProjection)
+		renderer.shadows.cascadeSplits[i] = splitFar
+		splitNear = splitFar
+	}
+
+	renderer.shadows.directional = light
+	renderer.shadows.directionalMap = shadowMap
+	renderer.shadows.directionalBias = settings.Bias
+	renderer.shadows.cascadeCount = int32(count)
+	renderer.stats.ShadowPasses += count
+}
+
+// renderSpotShadow renders a single perspective shadow map covering the spot light's cone
+func (renderer *Renderer) renderSpotShadow(light Light, depthShader *Shader) {
+	spot, ok := light.(*SpotLightComponent)
+	if !ok {
+		return
+	}
+	settings := light.ShadowSettings()
+	_, _, resolution := shadowAtlasLayout(1, settings.Resolution, MaxTextureSize())
+	shadowMap := renderer.shadowMapFor(light, resolution, resolution)
+	if shadowMap == nil {
+		return
+	}
+	shadowMap.Bind()
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+
+	transform := light.SceneObject().Transform
+	position := transform.WorldPosition()
+	direction := transform.Forward()
+	view := math.LookAt(position, position.Add(direction), stableUp(direction))
+	fov := math.Clamp(spot.OuterConeAngle+2, 1, 170)
+	near := math.Max(spot.Range*0.01, 0.05)
+	projection := math.PerspectiveFovY(fov, 1, near, math.Max(spot.Range, near+0.1))
+	lightViewProjection := projection.Mul(view)
+
+	renderer.drawShadowCasters(depthShader, &lightViewProjection)
+
+	bias := math.Matrix4x4TRS(math.Vector3f{X: 0.5, Y: 0.5, Z: 0.5}, math.IdentityQuaternion(), math.Vector3f{X: 0.5, Y: 0.5, Z: 0.5})
+	renderer.shadows.spot = light
+	renderer.shadows.spotMap = shadowMap
+	renderer.shadows.spotBias = settings.Bias
+	renderer.shadows.spotShadowMatrix = bias.Mul(lightViewProjection)
+	renderer.stats.ShadowPasses++
+}
+
+// drawShadowCasters draws every queued command's depth with the bound depth shader
+func (renderer *Renderer) drawShadowCasters(depthShader *Shader, lightViewProjection *math.Matrix4x4) {
+	if err := depthShader.SendParameterValue("u_LightViewProjection", lightViewProjection); err != nil {
+		dbg.LogError(err.Error())
+		return
+	}
+	for i := range renderer.commands {
+		command := &renderer.commands[i]
+		depthShader.SendParameterValue("u_Model", &command.Model)
+		if command.Mesh.Verticies != CurrentlyBoundVertexArray {
+			renderer.stats.VertexArrayBinds++
+		}
+		if command.Mesh.Verticies.Bind() != nil || command.Mesh.VertexIndicies.Bind() != nil {
+			continue
+		}
+		gl.DrawElements(gl.TRIANGLES, int32(command.Mesh.VertexIndicies.Count), uint32(gl.UNSIGNED_INT), gl.PtrOffset(0))
+		renderer.stats.DrawCalls++
+	}
+}
+
+// sendShadowParameters binds this flush's shadow maps and sends their matrices to shader if it uses them
+func (renderer *Renderer) sendShadowParameters(shader *Shader) {
+	shadows := &renderer.shadows
+	if param, ok := shader.TextureParameters["u_DirectionalShadowMap"]; ok && shadows.directionalMap != nil {
+		shadows.directionalMap.DepthTexture.BindToSlot(param.Slot)
+	}
+	if param, ok := shader.TextureParameters["u_SpotShadowMap"]; ok && shadows.spotMap != nil {
+		shadows.spotMap.DepthTexture.BindToSlot(param.Slot)
+	}
+
+	uniforms := []struct {
+		name  string
+		value interface{}
+	}{
+		{"u_DirectionalShadowMatrices", &shadows.cascadeMatrices[0][0]},
+		{"u_CascadeSplits", shadows.cascadeSplits[:]},
+		{"u_CascadeCount", shadows.cascadeCount},
+		{"u_DirectionalShadowBias", shadows.directionalBias},
+		{"u_SpotShadowMatrix", &shadows.spotShadowMatrix},
+		{"u_SpotShadowBias", shadows.spotBias},
+	}
+	for _, uniform := range uniforms {
+		if _, ok := shader.Parameters[uniform.name]; ok {
+			if err := shader.SendParameterValue(uniform.name, uniform.value); err != nil {
+				dbg.LogError(err.Error())
+			}
+		}
+	}
+}
+
+// shadowIndices returns where the shadowed lights ended up in the first count lights sent for a draw, or -1
+func (renderer *Renderer) shadowIndices(count int) (directional int32, spot int32) {
+	directional, spot = -1, -1
+	for i := 0; i < count; i++ {
+		source := renderer.lights[i].source
+		if source == nil {
+			continue
+		}
+		if source == renderer.shadows.directional && renderer.shadows.directionalMap != nil {
+			directional = int32(i)
+		} else if source == renderer.shadows.spot && renderer.shadows.spotMap != nil {
+			spot = int32(i)
+		}
+	}
+	return
+}
+
+// shadowMapFor returns the light's shadow map, creating or resizing it as needed
+func (renderer *Renderer) shadowMapFor(light Light, width int32, height int32) *ShadowMap {
+	if shadowMap, ok := renderer.shadowMaps[light]; ok {
+		if shadowMap.Width() != width || shadowMap.Height() != height {
+			if err := shadowMap.Resize(width, height); err != nil {
+				dbg.LogError(err.Error())
+				return nil
+			}
+		}
+		return shadowMap
+	}
+
+	shadowMap, err := CreateShadowMap(width, height)
+	if err != nil {
+		dbg.LogError(err.Error())
+		return nil
+	}
+	renderer.shadowMaps[light] = shadowMap
+	return shadowMap
+}
+
+// releaseShadowMaps frees the shadow maps of any light that isn't casting this frame
+func (renderer *Renderer) releaseShadowMaps(keep ...Light) {
+	for light, shadowMap := range renderer.shadowMaps {
+		kept := false
+		for _, k := range keep {
+			if k == light {
+				kept = true
+			}
+		}
+		if !kept {
+			shadowMap.Delete()
+			delete(renderer.shadowMaps, light)
+		}
+	}
+}
+
+// shadowAtlasLayout returns how count cascades are tiled into one shadow map, at most 2 across so 4 cascades make a
+// 2x2 grid, and the resolution of each tile lowered so the map fits within maxSize
+func shadowAtlasLayout(count int, resolution int32, maxSize int32) (columns int32, rows int32, tileResolution int32) {
+	if count < 1 {
+		count = 1
+	}
+	columns = int32(count)
+	if columns > 2 {
+		columns = 2
+	}
+	rows = (int32(count) + columns - 1) / columns
+
+	tileResolution = resolution
+	if maxSize > 0 {
+		largest := columns
+		if rows > largest {
+			largest = rows
+		}
+		if tileResolution*largest > maxSize {
+			tileResolution = maxSize / largest
+		}
+	}
+	if tileResolution < 1 {
+		tileResolution = 1
+	}
+	return
+}
+
+// stableUp returns an up vector that isn't parallel to direction
+func stableUp(direction math.Vector3f) math.Vector3f {
+	if math.Abs(direction.Dot(math.UpVector3f())) > 0.99 {
+		return math.ForwardVector3f()
+	}
+	return math.UpVector3f()
+}
@@ -0,0 +1,33 @@
+package gfx
+
+import "testing"
+
+func TestShadowAtlasLayout(t *testing.T) {
+	tests := []struct {
+		name       string
+		count      int
+		resolution int32
+		maxSize    int32
+		columns    int32
+		rows       int32
+		tile       int32
+	}{
+		{"single", 1, 2048, 16384, 1, 1, 2048},
+		{"two in a row", 2, 2048, 16384, 2, 1, 2048},
+		{"three in 2x2", 3, 2048, 16384, 2, 2, 2048},
+		{"four in 2x2", 4, 2048, 16384, 2, 2, 2048},
+		{"clamped to max size", 4, 4096, 4096, 2, 2, 2048},
+		{"single clamped", 1, 8192, 4096, 1, 1, 4096},
+		{"unknown max size", 4, 8192, 0, 2, 2, 8192},
+		{"no cascades", 0, 1024, 4096, 1, 1, 1024},
+	}
+	for _, test := range tests {
+		columns, rows, tile := shadowAtlasLayout(test.count, test.resolution, test.maxSize)
+		if columns != test.columns || rows != test.rows || tile != test.tile {
+			t.Errorf("%v: got %vx%v tiles of %v, expected %vx%v tiles of %v", test.name, columns, rows, tile, test.columns, test.rows, test.tile)
+		}
+		if test.maxSize > 0 && (columns*tile > test.maxSize || rows*tile > test.maxSize) {
+			t.Errorf("%v: got a %vx%v map, which is bigger than %v", test.name, columns*tile, rows*tile, test.maxSize)
+		}
+	}
+}
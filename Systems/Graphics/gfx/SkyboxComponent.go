@@ -0,0 +1,135 @@
+package gfx
+
+import (
+	"github.com/Surreal/Systems/Core/core"
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// skyboxShader is shared by every skybox
+var skyboxShader *Shader
+
+// SkyboxComponent fills the background of a camera with a cubemap. Attach it to the same scene object as the
+// CameraComponent. The sky is drawn after everything else at the far plane so only uncovered pixels pay for it
+type SkyboxComponent struct {
+	*core.BaseComponent
+	Cubemap             *CubemapTexture // The sky
+	Environment         *CubemapTexture // Prefiltered reflections for the lit shader. nil for no reflections
+	Exposure            float32         // Multiplier applied to the sky's color
+	ReflectionIntensity float32         // Multiplier applied to reflections of Environment
+}
+
+// CreateSkyboxComponent is the standard constructor for a SkyboxComponent
+func CreateSkyboxComponent(cubemap *CubemapTexture) *SkyboxComponent {
+	sky := new(SkyboxComponent)
+	sky.BaseComponent = core.CreateBaseComponent(sky)
+	sky.Cubemap = cubemap
+	sky.Exposure = 1
+	sky.ReflectionIntensity = 1
+	return sky
+}
+
+// SkyboxShader returns the shader used to draw skyboxes
+func SkyboxShader() (*Shader, error) {
+	if skyboxShader == nil {
+		shader, err := CreateShaderFromSource(skyboxVertexShaderSource, skyboxFragmentShaderSource)
+		if err != nil {
+			return nil, err
+		}
+		skyboxShader = shader
+	}
+	return skyboxShader, nil
+}
+
+// GenerateEnvironment prefilters the sky into Environment so lit objects reflect it. size is the width of the
+// sharpest reflection's faces
+func (sky *SkyboxComponent) GenerateEnvironment(size int32) error {
+	environment, err := sky.Cubemap.Prefilter(size)
+	if err != nil {
+		return err
+	}
+	if sky.Environment != nil {
+		sky.Environment.Delete()
+	}
+	sky.Environment = environment
+	return nil
+}
+
+// Skybox returns the first enabled skybox on the camera's scene object, or nil if there isn't one
+func (cam *CameraComponent) Skybox() *SkyboxComponent {
+	if cam.SceneObject() == nil {
+		return nil
+	}
+	for _, component := range cam.SceneObject().Components {
+		if sky, ok := component.(*SkyboxComponent); ok && sky.Enabled() && sky.Cubemap != nil {
+			return sky
+		}
+	}
+	return nil
+}
+
+// drawSkybox draws sky behind everything already drawn from the main camera's point of view
+func (renderer *Renderer) drawSkybox(sky *SkyboxComponent) error {
+	shader, err := SkyboxShader()
+	if err != nil {
+		return err
+	}
+
+	// Only the camera's rotation matters, the sky is infinitely far away
+	view := *MainCamera.ViewMatrix()
+	view[12], view[13], view[14] = 0, 0, 0
+	inverse, err := MainCamera.ActiveProjectionMatrix.Mul(view).Inverse()
+	if err != nil {
+		return err
+	}
+
+	if shader != CurrentlyBoundShader {
+		renderer.stats.ShaderBinds++
+	}
+	shader.Bind()
+	shader.SendParameterValue("u_InverseViewProjection", &inverse)
+	shader.SendParameterValue("u_Exposure", sky.Exposure)
+	if param, ok := shader.TextureParameters["u_Skybox"]; ok {
+		sky.Cubemap.BindToSlot(param.Slot)
+	}
+
+	// The sky sits exactly on the far plane, where the cleared depth is, so it has to pass on equal. It must not write
+	// depth or anything drawn later would be hidden by it
+	gl.DepthFunc(gl.LEQUAL)
+	gl.DepthMask(false)
+	defer gl.DepthMask(true)
+	defer gl.DepthFunc(gl.LESS)
+
+	triangle := fullScreenTriangleArray()
+	if triangle != CurrentlyBoundVertexArray {
+		renderer.stats.VertexArrayBinds++
+	}
+	if err := triangle.Bind(); err != nil {
+		return err
+	}
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	renderer.stats.DrawCalls++
+	renderer.stats.Triangles++
+	return nil
+}
+
+// sendEnvironmentParameters binds the main camera's reflection environment to shader if it uses one
+func (renderer *Renderer) sendEnvironmentParameters(shader *Shader, sky *SkyboxComponent) {
+	param, ok := shader.TextureParameters["u_Environment"]
+	if !ok {
+		return
+	}
+
+	intensity := float32(0)
+	mipCount := float32(1)
+	if sky != nil && sky.Environment != nil {
+		sky.Environment.BindToSlot(param.Slot)
+		intensity = sky.ReflectionIntensity
+		mipCount = float32(sky.Environment.MipLevels)
+	}
+	if _, ok := shader.Parameters["u_EnvironmentIntensity"]; ok {
+		shader.SendParameterValue("u_EnvironmentIntensity", intensity)
+	}
+	if _, ok := shader.Parameters["u_EnvironmentMipCount"]; ok {
+		shader.SendParameterValue("u_EnvironmentMipCount", mipCount)
+	}
+}
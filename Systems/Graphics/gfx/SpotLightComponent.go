@@ -0,0 +1,71 @@
+package gfx
+
+import (
+	"github.com/Surreal/Math/math"
+	"github.com/Surreal/Systems/Core/core"
+)
+
+// SpotLightComponent lights a cone in the direction it's scene object is facing. Full brightness inside
+// InnerConeAngle fading to nothing at OuterConeAngle
+type SpotLightComponent struct {
+	*core.BaseComponent
+	Color          math.Vector3f  // The color of the light
+	Intensity      float32        // Multiplier applied to Color
+	Range          float32        // The distance at which the light has faded to nothing
+	InnerConeAngle float32        // The full angle in degrees of the cone at full brightness
+	OuterConeAngle float32        // The full angle in degrees of the cone beyond which there is no light
+	Shadows        ShadowSettings // How this light casts shadows
+}
+
+// CreateSpotLightComponent is the standard constructor for a SpotLightComponent
+func CreateSpotLightComponent(color math.Vector3f, intensity float32, lightRange float32, innerConeAngle float32, outerConeAngle float32) *SpotLightComponent {
+	light := new(SpotLightComponent)
+	light.BaseComponent = core.CreateBaseComponent(light)
+	light.Color = color
+	light.Intensity = intensity
+	light.Shadows = DefaultShadowSettings()
+	light.Shadows.Resolution = 1024
+	light.Shadows.CascadeCount = 1
+	light.Range = lightRange
+	light.InnerConeAngle = innerConeAngle
+	light.OuterConeAngle = outerConeAngle
+	return light
+}
+
+// LightType implements the Light interface
+func (light *SpotLightComponent) LightType() LightType {
+	return SpotLight
+}
+
+// ShadowSettings implements the Light interface
+func (light *SpotLightComponent) ShadowSettings() *ShadowSettings {
+	return &light.Shadows
+}
+
+// OnEnable implements the core.Enabler interface
+func (light *SpotLightComponent) OnEnable() {
+	registerLight(light)
+}
+
+// OnDisable implements the core.Disabler interface
+func (light *SpotLightComponent) OnDisable() {
+	unregisterLight(light)
+}
+
+func (light *SpotLightComponent) lightInfo() lightInfo {
+	transform := light.SceneObject().Transform
+	inner := light.InnerConeAngle
+	if inner > light.OuterConeAngle {
+		inner = light.OuterConeAngle
+	}
+	return lightInfo{
+		source:     light,
+		lightType:  SpotLight,
+		position:   transform.WorldPosition(),
+		direction:  transform.Forward(),
+		color:      light.Color.Scale(light.Intensity),
+		lightRange: light.Range,
+		cosInner:   cosHalfAngle(inner),
+		cosOuter:   cosHalfAngle(light.OuterConeAngle),
+	}
+}
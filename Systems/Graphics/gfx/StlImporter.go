@@ -0,0 +1,265 @@
+package gfx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	gomath "math"
+	"strings"
+
+	"github.com/Surreal/Math/math"
+)
+
+// Sizes in bytes of the parts of a binary stl
+const (
+	stlHeaderSize   = 84 // The 80 byte header and the triangle count
+	stlTriangleSize = 50 // The normal, 3 corners and a 2 byte attribute
+)
+
+// stlCreaseAngle is the angle in degrees between two facets above which the edge between them stays sharp. Facets
+// closer than this share normals, so curved surfaces shade smoothly while the edges of boxy parts stay hard
+const stlCreaseAngle = 40
+
+// stlTriangle is one facet of an stl file, corners in counter clockwise order
+type stlTriangle struct {
+	normal  math.Vector3f
+	corners [3]math.Vector3f
+	color   math.Vector4f
+}
+
+// stlCornerKey identifies a welded corner. Corners in the same place with the same color are the same corner
+type stlCornerKey struct {
+	position math.Vector3f
+	color    math.Vector4f
+}
+
+// stlVertexKey identifies a unique vertex, a welded corner with one of the normals the facets around it gave it
+type stlVertexKey struct {
+	corner int
+	normal math.Vector3f
+}
+
+// parseStl parses an ascii or binary stl file. Every solid of an ascii file becomes a mesh
+func parseStl(fileData []byte) ([]*MeshData, error) {
+	if !isBinaryStl(fileData) {
+		return parseAsciiStl(string(fileData))
+	}
+
+	triangles, colored, err := parseBinaryStl(fileData)
+	if err != nil {
+		return nil, err
+	}
+	if len(triangles) == 0 {
+		return nil, errors.New("Invalid File Format: Stl file has no facets")
+	}
+	return []*MeshData{buildStlMesh("", triangles, colored)}, nil
+}
+
+// isBinaryStl guesses whether an stl file is binary. Ascii files start with "solid", but so do the headers of some
+// binary files, so a size that matches the triangle count wins
+func isBinaryStl(fileData []byte) bool {
+	if len(fileData) >= stlHeaderSize {
+		count := uint64(binary.LittleEndian.Uint32(fileData[80:]))
+		if uint64(len(fileData)) == stlHeaderSize+count*stlTriangleSize {
+			return true
+		}
+	}
+	return !bytes.HasPrefix(bytes.TrimLeft(fileData, " \t\r\n"), []byte("solid"))
+}
+
+// parseBinaryStl reads the triangles of a binary stl. Returns whether the file has colors, which are in the attribute
+// bits in one of two ways. VisCAM and SolidView set bit 15 on colored facets, Materialise Magics writes COLOR= in the
+// header and clears bit 15 on facets with their own color
+func parseBinaryStl(fileData []byte) ([]stlTriangle, bool, error) {
+	if len(fileData) < stlHeaderSize {
+		return nil, false, errors.New("Invalid File Format: Binary stl is too short for it's header")
+	}
+	count := int(binary.LittleEndian.Uint32(fileData[80:]))
+	if count < 0 || count > (len(fileData)-stlHeaderSize)/stlTriangleSize {
+		return nil, false, fmt.Errorf("Invalid File Format: Binary stl says it has %v triangles but is too short for them", count)
+	}
+
+	header := fileData[:80]
+	materialise := false
+	defaultColor := math.Vector4f{X: 1, Y: 1, Z: 1, W: 1}
+	if at := bytes.Index(header, []byte("COLOR=")); at >= 0 && at+10 <= len(header) {
+		materialise = true
+		rgba := header[at+6 : at+10]
+		defaultColor = math.Vector4f{X: float32(rgba[0]) / 255, Y: float32(rgba[1]) / 255, Z: float32(rgba[2]) / 255, W: float32(rgba[3]) / 255}
+	}
+
+	colored := materialise
+	triangles := make([]stlTriangle, count)
+	for i := range triangles {
+		at := fileData[stlHeaderSize+i*stlTriangleSize:]
+		triangle := &triangles[i]
+		triangle.normal = readStlVector(at)
+		for c := range triangle.corners {
+			triangle.corners[c] = readStlVector(at[12+c*12:])
+		}
+
+		attribute := binary.LittleEndian.Uint16(at[48:])
+		red, green, blue := float32(attribute&31)/31, float32(attribute>>5&31)/31, float32(attribute>>10&31)/31
+		triangle.color = defaultColor
+		switch {
+		case materialise && attribute&0x8000 == 0:
+			triangle.color = math.Vector4f{X: red, Y: green, Z: blue, W: 1}
+		case !materialise && attribute&0x8000 != 0:
+			// VisCAM packs the channels the other way around
+			triangle.color = math.Vector4f{X: blue, Y: green, Z: red, W: 1}
+			colored = true
+		}
+	}
+	return triangles, colored, nil
+}
+
+// readStlVector reads 3 little endian floats
+func readStlVector(data []byte) math.Vector3f {
+	return math.Vector3f{
+		X: gomath.Float32frombits(binary.LittleEndian.Uint32(data)),
+		Y: gomath.Float32frombits(binary.LittleEndian.Uint32(data[4:])),
+		Z: gomath.Float32frombits(binary.LittleEndian.Uint32(data[8:])),
+	}
+}
+
+// parseAsciiStl reads every solid of an ascii stl. Loops with more than 3 vertices, which some exporters write, are
+// split into a fan
+func parseAsciiStl(raw string) ([]*MeshData, error) {
+	var meshes []*MeshData
+	var triangles []stlTriangle
+	var normal math.Vector3f
+	var loop []math.Vector3f
+	name := ""
+	finishSolid := func() {
+		if len(triangles) > 0 {
+			meshes = append(meshes, buildStlMesh(name, triangles, false))
+		}
+		triangles = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(fields[0]) {
+		case "solid":
+			finishSolid()
+			name = strings.Join(fields[1:], " ")
+		case "facet":
+			if len(fields) < 2 || strings.ToLower(fields[1]) != "normal" {
+				err = errors.New("Invalid File Format: Expected facet normal")
+				break
+			}
+			var values []float32
+			if values, err = parseObjFloats(fields[2:], 3, "Stl Normal"); err == nil {
+				normal = math.Vector3f{X: values[0], Y: values[1], Z: values[2]}
+			}
+		case "outer":
+			loop = loop[:0]
+		case "vertex":
+			var values []float32
+			if values, err = parseObjFloats(fields[1:], 3, "Stl Vertex"); err == nil {
+				loop = append(loop, math.Vector3f{X: values[0], Y: values[1], Z: values[2]})
+			}
+		case "endloop":
+			if len(loop) < 3 {
+				err = errors.New("Invalid File Format: Stl facet has fewer than 3 vertices")
+				break
+			}
+			for i := 1; i+1 < len(loop); i++ {
+				triangles = append(triangles, stlTriangle{normal: normal, corners: [3]math.Vector3f{loop[0], loop[i], loop[i+1]}})
+			}
+		case "endsolid":
+			finishSolid()
+		}
+		// endfacet needs nothing done
+
+		if err != nil {
+			return nil, fmt.Errorf("%v (stl line %v)", err, lineNumber)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New("Invalid Stl File: " + err.Error())
+	}
+
+	// Forgiving of files cut off before endsolid
+	finishSolid()
+	if len(meshes) == 0 {
+		return nil, errors.New("Invalid File Format: Stl file has no facets")
+	}
+	return meshes, nil
+}
+
+// buildStlMesh welds the triangles' corners by position and color, then gives each corner the area weighted normal of
+// the facets around it that are within stlCreaseAngle of the facet using it. A corner ends up as one vertex on a smooth
+// surface and one per side of a sharp edge
+func buildStlMesh(name string, triangles []stlTriangle, colored bool) *MeshData {
+	mesh := &MeshData{Name: name}
+
+	corners := make(map[stlCornerKey]int)
+	cornerIDs := make([][3]int, len(triangles))
+	var around [][]int // The facets using each welded corner
+	weighted := make([]math.Vector3f, len(triangles))
+	facetNormals := make([]math.Vector3f, len(triangles))
+	for t, triangle := range triangles {
+		// Exporters often leave the facet normal as 0, the winding is what actually decides which way it faces. The
+		// cross product's length is twice the facet's area, which weights the smoothing
+		points := triangle.corners
+		weighted[t] = points[1].Sub(points[0]).Cross(points[2].Sub(points[0]))
+		facetNormals[t] = weighted[t].Normalize()
+		if facetNormals[t] == (math.Vector3f{}) {
+			facetNormals[t] = triangle.normal.Normalize()
+		}
+
+		for c, point := range points {
+			key := stlCornerKey{position: point, color: triangle.color}
+			id, ok := corners[key]
+			if !ok {
+				id = len(around)
+				corners[key] = id
+				around = append(around, nil)
+			}
+			cornerIDs[t][c] = id
+			around[id] = append(around[id], t)
+		}
+	}
+
+	creaseCos := float32(gomath.Cos(float64(stlCreaseAngle * math.Deg2Rad)))
+	vertices := make(map[stlVertexKey]uint32)
+	for t, triangle := range triangles {
+		for c, point := range triangle.corners {
+			var normal math.Vector3f
+			for _, other := range around[cornerIDs[t][c]] {
+				if facetNormals[other].Dot(facetNormals[t]) >= creaseCos {
+					normal = normal.Add(weighted[other])
+				}
+			}
+			normal = normal.Normalize()
+			if normal == (math.Vector3f{}) {
+				normal = facetNormals[t]
+			}
+
+			key := stlVertexKey{corner: cornerIDs[t][c], normal: normal}
+			index, ok := vertices[key]
+			if !ok {
+				index = uint32(len(vertices))
+				vertices[key] = index
+				mesh.Positions = append(mesh.Positions, point.X, point.Y, point.Z)
+				mesh.Normals = append(mesh.Normals, normal.X, normal.Y, normal.Z)
+				if colored {
+					mesh.Colors = append(mesh.Colors, triangle.color.X, triangle.color.Y, triangle.color.Z, triangle.color.W)
+				}
+			}
+			mesh.Indices = append(mesh.Indices, index)
+		}
+	}
+	return mesh
+}
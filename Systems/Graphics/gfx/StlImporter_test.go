@@ -0,0 +1,228 @@
+package gfx
+
+import (
+	"bytes"
+	"encoding/binary"
+	gomath "math"
+	"strings"
+	"testing"
+
+	"github.com/Surreal/Math/math"
+)
+
+// stlTestCube returns the 12 facets of a unit cube wound counter clockwise from outside
+func stlTestCube() []stlTriangle {
+	corner := func(i int) math.Vector3f {
+		return math.Vector3f{X: float32(i & 1), Y: float32(i >> 1 & 1), Z: float32(i >> 2 & 1)}
+	}
+	quads := [6][4]int{{0, 2, 3, 1}, {4, 5, 7, 6}, {0, 1, 5, 4}, {2, 6, 7, 3}, {0, 4, 6, 2}, {1, 3, 7, 5}}
+	var triangles []stlTriangle
+	for _, quad := range quads {
+		triangles = append(triangles,
+			stlTriangle{corners: [3]math.Vector3f{corner(quad[0]), corner(quad[1]), corner(quad[2])}},
+			stlTriangle{corners: [3]math.Vector3f{corner(quad[0]), corner(quad[2]), corner(quad[3])}})
+	}
+	return triangles
+}
+
+// stlTestFold returns two facets facing +Y, meeting along the X axis and each tilted up angle degrees from flat
+func stlTestFold(angle float64) []stlTriangle {
+	y := float32(gomath.Sin(angle * gomath.Pi / 180))
+	z := float32(gomath.Cos(angle * gomath.Pi / 180))
+	a, b := math.Vector3f{}, math.Vector3f{X: 1}
+	return []stlTriangle{
+		{corners: [3]math.Vector3f{b, a, {X: 0.5, Y: y, Z: z}}},
+		{corners: [3]math.Vector3f{a, b, {X: 0.5, Y: y, Z: -z}}},
+	}
+}
+
+// binaryStl writes triangles as a binary stl, giving each facet the attribute at the same index
+func binaryStl(header string, triangles []stlTriangle, attributes []uint16) []byte {
+	var buffer bytes.Buffer
+	headerBytes := make([]byte, 80)
+	copy(headerBytes, header)
+	buffer.Write(headerBytes)
+	binary.Write(&buffer, binary.LittleEndian, uint32(len(triangles)))
+	for i, triangle := range triangles {
+		binary.Write(&buffer, binary.LittleEndian, triangle.normal)
+		binary.Write(&buffer, binary.LittleEndian, triangle.corners)
+		attribute := uint16(0)
+		if i < len(attributes) {
+			attribute = attributes[i]
+		}
+		binary.Write(&buffer, binary.LittleEndian, attribute)
+	}
+	return buffer.Bytes()
+}
+
+func TestParseAsciiStl(t *testing.T) {
+	source := `solid first part
+  facet normal 0 0 1
+    outer loop
+      vertex 0 0 0
+      vertex 1 0 0
+      vertex 1 1 0
+    endloop
+  endfacet
+  facet normal 0 0 0
+    outer loop
+      vertex 0 0 0
+      vertex 1 1 0
+      vertex 0 1 0
+    endloop
+  endfacet
+endsolid first part
+SOLID second
+  FACET NORMAL 0 0 1
+    OUTER LOOP
+      VERTEX 0 0 1
+      VERTEX 1 0 1
+      VERTEX 1 1 1
+      VERTEX 0 1 1
+    ENDLOOP
+  ENDFACET
+ENDSOLID second
+`
+	meshes, err := parseStl([]byte(source))
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	tests := []struct {
+		name      string
+		vertices  int
+		triangles int
+	}{
+		{"first part", 4, 2},
+		{"second", 4, 2},
+	}
+	if len(meshes) != len(tests) {
+		t.Fatalf("got %v meshes, expected %v", len(meshes), len(tests))
+	}
+	for i, test := range tests {
+		mesh := meshes[i]
+		if err := mesh.Validate(); err != nil {
+			t.Errorf("%v: got an invalid mesh: %v", test.name, err)
+		}
+		if mesh.Name != test.name || mesh.VertexCount() != test.vertices || len(mesh.Indices)/3 != test.triangles {
+			t.Errorf("got mesh %q with %v vertices and %v triangles, expected %q with %v and %v",
+				mesh.Name, mesh.VertexCount(), len(mesh.Indices)/3, test.name, test.vertices, test.triangles)
+		}
+		if mesh.Colors != nil {
+			t.Errorf("%v: got colors from an ascii file", test.name)
+		}
+		for v := 0; v < mesh.VertexCount(); v++ {
+			if normal := (math.Vector3f{X: mesh.Normals[v*3], Y: mesh.Normals[v*3+1], Z: mesh.Normals[v*3+2]}); !normal.ApproxEqual(math.Vector3f{Z: 1}, 1e-5) {
+				t.Errorf("%v: got normal %v, expected +Z", test.name, normal)
+			}
+		}
+	}
+}
+
+func TestParseBinaryStl(t *testing.T) {
+	cube := stlTestCube()
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"plain header", "binary cube"},
+		{"header starting with solid", "solid cube exported as binary"},
+	}
+	for _, test := range tests {
+		meshes, err := parseStl(binaryStl(test.header, cube, nil))
+		if err != nil {
+			t.Errorf("%v: got error %v", test.name, err)
+			continue
+		}
+		mesh := meshes[0]
+		if err := mesh.Validate(); err != nil {
+			t.Errorf("%v: got an invalid mesh: %v", test.name, err)
+		}
+		// The cube's edges are all sharp, so each corner is a vertex on each of it's 3 sides
+		if mesh.VertexCount() != 24 || len(mesh.Indices) != 36 || mesh.Colors != nil {
+			t.Errorf("%v: got %v vertices, %v indices and colors %v, expected 24, 36 and none", test.name, mesh.VertexCount(), len(mesh.Indices), mesh.Colors != nil)
+		}
+	}
+
+	truncated := binaryStl("binary cube", cube, nil)
+	if _, err := parseStl(truncated[:len(truncated)-10]); err == nil || !strings.Contains(err.Error(), "too short") {
+		t.Errorf("truncated: got error %v, expected one about the file being too short", err)
+	}
+	if _, err := parseStl(binaryStl("binary", nil, nil)); err == nil {
+		t.Errorf("no facets: got no error, expected one")
+	}
+}
+
+func TestParseStlColors(t *testing.T) {
+	triangles := stlTestFold(0)
+	red, blue := math.Vector4f{X: 1, W: 1}, math.Vector4f{Z: 1, W: 1}
+	tests := []struct {
+		name       string
+		header     string
+		attributes []uint16
+		expected   []math.Vector4f // The color of each facet, nil if the file has none
+	}{
+		{"uncolored", "binary", []uint16{0, 0}, nil},
+		// VisCAM sets bit 15 on colored facets and keeps red in the high bits
+		{"VisCAM", "binary", []uint16{0x8000 | 31<<10, 0x8000 | 31}, []math.Vector4f{red, blue}},
+		// Magics has a default color in the header and clears bit 15 on facets with their own, red in the low bits
+		{"Magics", "COLOR=\x00\x00\xFF\xFF", []uint16{0x8000, 31}, []math.Vector4f{blue, red}},
+	}
+	for _, test := range tests {
+		meshes, err := parseStl(binaryStl(test.header, triangles, test.attributes))
+		if err != nil {
+			t.Errorf("%v: got error %v", test.name, err)
+			continue
+		}
+		mesh := meshes[0]
+		if test.expected == nil {
+			if mesh.Colors != nil {
+				t.Errorf("%v: got colors, expected none", test.name)
+			}
+			continue
+		}
+		if err := mesh.Validate(); err != nil || mesh.Colors == nil {
+			t.Errorf("%v: got an invalid or uncolored mesh: %v", test.name, err)
+			continue
+		}
+		// Facets of different colors never share vertices even though they're flat
+		if mesh.VertexCount() != 6 {
+			t.Errorf("%v: got %v vertices, expected 6", test.name, mesh.VertexCount())
+		}
+		for facet, expected := range test.expected {
+			index := mesh.Indices[facet*3]
+			got := math.Vector4f{X: mesh.Colors[index*4], Y: mesh.Colors[index*4+1], Z: mesh.Colors[index*4+2], W: mesh.Colors[index*4+3]}
+			if got != expected {
+				t.Errorf("%v: got facet %v colored %v, expected %v", test.name, facet, got, expected)
+			}
+		}
+	}
+}
+
+func TestStlWelding(t *testing.T) {
+	tests := []struct {
+		name     string
+		angle    float64 // Between the facets' normals
+		vertices int
+	}{
+		{"flat", 0, 4},
+		{"gentle curve", 15, 4},
+		{"just under the crease", stlCreaseAngle - 1, 4},
+		{"just over the crease", stlCreaseAngle + 1, 6},
+		{"sharp edge", 90, 6},
+	}
+	for _, test := range tests {
+		mesh := buildStlMesh("", stlTestFold(test.angle/2), false)
+		if mesh.VertexCount() != test.vertices {
+			t.Errorf("%v: got %v vertices, expected %v", test.name, mesh.VertexCount(), test.vertices)
+			continue
+		}
+		if test.vertices == 4 {
+			// The shared edge is smoothed halfway between both facets, which lean either side of +Y
+			shared := mesh.Indices[0]
+			normal := math.Vector3f{X: mesh.Normals[shared*3], Y: mesh.Normals[shared*3+1], Z: mesh.Normals[shared*3+2]}
+			if !normal.ApproxEqual(math.Vector3f{Y: 1}, 1e-5) {
+				t.Errorf("%v: got the shared edge's normal %v, expected +Y", test.name, normal)
+			}
+		}
+	}
+}
@@ -2,9 +2,6 @@ package gfx
 
 import (
 	"errors"
-	"image"
-	"os"
-	"runtime"
 
 	"github.com/go-gl/gl/v3.2-core/gl"
 )
@@ -15,23 +12,39 @@ const BytesPerPixel int = 4
 // CurrentlyBoundTextures tracks the textures currently bound to each texture slot
 var CurrentlyBoundTextures [32]*Texture
 
+// Anisotropic filtering is an extension rather than core 3.2, so the bindings don't have its enums
+const (
+	textureMaxAnisotropy    uint32 = 0x84FE // GL_TEXTURE_MAX_ANISOTROPY_EXT
+	maxTextureMaxAnisotropy uint32 = 0x84FF // GL_MAX_TEXTURE_MAX_ANISOTROPY_EXT
+)
+
+// maxAnisotropy caches the GPU's highest anisotropic filtering level. 0 until first queried
+var maxAnisotropy float32
+
 // Texture represents a texture ( mandatory Go comments :\ )
 type Texture struct {
-	ID                 uint32 // The texture id used to represent this in openGL
-	SourceFilePath     string // The absolute filepath to load this texture from
-	IsLoaded           bool   // If true, the texture has already been sent to the GPU
-	GenerateMipMaps    bool   // Whether or not to automatically generate mipmaps
-	horizontalWrapMode int    // The gl texture wrap mode to wrap this texture horizontally (s coord)
-	verticalWrapMode   int    // the gl texture wrap mode to wrap this texture vertically (t coord)
-	minFilterMode      int    // The gl texture filtering to be used for minification.
-	magFilterMode      int    // The gl texture filtering to be used for magnification.
-	lastLoadedPath     string // Used internally to prevent reloading already loaded textures
+	ID                 uint32          // The texture id used to represent this in openGL
+	Target             uint32          // The openGL texture target, i.e. gl.TEXTURE_2D or gl.TEXTURE_CUBE_MAP. 0 means gl.TEXTURE_2D
+	SourceFilePath     string          // The absolute filepath to load this texture from
+	IsLoaded           bool            // If true, the texture has already been sent to the GPU
+	GenerateMipMaps    bool            // Whether or not to automatically generate mipmaps
+	SRGB               bool            // If true, 8 bit color is stored as sRGB and converted to linear when sampled
+	Channels           TextureChannels // How many channels to store on the GPU
+	Width              int32           // Width in pixels of the loaded image
+	Height             int32           // Height in pixels of the loaded image
+	anisotropy         float32         // The anisotropic filtering level
+	horizontalWrapMode int             // The gl texture wrap mode to wrap this texture horizontally (s coord)
+	verticalWrapMode   int             // the gl texture wrap mode to wrap this texture vertically (t coord)
+	minFilterMode      int             // The gl texture filtering to be used for minification.
+	magFilterMode      int             // The gl texture filtering to be used for magnification.
+	lastLoadedPath     string          // Used internally to prevent reloading already loaded textures
 }
 
 // CreateTexture is the standard constructor for a texture struct
 func CreateTexture(sourceFilePath string) *Texture {
 	texture := new(Texture)
 	texture.Generate()
+	texture.Target = gl.TEXTURE_2D
 	texture.SourceFilePath = sourceFilePath
 	texture.IsLoaded = false
 	texture.GenerateMipMaps = false
@@ -53,7 +66,7 @@ func (tex *Texture) SetHorizontalWrapMode(mode int) {
 	tex.BindToSlot(gl.TEXTURE0)
 	defer tex.UnBindFromSlot(gl.TEXTURE0)
 	tex.horizontalWrapMode = mode
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, int32(mode))
+	gl.TexParameteri(tex.textureTarget(), gl.TEXTURE_WRAP_S, int32(mode))
 }
 
 // VerticalWrapMode is the getter for the vertical wrap mode
@@ -66,7 +79,7 @@ func (tex *Texture) SetVerticalWrapMode(mode int) {
 	tex.BindToSlot(gl.TEXTURE0)
 	defer tex.UnBindFromSlot(gl.TEXTURE0)
 	tex.verticalWrapMode = mode
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, int32(mode))
+	gl.TexParameteri(tex.textureTarget(), gl.TEXTURE_WRAP_T, int32(mode))
 }
 
 // MinFilterMode is the getter for the minification filter mode
@@ -79,7 +92,7 @@ func (tex *Texture) SetMinFilterMode(mode int) {
 	tex.BindToSlot(gl.TEXTURE0)
 	defer tex.UnBindFromSlot(gl.TEXTURE0)
 	tex.minFilterMode = mode
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, int32(mode))
+	gl.TexParameteri(tex.textureTarget(), gl.TEXTURE_MIN_FILTER, int32(mode))
 }
 
 // MagFilterMode is the getter for the magnification filter mode
@@ -92,7 +105,15 @@ func (tex *Texture) SetMagFilterMode(mode int) {
 	tex.BindToSlot(gl.TEXTURE0)
 	defer tex.UnBindFromSlot(gl.TEXTURE0)
 	tex.magFilterMode = mode
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int32(mode))
+	gl.TexParameteri(tex.textureTarget(), gl.TEXTURE_MAG_FILTER, int32(mode))
+}
+
+// textureTarget returns the target this texture binds to
+func (tex *Texture) textureTarget() uint32 {
+	if tex.Target == 0 {
+		return gl.TEXTURE_2D
+	}
+	return tex.Target
 }
 
 // Generate will generate the texture within openGL and assign an ID
@@ -104,6 +125,47 @@ func (tex *Texture) Generate() {
 	gl.GenTextures(1, &tex.ID)
 }
 
+// regenerate makes a fresh GL texture after Delete, keeping the wrap and filter settings. Does nothing if the texture
+// still has one
+func (tex *Texture) regenerate() {
+	if tex.ID > 0 {
+		return
+	}
+	tex.Generate()
+
+	wrapS, wrapT, minFilter, magFilter := tex.horizontalWrapMode, tex.verticalWrapMode, tex.minFilterMode, tex.magFilterMode
+	if wrapS != 0 {
+		tex.SetHorizontalWrapMode(wrapS)
+	}
+	if wrapT != 0 {
+		tex.SetVerticalWrapMode(wrapT)
+	}
+	if minFilter != 0 {
+		tex.SetMinFilterMode(minFilter)
+	}
+	if magFilter != 0 {
+		tex.SetMagFilterMode(magFilter)
+	}
+	if tex.anisotropy > 1 {
+		tex.SetAnisotropy(tex.anisotropy)
+	}
+}
+
+// Delete frees the GPU texture. Loading or uploading to it again afterwards makes a new one with the same settings
+func (tex *Texture) Delete() {
+	if tex == nil || tex.ID <= 0 {
+		return
+	}
+	for i, bound := range CurrentlyBoundTextures {
+		if bound == tex {
+			CurrentlyBoundTextures[i] = nil
+		}
+	}
+	gl.DeleteTextures(1, &tex.ID)
+	tex.ID = 0
+	tex.IsLoaded = false
+}
+
 // BindToSlot binds the texture to openGL slot. Use glEnum definitions not ints 0-32
 func (tex *Texture) BindToSlot(slot uint32) (err error) {
 	normalizedIndex := int(slot - gl.TEXTURE0)
@@ -121,7 +183,7 @@ func (tex *Texture) BindToSlot(slot uint32) (err error) {
 
 	// Bind buffer
 	gl.ActiveTexture(slot)
-	gl.BindTexture(gl.TEXTURE_2D, tex.ID)
+	gl.BindTexture(tex.textureTarget(), tex.ID)
 
 	// Update tracking
 	CurrentlyBoundTextures[normalizedIndex] = tex
@@ -139,60 +201,146 @@ func (tex *Texture) UnBindFromSlot(slot uint32) {
 	}
 
 	gl.ActiveTexture(slot)
-	gl.BindTexture(gl.TEXTURE_2D, 0)
+	gl.BindTexture(tex.textureTarget(), 0)
+	CurrentlyBoundTextures[normalizedIndex] = nil
 }
 
 // Load loads the texture file from hard disk into GPU memory
 func (tex *Texture) Load() error {
-	defer runtime.GC()
 	// Skip if already loaded
 	if tex.lastLoadedPath == tex.SourceFilePath && tex.IsLoaded {
 		return nil
 	}
 
-	// Open the file handle
-	imgFile, err := os.Open(tex.SourceFilePath)
+	decoded, err := decodeTextureFile(tex.SourceFilePath, tex.Channels, tex.SRGB)
 	if err != nil {
 		return err
 	}
-	defer imgFile.Close()
-
-	// Setup a decoder
-	img, _, err := image.Decode(imgFile)
-	if err != nil {
+	if err := tex.uploadDecoded(decoded); err != nil {
 		return err
 	}
 
-	// Copy image into ram
-	rgba := image.NewRGBA(img.Bounds())
-	for j := 0; j < img.Bounds().Dy(); j++ {
-		for i := 0; i < img.Bounds().Dx(); i++ {
-			rgba.Set(i, img.Bounds().Dy()-j, img.At(i, j))
+	// Bookkeeping
+	tex.lastLoadedPath = tex.SourceFilePath
+	return nil
+}
+
+// decodedTexture is a texture file read into memory, either as a container or as converted pixels
+type decodedTexture struct {
+	container *TextureContainer
+	data      *TextureData
+}
+
+// decodeTextureFile reads and decodes a texture file on the CPU. It doesn't touch openGL so it can run on any goroutine
+func decodeTextureFile(filePath string, channels TextureChannels, srgb bool) (*decodedTexture, error) {
+	// Container files hold data ready for the GPU
+	if IsTextureContainerFile(filePath) {
+		container, err := ReadTextureContainerFile(filePath)
+		if err != nil {
+			return nil, err
 		}
+		return &decodedTexture{container: container}, nil
 	}
-	//if rgba.Stride != rgba.Rect.Size().X*4 {
-	//	return errors.New("Invalid Texture File Format: Stride must be image width * 4")
-	//}
-	//draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
 
-	// Send data to the GPU
-	tex.BindToSlot(gl.TEXTURE0)
-	defer tex.UnBindFromSlot(gl.TEXTURE0)
+	// Everything else is decoded and converted
+	img, err := decodeImageFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := CreateTextureData(img, channels, srgb, true)
+	if err != nil {
+		return nil, err
+	}
+	return &decodedTexture{data: data}, nil
+}
 
+// uploadDecoded sends a decoded texture file to the GPU. Containers can change the texture's target, i.e. to a cubemap
+func (tex *Texture) uploadDecoded(decoded *decodedTexture) error {
+	if decoded.container != nil {
+		tex.retarget(decoded.container.Target())
+		return tex.UploadContainer(decoded.container)
+	}
+	return tex.Upload(decoded.data)
+}
+
+// Upload sends already decoded pixels to the GPU, replacing anything already there, and generates mipmaps if
+// GenerateMipMaps is set
+func (tex *Texture) Upload(data *TextureData) error {
+	tex.regenerate()
+	if err := tex.BindToSlot(gl.TEXTURE0); err != nil {
+		return err
+	}
+
+	// Rows are tightly packed, which breaks the default 4 byte row alignment for odd sizes
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
 	gl.TexImage2D(
-		gl.TEXTURE_2D,             // The openGL Target
-		0,                         // LOD Level (used if doing custom MipMaps)
-		gl.RGBA,                   // Specifies the format of the texture in GPU
-		int32(rgba.Rect.Size().X), // Width of the image in pixels
-		int32(rgba.Rect.Size().Y), // Height of the image in pixels
-		0,                // border. Don't know wtf this does. Spec says "must be 0"
-		gl.RGBA,          // Specifies the format of the data we're providing
-		gl.UNSIGNED_BYTE, // The format of each component of the data
-		gl.Ptr(rgba.Pix)) // The data
+		gl.TEXTURE_2D,       // The openGL Target
+		0,                   // LOD Level (used if doing custom MipMaps)
+		data.InternalFormat, // Specifies the format of the texture in GPU
+		data.Width,          // Width of the image in pixels
+		data.Height,         // Height of the image in pixels
+		0,                   // border. Don't know wtf this does. Spec says "must be 0"
+		data.Format,         // Specifies the format of the data we're providing
+		data.DataType,       // The format of each component of the data
+		gl.Ptr(data.Pixels)) // The data
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+
+	if tex.GenerateMipMaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+	}
+	tex.UnBindFromSlot(gl.TEXTURE0)
+
+	// Mipmaps are only sampled with a mipmap filter
+	if tex.GenerateMipMaps {
+		switch tex.minFilterMode {
+		case gl.LINEAR:
+			tex.SetMinFilterMode(gl.LINEAR_MIPMAP_LINEAR)
+		case gl.NEAREST:
+			tex.SetMinFilterMode(gl.NEAREST_MIPMAP_NEAREST)
+		}
+	}
 
-	// Bookkeeping
-	tex.lastLoadedPath = tex.SourceFilePath
+	tex.Width = data.Width
+	tex.Height = data.Height
 	tex.IsLoaded = true
-
 	return nil
 }
+
+// Anisotropy is the getter for the anisotropic filtering level. 1 is off
+func (tex *Texture) Anisotropy() float32 {
+	if tex.anisotropy < 1 {
+		return 1
+	}
+	return tex.anisotropy
+}
+
+// SetAnisotropy sets the anisotropic filtering level, which keeps textures sharp when viewed at an angle. Levels are
+// clamped to what the GPU supports and ignored if it doesn't support anisotropic filtering at all
+func (tex *Texture) SetAnisotropy(level float32) {
+	maxLevel := MaxAnisotropy()
+	if level > maxLevel {
+		level = maxLevel
+	}
+	if level < 1 {
+		level = 1
+	}
+	tex.anisotropy = level
+	if maxLevel <= 1 {
+		return
+	}
+
+	tex.BindToSlot(gl.TEXTURE0)
+	defer tex.UnBindFromSlot(gl.TEXTURE0)
+	gl.TexParameterf(tex.textureTarget(), textureMaxAnisotropy, level)
+}
+
+// MaxAnisotropy returns the highest anisotropic filtering level the GPU supports, or 1 if it isn't supported
+func MaxAnisotropy() float32 {
+	if maxAnisotropy == 0 {
+		maxAnisotropy = 1
+		if HasExtension("GL_EXT_texture_filter_anisotropic") || HasExtension("GL_ARB_texture_filter_anisotropic") {
+			gl.GetFloatv(maxTextureMaxAnisotropy, &maxAnisotropy)
+		}
+	}
+	return maxAnisotropy
+}
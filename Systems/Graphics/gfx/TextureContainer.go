@@ -0,0 +1,247 @@
+package gfx
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// Compressed formats that aren't part of core 3.2 so the bindings don't have their enums
+const (
+	compressedRGBS3TCDXT1        uint32 = 0x83F0 // GL_COMPRESSED_RGB_S3TC_DXT1_EXT
+	compressedRGBAS3TCDXT1       uint32 = 0x83F1 // GL_COMPRESSED_RGBA_S3TC_DXT1_EXT
+	compressedRGBAS3TCDXT3       uint32 = 0x83F2 // GL_COMPRESSED_RGBA_S3TC_DXT3_EXT
+	compressedRGBAS3TCDXT5       uint32 = 0x83F3 // GL_COMPRESSED_RGBA_S3TC_DXT5_EXT
+	compressedSRGBS3TCDXT1       uint32 = 0x8C4C // GL_COMPRESSED_SRGB_S3TC_DXT1_EXT
+	compressedSRGBAlphaS3TCDXT1  uint32 = 0x8C4D // GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT
+	compressedSRGBAlphaS3TCDXT3  uint32 = 0x8C4E // GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT
+	compressedSRGBAlphaS3TCDXT5  uint32 = 0x8C4F // GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT
+	compressedRGBABPTCUnorm      uint32 = 0x8E8C // GL_COMPRESSED_RGBA_BPTC_UNORM
+	compressedSRGBAlphaBPTCUnorm uint32 = 0x8E8D // GL_COMPRESSED_SRGB_ALPHA_BPTC_UNORM
+	compressedRGBBPTCSignedFloat uint32 = 0x8E8E // GL_COMPRESSED_RGB_BPTC_SIGNED_FLOAT
+	compressedRGBBPTCFloat       uint32 = 0x8E8F // GL_COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT
+)
+
+// textureContainerMaxSize is the widest or tallest image a container can hold, since openGL takes sizes as int32
+const textureContainerMaxSize = 1<<31 - 1
+
+// TextureContainer is the contents of a texture container file such as KTX or DDS, with the pixels still in whatever
+// format, compressed or not, the file stored them in
+type TextureContainer struct {
+	Width          int32                   // Width in pixels of the first mip level
+	Height         int32                   // Height in pixels of the first mip level
+	Layers         int                     // Number of array layers. 1 for regular textures
+	Faces          int                     // 6 for cubemaps, otherwise 1
+	InternalFormat uint32                  // The GPU format, compressed or not
+	Format         uint32                  // The pixel format of uncompressed data. 0 for compressed
+	DataType       uint32                  // The component type of uncompressed data. 0 for compressed
+	Compressed     bool                    // If true the data is uploaded with glCompressedTexImage
+	RowAlignment   int32                   // The byte alignment of each row of uncompressed data
+	Levels         []TextureContainerLevel // The mip levels, largest first
+}
+
+// TextureContainerLevel is one mip level of a TextureContainer
+type TextureContainerLevel struct {
+	Width  int32    // Width in pixels
+	Height int32    // Height in pixels
+	Images [][]byte // One image per layer and face, indexed layer * Faces + face
+}
+
+// IsTextureContainerFile returns true if path has the extension of a texture container file
+func IsTextureContainerFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ktx", ".dds":
+		return true
+	default:
+		return false
+	}
+}
+
+// ReadTextureContainerFile reads a .ktx or .dds file
+func ReadTextureContainerFile(path string) (*TextureContainer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ktx":
+		return ParseKTX(data)
+	case ".dds":
+		return ParseDDS(data)
+	default:
+		return nil, fmt.Errorf("Unsupported Texture Container: %v", filepath.Ext(path))
+	}
+}
+
+// Target returns the texture target the container's data belongs in
+func (container *TextureContainer) Target() uint32 {
+	if container.Faces == 6 {
+		return gl.TEXTURE_CUBE_MAP
+	}
+	if container.Layers > 1 {
+		return gl.TEXTURE_2D_ARRAY
+	}
+	return gl.TEXTURE_2D
+}
+
+// Supported returns an error if the GPU can't use the container's format
+func (container *TextureContainer) Supported() error {
+	if container.Faces == 6 && container.Layers > 1 {
+		return errors.New("Unsupported Texture Container: Cubemap arrays need openGL 4")
+	}
+	if !container.Compressed {
+		return nil
+	}
+
+	switch container.InternalFormat {
+	case compressedRGBS3TCDXT1, compressedRGBAS3TCDXT1, compressedRGBAS3TCDXT3, compressedRGBAS3TCDXT5:
+		if !HasExtension("GL_EXT_texture_compression_s3tc") {
+			return errors.New("Unsupported Texture Format: The GPU doesn't support S3TC (DXT/BC1-3) compression")
+		}
+	case compressedSRGBS3TCDXT1, compressedSRGBAlphaS3TCDXT1, compressedSRGBAlphaS3TCDXT3, compressedSRGBAlphaS3TCDXT5:
+		if !HasExtension("GL_EXT_texture_compression_s3tc") || !(HasExtension("GL_EXT_texture_sRGB") || HasExtension("GL_EXT_texture_compression_s3tc_srgb")) {
+			return errors.New("Unsupported Texture Format: The GPU doesn't support sRGB S3TC (DXT/BC1-3) compression")
+		}
+	case compressedRGBABPTCUnorm, compressedSRGBAlphaBPTCUnorm, compressedRGBBPTCSignedFloat, compressedRGBBPTCFloat:
+		if !HasExtension("GL_ARB_texture_compression_bptc") {
+			return errors.New("Unsupported Texture Format: The GPU doesn't support BPTC (BC6H/BC7) compression")
+		}
+	case gl.COMPRESSED_RED_RGTC1, gl.COMPRESSED_SIGNED_RED_RGTC1, gl.COMPRESSED_RG_RGTC2, gl.COMPRESSED_SIGNED_RG_RGTC2:
+		// Core since 3.0
+	default:
+		return fmt.Errorf("Unsupported Texture Format: Unknown compressed format 0x%X", container.InternalFormat)
+	}
+	return nil
+}
+
+// UploadContainer sends every mip level, layer and face in container to the texture. The texture's Target must
+// match container.Target()
+func (tex *Texture) UploadContainer(container *TextureContainer) error {
+	if err := container.Supported(); err != nil {
+		return err
+	}
+	target := container.Target()
+	if tex.textureTarget() != target {
+		return errors.New("Invalid Texture Container: The texture's target doesn't match the container, i.e. a cubemap file in a 2D texture")
+	}
+	if len(container.Levels) == 0 {
+		return errors.New("Invalid Texture Container: No image data")
+	}
+
+	tex.regenerate()
+	if err := tex.BindToSlot(gl.TEXTURE0); err != nil {
+		return err
+	}
+	if container.RowAlignment > 0 {
+		gl.PixelStorei(gl.UNPACK_ALIGNMENT, container.RowAlignment)
+	}
+	for level, mip := range container.Levels {
+		switch target {
+		case gl.TEXTURE_2D_ARRAY:
+			// Array layers are uploaded together as one block
+			var layers []byte
+			for _, image := range mip.Images {
+				layers = append(layers, image...)
+			}
+			if container.Compressed {
+				gl.CompressedTexImage3D(target, int32(level), container.InternalFormat, mip.Width, mip.Height, int32(container.Layers), 0, int32(len(layers)), gl.Ptr(layers))
+			} else {
+				gl.TexImage3D(target, int32(level), int32(container.InternalFormat), mip.Width, mip.Height, int32(container.Layers), 0, container.Format, container.DataType, gl.Ptr(layers))
+			}
+		default:
+			for face, image := range mip.Images {
+				faceTarget := target
+				if target == gl.TEXTURE_CUBE_MAP {
+					faceTarget = uint32(gl.TEXTURE_CUBE_MAP_POSITIVE_X + face)
+				}
+				if container.Compressed {
+					gl.CompressedTexImage2D(faceTarget, int32(level), container.InternalFormat, mip.Width, mip.Height, 0, int32(len(image)), gl.Ptr(image))
+				} else {
+					gl.TexImage2D(faceTarget, int32(level), int32(container.InternalFormat), mip.Width, mip.Height, 0, container.Format, container.DataType, gl.Ptr(image))
+				}
+			}
+		}
+	}
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+
+	// Only sample the levels the file had, unless we're asked to fill the rest in
+	levels := int32(len(container.Levels))
+	generate := tex.GenerateMipMaps && levels == 1 && !container.Compressed
+	if generate {
+		gl.GenerateMipmap(target)
+	} else {
+		gl.TexParameteri(target, gl.TEXTURE_MAX_LEVEL, levels-1)
+	}
+	tex.UnBindFromSlot(gl.TEXTURE0)
+
+	if generate || levels > 1 {
+		switch tex.minFilterMode {
+		case gl.LINEAR:
+			tex.SetMinFilterMode(gl.LINEAR_MIPMAP_LINEAR)
+		case gl.NEAREST:
+			tex.SetMinFilterMode(gl.NEAREST_MIPMAP_NEAREST)
+		}
+	}
+
+	tex.Width = container.Width
+	tex.Height = container.Height
+	tex.IsLoaded = true
+	return nil
+}
+
+// retarget replaces the GL texture with a fresh one for target, keeping the wrap and filter settings. A texture's
+// target is fixed the first time it's bound, so this is the only way to turn a 2D texture into a cubemap
+func (tex *Texture) retarget(target uint32) {
+	if tex.textureTarget() == target {
+		return
+	}
+	tex.Delete()
+	tex.Target = target
+	tex.regenerate()
+}
+
+// compressedBlockSize returns the bytes per 4x4 block of a compressed format
+func compressedBlockSize(internalFormat uint32) int {
+	switch internalFormat {
+	case compressedRGBS3TCDXT1, compressedRGBAS3TCDXT1, compressedSRGBS3TCDXT1, compressedSRGBAlphaS3TCDXT1,
+		gl.COMPRESSED_RED_RGTC1, gl.COMPRESSED_SIGNED_RED_RGTC1:
+		return 8
+	default:
+		return 16
+	}
+}
+
+// compressedImageSize returns the bytes in one compressed image
+func compressedImageSize(internalFormat uint32, width int32, height int32) int {
+	return (int(width) + 3) / 4 * ((int(height) + 3) / 4) * compressedBlockSize(internalFormat)
+}
+
+// containerLevelCount returns how many mip levels to read from a file that says it has levels. 0 means only the first
+// level is stored, and anything past a full mip chain for width by height is ignored
+func containerLevelCount(levels uint32, width int32, height int32) int {
+	if levels == 0 {
+		return 1
+	}
+	size := width
+	if height > size {
+		size = height
+	}
+	if full := mipCount(size); levels > uint32(full) {
+		return int(full)
+	}
+	return int(levels)
+}
+
+// mipDimension returns the size of a mip level, which never goes below 1
+func mipDimension(size int32, level int) int32 {
+	size >>= uint(level)
+	if size < 1 {
+		return 1
+	}
+	return size
+}
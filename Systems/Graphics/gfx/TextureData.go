@@ -0,0 +1,267 @@
+package gfx
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// TextureChannels picks how many channels a texture is stored with on the GPU
+type TextureChannels int
+
+// Enum values for TextureChannels
+const (
+	ChannelsRGBA TextureChannels = iota // Always store red, green, blue and alpha. Grayscale is copied into each color
+	ChannelsAuto                        // Store as many channels as the image has, i.e. red only for grayscale images
+	ChannelsR                           // Store red only. Grayscale images store their gray
+	ChannelsRG                          // Store red and green, i.e. for two channel normal maps
+	ChannelsRGB                         // Store red, green and blue, dropping alpha
+)
+
+// TextureData is decoded pixels laid out ready to upload to a texture
+type TextureData struct {
+	Width          int32       // Width in pixels
+	Height         int32       // Height in pixels
+	InternalFormat int32       // The sized format on the GPU, i.e. gl.RGBA8
+	Format         uint32      // The channels in Pixels, i.e. gl.RGBA
+	DataType       uint32      // The type of each channel in Pixels, i.e. gl.UNSIGNED_BYTE
+	Pixels         interface{} // Tightly packed []uint8, []uint16 or []float32
+}
+
+// CreateTextureData converts a decoded image into TextureData. 8 bit images stay 8 bit, 16 bit images stay 16 bit
+// and RGBFloatImages are stored as half floats. srgb only applies to 8 bit color. If flipY is set the bottom row comes
+// first, which is what openGL expects for regular textures, cubemap faces are not flipped. Premultiplied RGBA and RGBA64
+// images are converted to straight alpha like every other image
+func CreateTextureData(img image.Image, channels TextureChannels, srgb bool, flipY bool) (*TextureData, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("Invalid Texture: Image has no pixels")
+	}
+
+	data := new(TextureData)
+	data.Width = int32(width)
+	data.Height = int32(height)
+
+	switch source := img.(type) {
+	case *RGBFloatImage:
+		count := outputChannels(channels, 3)
+		data.Pixels = convertFloat(source.Pix[source.PixOffset(bounds.Min.X, bounds.Min.Y):], source.Stride, 3, width, height, count, flipY)
+		data.DataType = gl.FLOAT
+		data.Format, data.InternalFormat = formatsFor(count, [4]int32{gl.R16F, gl.RG16F, gl.RGB16F, gl.RGBA16F})
+	case *image.RGBA64:
+		// Premultiplied, unlike every other path
+		nrgba := unpremultiply64(source)
+		count := outputChannels(channels, 4)
+		data.Pixels = convert16(nrgba.Pix, nrgba.Stride, 4, width, height, count, flipY)
+		data.DataType = gl.UNSIGNED_SHORT
+		data.Format, data.InternalFormat = formatsFor(count, [4]int32{gl.R16, gl.RG16, gl.RGB16, gl.RGBA16})
+	case *image.NRGBA64:
+		count := outputChannels(channels, 4)
+		data.Pixels = convert16(source.Pix[source.PixOffset(bounds.Min.X, bounds.Min.Y):], source.Stride, 4, width, height, count, flipY)
+		data.DataType = gl.UNSIGNED_SHORT
+		data.Format, data.InternalFormat = formatsFor(count, [4]int32{gl.R16, gl.RG16, gl.RGB16, gl.RGBA16})
+	case *image.Gray16:
+		count := outputChannels(channels, 1)
+		data.Pixels = convert16(source.Pix[source.PixOffset(bounds.Min.X, bounds.Min.Y):], source.Stride, 1, width, height, count, flipY)
+		data.DataType = gl.UNSIGNED_SHORT
+		data.Format, data.InternalFormat = formatsFor(count, [4]int32{gl.R16, gl.RG16, gl.RGB16, gl.RGBA16})
+	default:
+		var pix []uint8
+		var stride, sourceCount int
+		switch source := img.(type) {
+		case *image.Gray:
+			pix, stride, sourceCount = source.Pix[source.PixOffset(bounds.Min.X, bounds.Min.Y):], source.Stride, 1
+		case *image.RGBA:
+			if source.Opaque() {
+				pix, stride, sourceCount = source.Pix[source.PixOffset(bounds.Min.X, bounds.Min.Y):], source.Stride, 4
+			} else {
+				// Premultiplied, so translucent pixels need to be made straight like every other path
+				nrgba := unpremultiply(source)
+				pix, stride, sourceCount = nrgba.Pix, nrgba.Stride, 4
+			}
+		case *image.NRGBA:
+			pix, stride, sourceCount = source.Pix[source.PixOffset(bounds.Min.X, bounds.Min.Y):], source.Stride, 4
+		default:
+			// Anything else, i.e. JPEG's YCbCr or paletted images, goes through the slow generic conversion
+			nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+			draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+			pix, stride, sourceCount = nrgba.Pix, nrgba.Stride, 4
+		}
+
+		count := outputChannels(channels, sourceCount)
+		data.Pixels = convert8(pix, stride, sourceCount, width, height, count, flipY)
+		data.DataType = gl.UNSIGNED_BYTE
+		if srgb {
+			data.Format, data.InternalFormat = formatsFor(count, [4]int32{gl.R8, gl.RG8, gl.SRGB8, gl.SRGB8_ALPHA8})
+		} else {
+			data.Format, data.InternalFormat = formatsFor(count, [4]int32{gl.R8, gl.RG8, gl.RGB8, gl.RGBA8})
+		}
+	}
+
+	return data, nil
+}
+
+// unpremultiply converts a premultiplied RGBA image to straight alpha, rounding the same way image/color does
+func unpremultiply(img *image.RGBA) *image.NRGBA {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		row := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+		outRow := out.Pix[y*out.Stride:]
+		for i := 0; i < bounds.Dx()*4; i += 4 {
+			a := uint32(row[i+3])
+			outRow[i+3] = uint8(a)
+			if a == 0 {
+				continue
+			}
+			for c := 0; c < 3; c++ {
+				outRow[i+c] = uint8((uint32(row[i+c]) * 0xFFFF / a) >> 8)
+			}
+		}
+	}
+	return out
+}
+
+// unpremultiply64 is unpremultiply for 16 bit images
+func unpremultiply64(img *image.RGBA64) *image.NRGBA64 {
+	bounds := img.Bounds()
+	out := image.NewNRGBA64(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		row := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+		outRow := out.Pix[y*out.Stride:]
+		for i := 0; i < bounds.Dx()*8; i += 8 {
+			a := uint32(row[i+6])<<8 | uint32(row[i+7])
+			outRow[i+6], outRow[i+7] = row[i+6], row[i+7]
+			if a == 0 {
+				continue
+			}
+			for c := 0; c < 6; c += 2 {
+				value := (uint32(row[i+c])<<8 | uint32(row[i+c+1])) * 0xFFFF / a
+				outRow[i+c], outRow[i+c+1] = uint8(value>>8), uint8(value)
+			}
+		}
+	}
+	return out
+}
+
+// outputChannels returns how many channels to store for an image with sourceCount channels
+func outputChannels(channels TextureChannels, sourceCount int) int {
+	switch channels {
+	case ChannelsAuto:
+		return sourceCount
+	case ChannelsR:
+		return 1
+	case ChannelsRG:
+		return 2
+	case ChannelsRGB:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// formatsFor picks the pixel format and internal format for count channels from the internal formats for 1 to 4
+func formatsFor(count int, internalFormats [4]int32) (uint32, int32) {
+	pixelFormats := [4]uint32{gl.RED, gl.RG, gl.RGB, gl.RGBA}
+	return pixelFormats[count-1], internalFormats[count-1]
+}
+
+// sourceChannel returns which source channel fills output channel, or -1 for an opaque alpha. Gray is spread across
+// red, green and blue
+func sourceChannel(channel int, sourceCount int) int {
+	if channel < sourceCount {
+		if sourceCount == 1 && channel > 0 {
+			return 0
+		}
+		return channel
+	}
+	if sourceCount == 1 && channel < 3 {
+		return 0
+	}
+	if channel == 3 {
+		return -1
+	}
+	return 0
+}
+
+// convert8 repacks 8 bit pixels tightly with count channels per pixel
+func convert8(pix []uint8, stride int, sourceCount int, width int, height int, count int, flipY bool) []uint8 {
+	out := make([]uint8, width*height*count)
+	rowLength := width * count
+	for y := 0; y < height; y++ {
+		row := pix[y*stride:]
+		outRow := out[outputRow(y, height, flipY)*rowLength:]
+
+		// Same layout means we can copy the whole row
+		if sourceCount == count {
+			copy(outRow[:rowLength], row[:rowLength])
+			continue
+		}
+		for x := 0; x < width; x++ {
+			for c := 0; c < count; c++ {
+				if from := sourceChannel(c, sourceCount); from >= 0 {
+					outRow[x*count+c] = row[x*sourceCount+from]
+				} else {
+					outRow[x*count+c] = 0xFF
+				}
+			}
+		}
+	}
+	return out
+}
+
+// convert16 repacks 16 bit big endian pixels, as the image package stores them, into native uint16s
+func convert16(pix []uint8, stride int, sourceCount int, width int, height int, count int, flipY bool) []uint16 {
+	out := make([]uint16, width*height*count)
+	rowLength := width * count
+	for y := 0; y < height; y++ {
+		row := pix[y*stride:]
+		outRow := out[outputRow(y, height, flipY)*rowLength:]
+		for x := 0; x < width; x++ {
+			for c := 0; c < count; c++ {
+				if from := sourceChannel(c, sourceCount); from >= 0 {
+					i := (x*sourceCount + from) * 2
+					outRow[x*count+c] = uint16(row[i])<<8 | uint16(row[i+1])
+				} else {
+					outRow[x*count+c] = 0xFFFF
+				}
+			}
+		}
+	}
+	return out
+}
+
+// convertFloat repacks float pixels with count channels per pixel
+func convertFloat(pix []float32, stride int, sourceCount int, width int, height int, count int, flipY bool) []float32 {
+	out := make([]float32, width*height*count)
+	rowLength := width * count
+	for y := 0; y < height; y++ {
+		row := pix[y*stride:]
+		outRow := out[outputRow(y, height, flipY)*rowLength:]
+		if sourceCount == count {
+			copy(outRow[:rowLength], row[:rowLength])
+			continue
+		}
+		for x := 0; x < width; x++ {
+			for c := 0; c < count; c++ {
+				if from := sourceChannel(c, sourceCount); from >= 0 {
+					outRow[x*count+c] = row[x*sourceCount+from]
+				} else {
+					outRow[x*count+c] = 1
+				}
+			}
+		}
+	}
+	return out
+}
+
+// outputRow returns which row of the output source row y goes to
+func outputRow(y int, height int, flipY bool) int {
+	if flipY {
+		return height - 1 - y
+	}
+	return y
+}
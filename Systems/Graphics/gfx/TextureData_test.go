@@ -0,0 +1,244 @@
+package gfx
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/go-gl/gl/v3.2-core/gl"
+)
+
+// textureDataTestValue is channel c of pixel i of the 2x2 test images, as 8 bits
+func textureDataTestValue(i int, c int) uint8 {
+	return uint8(40*i + 10*c + 5)
+}
+
+// textureDataTestSource is a 2x2 image of one of the types CreateTextureData has a fast path for
+type textureDataTestSource struct {
+	name            string
+	image           image.Image
+	channels        int                    // How many channels the image has
+	opaque          float64                // The value of an opaque alpha
+	value           func(i, c int) float64 // The value stored for channel c of pixel i
+	dataType        uint32
+	internalFormats [4]int32 // For 1 to 4 channels
+}
+
+// textureDataTestSources returns one image of every type with a fast path, and a paletted image for the generic one
+func textureDataTestSources() []textureDataTestSource {
+	rect := image.Rect(0, 0, 2, 2)
+	value8 := func(i, c int) float64 { return float64(textureDataTestValue(i, c)) }
+	value16 := func(i, c int) float64 { return float64(uint16(textureDataTestValue(i, c))<<8 | uint16(c+1)) }
+	formats8 := [4]int32{gl.R8, gl.RG8, gl.RGB8, gl.RGBA8}
+	formats16 := [4]int32{gl.R16, gl.RG16, gl.RGB16, gl.RGBA16}
+
+	rgba, nrgba, gray := image.NewRGBA(rect), image.NewNRGBA(rect), image.NewGray(rect)
+	rgba64, nrgba64, gray16 := image.NewRGBA64(rect), image.NewNRGBA64(rect), image.NewGray16(rect)
+	float := NewRGBFloatImage(rect)
+	palette := color.Palette{}
+	paletted := image.NewPaletted(rect, nil)
+	for i := 0; i < 4; i++ {
+		x, y := i%2, i/2
+		v := func(c int) uint8 { return textureDataTestValue(i, c) }
+		w := func(c int) uint16 { return uint16(value16(i, c)) }
+		// Premultiplied images are opaque so their values are the same straight or not
+		rgba.SetRGBA(x, y, color.RGBA{v(0), v(1), v(2), 0xFF})
+		nrgba.SetNRGBA(x, y, color.NRGBA{v(0), v(1), v(2), v(3)})
+		gray.SetGray(x, y, color.Gray{v(0)})
+		rgba64.SetRGBA64(x, y, color.RGBA64{w(0), w(1), w(2), 0xFFFF})
+		nrgba64.SetNRGBA64(x, y, color.NRGBA64{w(0), w(1), w(2), w(3)})
+		gray16.SetGray16(x, y, color.Gray16{w(0)})
+		for c := 0; c < 3; c++ {
+			float.Pix[float.PixOffset(x, y)+c] = float32(v(c)) / 8
+		}
+		palette = append(palette, color.NRGBA{v(0), v(1), v(2), 0xFF})
+		paletted.Pix[paletted.PixOffset(x, y)] = uint8(i)
+	}
+	paletted.Palette = palette
+	opaqueAlpha := func(value func(i, c int) float64, opaque float64) func(i, c int) float64 {
+		return func(i, c int) float64 {
+			if c == 3 {
+				return opaque
+			}
+			return value(i, c)
+		}
+	}
+
+	return []textureDataTestSource{
+		{"RGBA", rgba, 4, 0xFF, opaqueAlpha(value8, 0xFF), gl.UNSIGNED_BYTE, formats8},
+		{"NRGBA", nrgba, 4, 0xFF, value8, gl.UNSIGNED_BYTE, formats8},
+		{"Gray", gray, 1, 0xFF, value8, gl.UNSIGNED_BYTE, formats8},
+		{"RGBA64", rgba64, 4, 0xFFFF, opaqueAlpha(value16, 0xFFFF), gl.UNSIGNED_SHORT, formats16},
+		{"NRGBA64", nrgba64, 4, 0xFFFF, value16, gl.UNSIGNED_SHORT, formats16},
+		{"Gray16", gray16, 1, 0xFFFF, value16, gl.UNSIGNED_SHORT, formats16},
+		{"RGBFloat", float, 3, 1, func(i, c int) float64 { return value8(i, c) / 8 }, gl.FLOAT, [4]int32{gl.R16F, gl.RG16F, gl.RGB16F, gl.RGBA16F}},
+		{"Paletted", paletted, 4, 0xFF, opaqueAlpha(value8, 0xFF), gl.UNSIGNED_BYTE, formats8},
+	}
+}
+
+// textureDataPixels returns the data's pixels as float64s
+func textureDataPixels(data *TextureData) []float64 {
+	var values []float64
+	switch pixels := data.Pixels.(type) {
+	case []uint8:
+		for _, value := range pixels {
+			values = append(values, float64(value))
+		}
+	case []uint16:
+		for _, value := range pixels {
+			values = append(values, float64(value))
+		}
+	case []float32:
+		for _, value := range pixels {
+			values = append(values, float64(value))
+		}
+	}
+	return values
+}
+
+func TestCreateTextureData(t *testing.T) {
+	tests := []struct {
+		channels TextureChannels
+		count    int // How many channels are stored, 0 for as many as the image has
+	}{
+		{ChannelsRGBA, 4},
+		{ChannelsAuto, 0},
+		{ChannelsR, 1},
+		{ChannelsRG, 2},
+		{ChannelsRGB, 3},
+	}
+	pixelFormats := [4]uint32{gl.RED, gl.RG, gl.RGB, gl.RGBA}
+
+	for _, source := range textureDataTestSources() {
+		for _, test := range tests {
+			name := fmt.Sprintf("%v with channels %v", source.name, test.channels)
+			count := test.count
+			if count == 0 {
+				count = source.channels
+			}
+			data, err := CreateTextureData(source.image, test.channels, false, false)
+			if err != nil {
+				t.Errorf("%v: got error %v", name, err)
+				continue
+			}
+			if data.Width != 2 || data.Height != 2 || data.DataType != source.dataType ||
+				data.Format != pixelFormats[count-1] || data.InternalFormat != source.internalFormats[count-1] {
+				t.Errorf("%v: got %vx%v, type %x, format %x and internal format %x, expected 2x2, %x, %x and %x", name,
+					data.Width, data.Height, data.DataType, data.Format, data.InternalFormat, source.dataType, pixelFormats[count-1], source.internalFormats[count-1])
+			}
+
+			var expected []float64
+			for i := 0; i < 4; i++ {
+				for c := 0; c < count; c++ {
+					switch {
+					case c < source.channels:
+						// Gray spreads across red, green and blue
+						if source.channels == 1 {
+							expected = append(expected, source.value(i, 0))
+						} else {
+							expected = append(expected, source.value(i, c))
+						}
+					case c == 3:
+						expected = append(expected, source.opaque)
+					default:
+						expected = append(expected, source.value(i, 0))
+					}
+				}
+			}
+			if got := textureDataPixels(data); fmt.Sprint(got) != fmt.Sprint(expected) {
+				t.Errorf("%v: got pixels %v, expected %v", name, got, expected)
+			}
+		}
+	}
+}
+
+func TestCreateTextureDataSRGB(t *testing.T) {
+	for _, source := range textureDataTestSources() {
+		data, err := CreateTextureData(source.image, ChannelsRGBA, true, false)
+		if err != nil {
+			t.Errorf("%v: got error %v", source.name, err)
+			continue
+		}
+		// Only 8 bit color is stored as sRGB
+		expected := source.internalFormats[3]
+		if source.dataType == gl.UNSIGNED_BYTE {
+			expected = gl.SRGB8_ALPHA8
+		}
+		if data.InternalFormat != expected {
+			t.Errorf("%v: got internal format %x, expected %x", source.name, data.InternalFormat, expected)
+		}
+	}
+}
+
+func TestCreateTextureDataFlipY(t *testing.T) {
+	// 3 rows, so an off by one in the flip would repeat or drop the middle row, within a larger image so the stride
+	// and offset matter too
+	whole := image.NewNRGBA(image.Rect(0, 0, 4, 5))
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 4; x++ {
+			whole.SetNRGBA(x, y, color.NRGBA{uint8(y), uint8(x), 0, 0xFF})
+		}
+	}
+	sub := whole.SubImage(image.Rect(1, 1, 3, 4))
+
+	tests := []struct {
+		flipY bool
+		rows  []uint8 // The source row each output row came from
+	}{
+		{false, []uint8{1, 2, 3}},
+		{true, []uint8{3, 2, 1}},
+	}
+	for _, test := range tests {
+		data, err := CreateTextureData(sub, ChannelsR, false, test.flipY)
+		if err != nil {
+			t.Fatalf("flip %v: got error %v", test.flipY, err)
+		}
+		pixels := data.Pixels.([]uint8)
+		if data.Width != 2 || data.Height != 3 || len(pixels) != 6 {
+			t.Fatalf("flip %v: got %vx%v with %v values, expected 2x3 with 6", test.flipY, data.Width, data.Height, len(pixels))
+		}
+		for y, row := range test.rows {
+			if pixels[y*2] != row || pixels[y*2+1] != row {
+				t.Errorf("flip %v: got pixels %v, expected rows from %v", test.flipY, pixels, test.rows)
+				break
+			}
+		}
+	}
+}
+
+func TestCreateTextureDataPremultipliedAlpha(t *testing.T) {
+	rect := image.Rect(0, 0, 4, 1)
+	colors := []color.NRGBA{{200, 100, 50, 128}, {255, 255, 255, 1}, {10, 20, 30, 0}, {90, 180, 255, 254}}
+	premultiplied, premultiplied64 := image.NewRGBA(rect), image.NewRGBA64(rect)
+	straight, straight64 := image.NewNRGBA(rect), image.NewNRGBA64(rect)
+	for x, c := range colors {
+		premultiplied.Set(x, 0, c)
+		premultiplied64.Set(x, 0, c)
+		// Premultiplying loses some precision, so expect what the standard library gets back
+		straight.Set(x, 0, color.NRGBAModel.Convert(premultiplied.At(x, 0)))
+		straight64.Set(x, 0, color.NRGBA64Model.Convert(premultiplied64.At(x, 0)))
+	}
+
+	tests := []struct {
+		name          string
+		premultiplied image.Image
+		straight      image.Image
+	}{
+		{"8 bit", premultiplied, straight},
+		{"16 bit", premultiplied64, straight64},
+	}
+	for _, test := range tests {
+		got, err := CreateTextureData(test.premultiplied, ChannelsRGBA, false, false)
+		if err != nil {
+			t.Fatalf("%v: got error %v", test.name, err)
+		}
+		expected, err := CreateTextureData(test.straight, ChannelsRGBA, false, false)
+		if err != nil {
+			t.Fatalf("%v: got error %v", test.name, err)
+		}
+		if fmt.Sprint(got.Pixels) != fmt.Sprint(expected.Pixels) {
+			t.Errorf("%v: got premultiplied pixels %v, expected them straight like %v", test.name, got.Pixels, expected.Pixels)
+		}
+	}
+}
@@ -7,6 +7,12 @@ import (
 	"github.com/go-gl/gl/v3.2-core/gl"
 )
 
+// extensions caches the names of the extensions the GPU supports. nil until first queried
+var extensions map[string]bool
+
+// maxTextureSize caches GL_MAX_TEXTURE_SIZE. 0 until first queried
+var maxTextureSize int32
+
 // Uint32MaxValue is the max value for uint32
 const Uint32MaxValue int = 65535
 
@@ -72,6 +78,56 @@ func IsTextureType(glType uint32) bool {
 	}
 }
 
+// TextureTargetForType takes a gl sampler type and returns the texture target a texture needs to be bound to for the
+// sampler to see it, or 0 if glType isn't a sampler
+func TextureTargetForType(glType uint32) uint32 {
+	switch glType {
+	case gl.SAMPLER_2D, gl.SAMPLER_2D_SHADOW, gl.INT_SAMPLER_2D, gl.UNSIGNED_INT_SAMPLER_2D:
+		return gl.TEXTURE_2D
+	case gl.SAMPLER_CUBE, gl.SAMPLER_CUBE_SHADOW, gl.INT_SAMPLER_CUBE, gl.UNSIGNED_INT_SAMPLER_CUBE:
+		return gl.TEXTURE_CUBE_MAP
+	case gl.SAMPLER_1D, gl.SAMPLER_1D_SHADOW, gl.INT_SAMPLER_1D, gl.UNSIGNED_INT_SAMPLER_1D:
+		return gl.TEXTURE_1D
+	case gl.SAMPLER_3D, gl.INT_SAMPLER_3D, gl.UNSIGNED_INT_SAMPLER_3D:
+		return gl.TEXTURE_3D
+	case gl.SAMPLER_1D_ARRAY, gl.SAMPLER_1D_ARRAY_SHADOW, gl.INT_SAMPLER_1D_ARRAY, gl.UNSIGNED_INT_SAMPLER_1D_ARRAY:
+		return gl.TEXTURE_1D_ARRAY
+	case gl.SAMPLER_2D_ARRAY, gl.SAMPLER_2D_ARRAY_SHADOW, gl.INT_SAMPLER_2D_ARRAY, gl.UNSIGNED_INT_SAMPLER_2D_ARRAY:
+		return gl.TEXTURE_2D_ARRAY
+	case gl.SAMPLER_2D_MULTISAMPLE, gl.INT_SAMPLER_2D_MULTISAMPLE, gl.UNSIGNED_INT_SAMPLER_2D_MULTISAMPLE:
+		return gl.TEXTURE_2D_MULTISAMPLE
+	case gl.SAMPLER_2D_MULTISAMPLE_ARRAY, gl.INT_SAMPLER_2D_MULTISAMPLE_ARRAY, gl.UNSIGNED_INT_SAMPLER_2D_MULTISAMPLE_ARRAY:
+		return gl.TEXTURE_2D_MULTISAMPLE_ARRAY
+	case gl.SAMPLER_BUFFER, gl.INT_SAMPLER_BUFFER, gl.UNSIGNED_INT_SAMPLER_BUFFER:
+		return gl.TEXTURE_BUFFER
+	case gl.SAMPLER_2D_RECT, gl.SAMPLER_2D_RECT_SHADOW, gl.INT_SAMPLER_2D_RECT, gl.UNSIGNED_INT_SAMPLER_2D_RECT:
+		return gl.TEXTURE_RECTANGLE
+	default:
+		return 0
+	}
+}
+
+// HasExtension returns true if the GPU supports the named openGL extension, i.e. "GL_EXT_texture_filter_anisotropic"
+func HasExtension(name string) bool {
+	if extensions == nil {
+		extensions = make(map[string]bool)
+		var count int32
+		gl.GetIntegerv(gl.NUM_EXTENSIONS, &count)
+		for i := int32(0); i < count; i++ {
+			extensions[gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i)))] = true
+		}
+	}
+	return extensions[name]
+}
+
+// MaxTextureSize returns the largest width or height a 2D texture can have on this GPU
+func MaxTextureSize() int32 {
+	if maxTextureSize == 0 {
+		gl.GetIntegerv(gl.MAX_TEXTURE_SIZE, &maxTextureSize)
+	}
+	return maxTextureSize
+}
+
 // BoolToInt32 converts a value of true to 1 and a value of false to 0 for use with OpenGL
 func BoolToInt32(value bool) int32 {
 	if value {
@@ -34,6 +34,26 @@ func (vertexArray *VertexArray) Generate() {
 	gl.GenVertexArrays(1, &vertexArray.ID)
 }
 
+// Delete frees the openGL vertex array along with the buffers of all it's attributes
+func (vertexArray *VertexArray) Delete() {
+	for _, attribute := range vertexArray.Attributes {
+		attribute.DataBuffer.Delete()
+	}
+	vertexArray.Attributes = make(map[string]*VertexAttribute)
+	vertexArray.Count = 0
+
+	if vertexArray.ID <= 0 {
+		return
+	}
+	if CurrentlyBoundVertexArray == vertexArray {
+		// Deleting a bound vertex array reverts to 0, which has no element array either
+		CurrentlyBoundVertexArray = nil
+		CurrentlyBoundVertexIndexArray = nil
+	}
+	gl.DeleteVertexArrays(1, &vertexArray.ID)
+	vertexArray.ID = 0
+}
+
 // Bind will bind the vertex array to the open gl context
 func (vertexArray *VertexArray) Bind() (err error) {
 	if CurrentlyBoundVertexArray == vertexArray {
@@ -47,6 +67,9 @@ func (vertexArray *VertexArray) Bind() (err error) {
 
 	gl.BindVertexArray(vertexArray.ID)
 	CurrentlyBoundVertexArray = vertexArray
+
+	// The element array binding is part of the vertex array's state so whatever we thought was bound no longer is
+	CurrentlyBoundVertexIndexArray = nil
 	return
 }
 
@@ -57,13 +80,23 @@ func (vertexArray *VertexArray) UnBind() {
 	}
 	gl.BindVertexArray(uint32(0))
 	CurrentlyBoundVertexArray = nil
+	CurrentlyBoundVertexIndexArray = nil
 }
 
-// PushVertexAttribute declares the next vertex attribute for this vertex array. Order must match shaders
-// For now, without a customized shader language, there is no way to do order independent implementation. Using
-// something like names, would require shaders use consistant naming schemes, and this is not easily enforcible.
+// PushVertexAttribute declares the next vertex attribute for this vertex array. Attributes named in
+// StandardAttributeLocations always go at their standard location, so shaders find them whatever else the mesh has.
+// Any other attribute takes the location after the highest one used so far, so their order must match shaders
 func (vertexArray *VertexArray) PushVertexAttribute(name string, glType uint32, dimension int32) {
 	attribute := CreateVertexAttribute(name, glType, dimension)
+	if location, ok := StandardAttributeLocations[name]; ok {
+		attribute.Location = location
+	} else {
+		for _, other := range vertexArray.Attributes {
+			if other.Location >= attribute.Location {
+				attribute.Location = other.Location + 1
+			}
+		}
+	}
 
 	vertexArray.Bind()
 	defer vertexArray.UnBind()
@@ -72,8 +105,8 @@ func (vertexArray *VertexArray) PushVertexAttribute(name string, glType uint32,
 	// And define it to the vertex array
 	attribute.DataBuffer.Bind()
 	defer attribute.DataBuffer.UnBind()
-	gl.EnableVertexAttribArray(uint32(len(vertexArray.Attributes)))
-	gl.VertexAttribPointer(uint32(len(vertexArray.Attributes)), attribute.Dimension, attribute.AttributeType, false, int32(attribute.Size()), gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(attribute.Location)
+	gl.VertexAttribPointer(attribute.Location, attribute.Dimension, attribute.AttributeType, false, int32(attribute.Size()), gl.PtrOffset(0))
 
 	// Add it to our map
 	vertexArray.Attributes[attribute.Name] = attribute
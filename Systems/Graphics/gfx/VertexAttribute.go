@@ -6,6 +6,7 @@ type VertexAttribute struct {
 	AttributeType uint32        // AttributeType is a uint32 representing the GLType of the attribute. Use gl lib for these types. i.e. gl.FLOAT
 	Dimension     int32         // Dimension is the dimension of the attribute. For example a 3d position would be dimension 3 and type gl.FLOAT
 	DataBuffer    *VertexBuffer // The VertexBuffer that holds the data for this vertex attribute
+	Location      uint32        // The shader attribute location it's bound to, set when pushed onto a VertexArray
 }
 
 // CreateVertexAttribute is the generic factory for a Vertex Attribute
@@ -30,6 +30,18 @@ func (vb *VertexBuffer) Generate() {
 	gl.GenBuffers(1, &vb.ID)
 }
 
+// Delete frees the openGL buffer. The vertex buffer can be generated again afterwards
+func (vb *VertexBuffer) Delete() {
+	if vb.ID <= 0 {
+		return
+	}
+	if CurrentlyBoundBuffer == vb {
+		CurrentlyBoundBuffer = nil
+	}
+	gl.DeleteBuffers(1, &vb.ID)
+	vb.ID = 0
+}
+
 // Bind binds the vertex buffer to openGL.
 func (vb *VertexBuffer) Bind() (err error) {
 	// If we're already bound, ignore
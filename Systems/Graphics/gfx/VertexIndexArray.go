@@ -31,6 +31,19 @@ func (via *VertexIndexArray) Generate() {
 	gl.GenBuffers(1, &via.ID)
 }
 
+// Delete frees the openGL buffer. The index array can be generated again afterwards
+func (via *VertexIndexArray) Delete() {
+	if via.ID <= 0 {
+		return
+	}
+	if CurrentlyBoundVertexIndexArray == via {
+		CurrentlyBoundVertexIndexArray = nil
+	}
+	gl.DeleteBuffers(1, &via.ID)
+	via.ID = 0
+	via.Count = 0
+}
+
 // Bind binds the index buffer to openGL.
 func (via *VertexIndexArray) Bind() (err error) {
 	// If we're already bound, ignore
@@ -0,0 +1,62 @@
+package resources
+
+import (
+	"github.com/Surreal/Systems/Core/core"
+	"github.com/Surreal/Systems/Graphics/gfx"
+)
+
+// Handle is one reference to a shared resource. Release it when done, the resource is deleted from the GPU once every
+// handle to it is released
+type Handle struct {
+	Owner    *core.Scene // The scene this handle belongs to, nil if it's only released by hand
+	manager  *Manager
+	resource *resource
+	released bool
+}
+
+// FilePath returns the path the resource was loaded from
+func (handle *Handle) FilePath() string {
+	return handle.resource.path
+}
+
+// Released returns true once the handle has been released
+func (handle *Handle) Released() bool {
+	return handle.released
+}
+
+// Release gives up this handle's reference. Releasing twice does nothing
+func (handle *Handle) Release() {
+	if handle.released {
+		return
+	}
+	handle.released = true
+	handle.manager.release(handle)
+}
+
+// TextureHandle is a Handle to a shared texture
+type TextureHandle struct {
+	*Handle
+	Texture *gfx.Texture
+}
+
+// ShaderHandle is a Handle to a shared shader
+type ShaderHandle struct {
+	*Handle
+	Shader *gfx.Shader
+}
+
+// MeshHandle is a Handle to the shared meshes and materials of a mesh file
+type MeshHandle struct {
+	*Handle
+	Meshes    []*gfx.Mesh     // One mesh per group and material in the file
+	Materials []*gfx.Material // The material for each mesh from the file's material library
+}
+
+// Instantiate makes a scene object rendering the meshes with material, or with the file's own materials if material is
+// nil. The meshes are shared so keep the handle until the scene object is gone
+func (handle *MeshHandle) Instantiate(material *gfx.Material) *core.SceneObject {
+	if material == nil {
+		return gfx.CreateMeshSceneObjectWithMaterials(handle.Meshes, handle.Materials)
+	}
+	return gfx.CreateMeshSceneObject(handle.Meshes, material)
+}
@@ -0,0 +1,227 @@
+package resources
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/Surreal/Debug/dbg"
+	"github.com/Surreal/Systems/Core/core"
+	"github.com/Surreal/Systems/Graphics/gfx"
+)
+
+// MainManager is the global resource manager
+var MainManager = CreateManager()
+
+// Kinds of resources the manager loads
+const (
+	KindTexture = "Texture"
+	KindShader  = "Shader"
+	KindMesh    = "Mesh"
+)
+
+// resource is one loaded file shared between handles
+type resource struct {
+	key    string      // Unique per kind and path
+	kind   string      // One of the Kind constants
+	path   string      // The file(s) it was loaded from
+	refs   int         // Handles not yet released
+	value  interface{} // The *gfx.Texture, *gfx.Shader or a *MeshHandle to copy the meshes and materials from
+	delete func()      // Frees the GPU side
+}
+
+// Manager loads textures, shaders and meshes once per path and hands out reference counted handles to them. GPU
+// memory is freed when the last handle is released
+type Manager struct {
+	resources map[string]*resource
+	handles   map[*Handle]bool // Every handle not yet released
+}
+
+// CreateManager is the standard constructor for a Manager
+func CreateManager() *Manager {
+	manager := new(Manager)
+	manager.resources = make(map[string]*resource)
+	manager.handles = make(map[*Handle]bool)
+	return manager
+}
+
+// LoadTexture returns a handle to the texture at filePath, loading it if it isn't already. Every handle shares the
+// same texture, so settings changed on one change them all. owner may be nil
+func (manager *Manager) LoadTexture(filePath string, owner *core.Scene) (*TextureHandle, error) {
+	handle, err := manager.acquire(resourceKey(KindTexture, filePath), owner, func(res *resource) error {
+		res.kind, res.path = KindTexture, filePath
+		texture := gfx.CreateTexture(filePath)
+		if err := texture.Load(); err != nil {
+			texture.Delete()
+			return err
+		}
+		res.value = texture
+		res.delete = texture.Delete
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TextureHandle{Handle: handle, Texture: handle.resource.value.(*gfx.Texture)}, nil
+}
+
+// LoadShader returns a handle to the shader compiled from the two files, compiling it if it isn't already. owner may
+// be nil
+func (manager *Manager) LoadShader(vertexFilePath string, fragmentFilePath string, owner *core.Scene) (*ShaderHandle, error) {
+	handle, err := manager.acquire(resourceKey(KindShader, vertexFilePath, fragmentFilePath), owner, func(res *resource) error {
+		res.kind, res.path = KindShader, vertexFilePath+", "+fragmentFilePath
+		shader, err := gfx.CreateShader(vertexFilePath, fragmentFilePath)
+		if err != nil {
+			return err
+		}
+		res.value = shader
+		res.delete = shader.Delete
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ShaderHandle{Handle: handle, Shader: handle.resource.value.(*gfx.Shader)}, nil
+}
+
+// LoadMesh returns a handle to the meshes in the file at filePath, importing it if it isn't already. Use Instantiate
+// on the handle to make scene objects. owner may be nil
+func (manager *Manager) LoadMesh(filePath string, owner *core.Scene) (*MeshHandle, error) {
+	handle, err := manager.acquire(resourceKey(KindMesh, filePath), owner, func(res *resource) error {
+		res.kind, res.path = KindMesh, filePath
+		meshes, materials, err := gfx.ImportMeshes(filePath)
+		if err != nil {
+			return err
+		}
+		res.value = &MeshHandle{Meshes: meshes, Materials: materials}
+		res.delete = func() {
+			for _, mesh := range meshes {
+				mesh.Delete()
+			}
+			deleteMaterialTextures(materials)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	shared := handle.resource.value.(*MeshHandle)
+	return &MeshHandle{Handle: handle, Meshes: shared.Meshes, Materials: shared.Materials}, nil
+}
+
+// acquire returns a new handle to the resource at key, calling load to fill it in if this is the first
+func (manager *Manager) acquire(key string, owner *core.Scene, load func(res *resource) error) (*Handle, error) {
+	res, ok := manager.resources[key]
+	if !ok {
+		res = new(resource)
+		res.key = key
+		if err := load(res); err != nil {
+			return nil, err
+		}
+		manager.resources[key] = res
+	}
+
+	res.refs++
+	handle := &Handle{Owner: owner, manager: manager, resource: res}
+	manager.handles[handle] = true
+	return handle, nil
+}
+
+// release drops a handle's reference and deletes the resource if it was the last
+func (manager *Manager) release(handle *Handle) {
+	delete(manager.handles, handle)
+	res := handle.resource
+	res.refs--
+	if res.refs > 0 {
+		return
+	}
+	if res.delete != nil {
+		res.delete()
+	}
+	delete(manager.resources, res.key)
+}
+
+// UnloadScene releases every handle owned by scene. Returns how many were released
+func (manager *Manager) UnloadScene(scene *core.Scene) int {
+	var owned []*Handle
+	for handle := range manager.handles {
+		if handle.Owner == scene {
+			owned = append(owned, handle)
+		}
+	}
+	for _, handle := range owned {
+		handle.Release()
+	}
+	return len(owned)
+}
+
+// References returns how many unreleased handles there are to a kind of resource. Shaders take both their file paths
+func (manager *Manager) References(kind string, filePaths ...string) int {
+	if res, ok := manager.resources[resourceKey(kind, filePaths...)]; ok {
+		return res.refs
+	}
+	return 0
+}
+
+// LoadedCount returns how many resources are loaded
+func (manager *Manager) LoadedCount() int {
+	return len(manager.resources)
+}
+
+// Leaks describes every resource that still has unreleased handles, sorted by kind and path
+func (manager *Manager) Leaks() []string {
+	var leaks []string
+	for _, res := range manager.resources {
+		leaks = append(leaks, fmt.Sprintf("%v %v has %v unreleased handle(s)", res.kind, res.path, res.refs))
+	}
+	sort.Strings(leaks)
+	return leaks
+}
+
+// Shutdown logs any leaks then deletes every resource, released or not. Register it with Application.AddCleanup so it
+// runs while the GL context is still valid
+func (manager *Manager) Shutdown() {
+	for _, leak := range manager.Leaks() {
+		dbg.LogError("Resource Leak: " + leak)
+	}
+	for _, res := range manager.resources {
+		if res.delete != nil {
+			res.delete()
+		}
+	}
+	for handle := range manager.handles {
+		handle.released = true
+	}
+	manager.resources = make(map[string]*resource)
+	manager.handles = make(map[*Handle]bool)
+}
+
+// deleteMaterialTextures deletes the textures of materials made by an import. The default material is left alone since
+// it isn't the import's
+func deleteMaterialTextures(materials []*gfx.Material) {
+	deleted := make(map[*gfx.Texture]bool)
+	for _, material := range materials {
+		if material == gfx.DefaultMeshMaterial() {
+			continue
+		}
+		for _, texture := range material.ShaderTextures {
+			if !deleted[texture] {
+				texture.Delete()
+				deleted[texture] = true
+			}
+		}
+	}
+}
+
+// resourceKey makes the key for a kind of resource loaded from filePaths, so different spellings of the same file
+// share a key
+func resourceKey(kind string, filePaths ...string) string {
+	key := kind
+	for _, path := range filePaths {
+		if absolute, err := filepath.Abs(path); err == nil {
+			path = absolute
+		}
+		key += ":" + filepath.Clean(path)
+	}
+	return key
+}
@@ -0,0 +1,151 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Surreal/Systems/Core/core"
+)
+
+// acquireTest acquires a fake resource of kind at filePath, counting loads and deletes instead of touching openGL
+func acquireTest(manager *Manager, kind string, filePath string, owner *core.Scene, loads *int, deletes *int) *Handle {
+	handle, err := manager.acquire(resourceKey(kind, filePath), owner, func(res *resource) error {
+		*loads++
+		res.kind, res.path = kind, filePath
+		if deletes != nil {
+			res.delete = func() { *deletes++ }
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return handle
+}
+
+func TestManagerSharesByCleanedPath(t *testing.T) {
+	manager := CreateManager()
+	loads := 0
+	absolute, _ := filepath.Abs(filepath.Join("textures", "grass.png"))
+	paths := []string{"textures/grass.png", "./textures/grass.png", "textures/../textures//grass.png", absolute}
+	var handles []*Handle
+	for _, path := range paths {
+		handles = append(handles, acquireTest(manager, KindTexture, path, nil, &loads, nil))
+	}
+	if loads != 1 || manager.LoadedCount() != 1 {
+		t.Errorf("got %v loads and %v loaded for one file spelled %v ways, expected 1 and 1", loads, manager.LoadedCount(), len(paths))
+	}
+	if refs := manager.References(KindTexture, "textures/grass.png"); refs != len(paths) {
+		t.Errorf("got %v references, expected %v", refs, len(paths))
+	}
+	if handles[0].resource != handles[3].resource {
+		t.Errorf("handles to the same file got different resources")
+	}
+
+	// The same path as a different kind is a different resource
+	acquireTest(manager, KindMesh, "textures/grass.png", nil, &loads, nil)
+	if loads != 2 || manager.LoadedCount() != 2 {
+		t.Errorf("got %v loads and %v loaded after loading the file as a mesh, expected 2 and 2", loads, manager.LoadedCount())
+	}
+}
+
+func TestManagerRelease(t *testing.T) {
+	manager := CreateManager()
+	loads, deletes := 0, 0
+	first := acquireTest(manager, KindShader, "lit.vert", nil, &loads, &deletes)
+	second := acquireTest(manager, KindShader, "lit.vert", nil, &loads, &deletes)
+
+	first.Release()
+	first.Release()
+	if deletes != 0 || manager.References(KindShader, "lit.vert") != 1 || !first.Released() {
+		t.Errorf("releasing one handle twice: got %v deletes and %v references, expected 0 and 1", deletes, manager.References(KindShader, "lit.vert"))
+	}
+	second.Release()
+	if deletes != 1 || manager.LoadedCount() != 0 || manager.References(KindShader, "lit.vert") != 0 {
+		t.Errorf("releasing the last handle: got %v deletes and %v loaded, expected 1 and 0", deletes, manager.LoadedCount())
+	}
+
+	// Loaded again from scratch once it's gone
+	acquireTest(manager, KindShader, "lit.vert", nil, &loads, &deletes).Release()
+	if loads != 2 || deletes != 2 {
+		t.Errorf("reloading: got %v loads and %v deletes, expected 2 and 2", loads, deletes)
+	}
+
+	// Resources without a delete func are just forgotten
+	acquireTest(manager, KindMesh, "cube.obj", nil, &loads, nil).Release()
+	if manager.LoadedCount() != 0 {
+		t.Errorf("got %v loaded after releasing a resource without a delete func, expected 0", manager.LoadedCount())
+	}
+}
+
+func TestManagerFailedLoad(t *testing.T) {
+	manager := CreateManager()
+	_, err := manager.acquire(resourceKey(KindTexture, "missing.png"), nil, func(res *resource) error {
+		return errors.New("Test: Missing file")
+	})
+	if err == nil || manager.LoadedCount() != 0 || len(manager.handles) != 0 {
+		t.Errorf("got error %v with %v loaded and %v handles, expected an error and nothing tracked", err, manager.LoadedCount(), len(manager.handles))
+	}
+}
+
+func TestManagerUnloadScene(t *testing.T) {
+	manager := CreateManager()
+	loads, deletes := 0, 0
+	level, menu := new(core.Scene), new(core.Scene)
+	levelOnly := acquireTest(manager, KindTexture, "level.png", level, &loads, &deletes)
+	acquireTest(manager, KindTexture, "shared.png", level, &loads, &deletes)
+	acquireTest(manager, KindTexture, "shared.png", level, &loads, &deletes)
+	menuShared := acquireTest(manager, KindTexture, "shared.png", menu, &loads, &deletes)
+	unowned := acquireTest(manager, KindTexture, "level.png", nil, &loads, &deletes)
+
+	if released := manager.UnloadScene(level); released != 3 {
+		t.Errorf("got %v handles released, expected the level's 3", released)
+	}
+	if !levelOnly.Released() || menuShared.Released() || unowned.Released() {
+		t.Errorf("got released level %v, menu %v and unowned %v, expected only the level's", levelOnly.Released(), menuShared.Released(), unowned.Released())
+	}
+	// Both files still have a handle from outside the level
+	if deletes != 0 || manager.References(KindTexture, "shared.png") != 1 || manager.References(KindTexture, "level.png") != 1 {
+		t.Errorf("got %v deletes and references %v and %v, expected 0, 1 and 1", deletes,
+			manager.References(KindTexture, "shared.png"), manager.References(KindTexture, "level.png"))
+	}
+
+	if released := manager.UnloadScene(menu); released != 1 || deletes != 1 {
+		t.Errorf("unloading the menu: got %v released and %v deletes, expected 1 and 1", released, deletes)
+	}
+	if released := manager.UnloadScene(menu); released != 0 {
+		t.Errorf("unloading the menu again: got %v released, expected 0", released)
+	}
+}
+
+func TestManagerLeaksAndShutdown(t *testing.T) {
+	manager := CreateManager()
+	loads, deletes := 0, 0
+	acquireTest(manager, KindTexture, "b.png", nil, &loads, &deletes)
+	acquireTest(manager, KindTexture, "b.png", nil, &loads, &deletes)
+	acquireTest(manager, KindMesh, "a.obj", nil, &loads, &deletes)
+	kept := acquireTest(manager, KindTexture, "a.png", nil, &loads, &deletes)
+	acquireTest(manager, KindTexture, "released.png", nil, &loads, &deletes).Release()
+	deletes = 0
+
+	expected := []string{
+		"Mesh a.obj has 1 unreleased handle(s)",
+		"Texture a.png has 1 unreleased handle(s)",
+		"Texture b.png has 2 unreleased handle(s)",
+	}
+	if leaks := manager.Leaks(); fmt.Sprintf("%q", leaks) != fmt.Sprintf("%q", expected) {
+		t.Errorf("got leaks %q, expected %q", leaks, expected)
+	}
+
+	manager.Shutdown()
+	if deletes != 3 || manager.LoadedCount() != 0 || len(manager.Leaks()) != 0 || !kept.Released() {
+		t.Errorf("got %v deletes, %v loaded and handles released %v after Shutdown, expected 3, 0 and true", deletes, manager.LoadedCount(), kept.Released())
+	}
+	// Releasing a handle after Shutdown mustn't delete anything a second time
+	kept.Release()
+	if deletes != 3 {
+		t.Errorf("got %v deletes after releasing a handle past Shutdown, expected 3", deletes)
+	}
+}
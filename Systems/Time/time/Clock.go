@@ -0,0 +1,45 @@
+package time
+
+import gotime "time"
+
+// Clock is the source of time used by a Timer. Now should return seconds since some fixed point and never go backwards
+type Clock interface {
+	Now() float64
+}
+
+// systemClock reads the OS's monotonic clock
+type systemClock struct {
+	start gotime.Time
+}
+
+// CreateSystemClock is the standard constructor for a clock backed by the real time since the clock was created
+func CreateSystemClock() Clock {
+	return &systemClock{start: gotime.Now()}
+}
+
+// Now implements the Clock interface
+func (clock *systemClock) Now() float64 {
+	return gotime.Since(clock.start).Seconds()
+}
+
+// ManualClock is a clock that only moves when told to. Useful for tests, replays and headless runs
+type ManualClock struct {
+	now float64
+}
+
+// CreateManualClock is the standard constructor for a ManualClock
+func CreateManualClock(start float64) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now implements the Clock interface
+func (clock *ManualClock) Now() float64 {
+	return clock.now
+}
+
+// Advance moves the clock forward by seconds. Negative values are ignored so the clock never goes backwards
+func (clock *ManualClock) Advance(seconds float64) {
+	if seconds > 0 {
+		clock.now += seconds
+	}
+}
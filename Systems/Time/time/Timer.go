@@ -0,0 +1,177 @@
+package time
+
+// Default settings for a Timer
+const (
+	DefaultFixedDeltaTime float32 = 1.0 / 60.0 // 60 fixed updates a second
+	DefaultMaxFixedSteps  int     = 8          // Fixed steps allowed per frame before the simulation gives up catching up
+	DefaultMaxDeltaTime   float32 = 1.0 / 3.0  // Longest frame we'll simulate, stops a breakpoint or window drag exploding the sim
+)
+
+// Timer tracks frame timing and drives a fixed timestep accumulator. Call Tick once at the start of each frame then
+// drain the fixed steps:
+//
+//	timer.Tick()
+//	for timer.FixedStep() {
+//	    scene.FixedUpdate(timer.FixedDeltaTime())
+//	}
+//	scene.Update(timer.DeltaTime())
+type Timer struct {
+	clock     Clock
+	lastNow   float64
+	timeScale float32
+
+	deltaTime         float32
+	unscaledDeltaTime float32
+	maxDeltaTime      float32
+	time              float64
+	unscaledTime      float64
+	frameCount        uint64
+
+	fixedDeltaTime float32
+	maxFixedSteps  int
+	fixedSteps     int // Fixed steps taken since the last Tick
+	accumulator    float64
+	fixedTime      float64
+}
+
+// CreateTimer is the standard constructor for a Timer. If clock is nil the system clock is used
+func CreateTimer(clock Clock) *Timer {
+	if clock == nil {
+		clock = CreateSystemClock()
+	}
+	timer := new(Timer)
+	timer.clock = clock
+	timer.lastNow = clock.Now()
+	timer.timeScale = 1
+	timer.maxDeltaTime = DefaultMaxDeltaTime
+	timer.fixedDeltaTime = DefaultFixedDeltaTime
+	timer.maxFixedSteps = DefaultMaxFixedSteps
+	return timer
+}
+
+// Tick advances the timer to the clock's current time. Call it once per frame before any updates
+func (timer *Timer) Tick() {
+	now := timer.clock.Now()
+	unscaled := float32(now - timer.lastNow)
+	timer.lastNow = now
+	if unscaled < 0 {
+		unscaled = 0
+	}
+	if unscaled > timer.maxDeltaTime {
+		unscaled = timer.maxDeltaTime
+	}
+
+	timer.unscaledDeltaTime = unscaled
+	timer.deltaTime = unscaled * timer.timeScale
+	timer.unscaledTime += float64(timer.unscaledDeltaTime)
+	timer.time += float64(timer.deltaTime)
+	timer.frameCount++
+
+	timer.accumulator += float64(timer.deltaTime)
+	timer.fixedSteps = 0
+}
+
+// FixedStep returns true if another fixed update should run this frame and consumes one step from the accumulator.
+// Once MaxFixedSteps have been taken in a frame any remaining backlog is dropped, slowing the simulation down rather
+// than spiralling
+func (timer *Timer) FixedStep() bool {
+	step := float64(timer.fixedDeltaTime)
+	if timer.accumulator < step {
+		return false
+	}
+	if timer.fixedSteps >= timer.maxFixedSteps {
+		timer.accumulator -= step * float64(int(timer.accumulator/step))
+		return false
+	}
+
+	timer.accumulator -= step
+	timer.fixedTime += step
+	timer.fixedSteps++
+	return true
+}
+
+// Alpha returns how far between the last fixed step and the next one the current frame is, in the range [0, 1).
+// Use it to interpolate rendered state between the previous and current fixed step
+func (timer *Timer) Alpha() float32 {
+	return float32(timer.accumulator / float64(timer.fixedDeltaTime))
+}
+
+// DeltaTime returns the scaled number of seconds the last frame took
+func (timer *Timer) DeltaTime() float32 {
+	return timer.deltaTime
+}
+
+// UnscaledDeltaTime returns the number of seconds the last frame took ignoring TimeScale, e.g. for pause menus
+func (timer *Timer) UnscaledDeltaTime() float32 {
+	return timer.unscaledDeltaTime
+}
+
+// Time returns the scaled number of seconds since the timer was created
+func (timer *Timer) Time() float64 {
+	return timer.time
+}
+
+// UnscaledTime returns the number of seconds since the timer was created ignoring TimeScale
+func (timer *Timer) UnscaledTime() float64 {
+	return timer.unscaledTime
+}
+
+// FixedTime returns the simulated time reached by fixed steps
+func (timer *Timer) FixedTime() float64 {
+	return timer.fixedTime
+}
+
+// FrameCount returns the number of times Tick has been called
+func (timer *Timer) FrameCount() uint64 {
+	return timer.frameCount
+}
+
+// TimeScale returns the multiplier applied to delta time. 0 pauses, values below 1 slow things down
+func (timer *Timer) TimeScale() float32 {
+	return timer.timeScale
+}
+
+// SetTimeScale setter for TimeScale(). Negative values are treated as 0
+func (timer *Timer) SetTimeScale(value float32) {
+	if value < 0 {
+		value = 0
+	}
+	timer.timeScale = value
+}
+
+// FixedDeltaTime returns the number of seconds simulated by each fixed step
+func (timer *Timer) FixedDeltaTime() float32 {
+	return timer.fixedDeltaTime
+}
+
+// SetFixedDeltaTime setter for FixedDeltaTime(). Values <= 0 are ignored
+func (timer *Timer) SetFixedDeltaTime(value float32) {
+	if value > 0 {
+		timer.fixedDeltaTime = value
+	}
+}
+
+// MaxFixedSteps returns the most fixed steps that will be taken in a single frame
+func (timer *Timer) MaxFixedSteps() int {
+	return timer.maxFixedSteps
+}
+
+// SetMaxFixedSteps setter for MaxFixedSteps(). Values < 1 are treated as 1
+func (timer *Timer) SetMaxFixedSteps(value int) {
+	if value < 1 {
+		value = 1
+	}
+	timer.maxFixedSteps = value
+}
+
+// MaxDeltaTime returns the longest unscaled frame time that Tick will report
+func (timer *Timer) MaxDeltaTime() float32 {
+	return timer.maxDeltaTime
+}
+
+// SetMaxDeltaTime setter for MaxDeltaTime(). Values <= 0 are ignored
+func (timer *Timer) SetMaxDeltaTime(value float32) {
+	if value > 0 {
+		timer.maxDeltaTime = value
+	}
+}
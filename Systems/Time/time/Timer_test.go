@@ -0,0 +1,157 @@
+package time
+
+import (
+	gomath "math"
+	"testing"
+)
+
+// drainFixedSteps runs every fixed step of the frame and returns how many there were
+func drainFixedSteps(timer *Timer) int {
+	steps := 0
+	for timer.FixedStep() {
+		steps++
+	}
+	return steps
+}
+
+func approxEqual(a float64, b float64) bool {
+	return gomath.Abs(a-b) < 1e-5
+}
+
+func TestManualClock(t *testing.T) {
+	clock := CreateManualClock(5)
+	clock.Advance(0.5)
+	clock.Advance(-10)
+	if clock.Now() != 5.5 {
+		t.Errorf("Clock is at %v, expected 5.5 with the negative advance ignored", clock.Now())
+	}
+}
+
+func TestTimerDeltaTime(t *testing.T) {
+	clock := CreateManualClock(100)
+	timer := CreateTimer(clock)
+
+	clock.Advance(0.1)
+	timer.Tick()
+	if !approxEqual(float64(timer.DeltaTime()), 0.1) || timer.FrameCount() != 1 {
+		t.Errorf("DeltaTime %v and FrameCount %v after one 0.1s frame", timer.DeltaTime(), timer.FrameCount())
+	}
+
+	// Long frames are clamped
+	clock.Advance(5)
+	timer.Tick()
+	if timer.DeltaTime() != DefaultMaxDeltaTime || timer.UnscaledDeltaTime() != DefaultMaxDeltaTime {
+		t.Errorf("A 5s frame gave DeltaTime %v, expected it clamped to %v", timer.DeltaTime(), DefaultMaxDeltaTime)
+	}
+	if !approxEqual(timer.Time(), 0.1+float64(DefaultMaxDeltaTime)) {
+		t.Errorf("Time is %v", timer.Time())
+	}
+}
+
+func TestTimerTimeScaleAndPause(t *testing.T) {
+	clock := CreateManualClock(0)
+	timer := CreateTimer(clock)
+
+	timer.SetTimeScale(0.5)
+	clock.Advance(0.2)
+	timer.Tick()
+	if !approxEqual(float64(timer.DeltaTime()), 0.1) || !approxEqual(float64(timer.UnscaledDeltaTime()), 0.2) {
+		t.Errorf("Half speed gave DeltaTime %v and UnscaledDeltaTime %v", timer.DeltaTime(), timer.UnscaledDeltaTime())
+	}
+	drainFixedSteps(timer)
+
+	timer.SetTimeScale(0)
+	clock.Advance(0.2)
+	timer.Tick()
+	if timer.DeltaTime() != 0 || drainFixedSteps(timer) != 0 {
+		t.Errorf("Paused timer gave DeltaTime %v or took fixed steps", timer.DeltaTime())
+	}
+	if !approxEqual(timer.Time(), 0.1) || !approxEqual(timer.UnscaledTime(), 0.4) {
+		t.Errorf("Time %v should stop while paused, UnscaledTime %v shouldn't", timer.Time(), timer.UnscaledTime())
+	}
+
+	timer.SetTimeScale(-1)
+	if timer.TimeScale() != 0 {
+		t.Errorf("Negative time scale gave %v, expected 0", timer.TimeScale())
+	}
+}
+
+func TestTimerFixedStepAccumulation(t *testing.T) {
+	clock := CreateManualClock(0)
+	timer := CreateTimer(clock)
+	timer.SetFixedDeltaTime(0.1)
+
+	tests := []struct {
+		advance float64
+		steps   int
+	}{
+		{0.05, 0}, // 0.05 banked
+		{0.06, 1}, // 0.11, one step leaves 0.01
+		{0.25, 2}, // 0.26, two steps leave 0.06
+		{0.05, 1}, // 0.11, one step leaves 0.01
+		{0, 0},
+	}
+	total := 0
+	for i, test := range tests {
+		clock.Advance(test.advance)
+		timer.Tick()
+		steps := drainFixedSteps(timer)
+		total += steps
+		if steps != test.steps {
+			t.Errorf("Frame %v: took %v fixed steps, expected %v", i, steps, test.steps)
+		}
+	}
+	if !approxEqual(timer.FixedTime(), float64(total)*0.1) {
+		t.Errorf("FixedTime is %v after %v steps", timer.FixedTime(), total)
+	}
+}
+
+func TestTimerMaxFixedStepsClamp(t *testing.T) {
+	clock := CreateManualClock(0)
+	timer := CreateTimer(clock)
+	timer.SetFixedDeltaTime(0.01)
+	timer.SetMaxFixedSteps(3)
+
+	clock.Advance(0.1)
+	timer.Tick()
+	if steps := drainFixedSteps(timer); steps != 3 {
+		t.Errorf("Took %v fixed steps, expected the clamp of 3", steps)
+	}
+	// The backlog is dropped rather than carried into the next frame
+	if alpha := timer.Alpha(); alpha < 0 || alpha >= 1 {
+		t.Errorf("Alpha after dropping the backlog is %v, expected [0, 1)", alpha)
+	}
+	clock.Advance(0.005)
+	timer.Tick()
+	if steps := drainFixedSteps(timer); steps > 1 {
+		t.Errorf("Took %v fixed steps the frame after the clamp, the backlog should have been dropped", steps)
+	}
+
+	timer.SetMaxFixedSteps(0)
+	if timer.MaxFixedSteps() != 1 {
+		t.Errorf("MaxFixedSteps of 0 gave %v, expected 1", timer.MaxFixedSteps())
+	}
+}
+
+func TestTimerAlpha(t *testing.T) {
+	clock := CreateManualClock(0)
+	timer := CreateTimer(clock)
+	timer.SetFixedDeltaTime(0.1)
+
+	clock.Advance(0.125)
+	timer.Tick()
+	drainFixedSteps(timer)
+	if !approxEqual(float64(timer.Alpha()), 0.25) {
+		t.Errorf("Alpha is %v a quarter of the way to the next step", timer.Alpha())
+	}
+
+	clock.Advance(0.05)
+	timer.Tick()
+	if !approxEqual(float64(timer.Alpha()), 0.75) {
+		t.Errorf("Alpha before draining is %v, expected 0.75", timer.Alpha())
+	}
+	drainFixedSteps(timer)
+	if !approxEqual(float64(timer.Alpha()), 0.75) {
+		t.Errorf("Alpha is %v, expected 0.75 with no step due", timer.Alpha())
+	}
+}
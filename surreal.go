@@ -1,7 +1,5 @@
 // main is the entry point for the project
 // TODO:
-//   > Refactor render system to have global renderer with submit() and flush()
-//   > Basic lighting system
 //   > Camera models
 //   > General optimization
 //   > Check shader parameter setting is performant or not
@@ -10,16 +8,11 @@ package main
 
 import (
 	"path/filepath"
-	"runtime"
-	"unsafe"
 
 	"github.com/Surreal/Debug/dbg"
 
-	"github.com/go-gl/glfw/v3.2/glfw"
-
-	"github.com/go-gl/gl/v3.2-core/gl"
-
 	"github.com/Surreal/Math/math"
+	"github.com/Surreal/Systems/Application/app"
 	"github.com/Surreal/Systems/Core/core"
 	"github.com/Surreal/Systems/Graphics/gfx"
 	"github.com/Surreal/Systems/Input/input"
@@ -30,85 +23,42 @@ import (
 	_ "image/png"
 )
 
-func init() {
-	runtime.LockOSThread()
-}
-
-func glDebugCallback(
-	source uint32,
-	gltype uint32,
-	id uint32,
-	severity uint32,
-	length int32,
-	message string,
-	userParam unsafe.Pointer) {
-	dbg.LogError(message)
+// demoGame is the sample scene: a textured model that can be spun with WASD
+type demoGame struct {
+	scene *core.Scene
 }
 
-func main() {
-	// Init GLFW
-	if err := glfw.Init(); err != nil {
-		panic(err)
-	}
-	// Defer the terminate
-	defer glfw.Terminate()
-
-	// Window settings
-	glfw.WindowHint(glfw.ContextVersionMajor, 3)
-	glfw.WindowHint(glfw.ContextVersionMinor, 2)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-	glfw.WindowHint(glfw.Samples, 8)
-
-	// Create window
-	window, err := glfw.CreateWindow(1920, 1080, "Surreal Engine v0.0.0", nil, nil)
-	if err != nil {
-		panic(err)
-	}
-
-	// Bind context to window
-	window.MakeContextCurrent()
-
-	// Init OpenGL 3.2
-	if err := gl.Init(); err != nil {
-		panic(err)
-	}
-
-	gl.Enable(gl.DEBUG_OUTPUT)
-	gl.Enable(gl.DEPTH_TEST)
-	gl.Enable(gl.CULL_FACE)
-	gl.Enable(gl.MULTISAMPLE)
-	gl.FrontFace(gl.CCW)
-	gl.DebugMessageCallback(glDebugCallback, nil)
-	gl.ClearColor(float32(0), float32(0), float32(0.1), float32(1))
-
-	// Setup input loop
-	input.TrackWindow(window)
-
+// Init implements the app.Game interface
+func (game *demoGame) Init(application *app.Application) error {
 	// Create Texture
 	texture := gfx.CreateTexture(filepath.Join(util.DataRoot(), "Textures", "textures.png"))
 	//texture.SetHorizontalWrapMode(gl.CLAMP_TO_EDGE)
 	//texture.SetVerticalWrapMode(gl.CLAMP_TO_EDGE)
-	texture.Load()
+	texture.GenerateMipMaps = true
+	texture.SetAnisotropy(8)
+	gfx.MainAssetLoader.LoadTexture(texture).OnComplete(logLoadError)
+	application.AddCleanup(gfx.MainAssetLoader.Close)
 
 	// Create a scene
-	scene := &core.Scene{}
+	game.scene = &core.Scene{}
 
 	// Create Material
 	tintColor := []float32{1.0, 1.0, 1.0, 1.0}
 
-	// Create a SceneObject
-	cube, err := gfx.ImportMesh(filepath.Join(util.DataRoot(), "Models", "Anime_charcter.obj"))
-	if err != nil {
-		panic(err.Error())
-	}
-
-	cube.Transform.SetLocalPosition(math.Vector3f{X: 0, Y: -4, Z: 0})
-
 	gfx.DefaultMeshMaterial().SetMaterialParameter("u_Tint", &tintColor)
 	gfx.DefaultMeshMaterial().SetTextureParameter("u_Albedo", texture)
 
-	scene.AddSceneObject(cube)
+	// Create a SceneObject once the model has streamed in
+	model := gfx.MainAssetLoader.LoadMesh(filepath.Join(util.DataRoot(), "Models", "Anime_charcter.obj"))
+	model.OnComplete(func(err error) {
+		if err != nil {
+			logLoadError(err)
+			return
+		}
+		model.SceneObject.Transform.SetLocalPosition(math.Vector3f{X: 0, Y: -4, Z: 0})
+		model.SceneObject.AddComponent(createRotateOnInputComponent(30))
+		game.scene.AddSceneObject(model.SceneObject)
+	})
 
 	// Create a camera
 	camera := core.CreateSceneObject(nil)
@@ -116,32 +66,104 @@ func main() {
 	camComponent.Attach(camera)
 	camera.Transform.SetLocalPosition(math.Vector3f{X: 0, Y: 0, Z: 10})
 
-	for !window.ShouldClose() {
-		// Clear
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	// Smooth the edges and darken the corners
+	vignette, err := gfx.CreateVignetteEffect()
+	if err != nil {
+		return err
+	}
+	fxaa, err := gfx.CreateFXAAEffect()
+	if err != nil {
+		return err
+	}
+	camComponent.PostProcess = gfx.CreatePostProcessStack(vignette, fxaa)
+	application.AddCleanup(camComponent.PostProcess.Delete)
+
+	// Create some lights
+	sun := core.CreateSceneObject(nil)
+	sunLight := gfx.CreateDirectionalLightComponent(math.Vector3f{X: 1, Y: 0.95, Z: 0.85}, 1)
+	sunLight.Shadows.CastShadows = true
+	sun.AddComponent(sunLight)
+	sun.Transform.SetLocalRotation(math.Vector3f{X: -45, Y: 30, Z: 0})
+	game.scene.AddSceneObject(sun)
+
+	fill := core.CreateSceneObject(nil)
+	fill.AddComponent(gfx.CreatePointLightComponent(math.Vector3f{X: 0.4, Y: 0.6, Z: 1}, 2, 15))
+	fill.Transform.SetLocalPosition(math.Vector3f{X: -5, Y: 2, Z: 5})
+	game.scene.AddSceneObject(fill)
+	return nil
+}
 
-		// Game play
-		newPos := cube.Transform.LocalRotation()
-		if input.GetKey(input.KeyA) {
-			newPos.Y -= 0.5
-		}
-		if input.GetKey(input.KeyD) {
-			newPos.Y += 0.5
-		}
-		if input.GetKey(input.KeyW) {
-			newPos.X += 0.5
-		}
-		if input.GetKey(input.KeyS) {
-			newPos.X -= 0.5
-		}
-		cube.Transform.SetLocalRotation(newPos)
+// Update implements the app.Game interface
+func (game *demoGame) Update(application *app.Application) {
+	timer := application.Timer()
+	for timer.FixedStep() {
+		game.scene.FixedUpdate(timer.FixedDeltaTime())
+	}
+	game.scene.Update(timer.DeltaTime())
+	game.scene.LateUpdate(timer.DeltaTime())
+
+	if input.GetKeyDown(input.KeyEscape) {
+		application.Quit()
+	}
+}
 
-		scene.Render()
+// Render implements the app.Game interface
+func (game *demoGame) Render(application *app.Application) {
+	gfx.MainRenderer.ResetStats()
+	game.scene.Render()
+	if err := gfx.MainRenderer.Flush(); err != nil {
+		dbg.LogError(err.Error())
+	}
+}
+
+// Shutdown implements the app.Game interface
+func (game *demoGame) Shutdown(application *app.Application) {
+	game.scene = nil
+}
 
-		// End of frame
-		window.SwapBuffers()
-		glfw.PollEvents()
+// logLoadError logs assets that failed to load without stopping the game
+func logLoadError(err error) {
+	if err != nil {
+		dbg.LogError(err.Error())
+	}
+}
+
+func main() {
+	application := app.CreateApplication(app.DefaultConfig())
+	if err := application.Run(&demoGame{}); err != nil {
+		panic(err)
 	}
 
 	dbg.Log("Program Terminated Successfully!")
 }
+
+// rotateOnInputComponent spins it's scene object with the WASD keys
+type rotateOnInputComponent struct {
+	*core.BaseComponent
+	degreesPerSecond float32
+}
+
+func createRotateOnInputComponent(degreesPerSecond float32) *rotateOnInputComponent {
+	comp := new(rotateOnInputComponent)
+	comp.BaseComponent = core.CreateBaseComponent(comp)
+	comp.degreesPerSecond = degreesPerSecond
+	return comp
+}
+
+// Update implements the core.Updater interface
+func (comp *rotateOnInputComponent) Update(deltaTime float32) {
+	transform := comp.SceneObject().Transform
+	step := comp.degreesPerSecond * deltaTime
+	if input.GetKey(input.KeyA) {
+		transform.Rotate(math.QuaternionFromAxisAngle(math.UpVector3f(), -step))
+	}
+	if input.GetKey(input.KeyD) {
+		transform.Rotate(math.QuaternionFromAxisAngle(math.UpVector3f(), step))
+	}
+	if input.GetKey(input.KeyW) {
+		transform.Rotate(math.QuaternionFromAxisAngle(math.RightVector3f(), step))
+	}
+	if input.GetKey(input.KeyS) {
+		transform.Rotate(math.QuaternionFromAxisAngle(math.RightVector3f(), -step))
+	}
+}